@@ -11,13 +11,21 @@ import (
 	"syscall"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
 	"github.com/modelcontextprotocol/registry/internal/api"
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/config"
 	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/importer"
+	"github.com/modelcontextprotocol/registry/internal/introspection"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
+	"github.com/modelcontextprotocol/registry/internal/remotehealth"
+	"github.com/modelcontextprotocol/registry/internal/revalidation"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
+	"github.com/modelcontextprotocol/registry/internal/webhooks"
 )
 
 // Version info for the MCP Registry application
@@ -34,6 +42,11 @@ var (
 )
 
 func main() {
+	// Subcommands take over before the server's own flags are parsed
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	// Parse command line flags
 	showVersion := flag.Bool("version", false, "Display version information")
 	flag.Parse()
@@ -51,29 +64,61 @@ func main() {
 	var (
 		registryService service.RegistryService
 		db              database.Database
+		pgDB            *database.PostgreSQL
 		err             error
 	)
 
 	// Initialize configuration
 	cfg := config.NewConfig()
 
+	// Metrics are initialized first so the database can report transaction retries
+	shutdownTelemetry, metrics, err := telemetry.InitMetrics(cfg.Version)
+	if err != nil {
+		log.Printf("Failed to initialize metrics: %v", err)
+		return
+	}
+
+	defer func() {
+		if err := shutdownTelemetry(context.Background()); err != nil {
+			log.Printf("Failed to shutdown telemetry: %v", err)
+		}
+	}()
+
 	// Create a context with timeout for PostgreSQL connection
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	// Connect to PostgreSQL
-	db, err = database.NewPostgreSQL(ctx, cfg.DatabaseURL)
-	if err != nil {
-		log.Printf("Failed to connect to PostgreSQL: %v", err)
-		return
+	if database.IsMemoryURL(cfg.DatabaseURL) {
+		// Use the in-memory database for demos and local development
+		log.Println("Using in-memory database: data will be lost when the registry stops")
+		db = database.NewMemory()
+	} else {
+		// Connect to PostgreSQL
+		pgDB, err = database.NewPostgreSQL(ctx, cfg.DatabaseURL, database.Options{
+			MaxConns:        cfg.DatabaseMaxConns,
+			ReplicaURI:      cfg.DatabaseReplicaURL,
+			ReplicaMaxConns: cfg.DatabaseReplicaMaxConns,
+			SkipMigrations:  !cfg.MigrateOnStartup,
+			OnRetry: func(ctx context.Context, reason string) {
+				metrics.TransactionRetries.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+			},
+		})
+		if err != nil {
+			log.Printf("Failed to connect to PostgreSQL: %v", err)
+			return
+		}
+		db = pgDB
+		if pgDB.HasReplica() {
+			log.Println("Routing non-transactional reads to the read replica")
+		}
 	}
 
-	// Store the PostgreSQL instance for later cleanup
+	// Store the database instance for later cleanup
 	defer func() {
 		if err := db.Close(); err != nil {
-			log.Printf("Error closing PostgreSQL connection: %v", err)
+			log.Printf("Error closing database connection: %v", err)
 		} else {
-			log.Println("PostgreSQL connection closed successfully")
+			log.Println("Database connection closed successfully")
 		}
 	}()
 
@@ -91,17 +136,76 @@ func main() {
 		}
 	}
 
-	shutdownTelemetry, metrics, err := telemetry.InitMetrics(cfg.Version)
+	if pgDB != nil && pgDB.HasReplica() {
+		if err := metrics.ObserveReplicaLag(pgDB.ReplicaLag); err != nil {
+			log.Printf("Failed to register replica lag metric: %v", err)
+			return
+		}
+	}
+
+	// Background jobs run until shutdown
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+
+	// Deliver queued webhook events
+	if cfg.EnableWebhookDelivery {
+		dispatcher := webhooks.NewDispatcher(db, nil, webhooks.Options{
+			MaxAttempts:  cfg.WebhookMaxAttempts,
+			PollInterval: cfg.WebhookPollInterval,
+		})
+		go dispatcher.Run(backgroundCtx)
+	}
+
+	// Re-check package registry ownership of latest active versions
+	if cfg.EnablePackageRevalidation {
+		revalidator := revalidation.NewRevalidator(db, registryService, revalidation.Options{
+			Interval:       cfg.PackageRevalidationInterval,
+			Concurrency:    cfg.PackageRevalidationConcurrency,
+			DeprecateAfter: cfg.PackageRevalidationDeprecateAfter,
+		})
+		go revalidator.Run(backgroundCtx)
+	}
+
+	// Probe the remotes of latest active versions with the MCP initialize handshake
+	if cfg.EnableRemoteProbing {
+		prober := remotehealth.NewProber(db, nil, remotehealth.Options{
+			Interval:    cfg.RemoteProbeInterval,
+			Concurrency: cfg.RemoteProbeConcurrency,
+			Timeout:     cfg.RemoteProbeTimeout,
+		})
+		go prober.Run(backgroundCtx)
+	}
+
+	// Record the tools, prompts and resources of latest active versions
+	if cfg.EnableCapabilityIntrospection {
+		introspector := introspection.NewIntrospector(db, nil, introspection.Options{
+			Interval:    cfg.CapabilityIntrospectionInterval,
+			Concurrency: cfg.CapabilityIntrospectionConcurrency,
+			Timeout:     cfg.CapabilityIntrospectionTimeout,
+		})
+		go introspector.Run(backgroundCtx)
+	}
+
+	// Rate limit requests, sharing token buckets between replicas through the database if configured
+	var limiter *ratelimit.Limiter
+	policies, err := ratelimit.ParsePolicies(cfg.RateLimits)
 	if err != nil {
-		log.Printf("Failed to initialize metrics: %v", err)
+		log.Printf("Failed to parse rate limits: %v", err)
 		return
 	}
-
-	defer func() {
-		if err := shutdownTelemetry(context.Background()); err != nil {
-			log.Printf("Failed to shutdown telemetry: %v", err)
+	if len(policies) > 0 {
+		var store ratelimit.Store
+		switch cfg.RateLimitStore {
+		case "memory":
+			store = ratelimit.NewMemoryStore()
+		case "database":
+			store = ratelimit.NewDatabaseStore(db, policies)
+		default:
+			log.Printf("Invalid rate limit store %q: expected memory or database", cfg.RateLimitStore)
+			return
 		}
-	}()
+		limiter = ratelimit.NewLimiter(store, policies)
+	}
 
 	// Prepare version information
 	versionInfo := &v0.VersionBody{
@@ -111,7 +215,7 @@ func main() {
 	}
 
 	// Initialize HTTP server
-	server := api.NewServer(cfg, registryService, metrics, versionInfo)
+	server := api.NewServer(cfg, registryService, metrics, versionInfo, limiter)
 
 	// Start server in a goroutine so it doesn't block signal handling
 	go func() {
@@ -136,6 +240,7 @@ func main() {
 	if err := server.Shutdown(sctx); err != nil {
 		log.Printf("Server forced to shutdown: %v", err)
 	}
+	stopBackground()
 
 	log.Println("Server exiting")
 }
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+const migrateUsage = `Usage: registry migrate <command> [flags]
+
+Commands:
+  status                  List migrations and whether they have been applied
+  up [--to N] [--dry-run] Apply pending migrations, optionally only up to version N
+  down --to N [--dry-run] Revert applied migrations newer than version N
+
+The database is read from MCP_REGISTRY_DATABASE_URL.
+`
+
+// runMigrate implements the migrate subcommand, returning the process exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	command := args[0]
+	flags := flag.NewFlagSet("migrate "+command, flag.ContinueOnError)
+	target := flags.Int("to", 0, "Target migration version")
+	dryRun := flags.Bool("dry-run", false, "Print the migrations that would run without running them")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 2
+	}
+
+	cfg := config.NewConfig()
+	if database.IsMemoryURL(cfg.DatabaseURL) {
+		fmt.Fprintln(os.Stderr, "The in-memory database has no migrations")
+		return 1
+	}
+
+	// Migrations can hold locks for a while, so only connecting is bounded
+	connectCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	conn, err := pgx.Connect(connectCtx, cfg.DatabaseURL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to PostgreSQL: %v\n", err)
+		return 1
+	}
+	defer conn.Close(context.Background())
+
+	ctx := context.Background()
+	migrator := database.NewMigrator(conn)
+
+	switch command {
+	case "status":
+		err = printMigrationStatus(ctx, migrator)
+	case "up":
+		var applied []database.Migration
+		if applied, err = migrator.MigrateUp(ctx, *target, *dryRun); err == nil {
+			printMigrations(applied, "apply", *dryRun)
+		}
+	case "down":
+		if !isFlagSet(flags, "to") {
+			fmt.Fprintln(os.Stderr, "migrate down requires --to, the version to revert back to")
+			return 2
+		}
+		var reverted []database.Migration
+		if reverted, err = migrator.MigrateDown(ctx, *target, *dryRun); err == nil {
+			printMigrations(reverted, "revert", *dryRun)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown migrate command %q\n\n%s", command, migrateUsage)
+		return 2
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+		if errors.Is(err, database.ErrMigrationChecksumMismatch) {
+			fmt.Fprintln(os.Stderr, "Applied migrations must not be edited; add a new migration instead.")
+		}
+		return 1
+	}
+	return 0
+}
+
+// printMigrationStatus writes a table of all known migrations
+func printMigrationStatus(ctx context.Context, migrator *database.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT\tDOWN")
+	for _, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state, appliedAt = "applied", status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		down := "no"
+		if status.DownSQL != "" {
+			down = "yes"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt, down)
+	}
+	return w.Flush()
+}
+
+// printMigrations lists the migrations an up or down command ran, or would run
+func printMigrations(migrations []database.Migration, verb string, dryRun bool) {
+	if len(migrations) == 0 {
+		fmt.Println("Nothing to " + verb)
+		return
+	}
+
+	prefix := "Would " + verb
+	if !dryRun {
+		prefix = "Done: " + verb
+	}
+	for _, migration := range migrations {
+		fmt.Printf("%s %d %s\n", prefix, migration.Version, migration.Name)
+	}
+}
+
+// isFlagSet reports whether a flag was given explicitly on the command line
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -168,6 +168,17 @@ func DeployMCPRegistry(ctx *pulumi.Context, cluster *providers.ProviderInfo, env
 									Name:  pulumi.String("MCP_REGISTRY_OIDC_PUBLISH_PERMISSIONS"),
 									Value: pulumi.String("*"),
 								},
+								// Requests reach the registry through ingress-nginx, which sets X-Forwarded-For to the
+								// client address of the TCP connection. Without the trusted hop, every client would
+								// share the rate limits of the ingress pod's IP.
+								&corev1.EnvVarArgs{
+									Name:  pulumi.String("MCP_REGISTRY_RATE_LIMIT_TRUSTED_PROXY_HOPS"),
+									Value: pulumi.String("1"),
+								},
+								&corev1.EnvVarArgs{
+									Name:  pulumi.String("MCP_REGISTRY_RATE_LIMITS"),
+									Value: pulumi.String("auth=30/1m:ip,publish=120/1h:subject,*=1200/1m:ip"),
+								},
 							},
 							LivenessProbe: &corev1.ProbeArgs{
 								HttpGet: &corev1.HTTPGetActionArgs{
@@ -43,7 +43,7 @@ func TestCORSHeaders(t *testing.T) {
 	}
 
 	// Create server
-	_ = api.NewServer(cfg, registryService, metrics, versionInfo)
+	_ = api.NewServer(cfg, registryService, metrics, versionInfo, nil)
 
 	tests := []struct {
 		name           string
@@ -157,7 +157,7 @@ func TestCORSHeaderValues(t *testing.T) {
 	}
 
 	// Create server
-	_ = api.NewServer(cfg, registryService, metrics, versionInfo)
+	_ = api.NewServer(cfg, registryService, metrics, versionInfo, nil)
 
 	// Test that CORS is configured with correct values
 	// This is more of a documentation test to ensure we know what CORS settings we use
@@ -0,0 +1,47 @@
+package v0
+
+import (
+	"context"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+)
+
+// authenticate validates the bearer token in authHeader and returns its claims
+func authenticate(ctx context.Context, jwtManager *auth.JWTManager, authHeader string) (*auth.JWTClaims, error) {
+	// Extract bearer token
+	const bearerPrefix = "Bearer "
+	if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+		return nil, huma.Error401Unauthorized("Invalid Authorization header format. Expected 'Bearer <token>'")
+	}
+	token := authHeader[len(bearerPrefix):]
+
+	// Validate Registry JWT token
+	claims, err := jwtManager.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
+	}
+
+	return claims, nil
+}
+
+// isAdmin reports whether the claims grant registry-wide edit permissions, which is what
+// distinguishes admins from publishers
+func isAdmin(jwtManager *auth.JWTManager, claims *auth.JWTClaims) bool {
+	return jwtManager.HasPermission("*", auth.PermissionActionEdit, claims.Permissions)
+}
+
+// authenticateAdmin validates the bearer token in authHeader and checks that it belongs to an admin
+func authenticateAdmin(ctx context.Context, jwtManager *auth.JWTManager, authHeader string) (*auth.JWTClaims, error) {
+	claims, err := authenticate(ctx, jwtManager, authHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isAdmin(jwtManager, claims) {
+		return nil, huma.Error403Forbidden("This endpoint requires admin permissions")
+	}
+
+	return claims, nil
+}
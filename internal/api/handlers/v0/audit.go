@@ -0,0 +1,109 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListAuditEventsInput represents the input for listing audit events
+type ListAuditEventsInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	Cursor        string `query:"cursor" doc:"Pagination cursor" required:"false" example:"1234"`
+	Limit         int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
+	ServerName    string `query:"server_name" doc:"Filter by exact server name" required:"false" example:"io.github.user/weather"`
+	Actor         string `query:"actor" doc:"Filter by actor subject (e.g. GitHub username or domain)" required:"false" example:"user"`
+	Action        string `query:"action" doc:"Filter by mutation type" required:"false" enum:"publish,edit,status_change,tag_set,tag_delete,namespace_block,namespace_unblock,remote_url_release,webhook_subscribe,webhook_unsubscribe"`
+	Since         string `query:"since" doc:"Only include events at or after this time (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	Until         string `query:"until" doc:"Only include events before this time (RFC3339 datetime)" required:"false" example:"2025-08-08T13:15:04.280Z"`
+}
+
+// RegisterAuditEndpoints registers the admin audit log endpoints with a custom path prefix
+func RegisterAuditEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-audit-events" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/admin/audit",
+		Summary:     "List audit events",
+		Description: "Get a paginated list of registry mutations, newest first (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListAuditEventsInput) (*Response[apiv0.AuditEventListResponse], error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		filter, err := buildAuditEventFilter(input)
+		if err != nil {
+			return nil, err
+		}
+
+		events, nextCursor, err := registry.ListAuditEvents(ctx, filter, input.Cursor, input.Limit)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid audit log request", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to get audit events", err)
+		}
+
+		// Convert []*AuditEvent to []AuditEvent
+		eventValues := make([]apiv0.AuditEvent, len(events))
+		for i, event := range events {
+			eventValues[i] = *event
+		}
+
+		return &Response[apiv0.AuditEventListResponse]{
+			Body: apiv0.AuditEventListResponse{
+				Events: eventValues,
+				Metadata: apiv0.Metadata{
+					NextCursor: nextCursor,
+					Count:      len(events),
+				},
+			},
+		}, nil
+	})
+}
+
+// buildAuditEventFilter converts the query parameters into a database filter
+func buildAuditEventFilter(input *ListAuditEventsInput) (*database.AuditEventFilter, error) {
+	filter := &database.AuditEventFilter{}
+
+	if input.ServerName != "" {
+		filter.ServerName = &input.ServerName
+	}
+	if input.Actor != "" {
+		filter.ActorSubject = &input.Actor
+	}
+	if input.Action != "" {
+		filter.Action = &input.Action
+	}
+	if input.Since != "" {
+		since, err := time.Parse(time.RFC3339, input.Since)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid since format: expected RFC3339 timestamp (e.g., 2025-08-07T13:15:04.280Z)")
+		}
+		filter.Since = &since
+	}
+	if input.Until != "" {
+		until, err := time.Parse(time.RFC3339, input.Until)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid until format: expected RFC3339 timestamp (e.g., 2025-08-07T13:15:04.280Z)")
+		}
+		filter.Until = &until
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,140 @@
+package v0_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestListAuditEventsEndpoint(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+
+	publisher := service.Actor{AuthMethod: auth.MethodGitHubAT, Subject: "testuser"}
+	for _, name := range []string{"io.github.testuser/audit-one", "io.github.testuser/audit-two"} {
+		_, err := registryService.CreateServer(service.WithActor(context.Background(), publisher), &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Audited server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	jwtManager := auth.NewJWTManager(cfg)
+	generateToken := func(permissions []auth.Permission) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodOIDC,
+			AuthMethodSubject: "admin@modelcontextprotocol.io",
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+	adminToken := generateToken([]auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}})
+	publisherToken := generateToken([]auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "io.github.testuser/*"}})
+
+	testCases := []struct {
+		name           string
+		query          string
+		authHeader     string
+		expectedStatus int
+		expectedError  string
+		expectedCount  int
+	}{
+		{
+			name:           "admin lists all events",
+			authHeader:     adminToken,
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "admin filters by server name",
+			query:          "?server_name=io.github.testuser%2Faudit-two&actor=testuser&action=publish",
+			authHeader:     adminToken,
+			expectedStatus: http.StatusOK,
+			expectedCount:  1,
+		},
+		{
+			name:           "invalid since timestamp",
+			query:          "?since=yesterday",
+			authHeader:     adminToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid since format",
+		},
+		{
+			name:           "invalid cursor",
+			query:          "?cursor=abc",
+			authHeader:     adminToken,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "malformed audit cursor",
+		},
+		{
+			name:           "publisher without admin permissions",
+			authHeader:     publisherToken,
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "requires admin permissions",
+		},
+		{
+			name:           "invalid authorization header format",
+			authHeader:     "InvalidFormat token123",
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Invalid Authorization header format",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+			v0.RegisterAuditEndpoints(api, "/v0", registryService, cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/v0/admin/audit"+tc.query, nil)
+			req.Header.Set("Authorization", tc.authHeader)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedError)
+			}
+
+			if tc.expectedStatus == http.StatusOK {
+				var response apiv0.AuditEventListResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				assert.Len(t, response.Events, tc.expectedCount)
+				assert.Equal(t, tc.expectedCount, response.Metadata.Count)
+				for _, event := range response.Events {
+					assert.Equal(t, database.AuditActionPublish, event.Action)
+					assert.Equal(t, string(auth.MethodGitHubAT), event.ActorMethod)
+					assert.Equal(t, "testuser", event.ActorSubject)
+				}
+			}
+		})
+	}
+}
@@ -52,8 +52,9 @@ func (h *DNSAuthHandler) SetResolver(resolver DNSResolver) {
 }
 
 // RegisterDNSEndpoint registers the DNS authentication endpoint
-func RegisterDNSEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterDNSEndpoint(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	handler := NewDNSAuthHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// DNS authentication endpoint
 	huma.Register(api, huma.Operation{
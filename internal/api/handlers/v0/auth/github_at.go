@@ -44,8 +44,9 @@ func (h *GitHubHandler) SetBaseURL(url string) {
 }
 
 // RegisterGitHubATEndpoint registers the GitHub access token authentication endpoint with a custom path prefix
-func RegisterGitHubATEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterGitHubATEndpoint(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	handler := NewGitHubHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// GitHub token exchange endpoint
 	huma.Register(api, huma.Operation{
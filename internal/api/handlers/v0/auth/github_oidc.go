@@ -227,8 +227,9 @@ func (h *GitHubOIDCHandler) SetValidator(validator OIDCValidator) {
 }
 
 // RegisterGitHubOIDCEndpoint registers the GitHub OIDC authentication endpoint
-func RegisterGitHubOIDCEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterGitHubOIDCEndpoint(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	handler := NewGitHubOIDCHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// GitHub OIDC token exchange endpoint
 	huma.Register(api, huma.Operation{
@@ -108,8 +108,9 @@ func (h *HTTPAuthHandler) SetFetcher(fetcher HTTPKeyFetcher) {
 }
 
 // RegisterHTTPEndpoint registers the HTTP authentication endpoint
-func RegisterHTTPEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterHTTPEndpoint(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	handler := NewHTTPAuthHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// HTTP authentication endpoint
 	huma.Register(api, huma.Operation{
@@ -2,26 +2,28 @@ package auth
 
 import (
 	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
 )
 
-// RegisterAuthEndpoints registers all authentication endpoints with a custom path prefix
-func RegisterAuthEndpoints(api huma.API, pathPrefix string, cfg *config.Config) {
+// RegisterAuthEndpoints registers all authentication endpoints with a custom path prefix.
+// Tokens are not issued for namespaces on the blocklist.
+func RegisterAuthEndpoints(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	// Register GitHub access token authentication endpoint
-	RegisterGitHubATEndpoint(api, pathPrefix, cfg)
+	RegisterGitHubATEndpoint(api, pathPrefix, cfg, blocklist)
 
 	// Register GitHub OIDC authentication endpoint
-	RegisterGitHubOIDCEndpoint(api, pathPrefix, cfg)
+	RegisterGitHubOIDCEndpoint(api, pathPrefix, cfg, blocklist)
 
 	// Register configurable OIDC authentication endpoints
-	RegisterOIDCEndpoints(api, pathPrefix, cfg)
+	RegisterOIDCEndpoints(api, pathPrefix, cfg, blocklist)
 
 	// Register DNS-based authentication endpoint
-	RegisterDNSEndpoint(api, pathPrefix, cfg)
+	RegisterDNSEndpoint(api, pathPrefix, cfg, blocklist)
 
 	// Register HTTP-based authentication endpoint
-	RegisterHTTPEndpoint(api, pathPrefix, cfg)
+	RegisterHTTPEndpoint(api, pathPrefix, cfg, blocklist)
 
 	// Register anonymous authentication endpoint
-	RegisterNoneEndpoint(api, pathPrefix, cfg)
+	RegisterNoneEndpoint(api, pathPrefix, cfg, blocklist)
 }
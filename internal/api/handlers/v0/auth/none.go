@@ -29,12 +29,13 @@ func NewNoneHandler(cfg *config.Config) *NoneHandler {
 // RegisterNoneEndpoint registers the anonymous authentication endpoint
 // WARNING: This endpoint is intended for local development and automated tests only.
 // It should NOT be enabled in production environments as it bypasses normal authentication.
-func RegisterNoneEndpoint(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterNoneEndpoint(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	if !cfg.EnableAnonymousAuth {
 		return
 	}
 
 	handler := NewNoneHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// Anonymous token endpoint for development/testing only
 	huma.Register(api, huma.Operation{
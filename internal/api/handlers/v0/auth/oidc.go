@@ -145,12 +145,13 @@ func (h *OIDCHandler) SetValidator(validator GenericOIDCValidator) {
 }
 
 // RegisterOIDCEndpoints registers all OIDC authentication endpoints
-func RegisterOIDCEndpoints(api huma.API, pathPrefix string, cfg *config.Config) {
+func RegisterOIDCEndpoints(api huma.API, pathPrefix string, cfg *config.Config, blocklist auth.NamespaceBlocklist) {
 	if !cfg.OIDCEnabled {
 		return // Skip registration if OIDC is not enabled
 	}
 
 	handler := NewOIDCHandler(cfg)
+	handler.jwtManager.SetBlocklist(blocklist)
 
 	// Direct token exchange endpoint
 	huma.Register(api, huma.Operation{
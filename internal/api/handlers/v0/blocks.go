@@ -0,0 +1,127 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListNamespaceBlocksInput represents the input for listing blocked namespaces
+type ListNamespaceBlocksInput struct {
+	Authorization  string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	IncludeExpired bool   `query:"include_expired" doc:"Include blocks that have expired" default:"false"`
+}
+
+// BlockNamespaceInput represents the input for blocking a namespace
+type BlockNamespaceInput struct {
+	Authorization string                      `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	Namespace     string                      `path:"namespace" doc:"Namespace to block" example:"io.github.spammer"`
+	Body          apiv0.NamespaceBlockRequest `body:""`
+}
+
+// UnblockNamespaceInput represents the input for unblocking a namespace
+type UnblockNamespaceInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	Namespace     string `path:"namespace" doc:"Namespace to unblock" example:"io.github.spammer"`
+}
+
+// RegisterBlocksEndpoints registers the admin endpoints for managing blocked namespaces
+func RegisterBlocksEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// List blocks endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-namespace-blocks" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/admin/blocks",
+		Summary:     "List blocked namespaces",
+		Description: "Get the namespaces that cannot get publish tokens or publish servers (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListNamespaceBlocksInput) (*Response[apiv0.NamespaceBlockListResponse], error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		blocks, err := registry.ListNamespaceBlocks(ctx, input.IncludeExpired)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get namespace blocks", err)
+		}
+
+		// Convert []*NamespaceBlock to []NamespaceBlock
+		blockValues := make([]apiv0.NamespaceBlock, len(blocks))
+		for i, block := range blocks {
+			blockValues[i] = *block
+		}
+
+		return &Response[apiv0.NamespaceBlockListResponse]{
+			Body: apiv0.NamespaceBlockListResponse{Blocks: blockValues},
+		}, nil
+	})
+
+	// Block endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "block-namespace" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPut,
+		Path:        pathPrefix + "/admin/blocks/{namespace}",
+		Summary:     "Block a namespace",
+		Description: "Stop a namespace and its subdomains from getting publish tokens and publishing servers, including with tokens issued before the block (admin only). Blocking a namespace again replaces its reason and expiry.",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *BlockNamespaceInput) (*Response[apiv0.NamespaceBlock], error) {
+		claims, err := authenticateAdmin(ctx, jwtManager, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		block, err := registry.BlockNamespace(service.WithActor(ctx, service.ActorFromClaims(claims)), input.Namespace, &input.Body)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid namespace block", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to block namespace", err)
+		}
+
+		return &Response[apiv0.NamespaceBlock]{
+			Body: *block,
+		}, nil
+	})
+
+	// Unblock endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "unblock-namespace" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodDelete,
+		Path:        pathPrefix + "/admin/blocks/{namespace}",
+		Summary:     "Unblock a namespace",
+		Description: "Lift the block of a namespace (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *UnblockNamespaceInput) (*struct{}, error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		if err := registry.UnblockNamespace(ctx, input.Namespace); err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Namespace is not blocked")
+			}
+			return nil, huma.Error500InternalServerError("Failed to unblock namespace", err)
+		}
+
+		return &struct{}{}, nil
+	})
+}
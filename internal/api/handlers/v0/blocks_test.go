@@ -0,0 +1,139 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	v0auth "github.com/modelcontextprotocol/registry/internal/api/handlers/v0/auth"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestBlocksEndpoints(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+		EnableAnonymousAuth:      true,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterBlocksEndpoints(api, "/v0", registryService, cfg)
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, cfg)
+	v0auth.RegisterNoneEndpoint(api, "/v0", cfg, registryService)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	generateToken := func(subject string, permissions []auth.Permission) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+	// Issued before the block, so only publish-time enforcement can stop it
+	publisherToken := generateToken("spammer", []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.spammer/*"}})
+	adminToken := generateToken("admin", []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}})
+
+	do := func(method, path, authHeader string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("only admins manage blocks", func(t *testing.T) {
+		rr := do(http.MethodGet, "/v0/admin/blocks", publisherToken, nil)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = do(http.MethodPut, "/v0/admin/blocks/io.github.spammer", publisherToken, apiv0.NamespaceBlockRequest{Reason: "Nope"})
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("invalid blocks are rejected", func(t *testing.T) {
+		rr := do(http.MethodPut, "/v0/admin/blocks/io.github.*", adminToken, apiv0.NamespaceBlockRequest{Reason: "Spam"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	})
+
+	rr := do(http.MethodPut, "/v0/admin/blocks/io.github.spammer", adminToken, apiv0.NamespaceBlockRequest{Reason: "Publishing spam servers"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var block apiv0.NamespaceBlock
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&block))
+	assert.Equal(t, "io.github.spammer", block.Namespace)
+	assert.Equal(t, "github-at:admin", block.CreatedBy)
+
+	rr = do(http.MethodPut, "/v0/admin/blocks/io.modelcontextprotocol.anonymous", adminToken, apiv0.NamespaceBlockRequest{Reason: "Anonymous publishing is paused"})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+	t.Run("blocks are listed", func(t *testing.T) {
+		rr := do(http.MethodGet, "/v0/admin/blocks", adminToken, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response apiv0.NamespaceBlockListResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		require.Len(t, response.Blocks, 2)
+		assert.Equal(t, "io.github.spammer", response.Blocks[0].Namespace)
+	})
+
+	t.Run("existing tokens cannot publish to blocked namespaces", func(t *testing.T) {
+		rr := do(http.MethodPost, "/v0/publish", publisherToken, apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.spammer/weather",
+			Description: "Spam",
+			Version:     "1.0.0",
+		})
+		assert.Equal(t, http.StatusForbidden, rr.Code, rr.Body.String())
+		assert.Contains(t, rr.Body.String(), "Publishing spam servers")
+	})
+
+	t.Run("tokens are not issued for blocked namespaces", func(t *testing.T) {
+		rr := do(http.MethodPost, "/v0/auth/none", "", nil)
+		assert.NotEqual(t, http.StatusOK, rr.Code)
+		assert.Contains(t, rr.Body.String(), "your namespace is blocked")
+	})
+
+	t.Run("unblocking", func(t *testing.T) {
+		rr := do(http.MethodDelete, "/v0/admin/blocks/io.modelcontextprotocol.anonymous", adminToken, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+		rr = do(http.MethodDelete, "/v0/admin/blocks/io.modelcontextprotocol.anonymous", adminToken, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = do(http.MethodPost, "/v0/auth/none", "", nil)
+		assert.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	})
+}
@@ -0,0 +1,52 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListChangesInput represents the input for reading the change feed
+type ListChangesInput struct {
+	After int64 `query:"after" doc:"Return changes with a sequence number greater than this. Use 0 to read from the beginning, then the nextAfter value of the previous page." default:"0" minimum:"0" example:"1200"`
+	Limit int   `query:"limit" doc:"Number of changes per page" default:"100" minimum:"1" maximum:"1000" example:"500"`
+}
+
+// RegisterChangesEndpoint registers the change feed endpoint with a custom path prefix
+func RegisterChangesEndpoint(api huma.API, pathPrefix string, registry service.RegistryService) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-changes" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/changes",
+		Summary:     "List registry changes",
+		Description: "Get publishes, edits and status changes in commit order, for exact incremental synchronization.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ListChangesInput) (*Response[apiv0.ChangeListResponse], error) {
+		changes, err := registry.ListChanges(ctx, input.After, input.Limit)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get changes", err)
+		}
+
+		// Convert []*ChangeEvent to []ChangeEvent, tracking the resume position
+		nextAfter := input.After
+		changeValues := make([]apiv0.ChangeEvent, len(changes))
+		for i, change := range changes {
+			changeValues[i] = *change
+			nextAfter = change.Sequence
+		}
+
+		return &Response[apiv0.ChangeListResponse]{
+			Body: apiv0.ChangeListResponse{
+				Changes: changeValues,
+				Metadata: apiv0.ChangeFeedMetadata{
+					NextAfter: nextAfter,
+					Count:     len(changes),
+				},
+			},
+		}, nil
+	})
+}
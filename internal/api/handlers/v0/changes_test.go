@@ -0,0 +1,68 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestListChangesEndpoint(t *testing.T) {
+	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		_, err := registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "com.example/feed-server",
+			Description: "Server in the change feed",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterChangesEndpoint(api, "/v0", registryService)
+
+	listChanges := func(query string) apiv0.ChangeListResponse {
+		req := httptest.NewRequest(http.MethodGet, "/v0/changes"+query, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		var response apiv0.ChangeListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return response
+	}
+
+	// Publishing 2.0.0 records the publish of 1.0.0, 1.0.0 losing latest, and the publish of 2.0.0
+	first := listChanges("?limit=2")
+	require.Len(t, first.Changes, 2)
+	assert.Equal(t, "publish", first.Changes[0].Type)
+	assert.Equal(t, "latest_change", first.Changes[1].Type)
+	assert.Equal(t, first.Changes[1].Sequence, first.Metadata.NextAfter, 10)
+
+	second := listChanges("?limit=2&after=" + strconv.FormatInt(first.Metadata.NextAfter, 10))
+	require.Len(t, second.Changes, 1)
+	assert.Equal(t, "2.0.0", second.Changes[0].Version)
+	assert.True(t, second.Changes[0].Server.Meta.Official.IsLatest)
+
+	// Reading past the end keeps the resume position
+	caughtUp := listChanges("?after=" + strconv.FormatInt(second.Metadata.NextAfter, 10))
+	assert.Empty(t, caughtUp.Changes)
+	assert.Equal(t, second.Metadata.NextAfter, caughtUp.Metadata.NextAfter, 10)
+}
@@ -109,9 +109,8 @@ func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.Reg
 				return nil, huma.Error400BadRequest("Cannot change status of deleted server. Deleted servers cannot be undeleted.")
 			}
 
-			// For now, only allow status changes for admins
-			// Future: Implement logic to allow server authors to change active <-> deprecated
-			// but only admins can set to deleted
+			// Any status can be set here by admins; publishers deprecate their own versions through
+			// the status endpoint, and only admins can delete
 		}
 
 		// Update the server using the service
@@ -119,11 +118,14 @@ func RegisterEditEndpoints(api huma.API, pathPrefix string, registry service.Reg
 		if input.Status != "" {
 			statusPtr = &input.Status
 		}
-		updatedServer, err := registry.UpdateServer(ctx, serverName, version, &input.Body, statusPtr)
+		updatedServer, err := registry.UpdateServer(service.WithActor(ctx, service.ActorFromClaims(claims)), serverName, version, &input.Body, statusPtr)
 		if err != nil {
 			if errors.Is(err, database.ErrNotFound) {
 				return nil, huma.Error404NotFound("Server not found")
 			}
+			if errors.Is(err, database.ErrNamespaceBlocked) {
+				return nil, huma.Error403Forbidden("Failed to edit server", err)
+			}
 			return nil, huma.Error400BadRequest("Failed to edit server", err)
 		}
 
@@ -27,6 +27,14 @@ import (
 )
 
 func TestEditServerEndpoint(t *testing.T) {
+	testEditServerEndpoint(t, database.NewTestDB)
+}
+
+func TestEditServerEndpointWithMemoryDatabase(t *testing.T) {
+	testEditServerEndpoint(t, newMemoryDB)
+}
+
+func testEditServerEndpoint(t *testing.T, newDB func(testing.TB) database.Database) {
 	// Create test config
 	testSeed := make([]byte, ed25519.SeedSize)
 	_, err := rand.Read(testSeed)
@@ -37,7 +45,7 @@ func TestEditServerEndpoint(t *testing.T) {
 	}
 
 	// Create registry service and test data
-	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+	registryService := service.NewRegistryService(newDB(t), cfg)
 
 	// Create test servers for different scenarios
 	testServers := map[string]*apiv0.ServerJSON{
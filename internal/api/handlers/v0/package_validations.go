@@ -0,0 +1,61 @@
+package v0
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListPackageValidationsInput represents the input for listing package validations
+type ListPackageValidationsInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	ServerName    string `query:"server_name" doc:"Server to get the package validations of" required:"false" example:"io.github.user/weather"`
+	Failing       bool   `query:"failing" doc:"Only include packages whose latest check failed" default:"true"`
+}
+
+// RegisterPackageValidationsEndpoint registers the admin endpoint for package ownership revalidation results
+func RegisterPackageValidationsEndpoint(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-package-validations" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/admin/package-validations",
+		Summary:     "List package validations",
+		Description: "Get the outcome of periodically re-checking that the packages of latest active versions exist in their registries and still belong to their server, by default only for packages that failed (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListPackageValidationsInput) (*Response[apiv0.PackageValidationListResponse], error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		var serverName *string
+		if input.ServerName != "" {
+			serverName = &input.ServerName
+		}
+
+		validations, err := registry.ListPackageValidations(ctx, serverName, input.Failing)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get package validations", err)
+		}
+
+		// Convert []*PackageValidation to []PackageValidation
+		validationValues := make([]apiv0.PackageValidation, len(validations))
+		for i, validation := range validations {
+			validationValues[i] = *validation
+		}
+
+		return &Response[apiv0.PackageValidationListResponse]{
+			Body: apiv0.PackageValidationListResponse{Validations: validationValues},
+		}, nil
+	})
+}
@@ -0,0 +1,109 @@
+package v0_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestPackageValidationsEndpoint(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	db := database.NewTestDB(t)
+	registryService := service.NewRegistryService(db, cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPackageValidationsEndpoint(api, "/v0", registryService, cfg)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	generateToken := func(subject string, permissions []auth.Permission) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+	publisherToken := generateToken("example", []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "com.example/*"}})
+	adminToken := generateToken("admin", []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}})
+
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/weather",
+		Description: "Weather server",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	for identifier, passed := range map[string]bool{"@example/weather": false, "@example/weather-cli": true} {
+		require.NoError(t, db.RecordPackageValidation(context.Background(), nil, &apiv0.PackageValidation{
+			ServerName:   "com.example/weather",
+			Version:      "1.0.0",
+			RegistryType: "npm",
+			Identifier:   identifier,
+			Passed:       passed,
+			CheckedAt:    time.Now(),
+		}))
+	}
+
+	list := func(path, token string) (int, []apiv0.PackageValidation) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", token)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		var response apiv0.PackageValidationListResponse
+		if rr.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		}
+		return rr.Code, response.Validations
+	}
+
+	t.Run("non-admins cannot list validations", func(t *testing.T) {
+		code, _ := list("/v0/admin/package-validations", publisherToken)
+		assert.Equal(t, http.StatusForbidden, code)
+	})
+
+	t.Run("failing packages are listed by default", func(t *testing.T) {
+		code, validations := list("/v0/admin/package-validations", adminToken)
+		require.Equal(t, http.StatusOK, code)
+		require.Len(t, validations, 1)
+		assert.Equal(t, "@example/weather", validations[0].Identifier)
+		assert.Equal(t, 1, validations[0].ConsecutiveFailures)
+	})
+
+	t.Run("all packages of a server are listed on request", func(t *testing.T) {
+		code, validations := list("/v0/admin/package-validations?failing=false&server_name=com.example%2Fweather", adminToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.Len(t, validations, 2)
+
+		code, validations = list("/v0/admin/package-validations?failing=false&server_name=com.example%2Fother", adminToken)
+		require.Equal(t, http.StatusOK, code)
+		assert.Empty(t, validations)
+	})
+}
@@ -0,0 +1,58 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+)
+
+// PackageServersInput represents the input for finding the servers that ship a package
+type PackageServersInput struct {
+	RegistryType string `path:"registryType" doc:"Package registry type" enum:"npm,pypi,oci,nuget,mcpb" example:"npm"`
+	Identifier   string `path:"identifier" doc:"URL-encoded package identifier. For OCI this is the full image reference including the tag." example:"%40modelcontextprotocol%2Fserver-brave-search"`
+	Version      string `query:"version" doc:"Only match this package version" required:"false" example:"1.0.2"`
+	Cursor       string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
+	Limit        int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
+}
+
+// RegisterPackagesEndpoint registers the package reverse lookup endpoint with a custom path prefix
+func RegisterPackagesEndpoint(api huma.API, pathPrefix string, registry service.RegistryService) {
+	huma.Register(api, huma.Operation{
+		OperationID: "list-package-servers" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/packages/{registryType}/{identifier}",
+		Summary:     "Find servers by package",
+		Description: "Get every server version, in any status, whose packages include the given package identifier",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *PackageServersInput) (*Response[serverListBody], error) {
+		// URL-decode the identifier, which often contains slashes (npm scopes, OCI image paths)
+		identifier, err := url.PathUnescape(input.Identifier)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid package identifier encoding", err)
+		}
+
+		filter := &database.ServerFilter{
+			RegistryType: &input.RegistryType,
+			PackageID:    &identifier,
+		}
+		if input.Version != "" {
+			filter.PackageVersion = &input.Version
+		}
+
+		servers, nextCursor, err := registry.ListServersRaw(ctx, filter, input.Cursor, input.Limit)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid package lookup", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to find servers for package", err)
+		}
+
+		return &Response[serverListBody]{Body: newServerListBody(servers, nextCursor)}, nil
+	})
+}
@@ -0,0 +1,106 @@
+package v0_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestListPackageServersEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestDB(t), &config.Config{EnableRegistryValidation: false})
+
+	// Two versions of one server ship different versions of the same npm package
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.example/npm-server",
+			Description: "Server shipped as an npm package",
+			Version:     version,
+			Packages: []model.Package{{
+				RegistryType: model.RegistryTypeNPM,
+				Identifier:   "@example/mcp-server",
+				Version:      version,
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			}},
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPackagesEndpoint(api, "/v0", registryService)
+
+	tests := []struct {
+		name             string
+		path             string
+		expectedStatus   int
+		expectedVersions []string
+		expectedError    string
+	}{
+		{
+			name:             "all versions shipping the package",
+			path:             "/v0/packages/npm/%40example%2Fmcp-server",
+			expectedStatus:   http.StatusOK,
+			expectedVersions: []string{"1.0.0", "1.1.0"},
+		},
+		{
+			name:             "filter by package version",
+			path:             "/v0/packages/npm/%40example%2Fmcp-server?version=1.1.0",
+			expectedStatus:   http.StatusOK,
+			expectedVersions: []string{"1.1.0"},
+		},
+		{
+			name:             "same identifier in another registry",
+			path:             "/v0/packages/pypi/%40example%2Fmcp-server",
+			expectedStatus:   http.StatusOK,
+			expectedVersions: []string{},
+		},
+		{
+			name:           "unknown registry type",
+			path:           "/v0/packages/maven/%40example%2Fmcp-server",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "validation failed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+
+			if tt.expectedStatus == http.StatusOK {
+				var resp apiv0.ServerListResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+
+				versions := make([]string, len(resp.Servers))
+				for i, server := range resp.Servers {
+					assert.Equal(t, "io.github.example/npm-server", server.Server.Name)
+					versions[i] = server.Server.Version
+				}
+				assert.Equal(t, tt.expectedVersions, versions)
+				assert.Equal(t, len(tt.expectedVersions), resp.Metadata.Count)
+			}
+		})
+	}
+}
@@ -2,12 +2,16 @@ package v0
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"reflect"
 	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/modelcontextprotocol/registry/internal/auth"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/validators"
 	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
@@ -16,6 +20,7 @@ import (
 // PublishServerInput represents the input for publishing a server
 type PublishServerInput struct {
 	Authorization string           `header:"Authorization" doc:"Registry JWT token (obtained from /v0/auth/token/github)" required:"true"`
+	DryRun        bool             `query:"dryRun" doc:"Run every publish check, including registry ownership, duplicate remote URLs and version limits, then roll back and return a report instead of publishing"`
 	Body          apiv0.ServerJSON `body:""`
 }
 
@@ -24,17 +29,30 @@ func RegisterPublishEndpoint(api huma.API, pathPrefix string, registry service.R
 	// Create JWT manager for token validation
 	jwtManager := auth.NewJWTManager(cfg)
 
+	// The response is the published server, or the dry-run report when dryRun is set
+	schemas := api.OpenAPI().Components.Schemas
+	responseSchema := &huma.Schema{OneOf: []*huma.Schema{
+		schemas.Schema(reflect.TypeOf(apiv0.ServerResponse{}), true, ""),
+		schemas.Schema(reflect.TypeOf(apiv0.PublishDryRunResponse{}), true, ""),
+	}}
+
 	huma.Register(api, huma.Operation{
 		OperationID: "publish-server" + strings.ReplaceAll(pathPrefix, "/", "-"),
 		Method:      http.MethodPost,
 		Path:        pathPrefix + "/publish",
 		Summary:     "Publish MCP server",
-		Description: "Publish a new MCP server to the registry or update an existing one",
+		Description: "Publish a new MCP server to the registry or update an existing one. With dryRun=true, nothing is published: every check runs and the response reports which passed and whether the version would become latest.",
 		Tags:        []string{"publish"},
 		Security: []map[string][]string{
 			{"bearer": {}},
 		},
-	}, func(ctx context.Context, input *PublishServerInput) (*Response[apiv0.ServerResponse], error) {
+		Responses: map[string]*huma.Response{
+			"200": {
+				Description: "Published server, or the dry-run report",
+				Content:     map[string]*huma.MediaType{"application/json": {Schema: responseSchema}},
+			},
+		},
+	}, func(ctx context.Context, input *PublishServerInput) (*Response[any], error) {
 		// Extract bearer token
 		const bearerPrefix = "Bearer "
 		authHeader := input.Authorization
@@ -49,6 +67,10 @@ func RegisterPublishEndpoint(api huma.API, pathPrefix string, registry service.R
 			return nil, huma.Error401Unauthorized("Invalid or expired Registry JWT token", err)
 		}
 
+		if input.DryRun {
+			return dryRunPublish(ctx, jwtManager, claims, &input.Body, registry)
+		}
+
 		// Verify that the token has permission to publish the server
 		if !jwtManager.HasPermission(input.Body.Name, auth.PermissionActionPublish, claims.Permissions) {
 			return nil, huma.Error403Forbidden(buildPermissionErrorMessage(input.Body.Name, claims.Permissions))
@@ -60,19 +82,54 @@ func RegisterPublishEndpoint(api huma.API, pathPrefix string, registry service.R
 			return nil, huma.Error422UnprocessableEntity("Failed to publish server, invalid schema: call /validate for details")
 		}
 
-		// Publish the server with extensions
-		publishedServer, err := registry.CreateServer(ctx, &input.Body)
+		// Publish the server with extensions, attributing the change to the token holder in the audit log
+		publishedServer, err := registry.CreateServer(service.WithActor(ctx, service.ActorFromClaims(claims)), &input.Body)
 		if err != nil {
+			if errors.Is(err, database.ErrNamespaceBlocked) {
+				return nil, huma.Error403Forbidden("Failed to publish server", err)
+			}
 			return nil, huma.Error400BadRequest("Failed to publish server", err)
 		}
 
 		// Return the published server response with metadata
-		return &Response[apiv0.ServerResponse]{
+		return &Response[any]{
 			Body: *publishedServer,
 		}, nil
 	})
 }
 
+// dryRunPublish runs the checks of a publish without publishing. Failed checks are reported with
+// 200 OK, like /validate, so that callers can tell them apart from errors.
+func dryRunPublish(ctx context.Context, jwtManager *auth.JWTManager, claims *auth.JWTClaims, server *apiv0.ServerJSON, registry service.RegistryService) (*Response[any], error) {
+	report := apiv0.PublishDryRunResponse{ServerName: server.Name, Version: server.Version}
+
+	if !jwtManager.HasPermission(server.Name, auth.PermissionActionPublish, claims.Permissions) {
+		report.Checks = append(report.Checks, apiv0.PublishCheck{Name: "permission", Message: buildPermissionErrorMessage(server.Name, claims.Permissions)})
+		return &Response[any]{Body: report}, nil
+	}
+	report.Checks = append(report.Checks, apiv0.PublishCheck{Name: "permission", Passed: true, Message: "The token can publish " + server.Name})
+
+	validationResult := validators.ValidateServerJSON(server, validators.ValidationSchemaVersionAndSemantic)
+	if !validationResult.Valid {
+		messages := make([]string, 0, len(validationResult.Issues))
+		for _, issue := range validationResult.Issues {
+			if issue.Severity == validators.ValidationIssueSeverityError {
+				messages = append(messages, fmt.Sprintf("%s: %s", issue.Path, issue.Message))
+			}
+		}
+		report.Checks = append(report.Checks, apiv0.PublishCheck{Name: "schema", Message: strings.Join(messages, "; ")})
+		return &Response[any]{Body: report}, nil
+	}
+	report.Checks = append(report.Checks, apiv0.PublishCheck{Name: "schema", Passed: true, Message: "server.json is valid"})
+
+	result, err := registry.DryRunCreateServer(service.WithActor(ctx, service.ActorFromClaims(claims)), server)
+	if err != nil {
+		return nil, huma.Error500InternalServerError("Failed to run publish checks", err)
+	}
+	result.Checks = append(report.Checks, result.Checks...)
+	return &Response[any]{Body: *result}, nil
+}
+
 // buildPermissionErrorMessage creates a detailed error message showing what permissions
 // the user has and what they're trying to publish
 func buildPermissionErrorMessage(attemptedResource string, permissions []auth.Permission) string {
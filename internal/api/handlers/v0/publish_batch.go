@@ -0,0 +1,112 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// BatchPublishInput represents the input for publishing several servers at once
+type BatchPublishInput struct {
+	Authorization string             `header:"Authorization" doc:"Registry JWT token with publish permission for every server in the batch" required:"true"`
+	Body          []apiv0.ServerJSON `body:""`
+}
+
+// BatchPublishOutput carries the per-server results, which are returned with failures too
+type BatchPublishOutput struct {
+	Status int
+	Body   apiv0.BatchPublishResponse
+}
+
+// RegisterBatchPublishEndpoint registers the endpoint publishing several servers in one transaction
+func RegisterBatchPublishEndpoint(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "publish-servers-batch" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        pathPrefix + "/publish/batch",
+		Summary:     "Publish several MCP servers atomically",
+		Description: fmt.Sprintf("Publish up to %d servers in a single transaction: either every server is published or none is. Permissions and server.json are checked for every server before anything is written. The response lists the outcome of each server, also when the batch fails (403 for missing permissions or blocked namespaces, 422 for invalid server.json, 400 when publishing a server failed).", service.MaxBatchPublishSize),
+		Tags:        []string{"publish"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *BatchPublishInput) (*BatchPublishOutput, error) {
+		claims, err := authenticate(ctx, jwtManager, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(input.Body) == 0 || len(input.Body) > service.MaxBatchPublishSize {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("A batch must contain between 1 and %d servers", service.MaxBatchPublishSize))
+		}
+
+		servers := make([]*apiv0.ServerJSON, len(input.Body))
+		for i := range input.Body {
+			servers[i] = &input.Body[i]
+		}
+
+		names := make([]string, len(servers))
+		for i, server := range servers {
+			names[i] = server.Name
+		}
+		blockErrs, err := registry.CheckNamespacesNotBlocked(ctx, names)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to check blocked namespaces", err)
+		}
+
+		// Check every server before writing anything, so that one bad entry does not cost a transaction
+		response := service.NewBatchPublishResponse(servers)
+		status := http.StatusOK
+		for i, server := range servers {
+			result := &response.Results[i]
+			if !jwtManager.HasPermission(server.Name, auth.PermissionActionPublish, claims.Permissions) {
+				result.Status = service.BatchStatusFailed
+				result.Error = buildPermissionErrorMessage(server.Name, claims.Permissions)
+				status = http.StatusForbidden
+				continue
+			}
+			if blockErrs[i] != nil {
+				result.Status = service.BatchStatusFailed
+				result.Error = blockErrs[i].Error()
+				status = http.StatusForbidden
+				continue
+			}
+			if !validators.ValidateServerJSON(server, validators.ValidationSchemaVersionAndSemantic).Valid {
+				result.Status = service.BatchStatusFailed
+				result.Error = "invalid schema: call /validate for details"
+				if status == http.StatusOK {
+					status = http.StatusUnprocessableEntity
+				}
+			}
+		}
+		if status != http.StatusOK {
+			return &BatchPublishOutput{Status: status, Body: *response}, nil
+		}
+
+		// Publish the servers, attributing them to the token holder in the audit log
+		published, err := registry.CreateServers(service.WithActor(ctx, service.ActorFromClaims(claims)), servers)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Failed to publish servers", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to publish servers", err)
+		}
+		if !published.Published {
+			status = http.StatusBadRequest
+		}
+
+		return &BatchPublishOutput{Status: status, Body: *published}, nil
+	})
+}
@@ -0,0 +1,170 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestBatchPublishEndpoint(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "io.github.example/existing",
+		Description: "Server published before the batch",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	_, err = registryService.BlockNamespace(context.Background(), "io.github.example.spam", &apiv0.NamespaceBlockRequest{Reason: "Spam"})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterBatchPublishEndpoint(api, "/v0", registryService, cfg)
+
+	publisher := auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "example",
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.example/*"},
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.example.spam/*"},
+		},
+	}
+
+	newServer := func(name, version string) apiv0.ServerJSON {
+		return apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Server published in a batch",
+			Version:     version,
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		servers          []apiv0.ServerJSON
+		authHeader       string
+		expectedStatus   int
+		expectedStatuses []string
+		expectedError    string
+	}{
+		{
+			name:             "missing permission for one server fails the batch",
+			servers:          []apiv0.ServerJSON{newServer("io.github.example/alpha", "1.0.0"), newServer("io.github.other/beta", "1.0.0")},
+			expectedStatus:   http.StatusForbidden,
+			expectedStatuses: []string{"not_published", "failed"},
+			expectedError:    "You do not have permission to publish this server",
+		},
+		{
+			name:             "invalid server.json fails the batch",
+			servers:          []apiv0.ServerJSON{newServer("io.github.example/alpha", "latest"), newServer("io.github.example/beta", "1.0.0")},
+			expectedStatus:   http.StatusUnprocessableEntity,
+			expectedStatuses: []string{"failed", "not_published"},
+			expectedError:    "invalid schema",
+		},
+		{
+			name:             "blocked namespace fails the batch",
+			servers:          []apiv0.ServerJSON{newServer("io.github.example/alpha", "1.0.0"), newServer("io.github.example.spam/beta", "1.0.0")},
+			expectedStatus:   http.StatusForbidden,
+			expectedStatuses: []string{"not_published", "failed"},
+			expectedError:    "io.github.example.spam is blocked",
+		},
+		{
+			name:             "publish failure rolls back the batch",
+			servers:          []apiv0.ServerJSON{newServer("io.github.example/alpha", "1.0.0"), newServer("io.github.example/existing", "1.0.0")},
+			expectedStatus:   http.StatusBadRequest,
+			expectedStatuses: []string{"not_published", "failed"},
+			expectedError:    "cannot publish duplicate version",
+		},
+		{
+			name:             "all servers are published",
+			servers:          []apiv0.ServerJSON{newServer("io.github.example/alpha", "1.0.0"), newServer("io.github.example/existing", "1.1.0")},
+			expectedStatus:   http.StatusOK,
+			expectedStatuses: []string{"published", "published"},
+		},
+		{
+			name:           "empty batch",
+			servers:        []apiv0.ServerJSON{},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid authorization header",
+			servers:        []apiv0.ServerJSON{newServer("io.github.example/gamma", "1.0.0")},
+			authHeader:     "InvalidFormat",
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requestBody, err := json.Marshal(tc.servers)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v0/publish/batch", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			} else {
+				token, err := generateTestJWTToken(cfg, publisher)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+token)
+			}
+
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.expectedStatus, rr.Code, rr.Body.String())
+			if tc.expectedStatuses == nil {
+				return
+			}
+
+			var response apiv0.BatchPublishResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+			assert.Equal(t, tc.expectedStatus == http.StatusOK, response.Published)
+			statuses := make([]string, 0, len(response.Results))
+			for _, result := range response.Results {
+				statuses = append(statuses, result.Status)
+				if tc.expectedError != "" && result.Status == "failed" {
+					assert.Contains(t, result.Error, tc.expectedError)
+				}
+			}
+			assert.Equal(t, tc.expectedStatuses, statuses)
+		})
+	}
+
+	t.Run("only the successful batch was published", func(t *testing.T) {
+		versions, err := registryService.GetAllVersionsByServerName(context.Background(), "io.github.example/existing")
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+
+		_, err = registryService.GetServerByName(context.Background(), "io.github.other/beta")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
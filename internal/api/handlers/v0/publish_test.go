@@ -35,7 +35,20 @@ func generateTestJWTToken(cfg *config.Config, claims auth.JWTClaims) (string, er
 	return tokenResponse.RegistryToken, nil
 }
 
+// newMemoryDB creates an in-memory database for tests that also run against PostgreSQL
+func newMemoryDB(_ testing.TB) database.Database {
+	return database.NewMemory()
+}
+
 func TestPublishEndpoint(t *testing.T) {
+	testPublishEndpoint(t, database.NewTestDB)
+}
+
+func TestPublishEndpointWithMemoryDatabase(t *testing.T) {
+	testPublishEndpoint(t, newMemoryDB)
+}
+
+func testPublishEndpoint(t *testing.T, newDB func(testing.TB) database.Database) {
 	testSeed := make([]byte, ed25519.SeedSize)
 	_, err := rand.Read(testSeed)
 	require.NoError(t, err)
@@ -389,7 +402,7 @@ func TestPublishEndpoint(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Create registry service
-			registryService := service.NewRegistryService(database.NewTestDB(t), testConfig)
+			registryService := service.NewRegistryService(newDB(t), testConfig)
 
 			// Setup registry service
 			tc.setupRegistryService(registryService)
@@ -440,6 +453,141 @@ func TestPublishEndpoint(t *testing.T) {
 	}
 }
 
+func TestPublishEndpoint_DryRun(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	testConfig := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), testConfig)
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "io.github.example/dry-run-server",
+		Description: "A server checked before publishing",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterPublishEndpoint(api, "/v0", registryService, testConfig)
+
+	publisher := auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "example",
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.example/*"},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		server          apiv0.ServerJSON
+		expectedPublish bool
+		expectedLatest  bool
+		expectedChecks  []string
+		expectedFailure string
+	}{
+		{
+			name: "new version would publish and become latest",
+			server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "io.github.example/dry-run-server",
+				Description: "A server checked before publishing",
+				Version:     "1.1.0",
+			},
+			expectedPublish: true,
+			expectedLatest:  true,
+			expectedChecks:  []string{"permission", "schema", "namespace_block", "publish_validation", "remote_urls", "version_limit", "duplicate_version", "publish"},
+		},
+		{
+			name: "duplicate version fails",
+			server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "io.github.example/dry-run-server",
+				Description: "A server checked before publishing",
+				Version:     "1.0.0",
+			},
+			expectedChecks:  []string{"permission", "schema", "namespace_block", "publish_validation", "remote_urls", "version_limit", "duplicate_version"},
+			expectedFailure: "cannot publish duplicate version",
+		},
+		{
+			name: "missing permission fails",
+			server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "io.github.other/dry-run-server",
+				Description: "A server checked before publishing",
+				Version:     "1.0.0",
+			},
+			expectedChecks:  []string{"permission"},
+			expectedFailure: "You do not have permission to publish this server",
+		},
+		{
+			name: "invalid server.json fails",
+			server: apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "io.github.example/dry-run-server",
+				Description: "A server checked before publishing",
+				Version:     "^1.0.0",
+			},
+			expectedChecks:  []string{"permission", "schema"},
+			expectedFailure: "version",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requestBody, err := json.Marshal(tc.server)
+			require.NoError(t, err)
+			token, err := generateTestJWTToken(testConfig, publisher)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/v0/publish?dryRun=true", bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var report apiv0.PublishDryRunResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+
+			assert.Equal(t, tc.expectedPublish, report.WouldPublish)
+			assert.Equal(t, tc.expectedLatest, report.WouldBecomeLatest)
+			names := make([]string, 0, len(report.Checks))
+			for _, check := range report.Checks {
+				names = append(names, check.Name)
+			}
+			assert.Equal(t, tc.expectedChecks, names)
+			if tc.expectedFailure != "" {
+				failed := report.Checks[len(report.Checks)-1]
+				assert.False(t, failed.Passed)
+				assert.Contains(t, failed.Message, tc.expectedFailure)
+			}
+		})
+	}
+
+	t.Run("dry runs publish nothing", func(t *testing.T) {
+		versions, err := registryService.GetAllVersionsByServerName(context.Background(), "io.github.example/dry-run-server")
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+
+	t.Run("invalid token is rejected", func(t *testing.T) {
+		requestBody, err := json.Marshal(testCases[0].server)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/v0/publish?dryRun=true", bytes.NewReader(requestBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer invalid")
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+}
+
 // TestPublishEndpoint_MultipleSlashesEdgeCases tests additional edge cases for multi-slash validation
 func TestPublishEndpoint_MultipleSlashesEdgeCases(t *testing.T) {
 	testSeed := make([]byte, ed25519.SeedSize)
@@ -0,0 +1,102 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListRemoteURLClaimsInput represents the input for listing remote URL claims
+type ListRemoteURLClaimsInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	URL           string `query:"url" doc:"Remote URL to get the claim of, in any form that canonicalizes to the claimed URL" required:"false" example:"https://api.example.com/mcp"`
+	ServerName    string `query:"server_name" doc:"Server to get the claims of" required:"false" example:"com.example/weather"`
+}
+
+// ReleaseRemoteURLClaimInput represents the input for releasing a remote URL claim
+type ReleaseRemoteURLClaimInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with admin permissions" required:"true"`
+	URL           string `query:"url" doc:"Remote URL to release, in any form that canonicalizes to the claimed URL" required:"true" example:"https://api.example.com/mcp"`
+}
+
+// RegisterRemoteURLClaimsEndpoints registers the admin endpoints for managing remote URL claims
+func RegisterRemoteURLClaimsEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// List claims endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-remote-url-claims" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/admin/remote-url-claims",
+		Summary:     "List remote URL claims",
+		Description: "Get which server each remote URL belongs to, optionally for a single URL or server (admin only).",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListRemoteURLClaimsInput) (*Response[apiv0.RemoteURLClaimListResponse], error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		var url, serverName *string
+		if input.URL != "" {
+			url = &input.URL
+		}
+		if input.ServerName != "" {
+			serverName = &input.ServerName
+		}
+
+		claims, err := registry.ListRemoteURLClaims(ctx, url, serverName)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get remote URL claims", err)
+		}
+
+		// Convert []*RemoteURLClaim to []RemoteURLClaim
+		claimValues := make([]apiv0.RemoteURLClaim, len(claims))
+		for i, claim := range claims {
+			claimValues[i] = *claim
+		}
+
+		return &Response[apiv0.RemoteURLClaimListResponse]{
+			Body: apiv0.RemoteURLClaimListResponse{Claims: claimValues},
+		}, nil
+	})
+
+	// Release claim endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "release-remote-url-claim" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodDelete,
+		Path:        pathPrefix + "/admin/remote-url-claims",
+		Summary:     "Release a remote URL claim",
+		Description: "Release the claim of a server on a remote URL, so that another server can publish a remote with it (admin only). The server takes the URL back if it publishes or edits a version using it while the URL is still unclaimed.",
+		Tags:        []string{"admin"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ReleaseRemoteURLClaimInput) (*struct{}, error) {
+		if _, err := authenticateAdmin(ctx, jwtManager, input.Authorization); err != nil {
+			return nil, err
+		}
+
+		if err := registry.ReleaseRemoteURLClaim(ctx, input.URL); err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid remote URL", err)
+			}
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Remote URL is not claimed")
+			}
+			return nil, huma.Error500InternalServerError("Failed to release remote URL claim", err)
+		}
+
+		return &struct{}{}, nil
+	})
+}
@@ -0,0 +1,110 @@
+package v0_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestRemoteURLClaimsEndpoints(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterRemoteURLClaimsEndpoints(api, "/v0", registryService, cfg)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	generateToken := func(subject string, permissions []auth.Permission) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+	publisherToken := generateToken("example", []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "com.example/*"}})
+	adminToken := generateToken("admin", []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}})
+
+	do := func(method, path, authHeader string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", authHeader)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/weather",
+		Description: "Remote weather server",
+		Version:     "1.0.0",
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://weather.example.com/mcp"},
+		},
+	})
+	require.NoError(t, err)
+
+	claimPath := "/v0/admin/remote-url-claims?url=" + url.QueryEscape("HTTPS://Weather.example.com/mcp/")
+
+	t.Run("non-admins cannot manage claims", func(t *testing.T) {
+		rr := do(http.MethodGet, "/v0/admin/remote-url-claims", publisherToken)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+
+		rr = do(http.MethodDelete, claimPath, publisherToken)
+		assert.Equal(t, http.StatusForbidden, rr.Code)
+	})
+
+	t.Run("admins list claims by URL or server", func(t *testing.T) {
+		for _, path := range []string{claimPath, "/v0/admin/remote-url-claims?server_name=com.example%2Fweather"} {
+			rr := do(http.MethodGet, path, adminToken)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var response apiv0.RemoteURLClaimListResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+			require.Len(t, response.Claims, 1)
+			assert.Equal(t, "https://weather.example.com/mcp", response.Claims[0].URL)
+			assert.Equal(t, "com.example/weather", response.Claims[0].ServerName)
+		}
+	})
+
+	t.Run("admins release claims", func(t *testing.T) {
+		rr := do(http.MethodDelete, claimPath, adminToken)
+		assert.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+		rr = do(http.MethodDelete, claimPath, adminToken)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = do(http.MethodGet, "/v0/admin/remote-url-claims", adminToken)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response apiv0.RemoteURLClaimListResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		assert.Empty(t, response.Claims)
+	})
+}
@@ -0,0 +1,43 @@
+package v0
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/danielgtaylor/huma/v2"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// serverListBody is the body of server list responses built from stored server documents. Each
+// document is written out as the database returned it rather than re-encoded from apiv0.ServerJSON, so
+// the values match apiv0.ServerListResponse but the keys of each server object come in the order the
+// database keeps them; PostgreSQL orders JSONB keys by length, not by struct field.
+type serverListBody struct {
+	Servers  []serverListEntry `json:"servers"`
+	Metadata apiv0.Metadata    `json:"metadata"`
+}
+
+type serverListEntry struct {
+	Server json.RawMessage    `json:"server"`
+	Meta   apiv0.ResponseMeta `json:"_meta"`
+}
+
+// Schema documents the body as an apiv0.ServerListResponse, which clients decode it into
+func (serverListBody) Schema(r huma.Registry) *huma.Schema {
+	return r.Schema(reflect.TypeOf(apiv0.ServerListResponse{}), true, "")
+}
+
+// newServerListBody builds a list body from responses returned by the Raw list methods of the registry service
+func newServerListBody(servers []*apiv0.ServerResponse, nextCursor string) serverListBody {
+	entries := make([]serverListEntry, len(servers))
+	for i, server := range servers {
+		entries[i] = serverListEntry{Server: server.RawServer, Meta: server.Meta}
+	}
+	return serverListBody{
+		Servers: entries,
+		Metadata: apiv0.Metadata{
+			NextCursor: nextCursor,
+			Count:      len(servers),
+		},
+	}
+}
@@ -0,0 +1,134 @@
+//nolint:testpackage // Compares the unexported list body with decoded servers
+package v0
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newListedServer returns a server using most of server.json, so that both list encodings have every
+// kind of field to get right
+func newListedServer(i int) *apiv0.ServerJSON {
+	return &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        fmt.Sprintf("io.github.list-%05d/weather", i),
+		Title:       "Weather",
+		Description: "Forecasts & <alerts>",
+		Version:     "1.0.0",
+		WebsiteURL:  fmt.Sprintf("https://list-%05d.example.com", i),
+		Repository:  &model.Repository{URL: fmt.Sprintf("https://github.com/list-%05d/weather", i), Source: "github"},
+		Packages: []model.Package{{
+			RegistryType: model.RegistryTypeNPM,
+			Identifier:   fmt.Sprintf("@list-%05d/weather", i),
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: model.TransportTypeStdio},
+			EnvironmentVariables: []model.KeyValueInput{
+				{Name: "WEATHER_API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "API key", IsRequired: true, IsSecret: true}}},
+			},
+		}},
+		Remotes: []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: fmt.Sprintf("https://list-%05d.example.com/mcp", i)}},
+	}
+}
+
+// newListTestRegistry returns a registry service holding count servers and a mux serving its list endpoints
+func newListTestRegistry(tb testing.TB, count int) (service.RegistryService, *http.ServeMux) {
+	tb.Helper()
+	registryService := service.NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+	for i := 0; i < count; i++ {
+		_, err := registryService.CreateServer(context.Background(), newListedServer(i))
+		require.NoError(tb, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	RegisterServersEndpoints(api, "/v0", registryService)
+	return registryService, mux
+}
+
+func TestListServersEndpointMatchesDecodedServers(t *testing.T) {
+	registryService, mux := newListTestRegistry(t, 3)
+
+	req := httptest.NewRequest(http.MethodGet, "/v0/servers?limit=2", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stored apiv0.ServerListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stored))
+
+	// The same page, built from decoded servers and round-tripped like a client would
+	servers, nextCursor, err := registryService.ListServers(context.Background(), nil, "", 2)
+	require.NoError(t, err)
+	typed := apiv0.ServerListResponse{Metadata: apiv0.Metadata{NextCursor: nextCursor, Count: len(servers)}}
+	for _, server := range servers {
+		typed.Servers = append(typed.Servers, *server)
+	}
+	data, err := json.Marshal(typed)
+	require.NoError(t, err)
+	var decoded apiv0.ServerListResponse
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	require.Len(t, stored.Servers, 2)
+	require.Len(t, decoded.Servers, 2)
+	for i := range decoded.Servers {
+		assert.Equal(t, decoded.Servers[i].Server, stored.Servers[i].Server, "server %d", i)
+		assert.Equal(t, decoded.Servers[i].Meta, stored.Servers[i].Meta, "_meta of server %d", i)
+	}
+	assert.Equal(t, decoded.Metadata.Count, stored.Metadata.Count)
+	assert.NotEmpty(t, stored.Metadata.NextCursor)
+}
+
+// BenchmarkServerListEncoding encodes a page of stored server documents as the list endpoint does,
+// writing them out as stored, against decoding each document and encoding it again
+func BenchmarkServerListEncoding(b *testing.B) {
+	const pageSize = 100
+	documents := make([][]byte, pageSize)
+	for i := range documents {
+		document, err := json.Marshal(newListedServer(i))
+		require.NoError(b, err)
+		documents[i] = document
+	}
+	meta := apiv0.ResponseMeta{Official: &apiv0.RegistryExtensions{Status: model.StatusActive, IsLatest: true}}
+
+	b.Run("stored documents", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			servers := make([]*apiv0.ServerResponse, len(documents))
+			for j, document := range documents {
+				servers[j] = apiv0.NewRawServerResponse("", "", document, meta)
+			}
+			if _, err := json.Marshal(newServerListBody(servers, "")); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("decoded servers", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			page := apiv0.ServerListResponse{Servers: make([]apiv0.ServerResponse, len(documents))}
+			for j, document := range documents {
+				if err := json.Unmarshal(document, &page.Servers[j].Server); err != nil {
+					b.Fatal(err)
+				}
+				page.Servers[j].Meta = meta
+			}
+			if _, err := json.Marshal(page); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
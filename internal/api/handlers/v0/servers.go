@@ -18,11 +18,22 @@ const errRecordNotFound = "record not found"
 
 // ListServersInput represents the input for listing servers
 type ListServersInput struct {
-	Cursor       string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
-	Limit        int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
-	UpdatedSince string `query:"updated_since" doc:"Filter servers updated since timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
-	Search       string `query:"search" doc:"Search servers by name (substring match)" required:"false" example:"filesystem"`
-	Version      string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	Cursor          string `query:"cursor" doc:"Pagination cursor" required:"false" example:"server-cursor-123"`
+	Limit           int    `query:"limit" doc:"Number of items per page" default:"30" minimum:"1" maximum:"100" example:"50"`
+	UpdatedSince    string `query:"updated_since" doc:"Filter servers updated since timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	Search          string `query:"search" doc:"Search servers by name (substring match)" required:"false" example:"filesystem"`
+	Query           string `query:"q" doc:"Full-text search across server name, title and description. Results are ordered by relevance." required:"false" example:"weather forecast"`
+	Version         string `query:"version" doc:"Filter by version ('latest' for latest version, or an exact version like '1.2.3')" required:"false" example:"latest"`
+	RegistryType    string `query:"registry_type" doc:"Filter to servers with a package from this registry" required:"false" enum:"npm,pypi,oci,nuget,mcpb" example:"npm"`
+	Transport       string `query:"transport" doc:"Filter to servers with a package or remote using this transport" required:"false" enum:"stdio,streamable-http,sse" example:"stdio"`
+	Status          string `query:"status" doc:"Filter by server lifecycle status" required:"false" enum:"active,deprecated,deleted" example:"active"`
+	HideDeprecated  bool   `query:"hide_deprecated" doc:"Exclude deprecated servers" required:"false" example:"true"`
+	Namespace       string `query:"namespace" doc:"Filter to servers whose name starts with this prefix" required:"false" example:"io.github.example"`
+	PublishedSince  string `query:"published_since" doc:"Filter servers published at or after timestamp (RFC3339 datetime)" required:"false" example:"2025-08-07T13:15:04.280Z"`
+	PublishedBefore string `query:"published_before" doc:"Filter servers published before timestamp (RFC3339 datetime)" required:"false" example:"2025-09-01T00:00:00Z"`
+	Capability      string `query:"capability" doc:"Filter to server versions with a recorded tool, prompt or resource of this exact name, or a resource of this URI" required:"false" maxLength:"2048" example:"create_issue"`
+	Sort            string `query:"sort" doc:"Sort key. Defaults to name, or to relevance when q is set." required:"false" enum:"name,published_at,updated_at" example:"published_at"`
+	Order           string `query:"order" doc:"Sort direction" required:"false" enum:"asc,desc" example:"desc"`
 }
 
 // ServerDetailInput represents the input for getting server details
@@ -36,6 +47,19 @@ type ServerVersionDetailInput struct {
 	Version    string `path:"version" doc:"URL-encoded server version" example:"1.0.0"`
 }
 
+// ResolveServerVersionInput represents the input for resolving a version range
+type ResolveServerVersionInput struct {
+	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Range      string `query:"range" doc:"Semver range in npm syntax: caret, tilde, x-ranges, hyphen ranges and comparator sets, optionally joined by ||" required:"true" maxLength:"256" example:"^1.2.0"`
+}
+
+// ServerDiffInput represents the input for comparing two versions of a server
+type ServerDiffInput struct {
+	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	From       string `query:"from" doc:"Version or dist-tag to compare from" required:"true" example:"1.0.0"`
+	To         string `query:"to" doc:"Version or dist-tag to compare to" required:"true" example:"latest"`
+}
+
 // ServerVersionsInput represents the input for listing all versions of a server
 type ServerVersionsInput struct {
 	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
@@ -51,7 +75,7 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		Summary:     "List MCP servers",
 		Description: "Get a paginated list of MCP servers from the registry",
 		Tags:        []string{"servers"},
-	}, func(ctx context.Context, input *ListServersInput) (*Response[apiv0.ServerListResponse], error) {
+	}, func(ctx context.Context, input *ListServersInput) (*Response[serverListBody], error) {
 		// Build filter from input parameters
 		filter := &database.ServerFilter{}
 
@@ -70,48 +94,86 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 			filter.SubstringName = &input.Search
 		}
 
+		// Handle full-text search parameter
+		if strings.TrimSpace(input.Query) != "" {
+			filter.Query = &input.Query
+		}
+
 		// Handle version parameter
 		if input.Version != "" {
-			if input.Version == "latest" {
-				// Special case: filter for latest versions
-				isLatest := true
-				filter.IsLatest = &isLatest
+			if input.Version == database.TagLatest {
+				// Special case: filter for the versions the latest tag points at
+				tag := database.TagLatest
+				filter.Tag = &tag
 			} else {
 				// Future: exact version matching
 				filter.Version = &input.Version
 			}
 		}
 
+		// Handle package and transport filters
+		if input.RegistryType != "" {
+			filter.RegistryType = &input.RegistryType
+		}
+		if input.Transport != "" {
+			filter.TransportType = &input.Transport
+		}
+
+		// Handle status filters
+		if input.Status != "" {
+			filter.Status = &input.Status
+		}
+		filter.HideDeprecated = input.HideDeprecated
+
+		// Handle namespace parameter
+		if input.Namespace != "" {
+			filter.NamespacePrefix = &input.Namespace
+		}
+
+		// Parse published_since and published_before parameters
+		if input.PublishedSince != "" {
+			publishedSince, err := time.Parse(time.RFC3339, input.PublishedSince)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid published_since format: expected RFC3339 timestamp (e.g., 2025-08-07T13:15:04.280Z)")
+			}
+			filter.PublishedSince = &publishedSince
+		}
+		if input.PublishedBefore != "" {
+			publishedBefore, err := time.Parse(time.RFC3339, input.PublishedBefore)
+			if err != nil {
+				return nil, huma.Error400BadRequest("Invalid published_before format: expected RFC3339 timestamp (e.g., 2025-08-07T13:15:04.280Z)")
+			}
+			filter.PublishedBefore = &publishedBefore
+		}
+
+		// Handle capability parameter
+		if input.Capability != "" {
+			filter.Capability = &input.Capability
+		}
+
+		// Handle sort parameters
+		filter.SortBy = input.Sort
+		filter.SortOrder = input.Order
+
 		// Get paginated results with filtering
-		servers, nextCursor, err := registry.ListServers(ctx, filter, input.Cursor, input.Limit)
+		servers, nextCursor, err := registry.ListServersRaw(ctx, filter, input.Cursor, input.Limit)
 		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid list request", err)
+			}
 			return nil, huma.Error500InternalServerError("Failed to get registry list", err)
 		}
 
-		// Convert []*ServerResponse to []ServerResponse
-		serverValues := make([]apiv0.ServerResponse, len(servers))
-		for i, server := range servers {
-			serverValues[i] = *server
-		}
-
-		return &Response[apiv0.ServerListResponse]{
-			Body: apiv0.ServerListResponse{
-				Servers: serverValues,
-				Metadata: apiv0.Metadata{
-					NextCursor: nextCursor,
-					Count:      len(servers),
-				},
-			},
-		}, nil
+		return &Response[serverListBody]{Body: newServerListBody(servers, nextCursor)}, nil
 	})
 
-	// Get specific server version endpoint (supports "latest" as special version)
+	// Get specific server version endpoint (supports "latest" and other dist-tags in place of a version)
 	huma.Register(api, huma.Operation{
 		OperationID: "get-server-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
 		Method:      http.MethodGet,
 		Path:        pathPrefix + "/servers/{serverName}/versions/{version}",
 		Summary:     "Get specific MCP server version",
-		Description: "Get detailed information about a specific version of an MCP server. Use the special version 'latest' to get the latest version.",
+		Description: "Get detailed information about a specific version of an MCP server. Use the special version 'latest' to get the latest version, or another tag such as 'beta' to get the version it points at. Exact versions take precedence over tags of the same name.",
 		Tags:        []string{"servers"},
 	}, func(ctx context.Context, input *ServerVersionDetailInput) (*Response[apiv0.ServerResponse], error) {
 		// URL-decode the server name
@@ -127,11 +189,11 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		}
 
 		var serverResponse *apiv0.ServerResponse
-		// Handle "latest" as a special version
-		if version == "latest" {
+		// Handle "latest" as a special version, and other tags when no version matches
+		if version == database.TagLatest {
 			serverResponse, err = registry.GetServerByName(ctx, serverName)
 		} else {
-			serverResponse, err = registry.GetServerByNameAndVersion(ctx, serverName, version)
+			serverResponse, err = registry.GetServerByNameAndVersionOrTag(ctx, serverName, version)
 		}
 
 		if err != nil {
@@ -146,6 +208,104 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		}, nil
 	})
 
+	// Get server version capabilities endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "get-server-version-capabilities" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/versions/{version}/capabilities",
+		Summary:     "Get the capabilities of an MCP server version",
+		Description: "Get the tools, prompts and resources of a version of an MCP server, as declared in the capabilities manifest of its publisher-provided metadata or listed by its remote. Capabilities are only recorded on registries that introspect servers. Use 'latest' or another tag in place of a version.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ServerVersionDetailInput) (*Response[apiv0.ServerCapabilities], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		// URL-decode the version
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		capabilities, err := registry.GetServerCapabilities(ctx, serverName, version)
+		if err != nil {
+			if errors.Is(err, service.ErrCapabilitiesNotRecorded) {
+				return nil, huma.Error404NotFound("Capabilities have not been recorded for this version")
+			}
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Server not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to get server capabilities", err)
+		}
+
+		return &Response[apiv0.ServerCapabilities]{
+			Body: *capabilities,
+		}, nil
+	})
+
+	// Resolve version range endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "resolve-server-version" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/resolve",
+		Summary:     "Resolve a version range of an MCP server",
+		Description: "Get the highest active version of an MCP server that satisfies a semver range, such as ^1.2.0 or >=1.0.0 <2.0.0. Prereleases only match ranges that name a prerelease of the same version.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ResolveServerVersionInput) (*Response[apiv0.ServerResponse], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		serverResponse, err := registry.ResolveVersionRange(ctx, serverName, input.Range)
+		if err != nil {
+			switch {
+			case errors.Is(err, database.ErrInvalidInput):
+				return nil, huma.Error400BadRequest("Invalid version range", err)
+			case errors.Is(err, service.ErrNoMatchingVersion):
+				return nil, huma.Error404NotFound("No active version satisfies the range")
+			case errors.Is(err, database.ErrNotFound):
+				return nil, huma.Error404NotFound("Server not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to resolve server version", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *serverResponse,
+		}, nil
+	})
+
+	// Diff server versions endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "get-server-diff" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/diff",
+		Summary:     "Compare two versions of an MCP server",
+		Description: "Get a field-aware diff between two versions of an MCP server: added and removed packages, remotes, environment variables, arguments and headers, and changed fields. Highlights summarize new packages, endpoints, secrets and required inputs for security review.",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ServerDiffInput) (*Response[apiv0.ServerDiff], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		diff, err := registry.DiffServerVersions(ctx, serverName, input.From, input.To)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Server version not found", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to compare server versions", err)
+		}
+
+		return &Response[apiv0.ServerDiff]{
+			Body: *diff,
+		}, nil
+	})
+
 	// Get server versions endpoint
 	huma.Register(api, huma.Operation{
 		OperationID: "get-server-versions" + strings.ReplaceAll(pathPrefix, "/", "-"),
@@ -154,7 +314,7 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		Summary:     "Get all versions of an MCP server",
 		Description: "Get all available versions for a specific MCP server",
 		Tags:        []string{"servers"},
-	}, func(ctx context.Context, input *ServerVersionsInput) (*Response[apiv0.ServerListResponse], error) {
+	}, func(ctx context.Context, input *ServerVersionsInput) (*Response[serverListBody], error) {
 		// URL-decode the server name
 		serverName, err := url.PathUnescape(input.ServerName)
 		if err != nil {
@@ -162,7 +322,7 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 		}
 
 		// Get all versions for this server
-		servers, err := registry.GetAllVersionsByServerName(ctx, serverName)
+		servers, err := registry.GetAllVersionsByServerNameRaw(ctx, serverName)
 		if err != nil {
 			if err.Error() == errRecordNotFound || errors.Is(err, database.ErrNotFound) {
 				return nil, huma.Error404NotFound("Server not found")
@@ -170,19 +330,6 @@ func RegisterServersEndpoints(api huma.API, pathPrefix string, registry service.
 			return nil, huma.Error500InternalServerError("Failed to get server versions", err)
 		}
 
-		// Convert []*ServerResponse to []ServerResponse
-		serverValues := make([]apiv0.ServerResponse, len(servers))
-		for i, server := range servers {
-			serverValues[i] = *server
-		}
-
-		return &Response[apiv0.ServerListResponse]{
-			Body: apiv0.ServerListResponse{
-				Servers: serverValues,
-				Metadata: apiv0.Metadata{
-					Count: len(servers),
-				},
-			},
-		}, nil
+		return &Response[serverListBody]{Body: newServerListBody(servers, "")}, nil
 	})
 }
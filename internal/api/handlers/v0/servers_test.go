@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/danielgtaylor/huma/v2/adapters/humago"
@@ -77,6 +78,54 @@ func TestListServersEndpoint(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedCount:  2,
 		},
+		{
+			name:           "sort by published time",
+			queryParams:    "?sort=published_at&order=desc",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "filter by namespace and status",
+			queryParams:    "?namespace=com.example&status=active&hide_deprecated=true",
+			expectedStatus: http.StatusOK,
+			expectedCount:  2,
+		},
+		{
+			name:           "filter by package registry type",
+			queryParams:    "?registry_type=npm&transport=stdio",
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name:           "filter by publish time range",
+			queryParams:    "?published_since=2000-01-01T00:00:00Z&published_before=2000-01-02T00:00:00Z",
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+		},
+		{
+			name:           "invalid published_since",
+			queryParams:    "?published_since=yesterday",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "Invalid published_since format",
+		},
+		{
+			name:           "unknown registry type",
+			queryParams:    "?registry_type=maven",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "validation failed",
+		},
+		{
+			name:           "invalid sort key",
+			queryParams:    "?sort=popularity",
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "validation failed",
+		},
+		{
+			name:           "cursor from a different sort order",
+			queryParams:    "?sort=updated_at&cursor=com.example%2Fserver-alpha%3A1.0.0",
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "malformed cursor",
+		},
 		{
 			name:           "invalid limit",
 			queryParams:    "?limit=abc",
@@ -114,6 +163,49 @@ func TestListServersEndpoint(t *testing.T) {
 	}
 }
 
+func TestListServersCursorValidation(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
+
+	for _, name := range []string{"com.example/server-alpha", "com.example/server-beta"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Cursor test server",
+			Version:     "1.0.0",
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	get := func(queryParams string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v0/servers"+queryParams, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("?search=server&limit=1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var firstPage apiv0.ServerListResponse
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&firstPage))
+	require.NotEmpty(t, firstPage.Metadata.NextCursor)
+	cursor := url.QueryEscape(firstPage.Metadata.NextCursor)
+
+	w = get("?search=server&limit=1&cursor=" + cursor)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = get("?search=alpha&limit=1&cursor=" + cursor)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "cursor was issued for different filters")
+
+	w = get("?search=server&limit=1&cursor=" + cursor + "x")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
 func TestGetLatestServerVersionEndpoint(t *testing.T) {
 	ctx := context.Background()
 	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
@@ -300,6 +392,220 @@ func TestGetServerVersionEndpoint(t *testing.T) {
 	}
 }
 
+func TestResolveServerVersionEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
+
+	serverName := "com.example/ranged-server"
+	for _, version := range []string{"1.1.0", "1.2.0", "1.3.0-beta.1", "2.0.0"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Range test server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	tests := []struct {
+		name            string
+		serverName      string
+		rangeExpr       string
+		expectedStatus  int
+		expectedVersion string
+		expectedError   string
+	}{
+		{"caret range", serverName, "^1.1.0", http.StatusOK, "1.2.0", ""},
+		{"comparator set", serverName, ">=1.0.0 <1.2.0", http.StatusOK, "1.1.0", ""},
+		{"prerelease range", serverName, "^1.3.0-beta.1", http.StatusOK, "1.3.0-beta.1", ""},
+		{"alternatives", serverName, "~1.1 || ^2.0.0", http.StatusOK, "2.0.0", ""},
+		{"no matching version", serverName, "^3.0.0", http.StatusNotFound, "", "No active version satisfies the range"},
+		{"unknown server", "com.example/non-existent", "^1.0.0", http.StatusNotFound, "", "Server not found"},
+		{"invalid range", serverName, "^1.x.0", http.StatusBadRequest, "", "Invalid version range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL := "/v0/servers/" + url.PathEscape(tt.serverName) + "/resolve?range=" + url.QueryEscape(tt.rangeExpr)
+			req := httptest.NewRequest(http.MethodGet, requestURL, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var resp apiv0.ServerResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+				assert.Equal(t, tt.expectedVersion, resp.Server.Version)
+			} else {
+				assert.Contains(t, w.Body.String(), tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestServerDiffEndpoint(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
+
+	serverName := "com.example/diffed-server"
+	_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Diff test server",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	_, err = registryService.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Diff test server with a hosted remote",
+		Version:     "1.1.0",
+		Remotes: []model.Transport{{
+			Type: "streamable-http",
+			URL:  "https://diffed.example.com/mcp",
+			Headers: []model.KeyValueInput{
+				{Name: "X-API-Key", InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true, IsSecret: true}}},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	t.Run("versions and tags can be compared", func(t *testing.T) {
+		requestURL := "/v0/servers/" + url.PathEscape(serverName) + "/diff?from=1.0.0&to=latest"
+		req := httptest.NewRequest(http.MethodGet, requestURL, nil)
+		w := httptest.NewRecorder()
+
+		mux.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var diff apiv0.ServerDiff
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&diff))
+		assert.Equal(t, "1.0.0", diff.From)
+		assert.Equal(t, "1.1.0", diff.To)
+		assert.Equal(t, []string{"https://diffed.example.com/mcp"}, diff.Highlights.NewEndpoints)
+		assert.Equal(t, []string{"https://diffed.example.com/mcp header X-API-Key"}, diff.Highlights.NewSecrets)
+		require.Len(t, diff.Remotes, 1)
+		assert.Equal(t, "added", diff.Remotes[0].Change)
+		require.Len(t, diff.Fields, 1)
+		assert.Equal(t, "description", diff.Fields[0].Field)
+	})
+
+	tests := []struct {
+		name       string
+		serverName string
+		from       string
+		to         string
+	}{
+		{"unknown server", "com.example/non-existent", "1.0.0", "1.1.0"},
+		{"unknown version", serverName, "1.0.0", "9.9.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestURL := "/v0/servers/" + url.PathEscape(tt.serverName) + "/diff?from=" + tt.from + "&to=" + tt.to
+			req := httptest.NewRequest(http.MethodGet, requestURL, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), "Server version not found")
+		})
+	}
+}
+
+func TestServerCapabilitiesEndpoint(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewTestDB(t)
+	registryService := service.NewRegistryService(db, config.NewConfig())
+
+	serverName := "com.example/introspected-server"
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		_, err := registryService.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Introspected server",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{
+		ServerName: serverName,
+		Version:    "1.1.0",
+		Source:     apiv0.CapabilitySourceIntrospection,
+		Tools:      []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}},
+		CheckedAt:  time.Now(),
+	}))
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	t.Run("capabilities are returned by version or tag", func(t *testing.T) {
+		for _, version := range []string{"1.1.0", "latest"} {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+url.PathEscape(serverName)+"/versions/"+version+"/capabilities", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var capabilities apiv0.ServerCapabilities
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&capabilities))
+			assert.Equal(t, "1.1.0", capabilities.Version)
+			assert.Equal(t, apiv0.CapabilitySourceIntrospection, capabilities.Source)
+			assert.Equal(t, []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}}, capabilities.Tools)
+			assert.Empty(t, capabilities.Prompts)
+		}
+	})
+
+	tests := []struct {
+		name          string
+		serverName    string
+		version       string
+		expectedError string
+	}{
+		{"capabilities not recorded", serverName, "1.0.0", "Capabilities have not been recorded for this version"},
+		{"unknown version", serverName, "9.9.9", "Server not found"},
+		{"unknown server", "com.example/non-existent", "1.0.0", "Server not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers/"+url.PathEscape(tt.serverName)+"/versions/"+tt.version+"/capabilities", nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusNotFound, w.Code)
+			assert.Contains(t, w.Body.String(), tt.expectedError)
+		})
+	}
+
+	t.Run("listings can be filtered by capability", func(t *testing.T) {
+		for capability, expected := range map[string]int{"create_issue": 1, "close_issue": 0} {
+			req := httptest.NewRequest(http.MethodGet, "/v0/servers?capability="+capability, nil)
+			w := httptest.NewRecorder()
+
+			mux.ServeHTTP(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var resp apiv0.ServerListResponse
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+			require.Len(t, resp.Servers, expected, capability)
+			if expected > 0 {
+				assert.Equal(t, "1.1.0", resp.Servers[0].Server.Version)
+			}
+		}
+	})
+}
+
 func TestGetAllVersionsEndpoint(t *testing.T) {
 	ctx := context.Background()
 	registryService := service.NewRegistryService(database.NewTestDB(t), config.NewConfig())
@@ -518,3 +824,50 @@ func TestServersEndpointEdgeCases(t *testing.T) {
 		}
 	})
 }
+
+func TestListServersEndpointStoredDocuments(t *testing.T) {
+	ctx := context.Background()
+	registryService := service.NewRegistryService(database.NewMemory(), config.NewConfig())
+
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/weather",
+		Description: "Forecasts & <alerts>",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: "https://weather.example.com/mcp"}},
+	}
+	published, err := registryService.CreateServer(ctx, server)
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+
+	expected, err := json.Marshal(server)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/v0/servers", "/v0/servers/" + url.PathEscape(server.Name) + "/versions"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, path)
+
+		var body struct {
+			Servers []struct {
+				Server json.RawMessage    `json:"server"`
+				Meta   apiv0.ResponseMeta `json:"_meta"`
+			} `json:"servers"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), path)
+		require.Len(t, body.Servers, 1, path)
+		assert.JSONEq(t, string(expected), string(body.Servers[0].Server), path)
+		assert.Equal(t, published.Meta.Official.PublishedAt.UTC(), body.Servers[0].Meta.Official.PublishedAt.UTC(), path)
+	}
+
+	t.Run("list responses are documented as server lists", func(t *testing.T) {
+		operation := api.OpenAPI().Paths["/v0/servers"].Get
+		schema := operation.Responses["200"].Content["application/json"].Schema
+		assert.Equal(t, "#/components/schemas/ServerListResponse", schema.Ref)
+		assert.NotContains(t, api.OpenAPI().Components.Schemas.Map(), "ServerListBody")
+	})
+}
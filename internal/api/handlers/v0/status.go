@@ -0,0 +1,81 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// UpdateServerStatusInput represents the input for deprecating or reactivating a server version
+type UpdateServerStatusInput struct {
+	Authorization string                    `header:"Authorization" doc:"Registry JWT token with publish or edit permissions for the server" required:"true"`
+	ServerName    string                    `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Version       string                    `path:"version" doc:"URL-encoded version to update" example:"1.0.0"`
+	Body          apiv0.StatusUpdateRequest `body:""`
+}
+
+// RegisterStatusEndpoint registers the endpoint publishers use to deprecate their own server versions
+func RegisterStatusEndpoint(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-server-status" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPut,
+		Path:        pathPrefix + "/servers/{serverName}/versions/{version}/status",
+		Summary:     "Deprecate or reactivate a server version",
+		Description: "Deprecate a version of a server you can publish, optionally explaining why and naming the server that replaces it, or make a deprecated version active again. Deleted versions cannot be changed.",
+		Tags:        []string{"publish"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *UpdateServerStatusInput) (*Response[apiv0.ServerResponse], error) {
+		claims, err := authenticate(ctx, jwtManager, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		// URL-decode the version
+		version, err := url.PathUnescape(input.Version)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid version encoding", err)
+		}
+
+		// Publishers manage the lifecycle of their own versions; admins with edit permission can too
+		if !jwtManager.HasPermission(serverName, auth.PermissionActionPublish, claims.Permissions) &&
+			!jwtManager.HasPermission(serverName, auth.PermissionActionEdit, claims.Permissions) {
+			return nil, huma.Error403Forbidden(buildPermissionErrorMessage(serverName, claims.Permissions))
+		}
+
+		updatedServer, err := registry.UpdateServerStatus(service.WithActor(ctx, service.ActorFromClaims(claims)), serverName, version, &input.Body)
+		if err != nil {
+			switch {
+			case errors.Is(err, database.ErrNotFound):
+				return nil, huma.Error404NotFound("Server not found")
+			case errors.Is(err, database.ErrNamespaceBlocked):
+				return nil, huma.Error403Forbidden("Failed to update server status", err)
+			case errors.Is(err, database.ErrInvalidInput):
+				return nil, huma.Error400BadRequest("Failed to update server status", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to update server status", err)
+		}
+
+		return &Response[apiv0.ServerResponse]{
+			Body: *updatedServer,
+		}, nil
+	})
+}
@@ -0,0 +1,244 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestUpdateServerStatusEndpoint(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+
+	newServer := func(name, version string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Server with a managed lifecycle",
+			Version:     version,
+		}
+	}
+	for _, server := range []*apiv0.ServerJSON{
+		newServer("io.github.testuser/weather", "1.0.0"),
+		newServer("io.github.testuser/weather", "1.1.0"),
+		newServer("io.github.testuser/weather-v2", "2.0.0"),
+		newServer("io.github.otheruser/other-server", "1.0.0"),
+		newServer("io.github.testuser/deleted-server", "1.0.0"),
+	} {
+		_, err := registryService.CreateServer(context.Background(), server)
+		require.NoError(t, err)
+	}
+	_, err = registryService.UpdateServer(context.Background(), "io.github.testuser/deleted-server", "1.0.0",
+		newServer("io.github.testuser/deleted-server", "1.0.0"), stringPtr(string(model.StatusDeleted)))
+	require.NoError(t, err)
+
+	publisher := &auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "testuser",
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.testuser/*"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		serverName     string
+		version        string
+		authClaims     *auth.JWTClaims
+		authHeader     string
+		requestBody    apiv0.StatusUpdateRequest
+		expectedStatus int
+		expectedError  string
+		checkResult    func(*testing.T, *apiv0.ServerResponse)
+	}{
+		{
+			name:       "publisher deprecates with message and successor",
+			serverName: "io.github.testuser/weather",
+			version:    "1.0.0",
+			authClaims: publisher,
+			requestBody: apiv0.StatusUpdateRequest{
+				Status:     model.StatusDeprecated,
+				Message:    "Use weather-v2, which supports streamable HTTP",
+				ReplacedBy: "io.github.testuser/weather-v2",
+			},
+			expectedStatus: http.StatusOK,
+			checkResult: func(t *testing.T, resp *apiv0.ServerResponse) {
+				t.Helper()
+				require.NotNil(t, resp.Meta.Official)
+				assert.Equal(t, model.StatusDeprecated, resp.Meta.Official.Status)
+				assert.Equal(t, "Use weather-v2, which supports streamable HTTP", resp.Meta.Official.DeprecationMessage)
+				assert.Equal(t, "io.github.testuser/weather-v2", resp.Meta.Official.ReplacedBy)
+				assert.Equal(t, "Server with a managed lifecycle", resp.Server.Description)
+			},
+		},
+		{
+			name:       "admin with edit permission deprecates without details",
+			serverName: "io.github.otheruser/other-server",
+			version:    "1.0.0",
+			authClaims: &auth.JWTClaims{
+				AuthMethod: auth.MethodNone,
+				Permissions: []auth.Permission{
+					{Action: auth.PermissionActionEdit, ResourcePattern: "*"},
+				},
+			},
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusDeprecated},
+			expectedStatus: http.StatusOK,
+			checkResult: func(t *testing.T, resp *apiv0.ServerResponse) {
+				t.Helper()
+				assert.Equal(t, model.StatusDeprecated, resp.Meta.Official.Status)
+				assert.Empty(t, resp.Meta.Official.DeprecationMessage)
+				assert.Empty(t, resp.Meta.Official.ReplacedBy)
+			},
+		},
+		{
+			name:           "reactivating clears the deprecation details",
+			serverName:     "io.github.testuser/weather",
+			version:        "1.0.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusActive},
+			expectedStatus: http.StatusOK,
+			checkResult: func(t *testing.T, resp *apiv0.ServerResponse) {
+				t.Helper()
+				assert.Equal(t, model.StatusActive, resp.Meta.Official.Status)
+				assert.Empty(t, resp.Meta.Official.DeprecationMessage)
+				assert.Empty(t, resp.Meta.Official.ReplacedBy)
+			},
+		},
+		{
+			name:           "details are rejected when reactivating",
+			serverName:     "io.github.testuser/weather",
+			version:        "1.1.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusActive, Message: "Back again"},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "only be set when deprecating",
+		},
+		{
+			name:           "successor must be in the registry",
+			serverName:     "io.github.testuser/weather",
+			version:        "1.1.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusDeprecated, ReplacedBy: "io.github.testuser/missing"},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "is not in the registry",
+		},
+		{
+			name:           "publishers cannot delete",
+			serverName:     "io.github.testuser/weather",
+			version:        "1.1.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusDeleted},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedError:  "validation failed",
+		},
+		{
+			name:           "deleted versions cannot be reactivated",
+			serverName:     "io.github.testuser/deleted-server",
+			version:        "1.0.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusActive},
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "deleted servers cannot be undeleted",
+		},
+		{
+			name:           "other publishers are forbidden",
+			serverName:     "io.github.otheruser/other-server",
+			version:        "1.0.0",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusActive},
+			expectedStatus: http.StatusForbidden,
+			expectedError:  "You do not have permission to publish this server",
+		},
+		{
+			name:           "unknown version",
+			serverName:     "io.github.testuser/weather",
+			version:        "9.9.9",
+			authClaims:     publisher,
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusDeprecated},
+			expectedStatus: http.StatusNotFound,
+			expectedError:  "Server not found",
+		},
+		{
+			name:           "invalid authorization header",
+			serverName:     "io.github.testuser/weather",
+			version:        "1.0.0",
+			authHeader:     "InvalidFormat",
+			requestBody:    apiv0.StatusUpdateRequest{Status: model.StatusDeprecated},
+			expectedStatus: http.StatusUnauthorized,
+			expectedError:  "Invalid Authorization header format",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+			v0.RegisterStatusEndpoint(api, "/v0", registryService, cfg)
+
+			requestBody, err := json.Marshal(tc.requestBody)
+			require.NoError(t, err)
+
+			requestURL := "/v0/servers/" + url.PathEscape(tc.serverName) + "/versions/" + url.PathEscape(tc.version) + "/status"
+			req := httptest.NewRequest(http.MethodPut, requestURL, bytes.NewReader(requestBody))
+			req.Header.Set("Content-Type", "application/json")
+
+			if tc.authHeader != "" {
+				req.Header.Set("Authorization", tc.authHeader)
+			} else if tc.authClaims != nil {
+				jwtManager := auth.NewJWTManager(cfg)
+				tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), *tc.authClaims)
+				require.NoError(t, err)
+				req.Header.Set("Authorization", "Bearer "+tokenResponse.RegistryToken)
+			}
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedError != "" {
+				assert.Contains(t, w.Body.String(), tc.expectedError)
+			}
+
+			if tc.expectedStatus == http.StatusOK && tc.checkResult != nil {
+				var response apiv0.ServerResponse
+				require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+				tc.checkResult(t, &response)
+			}
+		})
+	}
+
+	t.Run("stored status reflects the last change", func(t *testing.T) {
+		server, err := registryService.GetServerByNameAndVersion(context.Background(), "io.github.testuser/weather", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, server.Meta.Official.Status)
+		assert.Empty(t, server.Meta.Official.DeprecationMessage)
+		assert.Empty(t, server.Meta.Official.ReplacedBy)
+	})
+}
@@ -0,0 +1,158 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ServerTagsInput represents the input for listing the dist-tags of a server
+type ServerTagsInput struct {
+	ServerName string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+}
+
+// SetServerTagInput represents the input for moving a dist-tag
+type SetServerTagInput struct {
+	Authorization string                 `header:"Authorization" doc:"Registry JWT token with publish or edit permissions for the server" required:"true"`
+	ServerName    string                 `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Tag           string                 `path:"tag" doc:"Name of the tag" example:"beta"`
+	Body          apiv0.TagUpdateRequest `body:""`
+}
+
+// DeleteServerTagInput represents the input for removing a dist-tag
+type DeleteServerTagInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token with publish or edit permissions for the server" required:"true"`
+	ServerName    string `path:"serverName" doc:"URL-encoded server name" example:"com.example%2Fmy-server"`
+	Tag           string `path:"tag" doc:"Name of the tag" example:"beta"`
+}
+
+// RegisterTagsEndpoints registers the endpoints for listing and moving dist-tags
+func RegisterTagsEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// authorize validates the bearer token and checks that it can publish or edit the server
+	authorize := func(ctx context.Context, authHeader, encodedServerName string) (*auth.JWTClaims, string, error) {
+		claims, err := authenticate(ctx, jwtManager, authHeader)
+		if err != nil {
+			return nil, "", err
+		}
+
+		serverName, err := url.PathUnescape(encodedServerName)
+		if err != nil {
+			return nil, "", huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		if !jwtManager.HasPermission(serverName, auth.PermissionActionPublish, claims.Permissions) &&
+			!jwtManager.HasPermission(serverName, auth.PermissionActionEdit, claims.Permissions) {
+			return nil, "", huma.Error403Forbidden(buildPermissionErrorMessage(serverName, claims.Permissions))
+		}
+
+		return claims, serverName, nil
+	}
+
+	// List tags endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-server-tags" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/servers/{serverName}/tags",
+		Summary:     "List the dist-tags of an MCP server",
+		Description: "Get the named tags, such as latest or beta, that point at versions of a server",
+		Tags:        []string{"servers"},
+	}, func(ctx context.Context, input *ServerTagsInput) (*Response[apiv0.ServerTagListResponse], error) {
+		// URL-decode the server name
+		serverName, err := url.PathUnescape(input.ServerName)
+		if err != nil {
+			return nil, huma.Error400BadRequest("Invalid server name encoding", err)
+		}
+
+		tags, err := registry.ListServerTags(ctx, serverName)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Server not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to get server tags", err)
+		}
+
+		// Convert []*ServerTag to []ServerTag
+		tagValues := make([]apiv0.ServerTag, len(tags))
+		for i, tag := range tags {
+			tagValues[i] = *tag
+		}
+
+		return &Response[apiv0.ServerTagListResponse]{
+			Body: apiv0.ServerTagListResponse{Tags: tagValues},
+		}, nil
+	})
+
+	// Move tag endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "set-server-tag" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPut,
+		Path:        pathPrefix + "/servers/{serverName}/tags/{tag}",
+		Summary:     "Point a dist-tag at a server version",
+		Description: "Create a tag such as beta or next, or move an existing tag (including latest) to another version of a server you can publish. Deleted versions cannot be tagged.",
+		Tags:        []string{"publish"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *SetServerTagInput) (*Response[apiv0.ServerTag], error) {
+		claims, serverName, err := authorize(ctx, input.Authorization, input.ServerName)
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := registry.SetServerTag(service.WithActor(ctx, service.ActorFromClaims(claims)), serverName, input.Tag, &input.Body)
+		if err != nil {
+			switch {
+			case errors.Is(err, database.ErrNotFound):
+				return nil, huma.Error404NotFound("Server version not found")
+			case errors.Is(err, database.ErrInvalidInput):
+				return nil, huma.Error400BadRequest("Failed to set server tag", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to set server tag", err)
+		}
+
+		return &Response[apiv0.ServerTag]{
+			Body: *tag,
+		}, nil
+	})
+
+	// Remove tag endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-server-tag" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodDelete,
+		Path:        pathPrefix + "/servers/{serverName}/tags/{tag}",
+		Summary:     "Remove a dist-tag",
+		Description: "Remove a tag from a server you can publish. The latest tag can only be moved, not removed.",
+		Tags:        []string{"publish"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *DeleteServerTagInput) (*struct{}, error) {
+		claims, serverName, err := authorize(ctx, input.Authorization, input.ServerName)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := registry.DeleteServerTag(service.WithActor(ctx, service.ActorFromClaims(claims)), serverName, input.Tag); err != nil {
+			switch {
+			case errors.Is(err, database.ErrNotFound):
+				return nil, huma.Error404NotFound("Tag not found")
+			case errors.Is(err, database.ErrInvalidInput):
+				return nil, huma.Error400BadRequest("Failed to delete server tag", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to delete server tag", err)
+		}
+
+		return &struct{}{}, nil
+	})
+}
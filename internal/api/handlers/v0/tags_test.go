@@ -0,0 +1,217 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestServerTagsEndpoints(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+	for _, version := range []string{"1.2.9", "1.3.0-beta.1"} {
+		_, err := registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        "io.github.testuser/weather",
+			Description: "Server with release channels",
+			Version:     version,
+		})
+		require.NoError(t, err)
+	}
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterServersEndpoints(api, "/v0", registryService)
+	v0.RegisterTagsEndpoints(api, "/v0", registryService, cfg)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "testuser",
+		Permissions: []auth.Permission{
+			{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.testuser/*"},
+		},
+	})
+	require.NoError(t, err)
+	publisherToken := "Bearer " + tokenResponse.RegistryToken
+
+	serverPath := "/v0/servers/" + url.PathEscape("io.github.testuser/weather")
+	do := func(method, path, authHeader string, body any) *httptest.ResponseRecorder {
+		t.Helper()
+		var reader *bytes.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+		return w
+	}
+	getVersion := func(t *testing.T, versionOrTag string) string {
+		t.Helper()
+		w := do(http.MethodGet, serverPath+"/versions/"+url.PathEscape(versionOrTag), "", nil)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var response apiv0.ServerResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		return response.Server.Version
+	}
+
+	t.Run("prereleases do not become latest", func(t *testing.T) {
+		assert.Equal(t, "1.2.9", getVersion(t, "latest"))
+	})
+
+	t.Run("publisher creates a tag", func(t *testing.T) {
+		w := do(http.MethodPut, serverPath+"/tags/beta", publisherToken, apiv0.TagUpdateRequest{Version: "1.3.0-beta.1"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var tag apiv0.ServerTag
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&tag))
+		assert.Equal(t, "beta", tag.Tag)
+		assert.Equal(t, "1.3.0-beta.1", tag.Version)
+
+		assert.Equal(t, "1.3.0-beta.1", getVersion(t, "beta"))
+	})
+
+	t.Run("tags are listed", func(t *testing.T) {
+		w := do(http.MethodGet, serverPath+"/tags", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response apiv0.ServerTagListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		require.Len(t, response.Tags, 2)
+		assert.Equal(t, "beta", response.Tags[0].Tag)
+		assert.Equal(t, "latest", response.Tags[1].Tag)
+
+		w = do(http.MethodGet, "/v0/servers/"+url.PathEscape("io.github.testuser/missing")+"/tags", "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("moving latest updates listings", func(t *testing.T) {
+		w := do(http.MethodPut, serverPath+"/tags/latest", publisherToken, apiv0.TagUpdateRequest{Version: "1.3.0-beta.1"})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Equal(t, "1.3.0-beta.1", getVersion(t, "latest"))
+
+		w = do(http.MethodGet, "/v0/servers?version=latest", "", nil)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response apiv0.ServerListResponse
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&response))
+		require.Len(t, response.Servers, 1)
+		assert.Equal(t, "1.3.0-beta.1", response.Servers[0].Server.Version)
+		assert.True(t, response.Servers[0].Meta.Official.IsLatest)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			name           string
+			method         string
+			path           string
+			authHeader     string
+			body           any
+			expectedStatus int
+			expectedError  string
+		}{
+			{
+				name:           "tag names cannot look like versions",
+				method:         http.MethodPut,
+				path:           serverPath + "/tags/1.0.0",
+				authHeader:     publisherToken,
+				body:           apiv0.TagUpdateRequest{Version: "1.2.9"},
+				expectedStatus: http.StatusBadRequest,
+				expectedError:  "must start with a lowercase letter",
+			},
+			{
+				name:           "unknown version",
+				method:         http.MethodPut,
+				path:           serverPath + "/tags/next",
+				authHeader:     publisherToken,
+				body:           apiv0.TagUpdateRequest{Version: "9.9.9"},
+				expectedStatus: http.StatusNotFound,
+				expectedError:  "Server version not found",
+			},
+			{
+				name:           "latest cannot be removed",
+				method:         http.MethodDelete,
+				path:           serverPath + "/tags/latest",
+				authHeader:     publisherToken,
+				expectedStatus: http.StatusBadRequest,
+				expectedError:  "cannot be removed",
+			},
+			{
+				name:           "unknown tag",
+				method:         http.MethodDelete,
+				path:           serverPath + "/tags/next",
+				authHeader:     publisherToken,
+				expectedStatus: http.StatusNotFound,
+				expectedError:  "Tag not found",
+			},
+			{
+				name:           "other publishers are forbidden",
+				method:         http.MethodPut,
+				path:           "/v0/servers/" + url.PathEscape("io.github.otheruser/server") + "/tags/beta",
+				authHeader:     publisherToken,
+				body:           apiv0.TagUpdateRequest{Version: "1.0.0"},
+				expectedStatus: http.StatusForbidden,
+				expectedError:  "You do not have permission to publish this server",
+			},
+			{
+				name:           "invalid authorization header",
+				method:         http.MethodDelete,
+				path:           serverPath + "/tags/beta",
+				authHeader:     "InvalidFormat",
+				expectedStatus: http.StatusUnauthorized,
+				expectedError:  "Invalid Authorization header format",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				w := do(tc.method, tc.path, tc.authHeader, tc.body)
+				assert.Equal(t, tc.expectedStatus, w.Code)
+				assert.Contains(t, w.Body.String(), tc.expectedError)
+			})
+		}
+	})
+
+	t.Run("publisher removes a tag", func(t *testing.T) {
+		w := do(http.MethodDelete, serverPath+"/tags/beta", publisherToken, nil)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		w = do(http.MethodGet, serverPath+"/versions/beta", "", nil)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
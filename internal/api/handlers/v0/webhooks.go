@@ -0,0 +1,229 @@
+package v0
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// CreateWebhookInput represents the input for subscribing to webhook events
+type CreateWebhookInput struct {
+	Authorization string                           `header:"Authorization" doc:"Registry JWT token" required:"true"`
+	Body          apiv0.WebhookSubscriptionRequest `body:""`
+}
+
+// ListWebhooksInput represents the input for listing webhook subscriptions
+type ListWebhooksInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token" required:"true"`
+}
+
+// WebhookInput represents the input for operating on a webhook subscription
+type WebhookInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token" required:"true"`
+	ID            int64  `path:"id" doc:"Identifier of the webhook subscription" example:"1"`
+}
+
+// ListWebhookDeliveriesInput represents the input for listing the deliveries of a webhook subscription
+type ListWebhookDeliveriesInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token" required:"true"`
+	ID            int64  `path:"id" doc:"Identifier of the webhook subscription" example:"1"`
+	Status        string `query:"status" doc:"Only include deliveries with this status" required:"false" enum:"pending,delivered,dead"`
+	Limit         int    `query:"limit" doc:"Number of deliveries to return" default:"30" minimum:"1" maximum:"100" example:"50"`
+}
+
+// RedeliverWebhookInput represents the input for redelivering a webhook event
+type RedeliverWebhookInput struct {
+	Authorization string `header:"Authorization" doc:"Registry JWT token" required:"true"`
+	ID            int64  `path:"id" doc:"Identifier of the webhook subscription" example:"1"`
+	DeliveryID    int64  `path:"deliveryId" doc:"Identifier of the delivery" example:"42"`
+}
+
+// RegisterWebhooksEndpoints registers the endpoints for managing webhook subscriptions and their deliveries
+func RegisterWebhooksEndpoints(api huma.API, pathPrefix string, registry service.RegistryService, cfg *config.Config) {
+	jwtManager := auth.NewJWTManager(cfg)
+
+	// authorize validates the bearer token and returns the owner whose subscriptions it can manage,
+	// which is nobody in particular (all subscriptions) for admins
+	authorize := func(ctx context.Context, authHeader string) (context.Context, *service.Actor, error) {
+		claims, err := authenticate(ctx, jwtManager, authHeader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		actor := service.ActorFromClaims(claims)
+		ctx = service.WithActor(ctx, actor)
+		if isAdmin(jwtManager, claims) {
+			return ctx, nil, nil
+		}
+		return ctx, &actor, nil
+	}
+
+	// Subscribe endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "create-webhook" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        pathPrefix + "/webhooks",
+		Summary:     "Subscribe to server events",
+		Description: "Register an HTTPS endpoint that is sent a signed POST whenever a server matching the namespace pattern is published, updated, deprecated or deleted. The response contains the signing secret, which is not shown again.",
+		Tags:        []string{"webhooks"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *CreateWebhookInput) (*Response[apiv0.WebhookSubscription], error) {
+		ctx, _, err := authorize(ctx, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		subscription, err := registry.CreateWebhookSubscription(ctx, &input.Body)
+		if err != nil {
+			if errors.Is(err, database.ErrInvalidInput) {
+				return nil, huma.Error400BadRequest("Invalid webhook subscription", err)
+			}
+			return nil, huma.Error500InternalServerError("Failed to create webhook subscription", err)
+		}
+
+		return &Response[apiv0.WebhookSubscription]{
+			Body: *subscription,
+		}, nil
+	})
+
+	// List subscriptions endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-webhooks" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/webhooks",
+		Summary:     "List webhook subscriptions",
+		Description: "Get the webhook subscriptions you created. Admins see every subscription.",
+		Tags:        []string{"webhooks"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListWebhooksInput) (*Response[apiv0.WebhookSubscriptionListResponse], error) {
+		ctx, owner, err := authorize(ctx, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		subscriptions, err := registry.ListWebhookSubscriptions(ctx, owner)
+		if err != nil {
+			return nil, huma.Error500InternalServerError("Failed to get webhook subscriptions", err)
+		}
+
+		// Convert []*WebhookSubscription to []WebhookSubscription
+		subscriptionValues := make([]apiv0.WebhookSubscription, len(subscriptions))
+		for i, subscription := range subscriptions {
+			subscriptionValues[i] = *subscription
+		}
+
+		return &Response[apiv0.WebhookSubscriptionListResponse]{
+			Body: apiv0.WebhookSubscriptionListResponse{Subscriptions: subscriptionValues},
+		}, nil
+	})
+
+	// Unsubscribe endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-webhook" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodDelete,
+		Path:        pathPrefix + "/webhooks/{id}",
+		Summary:     "Delete a webhook subscription",
+		Description: "Stop sending events to a subscription and discard its queued deliveries.",
+		Tags:        []string{"webhooks"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *WebhookInput) (*struct{}, error) {
+		ctx, owner, err := authorize(ctx, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := registry.DeleteWebhookSubscription(ctx, input.ID, owner); err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Webhook subscription not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to delete webhook subscription", err)
+		}
+
+		return &struct{}{}, nil
+	})
+
+	// List deliveries endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "list-webhook-deliveries" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodGet,
+		Path:        pathPrefix + "/webhooks/{id}/deliveries",
+		Summary:     "List webhook deliveries",
+		Description: "Get the events queued for or sent to a subscription, newest first, with the outcome of their last attempt.",
+		Tags:        []string{"webhooks"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *ListWebhookDeliveriesInput) (*Response[apiv0.WebhookDeliveryListResponse], error) {
+		ctx, owner, err := authorize(ctx, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		var status *string
+		if input.Status != "" {
+			status = &input.Status
+		}
+
+		deliveries, err := registry.ListWebhookDeliveries(ctx, input.ID, status, input.Limit, owner)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Webhook subscription not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to get webhook deliveries", err)
+		}
+
+		// Convert []*WebhookDelivery to []WebhookDelivery
+		deliveryValues := make([]apiv0.WebhookDelivery, len(deliveries))
+		for i, delivery := range deliveries {
+			deliveryValues[i] = *delivery
+		}
+
+		return &Response[apiv0.WebhookDeliveryListResponse]{
+			Body: apiv0.WebhookDeliveryListResponse{Deliveries: deliveryValues},
+		}, nil
+	})
+
+	// Redeliver endpoint
+	huma.Register(api, huma.Operation{
+		OperationID: "redeliver-webhook" + strings.ReplaceAll(pathPrefix, "/", "-"),
+		Method:      http.MethodPost,
+		Path:        pathPrefix + "/webhooks/{id}/deliveries/{deliveryId}/redeliver",
+		Summary:     "Redeliver a webhook event",
+		Description: "Queue a delivery to be sent again right away with a fresh set of attempts, for example after it went dead while your endpoint was down.",
+		Tags:        []string{"webhooks"},
+		Security: []map[string][]string{
+			{"bearer": {}},
+		},
+	}, func(ctx context.Context, input *RedeliverWebhookInput) (*Response[apiv0.WebhookDelivery], error) {
+		ctx, owner, err := authorize(ctx, input.Authorization)
+		if err != nil {
+			return nil, err
+		}
+
+		delivery, err := registry.RedeliverWebhookDelivery(ctx, input.ID, input.DeliveryID, owner)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return nil, huma.Error404NotFound("Webhook delivery not found")
+			}
+			return nil, huma.Error500InternalServerError("Failed to redeliver webhook", err)
+		}
+
+		return &Response[apiv0.WebhookDelivery]{
+			Body: *delivery,
+		}, nil
+	})
+}
@@ -0,0 +1,154 @@
+package v0_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+func TestWebhooksEndpoints(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{
+		JWTPrivateKey:            hex.EncodeToString(testSeed),
+		EnableRegistryValidation: false,
+	}
+
+	registryService := service.NewRegistryService(database.NewTestDB(t), cfg)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	v0.RegisterWebhooksEndpoints(api, "/v0", registryService, cfg)
+
+	jwtManager := auth.NewJWTManager(cfg)
+	generateToken := func(subject string, permissions []auth.Permission) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+			Permissions:       permissions,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+	ownerToken := generateToken("example", []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.example/*"}})
+	otherToken := generateToken("other", []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.other/*"}})
+	adminToken := generateToken("admin", []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}})
+
+	do := func(method, path, authHeader string, body any) *httptest.ResponseRecorder {
+		var reader *bytes.Reader
+		if body != nil {
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+			reader = bytes.NewReader(data)
+		} else {
+			reader = bytes.NewReader(nil)
+		}
+		req := httptest.NewRequest(method, path, reader)
+		req.Header.Set("Content-Type", "application/json")
+		if authHeader != "" {
+			req.Header.Set("Authorization", authHeader)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	// Subscribe
+	rr := do(http.MethodPost, "/v0/webhooks", ownerToken, apiv0.WebhookSubscriptionRequest{
+		URL:              "https://hooks.example.com/mcp",
+		NamespacePattern: "io.github.example/*",
+	})
+	require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+	var subscription apiv0.WebhookSubscription
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&subscription))
+	assert.NotEmpty(t, subscription.Secret)
+	assert.Equal(t, "example", subscription.OwnerSubject)
+
+	// Publishing a matching server queues a delivery
+	_, err = registryService.CreateServer(context.Background(), &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "io.github.example/weather",
+		Description: "Server with a webhook subscriber",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	deliveriesPath := fmt.Sprintf("/v0/webhooks/%d/deliveries", subscription.ID)
+
+	t.Run("invalid subscriptions are rejected", func(t *testing.T) {
+		rr := do(http.MethodPost, "/v0/webhooks", ownerToken, apiv0.WebhookSubscriptionRequest{URL: "http://hooks.example.com/mcp"})
+		assert.Equal(t, http.StatusBadRequest, rr.Code, rr.Body.String())
+	})
+
+	t.Run("authentication is required", func(t *testing.T) {
+		rr := do(http.MethodGet, "/v0/webhooks", "InvalidFormat", nil)
+		assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	})
+
+	t.Run("owners and admins list the subscription", func(t *testing.T) {
+		for token, expected := range map[string]int{ownerToken: 1, adminToken: 1, otherToken: 0} {
+			rr := do(http.MethodGet, "/v0/webhooks", token, nil)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			var response apiv0.WebhookSubscriptionListResponse
+			require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+			require.Len(t, response.Subscriptions, expected)
+			if expected > 0 {
+				assert.Empty(t, response.Subscriptions[0].Secret)
+			}
+		}
+	})
+
+	t.Run("deliveries are listed and redelivered", func(t *testing.T) {
+		rr := do(http.MethodGet, deliveriesPath+"?status=pending", ownerToken, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		var response apiv0.WebhookDeliveryListResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&response))
+		require.Len(t, response.Deliveries, 1)
+		delivery := response.Deliveries[0]
+		assert.Equal(t, "server.published", delivery.EventType)
+		assert.Equal(t, "io.github.example/weather", delivery.ServerName)
+
+		rr = do(http.MethodPost, fmt.Sprintf("%s/%d/redeliver", deliveriesPath, delivery.ID), ownerToken, nil)
+		require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+
+		rr = do(http.MethodPost, fmt.Sprintf("%s/%d/redeliver", deliveriesPath, delivery.ID+1000), ownerToken, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("other users cannot see or delete the subscription", func(t *testing.T) {
+		rr := do(http.MethodGet, deliveriesPath, otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+
+		rr = do(http.MethodDelete, fmt.Sprintf("/v0/webhooks/%d", subscription.ID), otherToken, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+
+	t.Run("owners delete the subscription", func(t *testing.T) {
+		rr := do(http.MethodDelete, fmt.Sprintf("/v0/webhooks/%d", subscription.ID), ownerToken, nil)
+		assert.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+
+		rr = do(http.MethodGet, deliveriesPath, adminToken, nil)
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	})
+}
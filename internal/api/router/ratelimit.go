@@ -0,0 +1,184 @@
+package router
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
+)
+
+// maxNamespaceBodyBytes bounds how much of a request body is read to find the server name it is for
+const maxNamespaceBodyBytes = 1 << 20
+
+// versionSuffix matches the API version suffix of operation IDs, such as -v0.1 in get-server-v0.1
+var versionSuffix = regexp.MustCompile(`-v[0-9]+(\.[0-9]+)*$`)
+
+// RateLimitMiddleware limits request rates according to the policy of each operation, chosen by
+// operation ID (without the API version suffix), then by operation tag, then the default policy.
+// Requests are counted per client IP, token subject or server namespace, as the policy says.
+// The client IP is read from the X-Forwarded-For header set by trustedProxyHops proxies in front of the registry.
+func RateLimitMiddleware(api huma.API, limiter *ratelimit.Limiter, jwtManager *auth.JWTManager, trustedProxyHops int) func(huma.Context, func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		op := ctx.Operation()
+		routes := []string{versionSuffix.ReplaceAllString(op.OperationID, "")}
+		routes = append(routes, op.Tags...)
+		policy, ok := limiter.Policy(routes...)
+		if !ok {
+			next(ctx)
+			return
+		}
+
+		key := rateLimitKey(ctx, policy.Key, jwtManager, trustedProxyHops)
+		result, err := limiter.Allow(ctx.Context(), policy, key)
+		if errors.Is(err, ratelimit.ErrInvalidKey) {
+			// Unlike an unavailable store, this is down to the request, which must not get past the limit
+			_ = huma.WriteErr(api, ctx, http.StatusBadRequest, "Invalid request: cannot apply rate limit")
+			return
+		}
+		if err != nil {
+			// Fail open: an unavailable store should not take the registry down with it
+			log.Printf("Failed to check rate limit for %s: %v", policy.Route, err)
+			next(ctx)
+			return
+		}
+
+		ctx.SetHeader("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.SetHeader("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.SetHeader("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+		ctx.SetHeader("RateLimit-Policy", fmt.Sprintf("%d;w=%d", policy.Requests, ceilSeconds(policy.Window)))
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			ctx.SetHeader("Retry-After", strconv.Itoa(retryAfter))
+			_ = huma.WriteErr(api, ctx, http.StatusTooManyRequests,
+				fmt.Sprintf("Rate limit exceeded: %d requests per %s. Try again in %d seconds.", policy.Requests, policy.Window, retryAfter))
+			return
+		}
+
+		next(ctx)
+	}
+}
+
+// rateLimitKey identifies who a request is counted for
+func rateLimitKey(ctx huma.Context, keyType ratelimit.KeyType, jwtManager *auth.JWTManager, trustedProxyHops int) string {
+	switch keyType {
+	case ratelimit.KeyNamespace:
+		if namespace := requestNamespace(ctx); namespace != "" {
+			return "namespace:" + namespace
+		}
+		return rateLimitKey(ctx, ratelimit.KeySubject, jwtManager, trustedProxyHops)
+	case ratelimit.KeySubject:
+		if subject := requestSubject(ctx, jwtManager); subject != "" {
+			return "subject:" + subject
+		}
+	case ratelimit.KeyIP:
+	}
+	return "ip:" + clientIP(ctx, trustedProxyHops)
+}
+
+// requestSubject returns the auth method and subject of a valid bearer token, or "" for anonymous requests
+func requestSubject(ctx huma.Context, jwtManager *auth.JWTManager) string {
+	const bearerPrefix = "Bearer "
+	authHeader := ctx.Header("Authorization")
+	if len(authHeader) < len(bearerPrefix) || !strings.EqualFold(authHeader[:len(bearerPrefix)], bearerPrefix) {
+		return ""
+	}
+
+	claims, err := jwtManager.ValidateToken(ctx.Context(), authHeader[len(bearerPrefix):])
+	if err != nil || claims.AuthMethodSubject == "" {
+		return ""
+	}
+	return string(claims.AuthMethod) + ":" + claims.AuthMethodSubject
+}
+
+// requestNamespace returns the namespace of the server a request is for, taken from the serverName
+// path parameter or the name in a JSON request body, or "" when there is none
+func requestNamespace(ctx huma.Context) string {
+	serverName := ctx.Param("serverName")
+	if serverName != "" {
+		if unescaped, err := url.PathUnescape(serverName); err == nil {
+			serverName = unescaped
+		}
+	} else if ctx.Method() == http.MethodPost || ctx.Method() == http.MethodPut {
+		serverName = bodyServerName(ctx)
+	}
+
+	namespace, _, found := strings.Cut(serverName, "/")
+	if !found {
+		return ""
+	}
+	return namespace
+}
+
+// bodyServerName peeks at the name in a JSON request body, leaving the body intact for the handler
+func bodyServerName(ctx huma.Context) string {
+	r, _ := humago.Unwrap(ctx)
+	if r.Body == nil {
+		return ""
+	}
+
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxNamespaceBodyBytes))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), r.Body), r.Body}
+	if err != nil {
+		return ""
+	}
+
+	var body struct {
+		Name string `json:"name"`
+	}
+	if json.Unmarshal(data, &body) != nil {
+		return ""
+	}
+	return body.Name
+}
+
+// clientIP returns the address of the client, as seen by the outermost of trustedProxyHops proxies
+func clientIP(ctx huma.Context, trustedProxyHops int) string {
+	if trustedProxyHops > 0 {
+		// Each proxy appends the address it received the request from, possibly in a header line of its own
+		var hops []string
+		ctx.EachHeader(func(name, value string) {
+			if !strings.EqualFold(name, "X-Forwarded-For") {
+				return
+			}
+			for _, entry := range strings.Split(value, ",") {
+				if entry = strings.TrimSpace(entry); entry != "" {
+					hops = append(hops, entry)
+				}
+			}
+		})
+		if len(hops) >= trustedProxyHops {
+			return hops[len(hops)-trustedProxyHops]
+		}
+	}
+
+	host, _, err := net.SplitHostPort(ctx.RemoteAddr())
+	if err != nil {
+		return ctx.RemoteAddr()
+	}
+	return host
+}
+
+// ceilSeconds rounds d up to whole seconds, as rate limit headers expect
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,232 @@
+package router_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/danielgtaylor/huma/v2/adapters/humago"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/api/router"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
+)
+
+type rateLimitTestInput struct {
+	ServerName string `path:"serverName"`
+	Body       struct {
+		Name string `json:"name"`
+	}
+}
+
+type rateLimitTestOutput struct {
+	Body struct {
+		Name string `json:"name"`
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg := &config.Config{JWTPrivateKey: hex.EncodeToString(testSeed)}
+	jwtManager := auth.NewJWTManager(cfg)
+
+	policies, err := ratelimit.ParsePolicies("auth=2/1m:ip,publish-server=2/1h:namespace,edit=2/1h:subject,*=100/1m")
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policies)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.RateLimitMiddleware(api, limiter, jwtManager, 1))
+
+	echo := func(_ context.Context, input *rateLimitTestInput) (*rateLimitTestOutput, error) {
+		output := &rateLimitTestOutput{}
+		output.Body.Name = input.Body.Name
+		return output, nil
+	}
+	huma.Register(api, huma.Operation{
+		OperationID: "exchange-dns-token-v0.1",
+		Method:      http.MethodPost,
+		Path:        "/v0.1/auth/dns",
+		Tags:        []string{"auth"},
+	}, func(_ context.Context, _ *struct{}) (*struct{}, error) {
+		return &struct{}{}, nil
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "publish-server-v0",
+		Method:      http.MethodPost,
+		Path:        "/v0/publish",
+		Tags:        []string{"publish"},
+	}, func(ctx context.Context, input *struct {
+		Body struct {
+			Name string `json:"name"`
+		}
+	}) (*rateLimitTestOutput, error) {
+		return echo(ctx, &rateLimitTestInput{Body: input.Body})
+	})
+	huma.Register(api, huma.Operation{
+		OperationID: "edit-server-v0",
+		Method:      http.MethodPut,
+		Path:        "/v0/servers/{serverName}",
+		Tags:        []string{"edit"},
+	}, echo)
+
+	generateToken := func(subject string) string {
+		tokenResponse, err := jwtManager.GenerateTokenResponse(context.Background(), auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: subject,
+		})
+		require.NoError(t, err)
+		return "Bearer " + tokenResponse.RegistryToken
+	}
+
+	do := func(method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr
+	}
+
+	t.Run("requests over the limit are rejected", func(t *testing.T) {
+		headers := map[string]string{"X-Forwarded-For": "198.51.100.7, 192.0.2.1"}
+		for i := 0; i < 2; i++ {
+			rr := do(http.MethodPost, "/v0.1/auth/dns", "", headers)
+			require.Equal(t, http.StatusNoContent, rr.Code, rr.Body.String())
+			assert.Equal(t, "2", rr.Header().Get("RateLimit-Limit"))
+			assert.Equal(t, "2;w=60", rr.Header().Get("RateLimit-Policy"))
+		}
+
+		rr := do(http.MethodPost, "/v0.1/auth/dns", "", headers)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+		assert.Equal(t, "30", rr.Header().Get("Retry-After"))
+		assert.Contains(t, rr.Body.String(), "Rate limit exceeded")
+
+		// The client IP is the address the trusted proxy received the request from
+		rr = do(http.MethodPost, "/v0.1/auth/dns", "", map[string]string{"X-Forwarded-For": "192.0.2.1, 198.51.100.8"})
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+	})
+
+	t.Run("publishing is limited per namespace", func(t *testing.T) {
+		for i := 0; i < 2; i++ {
+			rr := do(http.MethodPost, "/v0/publish", `{"name":"io.github.alice/weather"}`, nil)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+			// The handler still sees the body the middleware read
+			assert.Contains(t, rr.Body.String(), "io.github.alice/weather")
+		}
+		rr := do(http.MethodPost, "/v0/publish", `{"name":"io.github.alice/calendar"}`, nil)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = do(http.MethodPost, "/v0/publish", `{"name":"io.github.bob/weather"}`, nil)
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("edits are limited per token subject", func(t *testing.T) {
+		alice := map[string]string{"Authorization": generateToken("alice")}
+		for i := 0; i < 2; i++ {
+			rr := do(http.MethodPut, "/v0/servers/io.github.alice%2Fweather", `{"name":""}`, alice)
+			require.Equal(t, http.StatusOK, rr.Code, rr.Body.String())
+		}
+		rr := do(http.MethodPut, "/v0/servers/io.github.alice%2Fcalendar", `{"name":""}`, alice)
+		assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+
+		rr = do(http.MethodPut, "/v0/servers/io.github.alice%2Fweather", `{"name":""}`, map[string]string{"Authorization": generateToken("bob")})
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+}
+
+func TestRateLimitMiddlewareBehindIngress(t *testing.T) {
+	// Rate limiting is opt-in
+	assert.Empty(t, config.NewConfig().RateLimits)
+
+	// The deployment sets its policies and trusts one proxy, the ingress
+	t.Setenv("MCP_REGISTRY_RATE_LIMITS", "auth=30/1m:ip,publish=120/1h:subject,*=1200/1m:ip")
+	t.Setenv("MCP_REGISTRY_RATE_LIMIT_TRUSTED_PROXY_HOPS", "1")
+	cfg := config.NewConfig()
+	testSeed := make([]byte, ed25519.SeedSize)
+	_, err := rand.Read(testSeed)
+	require.NoError(t, err)
+	cfg.JWTPrivateKey = hex.EncodeToString(testSeed)
+
+	policies, err := ratelimit.ParsePolicies(cfg.RateLimits)
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), policies)
+	authPolicy, ok := limiter.Policy("auth")
+	require.True(t, ok)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.RateLimitMiddleware(api, limiter, auth.NewJWTManager(cfg), cfg.RateLimitTrustedProxyHops))
+	huma.Register(api, huma.Operation{
+		OperationID: "exchange-github-token-v0",
+		Method:      http.MethodPost,
+		Path:        "/v0/auth/github-at",
+		Tags:        []string{"auth"},
+	}, func(_ context.Context, _ *struct{}) (*struct{}, error) {
+		return &struct{}{}, nil
+	})
+
+	// Every request comes from the ingress pod, which appends the address of the client
+	do := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/v0/auth/github-at", nil)
+		req.RemoteAddr = "10.8.0.12:43120"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, req)
+		return rr.Code
+	}
+
+	for i := 0; i < authPolicy.Requests; i++ {
+		require.Equal(t, http.StatusNoContent, do("203.0.113.5"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, do("203.0.113.5"))
+	// Entries set by the client itself do not escape its limit
+	assert.Equal(t, http.StatusTooManyRequests, do("198.51.100.99, 203.0.113.5"))
+	// Other clients behind the same ingress keep their own limit
+	assert.Equal(t, http.StatusNoContent, do("203.0.113.6"))
+}
+
+// invalidKeyStore refuses every bucket key
+type invalidKeyStore struct{}
+
+func (invalidKeyStore) Take(_ context.Context, _ string, _ ratelimit.Policy) (*ratelimit.Result, error) {
+	return nil, ratelimit.ErrInvalidKey
+}
+
+func TestRateLimitMiddlewareInvalidKey(t *testing.T) {
+	policies, err := ratelimit.ParsePolicies("*=100/1m")
+	require.NoError(t, err)
+	limiter := ratelimit.NewLimiter(invalidKeyStore{}, policies)
+
+	mux := http.NewServeMux()
+	api := humago.New(mux, huma.DefaultConfig("Test API", "1.0.0"))
+	api.UseMiddleware(router.RateLimitMiddleware(api, limiter, nil, 0))
+	called := false
+	huma.Register(api, huma.Operation{
+		OperationID: "list-servers",
+		Method:      http.MethodGet,
+		Path:        "/v0/servers",
+	}, func(_ context.Context, _ *struct{}) (*struct{}, error) {
+		called = true
+		return &struct{}{}, nil
+	})
+
+	// Unlike store errors, which let requests through, a key the store refuses rejects the request
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/v0/servers", nil))
+	assert.Equal(t, http.StatusBadRequest, rr.Code)
+	assert.False(t, called)
+}
@@ -18,9 +18,19 @@ func RegisterV0Routes(
 	v0.RegisterPingEndpoint(api, "/v0")
 	v0.RegisterVersionEndpoint(api, "/v0", versionInfo)
 	v0.RegisterServersEndpoints(api, "/v0", registry)
+	v0.RegisterChangesEndpoint(api, "/v0", registry)
+	v0.RegisterPackagesEndpoint(api, "/v0", registry)
 	v0.RegisterEditEndpoints(api, "/v0", registry, cfg)
-	v0auth.RegisterAuthEndpoints(api, "/v0", cfg)
+	v0.RegisterStatusEndpoint(api, "/v0", registry, cfg)
+	v0.RegisterTagsEndpoints(api, "/v0", registry, cfg)
+	v0.RegisterAuditEndpoints(api, "/v0", registry, cfg)
+	v0.RegisterBlocksEndpoints(api, "/v0", registry, cfg)
+	v0.RegisterRemoteURLClaimsEndpoints(api, "/v0", registry, cfg)
+	v0.RegisterPackageValidationsEndpoint(api, "/v0", registry, cfg)
+	v0.RegisterWebhooksEndpoints(api, "/v0", registry, cfg)
+	v0auth.RegisterAuthEndpoints(api, "/v0", cfg, registry)
 	v0.RegisterPublishEndpoint(api, "/v0", registry, cfg)
+	v0.RegisterBatchPublishEndpoint(api, "/v0", registry, cfg)
 	v0.RegisterValidateEndpoint(api, "/v0")
 }
 
@@ -31,8 +41,18 @@ func RegisterV0_1Routes(
 	v0.RegisterPingEndpoint(api, "/v0.1")
 	v0.RegisterVersionEndpoint(api, "/v0.1", versionInfo)
 	v0.RegisterServersEndpoints(api, "/v0.1", registry)
+	v0.RegisterChangesEndpoint(api, "/v0.1", registry)
+	v0.RegisterPackagesEndpoint(api, "/v0.1", registry)
 	v0.RegisterEditEndpoints(api, "/v0.1", registry, cfg)
-	v0auth.RegisterAuthEndpoints(api, "/v0.1", cfg)
+	v0.RegisterStatusEndpoint(api, "/v0.1", registry, cfg)
+	v0.RegisterTagsEndpoints(api, "/v0.1", registry, cfg)
+	v0.RegisterAuditEndpoints(api, "/v0.1", registry, cfg)
+	v0.RegisterBlocksEndpoints(api, "/v0.1", registry, cfg)
+	v0.RegisterRemoteURLClaimsEndpoints(api, "/v0.1", registry, cfg)
+	v0.RegisterPackageValidationsEndpoint(api, "/v0.1", registry, cfg)
+	v0.RegisterWebhooksEndpoints(api, "/v0.1", registry, cfg)
+	v0auth.RegisterAuthEndpoints(api, "/v0.1", cfg, registry)
 	v0.RegisterPublishEndpoint(api, "/v0.1", registry, cfg)
+	v0.RegisterBatchPublishEndpoint(api, "/v0.1", registry, cfg)
 	v0.RegisterValidateEndpoint(api, "/v0.1")
 }
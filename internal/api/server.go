@@ -14,6 +14,7 @@ import (
 	v0 "github.com/modelcontextprotocol/registry/internal/api/handlers/v0"
 	"github.com/modelcontextprotocol/registry/internal/api/router"
 	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/ratelimit"
 	"github.com/modelcontextprotocol/registry/internal/service"
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
 )
@@ -92,12 +93,12 @@ type Server struct {
 	server   *http.Server
 }
 
-// NewServer creates a new HTTP server
-func NewServer(cfg *config.Config, registryService service.RegistryService, metrics *telemetry.Metrics, versionInfo *v0.VersionBody) *Server {
+// NewServer creates a new HTTP server, rate limiting requests with limiter unless it is nil
+func NewServer(cfg *config.Config, registryService service.RegistryService, metrics *telemetry.Metrics, versionInfo *v0.VersionBody, limiter *ratelimit.Limiter) *Server {
 	// Create HTTP mux and Huma API
 	mux := http.NewServeMux()
 
-	api := router.NewHumaAPI(cfg, registryService, mux, metrics, versionInfo)
+	api := router.NewHumaAPI(cfg, registryService, mux, metrics, versionInfo, limiter)
 
 	// Configure CORS with permissive settings for public API
 	corsHandler := cors.New(cors.Options{
@@ -109,8 +110,16 @@ func NewServer(cfg *config.Config, registryService service.RegistryService, metr
 			http.MethodDelete,
 			http.MethodOptions,
 		},
-		AllowedHeaders:   []string{"*"},
-		ExposedHeaders:   []string{"Content-Type", "Content-Length"},
+		AllowedHeaders: []string{"*"},
+		ExposedHeaders: []string{
+			"Content-Type",
+			"Content-Length",
+			"RateLimit-Limit",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
+			"RateLimit-Policy",
+			"Retry-After",
+		},
 		AllowCredentials: false, // Must be false when AllowedOrigins is "*"
 		MaxAge:           86400, // 24 hours
 	})
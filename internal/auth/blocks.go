@@ -1,9 +1,30 @@
 package auth
 
-// BlockedNamespaces contains a list of namespaces that are not allowed to publish packages.
-// This is used as a denylist mechanism to prevent abuse.
-var BlockedNamespaces = []string{
-	// Add blocked namespaces here, e.g.:
-	// "io.github.spammer",
-	// "com.evil-domain",
+import (
+	"context"
+	"strings"
+)
+
+// NamespaceBlocklist is a denylist of namespaces that are not allowed to publish packages, to prevent abuse
+type NamespaceBlocklist interface {
+	// BlockedNamespaces returns the namespaces that are currently blocked
+	BlockedNamespaces(ctx context.Context) ([]string, error)
+}
+
+// IsNamespaceBlockedBy reports whether a block of blockedNamespace applies to namespace, which is the case
+// for the namespace itself and its subdomains (com.evil-domain.api is blocked along with com.evil-domain)
+func IsNamespaceBlockedBy(namespace, blockedNamespace string) bool {
+	return namespace == blockedNamespace || strings.HasPrefix(namespace, blockedNamespace+".")
+}
+
+// isPermissionBlockedBy reports whether a permission reaches a namespace under a block of blockedNamespace.
+// A permission scoped to a namespace, such as com.example/*, is blocked with that namespace. A wildcard
+// permission, such as com.example.*, is blocked when it covers a blocked namespace, such as com.example.evil,
+// as well as when a parent of its namespace is blocked.
+func isPermissionBlockedBy(resourcePattern, blockedNamespace string) bool {
+	namespace, _, _ := strings.Cut(resourcePattern, "/")
+	if prefix, ok := strings.CutSuffix(namespace, "*"); ok {
+		return strings.HasPrefix(blockedNamespace, prefix) || IsNamespaceBlockedBy(strings.TrimSuffix(prefix, "."), blockedNamespace)
+	}
+	return IsNamespaceBlockedBy(namespace, blockedNamespace)
 }
@@ -45,6 +45,7 @@ type JWTManager struct {
 	privateKey    ed25519.PrivateKey
 	publicKey     ed25519.PublicKey
 	tokenDuration time.Duration
+	blocklist     NamespaceBlocklist
 }
 
 func NewJWTManager(cfg *config.Config) *JWTManager {
@@ -69,8 +70,13 @@ func NewJWTManager(cfg *config.Config) *JWTManager {
 	}
 }
 
+// SetBlocklist sets the namespaces that tokens are not issued for. Without one, no namespace is blocked.
+func (j *JWTManager) SetBlocklist(blocklist NamespaceBlocklist) {
+	j.blocklist = blocklist
+}
+
 // GenerateToken generates a new Registry JWT token
-func (j *JWTManager) GenerateTokenResponse(_ context.Context, claims JWTClaims) (*TokenResponse, error) {
+func (j *JWTManager) GenerateTokenResponse(ctx context.Context, claims JWTClaims) (*TokenResponse, error) {
 	// Check whether they have global permissions (used by admins)
 	hasGlobalPermissions := false
 	for _, perm := range claims.Permissions {
@@ -81,10 +87,18 @@ func (j *JWTManager) GenerateTokenResponse(_ context.Context, claims JWTClaims)
 	}
 
 	// Check permissions against denylist, provided they are not an admin
-	if !hasGlobalPermissions {
-		for _, blockedNamespace := range BlockedNamespaces {
-			if j.HasPermission(blockedNamespace+"/test", PermissionActionPublish, claims.Permissions) {
-				return nil, fmt.Errorf("your namespace is blocked. raise an issue at https://github.com/modelcontextprotocol/registry/ if you think this is a mistake")
+	if !hasGlobalPermissions && j.blocklist != nil {
+		blockedNamespaces, err := j.blocklist.BlockedNamespaces(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check blocked namespaces: %w", err)
+		}
+		// Permissions on subdomains of a blocked namespace are blocked too, as they are for publishing, and
+		// so are wildcard permissions covering a blocked namespace
+		for _, perm := range claims.Permissions {
+			for _, blockedNamespace := range blockedNamespaces {
+				if isPermissionBlockedBy(perm.ResourcePattern, blockedNamespace) {
+					return nil, fmt.Errorf("your namespace is blocked. raise an issue at https://github.com/modelcontextprotocol/registry/ if you think this is a mistake")
+				}
 			}
 		}
 	}
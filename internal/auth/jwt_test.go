@@ -5,6 +5,7 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"testing"
 	"time"
 
@@ -283,6 +284,20 @@ func TestNewJWTManager_InvalidKeySize(t *testing.T) {
 	})
 }
 
+// staticBlocklist blocks a fixed list of namespaces
+type staticBlocklist []string
+
+func (b staticBlocklist) BlockedNamespaces(_ context.Context) ([]string, error) {
+	return b, nil
+}
+
+// failingBlocklist cannot tell which namespaces are blocked
+type failingBlocklist struct{}
+
+func (failingBlocklist) BlockedNamespaces(_ context.Context) ([]string, error) {
+	return nil, errors.New("database unavailable")
+}
+
 func TestJWTManager_BlockedNamespaces(t *testing.T) {
 	// Generate a proper Ed25519 seed for testing
 	testSeed := make([]byte, ed25519.SeedSize)
@@ -296,12 +311,8 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("blocked namespace should deny token", func(t *testing.T) {
-		// Temporarily override blocked namespaces for testing
-		originalBlocked := auth.BlockedNamespaces
-		auth.BlockedNamespaces = []string{"io.github.spammer"}
-		defer func() { auth.BlockedNamespaces = originalBlocked }()
-
 		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"io.github.spammer"})
 
 		claims := auth.JWTClaims{
 			AuthMethod:        auth.MethodGitHubAT,
@@ -321,12 +332,8 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 	})
 
 	t.Run("non-blocked namespace should allow token", func(t *testing.T) {
-		// Temporarily override blocked namespaces for testing
-		originalBlocked := auth.BlockedNamespaces
-		auth.BlockedNamespaces = []string{"io.github.spammer"}
-		defer func() { auth.BlockedNamespaces = originalBlocked }()
-
 		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"io.github.spammer"})
 
 		claims := auth.JWTClaims{
 			AuthMethod:        auth.MethodGitHubAT,
@@ -345,12 +352,8 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 	})
 
 	t.Run("multiple permissions with one blocked should deny token", func(t *testing.T) {
-		// Temporarily override blocked namespaces for testing
-		originalBlocked := auth.BlockedNamespaces
-		auth.BlockedNamespaces = []string{"io.github.badorg"}
-		defer func() { auth.BlockedNamespaces = originalBlocked }()
-
 		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"io.github.badorg"})
 
 		claims := auth.JWTClaims{
 			AuthMethod:        auth.MethodGitHubAT,
@@ -373,13 +376,53 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 		assert.Nil(t, tokenResponse)
 	})
 
-	t.Run("global admin permissions should bypass denylist", func(t *testing.T) {
-		// Temporarily override blocked namespaces for testing
-		originalBlocked := auth.BlockedNamespaces
-		auth.BlockedNamespaces = []string{"io.github.spammer"}
-		defer func() { auth.BlockedNamespaces = originalBlocked }()
+	t.Run("permissions on subdomains of a blocked namespace should deny token", func(t *testing.T) {
+		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"com.evil"})
+
+		for _, pattern := range []string{"com.evil/*", "com.evil.*", "com.evil.api/*", "com.evil.api/weather"} {
+			claims := auth.JWTClaims{
+				AuthMethod:        auth.MethodDNS,
+				AuthMethodSubject: "evil.com",
+				Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: pattern}},
+			}
+
+			tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, claims)
+			require.Error(t, err, pattern)
+			assert.Nil(t, tokenResponse)
+		}
+
+		claims := auth.JWTClaims{
+			AuthMethod:        auth.MethodDNS,
+			AuthMethodSubject: "evilcorp.com",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "com.evilcorp/*"}},
+		}
+		_, err := jwtManager.GenerateTokenResponse(ctx, claims)
+		require.NoError(t, err)
+	})
+
+	t.Run("wildcard permissions covering a blocked namespace should deny token", func(t *testing.T) {
+		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"io.github.evil"})
+
+		claims := auth.JWTClaims{
+			AuthMethod:        auth.MethodDNS,
+			AuthMethodSubject: "github.io",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.*"}},
+		}
+		tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, claims)
+		require.Error(t, err)
+		assert.Nil(t, tokenResponse)
+
+		// Wildcards that do not reach the blocked namespace are not affected
+		claims.Permissions = []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.evilcorp.*"}}
+		_, err = jwtManager.GenerateTokenResponse(ctx, claims)
+		require.NoError(t, err)
+	})
 
+	t.Run("global admin permissions should bypass denylist", func(t *testing.T) {
 		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(staticBlocklist{"io.github.spammer"})
 
 		claims := auth.JWTClaims{
 			AuthMethod:        auth.MethodNone,
@@ -396,4 +439,24 @@ func TestJWTManager_BlockedNamespaces(t *testing.T) {
 		require.NoError(t, err)
 		assert.NotEmpty(t, tokenResponse.RegistryToken)
 	})
+
+	t.Run("tokens are not issued when the blocklist cannot be read", func(t *testing.T) {
+		jwtManager := auth.NewJWTManager(cfg)
+		jwtManager.SetBlocklist(failingBlocklist{})
+
+		claims := auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "gooduser",
+			Permissions: []auth.Permission{
+				{
+					Action:          auth.PermissionActionPublish,
+					ResourcePattern: "io.github.gooduser/*",
+				},
+			},
+		}
+
+		tokenResponse, err := jwtManager.GenerateTokenResponse(ctx, claims)
+		assert.Error(t, err)
+		assert.Nil(t, tokenResponse)
+	})
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
@@ -9,6 +11,10 @@ import (
 type Config struct {
 	ServerAddress            string `env:"SERVER_ADDRESS" envDefault:":8080"`
 	DatabaseURL              string `env:"DATABASE_URL" envDefault:"postgres://localhost:5432/mcp-registry?sslmode=disable"`
+	DatabaseReplicaURL       string `env:"DATABASE_REPLICA_URL" envDefault:""`
+	DatabaseMaxConns         int32  `env:"DATABASE_MAX_CONNS" envDefault:"30"`
+	DatabaseReplicaMaxConns  int32  `env:"DATABASE_REPLICA_MAX_CONNS" envDefault:"30"`
+	MigrateOnStartup         bool   `env:"MIGRATE_ON_STARTUP" envDefault:"true"`
 	SeedFrom                 string `env:"SEED_FROM" envDefault:""`
 	Version                  string `env:"VERSION" envDefault:"dev"`
 	GithubClientID           string `env:"GITHUB_CLIENT_ID" envDefault:""`
@@ -16,6 +22,38 @@ type Config struct {
 	JWTPrivateKey            string `env:"JWT_PRIVATE_KEY" envDefault:""`
 	EnableAnonymousAuth      bool   `env:"ENABLE_ANONYMOUS_AUTH" envDefault:"false"`
 	EnableRegistryValidation bool   `env:"ENABLE_REGISTRY_VALIDATION" envDefault:"true"`
+	CursorSecret             string `env:"CURSOR_SECRET" envDefault:""`
+
+	// Plain cursors from before cursors were signed
+	CursorLegacyUntil time.Time `env:"CURSOR_LEGACY_UNTIL" envDefault:"2027-01-01T00:00:00Z"`
+
+	// Webhook delivery
+	EnableWebhookDelivery bool          `env:"ENABLE_WEBHOOK_DELIVERY" envDefault:"true"`
+	WebhookMaxAttempts    int           `env:"WEBHOOK_MAX_ATTEMPTS" envDefault:"8"`
+	WebhookPollInterval   time.Duration `env:"WEBHOOK_POLL_INTERVAL" envDefault:"5s"`
+
+	// Package ownership revalidation
+	EnablePackageRevalidation         bool          `env:"ENABLE_PACKAGE_REVALIDATION" envDefault:"false"`
+	PackageRevalidationInterval       time.Duration `env:"PACKAGE_REVALIDATION_INTERVAL" envDefault:"24h"`
+	PackageRevalidationConcurrency    int           `env:"PACKAGE_REVALIDATION_CONCURRENCY" envDefault:"4"`
+	PackageRevalidationDeprecateAfter int           `env:"PACKAGE_REVALIDATION_DEPRECATE_AFTER" envDefault:"0"`
+
+	// Remote health probing
+	EnableRemoteProbing    bool          `env:"ENABLE_REMOTE_PROBING" envDefault:"false"`
+	RemoteProbeInterval    time.Duration `env:"REMOTE_PROBE_INTERVAL" envDefault:"15m"`
+	RemoteProbeConcurrency int           `env:"REMOTE_PROBE_CONCURRENCY" envDefault:"8"`
+	RemoteProbeTimeout     time.Duration `env:"REMOTE_PROBE_TIMEOUT" envDefault:"10s"`
+
+	// Capability introspection
+	EnableCapabilityIntrospection      bool          `env:"ENABLE_CAPABILITY_INTROSPECTION" envDefault:"false"`
+	CapabilityIntrospectionInterval    time.Duration `env:"CAPABILITY_INTROSPECTION_INTERVAL" envDefault:"24h"`
+	CapabilityIntrospectionConcurrency int           `env:"CAPABILITY_INTROSPECTION_CONCURRENCY" envDefault:"4"`
+	CapabilityIntrospectionTimeout     time.Duration `env:"CAPABILITY_INTROSPECTION_TIMEOUT" envDefault:"30s"`
+
+	// Rate limiting
+	RateLimits                string `env:"RATE_LIMITS" envDefault:""`
+	RateLimitStore            string `env:"RATE_LIMIT_STORE" envDefault:"memory"`
+	RateLimitTrustedProxyHops int    `env:"RATE_LIMIT_TRUSTED_PROXY_HOPS" envDefault:"0"`
 
 	// OIDC Configuration
 	OIDCEnabled      bool   `env:"OIDC_ENABLED" envDefault:"false"`
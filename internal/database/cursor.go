@@ -0,0 +1,131 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// serverSort is the resolved ordering of a server listing
+type serverSort struct {
+	by   string // one of the SortBy constants
+	desc bool
+}
+
+// resolveServerSort validates the sort options of a filter, applying the name ascending default
+func resolveServerSort(filter *ServerFilter) (serverSort, error) {
+	sort := serverSort{by: SortByName}
+	if filter == nil {
+		return sort, nil
+	}
+
+	switch filter.SortBy {
+	case "", SortByName:
+	case SortByPublishedAt, SortByUpdatedAt:
+		sort.by = filter.SortBy
+	default:
+		return sort, fmt.Errorf("%w: unknown sort key %q", ErrInvalidInput, filter.SortBy)
+	}
+
+	switch filter.SortOrder {
+	case "", SortOrderAsc:
+	case SortOrderDesc:
+		sort.desc = true
+	default:
+		return sort, fmt.Errorf("%w: unknown sort order %q", ErrInvalidInput, filter.SortOrder)
+	}
+
+	return sort, nil
+}
+
+// order returns the sort direction as a string, as stored in cursors
+func (s serverSort) order() string {
+	if s.desc {
+		return SortOrderDesc
+	}
+	return SortOrderAsc
+}
+
+// listCursor is the position of the last server on a page of a sorted listing.
+// Server name and version break ties between servers that share a timestamp.
+type listCursor struct {
+	Sort    string     `json:"s"`
+	Order   string     `json:"o"`
+	Time    *time.Time `json:"t,omitempty"` // value of the sort column for timestamp sorts
+	Name    string     `json:"n"`
+	Version string     `json:"v"`
+}
+
+// formatListCursor encodes the cursor for the page ending at the given server
+func formatListCursor(sort serverSort, sortTime time.Time, serverName, version string) string {
+	cursor := listCursor{Sort: sort.by, Order: sort.order(), Name: serverName, Version: version}
+	if sort.by != SortByName {
+		cursor.Time = &sortTime
+	}
+
+	// Marshalling a struct of strings and times cannot fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// NameListPosition returns the cursor for the page ending at a server version in the default name order.
+// Without a version, the page ends after every version of the server.
+func NameListPosition(serverName, version string) string {
+	return formatListCursor(serverSort{by: SortByName}, time.Time{}, serverName, version)
+}
+
+// parseListCursor decodes a cursor produced by formatListCursor, checking it was issued for the same ordering
+func parseListCursor(cursor string, sort serverSort) (listCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	var parsed listCursor
+	if err != nil || json.Unmarshal(data, &parsed) != nil {
+		return listCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	if parsed.Sort != sort.by || parsed.Order != sort.order() {
+		return listCursor{}, fmt.Errorf("%w: cursor was issued for a different sort order", ErrInvalidInput)
+	}
+	if sort.by != SortByName && parsed.Time == nil {
+		return listCursor{}, fmt.Errorf("%w: malformed cursor", ErrInvalidInput)
+	}
+
+	return parsed, nil
+}
+
+// formatSearchCursor builds the cursor for full-text search results, which are ordered by
+// relevance first and then by server name and version to break ties
+func formatSearchCursor(rank float64, serverName, version string) string {
+	return strconv.FormatFloat(rank, 'g', -1, 64) + ":" + serverName + ":" + version
+}
+
+// parseSearchCursor parses a cursor produced by formatSearchCursor
+func parseSearchCursor(cursor string) (float64, string, string, error) {
+	parts := strings.SplitN(cursor, ":", 3)
+	if len(parts) != 3 {
+		return 0, "", "", fmt.Errorf("%w: malformed search cursor", ErrInvalidInput)
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return 0, "", "", fmt.Errorf("%w: malformed search cursor", ErrInvalidInput)
+	}
+
+	return rank, parts[1], parts[2], nil
+}
+
+// formatAuditCursor builds the cursor for audit log results, which are ordered by descending ID
+func formatAuditCursor(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// parseAuditCursor parses a cursor produced by formatAuditCursor
+func parseAuditCursor(cursor string) (int64, error) {
+	id, err := strconv.ParseInt(cursor, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("%w: malformed audit cursor", ErrInvalidInput)
+	}
+	return id, nil
+}
@@ -16,47 +16,268 @@ var (
 	ErrInvalidInput      = errors.New("invalid input")
 	ErrDatabase          = errors.New("database error")
 	ErrInvalidVersion    = errors.New("invalid version: cannot publish duplicate version")
+	ErrNamespaceBlocked  = errors.New("namespace is blocked")
 	ErrMaxServersReached = errors.New("maximum number of versions for this server reached (10000): please reach out at https://github.com/modelcontextprotocol/registry to explain your use case")
 )
 
 // ServerFilter defines filtering options for server queries
 type ServerFilter struct {
-	Name          *string    // for finding versions of same server
-	RemoteURL     *string    // for duplicate URL detection
-	UpdatedSince  *time.Time // for incremental sync filtering
-	SubstringName *string    // for substring search on name
-	Version       *string    // for exact version matching
-	IsLatest      *bool      // for filtering latest versions only
+	Name            *string    // for finding versions of same server
+	RemoteURL       *string    // for duplicate URL detection
+	UpdatedSince    *time.Time // for incremental sync filtering
+	SubstringName   *string    // for substring search on name
+	Version         *string    // for exact version matching
+	IsLatest        *bool      // for filtering latest versions only
+	Tag             *string    // for versions a dist-tag currently points at, such as TagLatest
+	Query           *string    // for ranked full-text search on name, title and description
+	RegistryType    *string    // for servers with at least one package from this registry (npm, pypi, ...)
+	PackageID       *string    // for reverse lookup of servers shipping a package, matched within RegistryType when set
+	PackageVersion  *string    // for narrowing a PackageID lookup to one package version
+	TransportType   *string    // for servers with a package transport or remote of this type (stdio, sse, ...)
+	Status          *string    // for servers in exactly this lifecycle status
+	HideDeprecated  bool       // for excluding deprecated servers regardless of Status
+	NamespacePrefix *string    // for servers whose name starts with this prefix
+	PublishedSince  *time.Time // for servers published at or after this time
+	PublishedBefore *time.Time // for servers published before this time
+	Capability      *string    // for versions with a recorded tool, prompt or resource of this name, or resource of this URI
+	SortBy          string     // ordering rather than filtering: one of the SortBy constants, name when empty (relevance when searching)
+	SortOrder       string     // SortOrderAsc (default) or SortOrderDesc
+}
+
+// Server list sort keys and directions
+const (
+	SortByName        = "name"
+	SortByPublishedAt = "published_at"
+	SortByUpdatedAt   = "updated_at"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+)
+
+// TagLatest is the dist-tag followed by GetServerByName and version=latest listings. It is kept in
+// sync with the isLatest flag, and is the only tag that always exists once a server is published.
+const TagLatest = "latest"
+
+// Audit event actions recorded for registry mutations
+const (
+	AuditActionPublish      = "publish"
+	AuditActionEdit         = "edit"
+	AuditActionStatusChange = "status_change"
+	AuditActionTagSet       = "tag_set"
+	AuditActionTagDelete    = "tag_delete"
+	// The actions below do not concern a server version, so their events leave the version empty, and
+	// the server name too except for remote URL releases, which name the server that owned the URL
+	AuditActionNamespaceBlock     = "namespace_block"
+	AuditActionNamespaceUnblock   = "namespace_unblock"
+	AuditActionRemoteURLRelease   = "remote_url_release"
+	AuditActionWebhookSubscribe   = "webhook_subscribe"
+	AuditActionWebhookUnsubscribe = "webhook_unsubscribe"
+)
+
+// auditActionConcernsVersion reports whether events of an audit action must name a server version
+func auditActionConcernsVersion(action string) bool {
+	switch action {
+	case AuditActionPublish, AuditActionEdit, AuditActionStatusChange, AuditActionTagSet, AuditActionTagDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Change feed event types
+const (
+	ChangeTypePublish      = "publish"
+	ChangeTypeEdit         = "edit"
+	ChangeTypeStatusChange = "status_change"
+	ChangeTypeLatestChange = "latest_change"
+)
+
+// Webhook event types
+const (
+	WebhookEventPublished  = "server.published"
+	WebhookEventUpdated    = "server.updated"
+	WebhookEventDeprecated = "server.deprecated"
+	WebhookEventDeleted    = "server.deleted"
+)
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookJob is a claimed webhook delivery together with what is needed to send it
+type WebhookJob struct {
+	Delivery apiv0.WebhookDelivery
+	URL      string
+	Secret   string
+	Payload  []byte
+}
+
+// WebhookAttempt is the outcome of one attempt at sending a webhook delivery
+type WebhookAttempt struct {
+	Status        string    // WebhookDeliveryDelivered, WebhookDeliveryDead, or WebhookDeliveryPending to retry
+	StatusCode    int       // HTTP status of the response, 0 when there was none
+	Error         string    // why the attempt failed, empty when it was delivered
+	NextAttemptAt time.Time // when to retry a pending delivery
+}
+
+// AuditEventFilter defines filtering options for audit log queries
+type AuditEventFilter struct {
+	ServerName   *string    // for the history of a single server
+	ActorSubject *string    // for everything done by one actor
+	Action       *string    // for a single kind of mutation
+	Since        *time.Time // for events created at or after this time
+	Until        *time.Time // for events created before this time
+}
+
+// RemoteURLClaimFilter defines filtering options for remote URL claim queries
+type RemoteURLClaimFilter struct {
+	URL        *string // for the claim on a single canonical URL
+	ServerName *string // for the claims of a single server
+}
+
+// PackageValidationFilter defines filtering options for package validation queries
+type PackageValidationFilter struct {
+	ServerName  *string // for the packages of a single server
+	FailingOnly bool    // for packages whose latest check failed
+}
+
+// RemoteProbe is the outcome of probing a remote of a server version
+type RemoteProbe struct {
+	ServerName string
+	Version    string
+	apiv0.RemoteHealth
+}
+
+// RemoteProbeFilter defines filtering options for remote probe queries
+type RemoteProbeFilter struct {
+	ServerNames []string // for the remotes of these servers
+}
+
+// ServerCapabilitiesFilter defines filtering options for server capabilities queries
+type ServerCapabilitiesFilter struct {
+	ServerName *string // for the versions of a single server
 }
 
 // Database defines the interface for database operations
 type Database interface {
-	// CreateServer inserts a new server version with official metadata
+	// CreateServer inserts a new server version with official metadata, tagging it as latest when it is the latest version
 	CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *apiv0.ServerJSON, officialMeta *apiv0.RegistryExtensions) (*apiv0.ServerResponse, error)
 	// UpdateServer updates an existing server record
 	UpdateServer(ctx context.Context, tx pgx.Tx, serverName, version string, serverJSON *apiv0.ServerJSON) (*apiv0.ServerResponse, error)
-	// SetServerStatus updates the status of a specific server version
+	// SetServerStatus updates the status of a specific server version. Reactivating a version clears its
+	// deprecation message and successor.
 	SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status string) (*apiv0.ServerResponse, error)
+	// DeprecateServer marks a server version as deprecated with an optional message and successor server name
+	DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, message, replacedBy string) (*apiv0.ServerResponse, error)
 	// ListServers retrieve server entries with optional filtering
 	ListServers(ctx context.Context, tx pgx.Tx, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
+	// ListServersRaw is ListServers for responses that are only written out: each server document is
+	// returned in RawServer as stored instead of being decoded, and only Server.Name and Server.Version are set
+	ListServersRaw(ctx context.Context, tx pgx.Tx, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
 	// GetServerByName retrieve a single server by its name
 	GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error)
 	// GetServerByNameAndVersion retrieve specific version of a server by server name and version
 	GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx, serverName string, version string) (*apiv0.ServerResponse, error)
 	// GetAllVersionsByServerName retrieve all versions of a server by server name
 	GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error)
+	// GetAllVersionsByServerNameRaw is GetAllVersionsByServerName without decoding the server documents, see ListServersRaw
+	GetAllVersionsByServerNameRaw(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error)
 	// GetCurrentLatestVersion retrieve the current latest version of a server by server name
 	GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error)
 	// CountServerVersions count the number of versions for a server
 	CountServerVersions(ctx context.Context, tx pgx.Tx, serverName string) (int, error)
 	// CheckVersionExists check if a specific version exists for a server
 	CheckVersionExists(ctx context.Context, tx pgx.Tx, serverName, version string) (bool, error)
-	// UnmarkAsLatest marks the current latest version of a server as no longer latest
+	// UnmarkAsLatest marks the current latest version of a server as no longer latest and removes its latest tag
 	UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName string) error
+	// GetServerByNameAndTag retrieve the version of a server that a dist-tag points at
+	GetServerByNameAndTag(ctx context.Context, tx pgx.Tx, serverName, tag string) (*apiv0.ServerResponse, error)
+	// ListServerTags retrieve the dist-tags of a server, ordered by tag name
+	ListServerTags(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerTag, error)
+	// SetServerTag creates a dist-tag or moves it to another existing version. Moving TagLatest also
+	// moves the isLatest flag.
+	SetServerTag(ctx context.Context, tx pgx.Tx, serverName, tag, version string) (*apiv0.ServerTag, error)
+	// DeleteServerTag removes a dist-tag
+	DeleteServerTag(ctx context.Context, tx pgx.Tx, serverName, tag string) error
+	// CreateAuditEvent appends an event to the audit log, assigning its ID and timestamp
+	CreateAuditEvent(ctx context.Context, tx pgx.Tx, event *apiv0.AuditEvent) error
+	// ListAuditEvents retrieve audit events, newest first, with optional filtering
+	ListAuditEvents(ctx context.Context, tx pgx.Tx, filter *AuditEventFilter, cursor string, limit int) ([]*apiv0.AuditEvent, string, error)
+	// CreateChangeEvent appends an event to the change feed, assigning its sequence number and timestamp by the
+	// time the transaction commits. Sequence numbers are assigned in commit order, so the event must be created
+	// inside the mutating transaction.
+	CreateChangeEvent(ctx context.Context, tx pgx.Tx, event *apiv0.ChangeEvent) error
+	// ListChangeEvents retrieve change events after the given sequence number, in sequence order
+	ListChangeEvents(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]*apiv0.ChangeEvent, error)
+	// CreateWebhookSubscription stores a webhook subscription with its secret, assigning its ID and creation time
+	CreateWebhookSubscription(ctx context.Context, tx pgx.Tx, subscription *apiv0.WebhookSubscription) error
+	// ListWebhookSubscriptions retrieve all webhook subscriptions in ID order, without their secrets
+	ListWebhookSubscriptions(ctx context.Context, tx pgx.Tx) ([]*apiv0.WebhookSubscription, error)
+	// GetWebhookSubscription retrieve a webhook subscription without its secret
+	GetWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) (*apiv0.WebhookSubscription, error)
+	// DeleteWebhookSubscription removes a webhook subscription together with its deliveries
+	DeleteWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) error
+	// CreateWebhookDelivery queues an event for a subscription, to be attempted right away. It assigns the
+	// delivery's ID, status and timestamps, and must be called inside the mutating transaction.
+	CreateWebhookDelivery(ctx context.Context, tx pgx.Tx, delivery *apiv0.WebhookDelivery, payload []byte) error
+	// ListWebhookDeliveries retrieve the deliveries of a subscription, newest first, optionally only those with a status
+	ListWebhookDeliveries(ctx context.Context, tx pgx.Tx, subscriptionID int64, status *string, limit int) ([]*apiv0.WebhookDelivery, error)
+	// ClaimWebhookDeliveries leases up to limit pending deliveries that are due, so that no other worker
+	// sends them until the lease expires or the attempt is recorded
+	ClaimWebhookDeliveries(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]*WebhookJob, error)
+	// RecordWebhookAttempt counts an attempt at a delivery and stores its outcome
+	RecordWebhookAttempt(ctx context.Context, tx pgx.Tx, deliveryID int64, attempt *WebhookAttempt) error
+	// RedeliverWebhookDelivery makes a delivery pending again with a fresh set of attempts
+	RedeliverWebhookDelivery(ctx context.Context, tx pgx.Tx, deliveryID int64) (*apiv0.WebhookDelivery, error)
+	// SetNamespaceBlock blocks a namespace, replacing any existing block of it
+	SetNamespaceBlock(ctx context.Context, tx pgx.Tx, block *apiv0.NamespaceBlock) error
+	// ListNamespaceBlocks retrieve namespace blocks in namespace order, including those that have expired if requested
+	ListNamespaceBlocks(ctx context.Context, tx pgx.Tx, includeExpired bool) ([]*apiv0.NamespaceBlock, error)
+	// DeleteNamespaceBlock unblocks a namespace
+	DeleteNamespaceBlock(ctx context.Context, tx pgx.Tx, namespace string) error
+	// ClaimRemoteURLs claims canonical remote URLs for a server, leaving claims held by other servers in place.
+	// It returns the URLs that other servers hold, mapped to the name of the server holding each.
+	ClaimRemoteURLs(ctx context.Context, tx pgx.Tx, serverName string, urls []string) (map[string]string, error)
+	// ReleaseRemoteURLClaims releases the claims of a server on every URL not in keep
+	ReleaseRemoteURLClaims(ctx context.Context, tx pgx.Tx, serverName string, keep []string) error
+	// ListRemoteURLClaims retrieve remote URL claims in URL order
+	ListRemoteURLClaims(ctx context.Context, tx pgx.Tx, filter *RemoteURLClaimFilter) ([]*apiv0.RemoteURLClaim, error)
+	// DeleteRemoteURLClaim releases the claim on a canonical URL, whichever server holds it
+	DeleteRemoteURLClaim(ctx context.Context, tx pgx.Tx, url string) error
+	// RecordPackageValidation records the outcome of checking a package, counting consecutive failures from
+	// the previous outcome. Inconclusive failures keep the previous count. It fills in the failure count and
+	// last pass of validation.
+	RecordPackageValidation(ctx context.Context, tx pgx.Tx, validation *apiv0.PackageValidation) error
+	// ListPackageValidations retrieve package validations ordered by server name, version, registry type and identifier
+	ListPackageValidations(ctx context.Context, tx pgx.Tx, filter *PackageValidationFilter) ([]*apiv0.PackageValidation, error)
+	// DeletePackageValidationsCheckedBefore removes package validations last checked before the given time
+	DeletePackageValidationsCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error
+	// RecordRemoteProbe records the outcome of probing a remote. Failed probes keep the protocol version, latency
+	// and success time of the last probe that succeeded, which are filled in on probe.
+	RecordRemoteProbe(ctx context.Context, tx pgx.Tx, probe *RemoteProbe) error
+	// ListRemoteProbes retrieve remote probes ordered by server name, version and URL
+	ListRemoteProbes(ctx context.Context, tx pgx.Tx, filter *RemoteProbeFilter) ([]*RemoteProbe, error)
+	// DeleteRemoteProbesCheckedBefore removes remote probes last checked before the given time
+	DeleteRemoteProbesCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error
+	// RecordServerCapabilities records the capabilities of a server version. Failed introspections keep the
+	// capabilities and update time of the last one that succeeded, which are filled in on capabilities.
+	RecordServerCapabilities(ctx context.Context, tx pgx.Tx, capabilities *apiv0.ServerCapabilities) error
+	// GetServerCapabilities retrieves the capabilities recorded for a server version
+	GetServerCapabilities(ctx context.Context, tx pgx.Tx, serverName, version string) (*apiv0.ServerCapabilities, error)
+	// ListServerCapabilities retrieve server capabilities ordered by server name and version
+	ListServerCapabilities(ctx context.Context, tx pgx.Tx, filter *ServerCapabilitiesFilter) ([]*apiv0.ServerCapabilities, error)
+	// TakeRateLimitToken refills the token bucket for key, which is shared by every replica, and takes a token
+	// from it if one is available. It returns the tokens left in the bucket and whether a token was taken.
+	TakeRateLimitToken(ctx context.Context, tx pgx.Tx, key string, capacity, refillPerSecond float64) (float64, bool, error)
+	// DeleteIdleRateLimitBuckets removes token buckets last taken from before the given time
+	DeleteIdleRateLimitBuckets(ctx context.Context, tx pgx.Tx, before time.Time) error
 	// AcquirePublishLock acquires an exclusive advisory lock for publishing a server
 	// This prevents race conditions when multiple versions are published concurrently
 	AcquirePublishLock(ctx context.Context, tx pgx.Tx, serverName string) error
-	// InTransaction executes a function within a database transaction
+	// InTransaction executes a function within a database transaction, which may be retried after transient failures
 	InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error
 	// Close closes the database connection
 	Close() error
@@ -0,0 +1,2155 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// MemoryURL is the DATABASE_URL value that selects the in-memory database
+const MemoryURL = "memory://"
+
+// errMemoryTxSQL is returned when SQL is executed against an in-memory transaction
+var errMemoryTxSQL = errors.New("SQL execution is not supported by the in-memory database")
+
+// ErrNestedTransaction is returned by the in-memory database when a transaction, or a write without one, is
+// started while the context already belongs to an open transaction. PostgreSQL would run it on another
+// connection; the in-memory database serializes transactions and would deadlock, so the open transaction
+// must be passed instead.
+var ErrNestedTransaction = errors.New("cannot start a transaction while another one is open; pass the open transaction instead")
+
+// memoryTxKey marks the contexts passed to InTransaction callbacks with the transaction they run in
+type memoryTxKey struct{}
+
+// IsMemoryURL reports whether the connection URI selects the in-memory database
+func IsMemoryURL(connectionURI string) bool {
+	return strings.HasPrefix(connectionURI, MemoryURL)
+}
+
+// Memory is an in-memory implementation of the Database interface.
+// It is intended for tests and local development, and keeps no data across restarts.
+type Memory struct {
+	// mu guards state, which always holds the last committed snapshot
+	mu    sync.RWMutex
+	state *memoryState
+	// txMu serializes transactions, standing in for PostgreSQL row and advisory locks
+	txMu sync.Mutex
+}
+
+// serverKey is the natural key of a server version, mirroring the servers primary key
+type serverKey struct {
+	name    string
+	version string
+}
+
+// serverTagKey is the natural key of a dist-tag, mirroring the server_tags primary key
+type serverTagKey struct {
+	name string
+	tag  string
+}
+
+// packageValidationKey is the natural key of a package validation, mirroring the package_validations primary key
+type packageValidationKey struct {
+	name         string
+	version      string
+	registryType string
+	identifier   string
+}
+
+// remoteProbeKey is the natural key of a remote probe, mirroring the remote_probes primary key
+type remoteProbeKey struct {
+	name    string
+	version string
+	url     string
+}
+
+// memoryState holds all tables of the in-memory database
+type memoryState struct {
+	servers     map[serverKey]serverRow
+	tags        map[serverTagKey]apiv0.ServerTag
+	auditEvents []apiv0.AuditEvent // in ID order
+	lastAuditID int64
+	changes     []apiv0.ChangeEvent // in sequence order, without the server snapshot
+	lastChange  int64
+
+	webhookSubscriptions    map[int64]apiv0.WebhookSubscription // including their secrets
+	lastWebhookSubscription int64
+	webhookDeliveries       map[int64]webhookDeliveryRow
+	lastWebhookDelivery     int64
+
+	namespaceBlocks  map[string]apiv0.NamespaceBlock
+	remoteURLClaims  map[string]apiv0.RemoteURLClaim
+	rateLimitBuckets map[string]rateLimitBucket
+
+	packageValidations map[packageValidationKey]apiv0.PackageValidation
+	remoteProbes       map[remoteProbeKey]RemoteProbe
+	serverCapabilities map[serverKey]apiv0.ServerCapabilities
+}
+
+// rateLimitBucket is a stored token bucket
+type rateLimitBucket struct {
+	tokens    float64
+	updatedAt time.Time
+}
+
+// webhookDeliveryRow is a stored webhook delivery. The next attempt is kept even once the delivery
+// is no longer pending, like the next_attempt_at column.
+type webhookDeliveryRow struct {
+	delivery      apiv0.WebhookDelivery
+	nextAttemptAt time.Time
+	payload       []byte
+}
+
+// toWebhookDelivery returns the delivery as exposed by the API
+func (r webhookDeliveryRow) toWebhookDelivery() *apiv0.WebhookDelivery {
+	delivery := r.delivery
+	if delivery.Status == WebhookDeliveryPending {
+		nextAttemptAt := r.nextAttemptAt
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+	return &delivery
+}
+
+// clone returns a copy of the state that can be modified without affecting the original.
+// Rows are treated as immutable values, so a shallow copy of each table is sufficient.
+func (s *memoryState) clone() *memoryState {
+	servers := make(map[serverKey]serverRow, len(s.servers))
+	for k, v := range s.servers {
+		servers[k] = v
+	}
+	tags := make(map[serverTagKey]apiv0.ServerTag, len(s.tags))
+	for k, v := range s.tags {
+		tags[k] = v
+	}
+	auditEvents := make([]apiv0.AuditEvent, len(s.auditEvents))
+	copy(auditEvents, s.auditEvents)
+	changes := make([]apiv0.ChangeEvent, len(s.changes))
+	copy(changes, s.changes)
+	webhookSubscriptions := make(map[int64]apiv0.WebhookSubscription, len(s.webhookSubscriptions))
+	for k, v := range s.webhookSubscriptions {
+		webhookSubscriptions[k] = v
+	}
+	webhookDeliveries := make(map[int64]webhookDeliveryRow, len(s.webhookDeliveries))
+	for k, v := range s.webhookDeliveries {
+		webhookDeliveries[k] = v
+	}
+	namespaceBlocks := make(map[string]apiv0.NamespaceBlock, len(s.namespaceBlocks))
+	for k, v := range s.namespaceBlocks {
+		namespaceBlocks[k] = v
+	}
+	remoteURLClaims := make(map[string]apiv0.RemoteURLClaim, len(s.remoteURLClaims))
+	for k, v := range s.remoteURLClaims {
+		remoteURLClaims[k] = v
+	}
+	rateLimitBuckets := make(map[string]rateLimitBucket, len(s.rateLimitBuckets))
+	for k, v := range s.rateLimitBuckets {
+		rateLimitBuckets[k] = v
+	}
+	packageValidations := make(map[packageValidationKey]apiv0.PackageValidation, len(s.packageValidations))
+	for k, v := range s.packageValidations {
+		packageValidations[k] = v
+	}
+	remoteProbes := make(map[remoteProbeKey]RemoteProbe, len(s.remoteProbes))
+	for k, v := range s.remoteProbes {
+		remoteProbes[k] = v
+	}
+	serverCapabilities := make(map[serverKey]apiv0.ServerCapabilities, len(s.serverCapabilities))
+	for k, v := range s.serverCapabilities {
+		serverCapabilities[k] = v
+	}
+	return &memoryState{
+		servers:                 servers,
+		tags:                    tags,
+		auditEvents:             auditEvents,
+		lastAuditID:             s.lastAuditID,
+		changes:                 changes,
+		lastChange:              s.lastChange,
+		webhookSubscriptions:    webhookSubscriptions,
+		lastWebhookSubscription: s.lastWebhookSubscription,
+		webhookDeliveries:       webhookDeliveries,
+		lastWebhookDelivery:     s.lastWebhookDelivery,
+		namespaceBlocks:         namespaceBlocks,
+		remoteURLClaims:         remoteURLClaims,
+		rateLimitBuckets:        rateLimitBuckets,
+		packageValidations:      packageValidations,
+		remoteProbes:            remoteProbes,
+		serverCapabilities:      serverCapabilities,
+	}
+}
+
+// NewMemory creates a new, empty in-memory database
+func NewMemory() *Memory {
+	return &Memory{
+		state: &memoryState{
+			servers:              make(map[serverKey]serverRow),
+			tags:                 make(map[serverTagKey]apiv0.ServerTag),
+			webhookSubscriptions: make(map[int64]apiv0.WebhookSubscription),
+			webhookDeliveries:    make(map[int64]webhookDeliveryRow),
+			namespaceBlocks:      make(map[string]apiv0.NamespaceBlock),
+			remoteURLClaims:      make(map[string]apiv0.RemoteURLClaim),
+			rateLimitBuckets:     make(map[string]rateLimitBucket),
+			packageValidations:   make(map[packageValidationKey]apiv0.PackageValidation),
+			remoteProbes:         make(map[remoteProbeKey]RemoteProbe),
+			serverCapabilities:   make(map[serverKey]apiv0.ServerCapabilities),
+		},
+	}
+}
+
+// read returns the state visible to the given transaction (or the committed state when tx is nil)
+// along with a function that must be called once the caller is done reading
+func (db *Memory) read(tx pgx.Tx) (*memoryState, func(), error) {
+	if tx == nil {
+		db.mu.RLock()
+		return db.state, db.mu.RUnlock, nil
+	}
+
+	mtx, err := asMemoryTx(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mtx.state, func() {}, nil
+}
+
+// write applies fn to the state of the given transaction, or runs it in its own transaction when tx is nil
+func (db *Memory) write(ctx context.Context, tx pgx.Tx, fn func(state *memoryState) error) error {
+	if tx != nil {
+		mtx, err := asMemoryTx(tx)
+		if err != nil {
+			return err
+		}
+		return fn(mtx.state)
+	}
+
+	return db.InTransaction(ctx, func(_ context.Context, tx pgx.Tx) error {
+		return fn(tx.(*memoryTx).state)
+	})
+}
+
+// matchesFilter reports whether a server matches every set field of the filter
+func (s *memoryState) matchesFilter(server *apiv0.ServerResponse, filter *ServerFilter) bool {
+	if filter == nil {
+		return true
+	}
+
+	official := server.Meta.Official
+	if filter.Name != nil && server.Server.Name != *filter.Name {
+		return false
+	}
+	if filter.RemoteURL != nil {
+		found := false
+		for _, remote := range server.Server.Remotes {
+			if remote.URL == *filter.RemoteURL {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if filter.UpdatedSince != nil && !official.UpdatedAt.After(*filter.UpdatedSince) {
+		return false
+	}
+	if filter.SubstringName != nil && !strings.Contains(strings.ToLower(server.Server.Name), strings.ToLower(*filter.SubstringName)) {
+		return false
+	}
+	if filter.Version != nil && server.Server.Version != *filter.Version {
+		return false
+	}
+	if filter.IsLatest != nil && official.IsLatest != *filter.IsLatest {
+		return false
+	}
+	if filter.Tag != nil && s.tags[serverTagKey{name: server.Server.Name, tag: *filter.Tag}].Version != server.Server.Version {
+		return false
+	}
+	if filter.RegistryType != nil && !hasRegistryType(server, *filter.RegistryType) {
+		return false
+	}
+	if filter.PackageID != nil && !hasPackage(server, filter) {
+		return false
+	}
+	if filter.TransportType != nil && !hasTransportType(server, *filter.TransportType) {
+		return false
+	}
+	if filter.Status != nil && string(official.Status) != *filter.Status {
+		return false
+	}
+	if filter.HideDeprecated && official.Status == model.StatusDeprecated {
+		return false
+	}
+	if filter.NamespacePrefix != nil && !strings.HasPrefix(server.Server.Name, *filter.NamespacePrefix) {
+		return false
+	}
+	if filter.PublishedSince != nil && official.PublishedAt.Before(*filter.PublishedSince) {
+		return false
+	}
+	if filter.PublishedBefore != nil && !official.PublishedAt.Before(*filter.PublishedBefore) {
+		return false
+	}
+	if filter.Capability != nil && !hasCapability(s.serverCapabilities[serverKey{name: server.Server.Name, version: server.Server.Version}], *filter.Capability) {
+		return false
+	}
+	return true
+}
+
+// hasRegistryType reports whether any of the server's packages comes from the given registry
+func hasRegistryType(server *apiv0.ServerResponse, registryType string) bool {
+	for _, pkg := range server.Server.Packages {
+		if pkg.RegistryType == registryType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasPackage reports whether any of the server's packages matches the package lookup in the filter
+func hasPackage(server *apiv0.ServerResponse, filter *ServerFilter) bool {
+	for _, pkg := range server.Server.Packages {
+		if pkg.Identifier != *filter.PackageID {
+			continue
+		}
+		if filter.RegistryType != nil && pkg.RegistryType != *filter.RegistryType {
+			continue
+		}
+		if filter.PackageVersion != nil && pkg.Version != *filter.PackageVersion {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// hasTransportType reports whether any of the server's packages or remotes uses the given transport
+func hasTransportType(server *apiv0.ServerResponse, transportType string) bool {
+	for _, pkg := range server.Server.Packages {
+		if pkg.Transport.Type == transportType {
+			return true
+		}
+	}
+	for _, remote := range server.Server.Remotes {
+		if remote.Type == transportType {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCapability reports whether recorded capabilities include a tool, prompt or resource with the given name,
+// or a resource with the given URI
+func hasCapability(capabilities apiv0.ServerCapabilities, name string) bool {
+	for _, tool := range capabilities.Tools {
+		if tool.Name == name {
+			return true
+		}
+	}
+	for _, prompt := range capabilities.Prompts {
+		if prompt.Name == name {
+			return true
+		}
+	}
+	for _, resource := range capabilities.Resources {
+		if resource.Name == name || resource.URI == name {
+			return true
+		}
+	}
+	return false
+}
+
+// compareServers compares two servers by the given sort key, breaking ties by name and version.
+// The result is negative when a sorts before b in ascending order.
+func compareServers(a, b *apiv0.ServerResponse, sortBy string) int {
+	if c := sortTime(a, sortBy).Compare(sortTime(b, sortBy)); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Server.Name, b.Server.Name); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Server.Version, b.Server.Version)
+}
+
+// afterListCursor reports whether a server sorts after the cursor position
+func afterListCursor(server *apiv0.ServerResponse, position listCursor, order serverSort) bool {
+	// Name-only cursors predate compound cursors
+	if position.Version == "" {
+		return server.Server.Name > position.Name
+	}
+
+	cursorServer := &apiv0.ServerResponse{
+		Server: apiv0.ServerJSON{Name: position.Name, Version: position.Version},
+		Meta:   apiv0.ResponseMeta{Official: &apiv0.RegistryExtensions{}},
+	}
+	if position.Time != nil {
+		cursorServer.Meta.Official.PublishedAt = *position.Time
+		cursorServer.Meta.Official.UpdatedAt = *position.Time
+	}
+
+	c := compareServers(server, cursorServer, order.by)
+	if order.desc {
+		return c < 0
+	}
+	return c > 0
+}
+
+func (db *Memory) ListServers(
+	ctx context.Context,
+	tx pgx.Tx,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+) ([]*apiv0.ServerResponse, string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	// Full-text search results are ordered by relevance unless another ordering was requested
+	if filter != nil && filter.Query != nil && filter.SortBy == "" {
+		return db.searchServers(tx, filter, cursor, limit)
+	}
+
+	order, err := resolveServerSort(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var position listCursor
+	if cursor != "" {
+		if position, err = parseListCursor(cursor, order); err != nil {
+			return nil, "", err
+		}
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	var results []*apiv0.ServerResponse
+	for _, row := range state.servers {
+		server, err := row.toServerResponse()
+		if err != nil {
+			return nil, "", err
+		}
+		if !state.matchesFilter(server, filter) {
+			continue
+		}
+		if filter != nil && filter.Query != nil {
+			if _, ok := searchRank(server.Server, *filter.Query); !ok {
+				continue
+			}
+		}
+		if cursor != "" && !afterListCursor(server, position, order) {
+			continue
+		}
+		results = append(results, server)
+	}
+
+	// Match the ORDER BY of the PostgreSQL implementation
+	sort.Slice(results, func(i, j int) bool {
+		c := compareServers(results[i], results[j], order.by)
+		if order.desc {
+			return c > 0
+		}
+		return c < 0
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	// Determine next cursor from the sort key of the last result
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		last := results[len(results)-1]
+		nextCursor = formatListCursor(order, sortTime(last, order.by), last.Server.Name, last.Server.Version)
+	}
+
+	return results, nextCursor, nil
+}
+
+// searchServers runs a full-text search ListServers query, ordering results by relevance
+func (db *Memory) searchServers(tx pgx.Tx, filter *ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error) {
+	var cursorRank float64
+	var cursorServerName, cursorVersion string
+	if cursor != "" {
+		var err error
+		cursorRank, cursorServerName, cursorVersion, err = parseSearchCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	var results []rankedServer
+	for _, row := range state.servers {
+		server, err := row.toServerResponse()
+		if err != nil {
+			return nil, "", err
+		}
+		if !state.matchesFilter(server, filter) {
+			continue
+		}
+
+		rank, ok := searchRank(server.Server, *filter.Query)
+		if !ok {
+			continue
+		}
+		if cursor != "" && !afterSearchCursor(server, rank, cursorRank, cursorServerName, cursorVersion) {
+			continue
+		}
+		results = append(results, rankedServer{server: server, rank: rank})
+	}
+
+	// Match the ORDER BY of the PostgreSQL implementation
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].rank != results[j].rank {
+			return results[i].rank > results[j].rank
+		}
+		return compareServers(results[i].server, results[j].server, SortByName) < 0
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	servers := make([]*apiv0.ServerResponse, len(results))
+	for i, result := range results {
+		servers[i] = result.server
+	}
+
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		last := results[len(results)-1]
+		nextCursor = formatSearchCursor(last.rank, last.server.Server.Name, last.server.Server.Version)
+	}
+
+	return servers, nextCursor, nil
+}
+
+// rankedServer pairs a search result with its relevance
+type rankedServer struct {
+	server *apiv0.ServerResponse
+	rank   float64
+}
+
+// searchNameSeparators are replaced with spaces so each segment of a server name is searchable
+var searchNameSeparators = strings.NewReplacer(".", " ", "/", " ", "_", " ", "-", " ")
+
+// searchRank approximates the PostgreSQL full-text search: every term or phrase of the query, or one of
+// its alternatives, must appear in the name, title or description, and matches in the name or title rank
+// higher. Excluded terms and phrases must appear in none of them. Terms match partial words, and phrases
+// words in that order, but words are not stemmed.
+func searchRank(server apiv0.ServerJSON, query string) (float64, bool) {
+	q := parseSearchQuery(query)
+	if len(q.groups) == 0 && len(q.excluded) == 0 {
+		return 0, false
+	}
+
+	normalize := func(s string) string { return strings.Join(strings.Fields(strings.ToLower(s)), " ") }
+	name := normalize(searchNameSeparators.Replace(server.Name) + " " + server.Name)
+	title := normalize(server.Title)
+	description := normalize(server.Description)
+
+	for _, term := range q.excluded {
+		if strings.Contains(name, term) || strings.Contains(title, term) || strings.Contains(description, term) {
+			return 0, false
+		}
+	}
+
+	rank := 0.0
+	for _, alternatives := range q.groups {
+		best := 0.0
+		for _, term := range alternatives {
+			switch {
+			case strings.Contains(name, term), strings.Contains(title, term):
+				best = max(best, 1.0)
+			case strings.Contains(description, term):
+				best = max(best, 0.4)
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		rank += best
+	}
+
+	return rank, true
+}
+
+// afterSearchCursor reports whether a search result sorts after the rank:serverName:version cursor
+func afterSearchCursor(server *apiv0.ServerResponse, rank, cursorRank float64, cursorServerName, cursorVersion string) bool {
+	if rank != cursorRank {
+		return rank < cursorRank
+	}
+	if server.Server.Name != cursorServerName {
+		return server.Server.Name > cursorServerName
+	}
+	return server.Server.Version > cursorVersion
+}
+
+// GetServerByName retrieves the latest version of a server by server name
+func (db *Memory) GetServerByName(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var latest *serverRow
+	for _, row := range state.servers {
+		if row.serverName != serverName || !row.isLatest {
+			continue
+		}
+		if latest == nil || row.publishedAt.After(latest.publishedAt) {
+			latest = &row
+		}
+	}
+	if latest == nil {
+		return nil, ErrNotFound
+	}
+
+	return latest.toServerResponse()
+}
+
+// GetServerByNameAndVersion retrieves a specific version of a server by server name and version
+func (db *Memory) GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx, serverName string, version string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	row, ok := state.servers[serverKey{name: serverName, version: version}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return row.toServerResponse()
+}
+
+// GetAllVersionsByServerName retrieves all versions of a server by server name
+func (db *Memory) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var rows []serverRow
+	for _, row := range state.servers {
+		if row.serverName == serverName {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, ErrNotFound
+	}
+
+	// Match the ORDER BY published_at DESC of the PostgreSQL implementation
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].publishedAt.After(rows[j].publishedAt)
+	})
+
+	results := make([]*apiv0.ServerResponse, 0, len(rows))
+	for _, row := range rows {
+		server, err := row.toServerResponse()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, server)
+	}
+
+	return results, nil
+}
+
+// ListServersRaw lists servers like ListServers, returning responses that keep the stored JSON undecoded
+func (db *Memory) ListServersRaw(
+	ctx context.Context,
+	tx pgx.Tx,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+) ([]*apiv0.ServerResponse, string, error) {
+	servers, nextCursor, err := db.ListServers(ctx, tx, filter, cursor, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	raw, err := db.rawServers(tx, servers)
+	if err != nil {
+		return nil, "", err
+	}
+	return raw, nextCursor, nil
+}
+
+// GetAllVersionsByServerNameRaw retrieves all versions of a server like GetAllVersionsByServerName,
+// returning responses that keep the stored JSON undecoded
+func (db *Memory) GetAllVersionsByServerNameRaw(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	servers, err := db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if err != nil {
+		return nil, err
+	}
+	return db.rawServers(tx, servers)
+}
+
+// rawServers swaps decoded servers for raw responses around their stored rows. Filtering in memory
+// needs the decoded servers anyway, so this only exists to mirror the PostgreSQL fast path.
+func (db *Memory) rawServers(tx pgx.Tx, servers []*apiv0.ServerResponse) ([]*apiv0.ServerResponse, error) {
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	raw := make([]*apiv0.ServerResponse, len(servers))
+	for i, server := range servers {
+		row, ok := state.servers[serverKey{name: server.Server.Name, version: server.Server.Version}]
+		if !ok {
+			// Rows are never deleted, but keep the decoded server rather than dropping it
+			value, err := json.Marshal(server.Server)
+			if err != nil {
+				return nil, err
+			}
+			raw[i] = apiv0.NewRawServerResponse(server.Server.Name, server.Server.Version, value, server.Meta)
+			continue
+		}
+		raw[i] = row.toRawServerResponse()
+	}
+	return raw, nil
+}
+
+// CreateServer inserts a new server version with official metadata
+func (db *Memory) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *apiv0.ServerJSON, officialMeta *apiv0.RegistryExtensions) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Validate inputs
+	if serverJSON == nil || officialMeta == nil {
+		return nil, fmt.Errorf("serverJSON and officialMeta are required")
+	}
+
+	if serverJSON.Name == "" || serverJSON.Version == "" {
+		return nil, fmt.Errorf("server name and version are required")
+	}
+
+	if !isValidStatus(officialMeta.Status) {
+		return nil, fmt.Errorf("failed to insert server: %w: invalid status %q", ErrInvalidInput, officialMeta.Status)
+	}
+
+	// Marshal the ServerJSON so stored rows are independent of the caller's value
+	valueJSON, err := json.Marshal(serverJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server JSON: %w", err)
+	}
+
+	err = db.write(ctx, tx, func(state *memoryState) error {
+		key := serverKey{name: serverJSON.Name, version: serverJSON.Version}
+		if _, exists := state.servers[key]; exists {
+			return fmt.Errorf("failed to insert server: %w", ErrAlreadyExists)
+		}
+
+		// Enforce the same one-latest-per-server constraint as idx_unique_latest_per_server
+		if officialMeta.IsLatest {
+			for _, row := range state.servers {
+				if row.serverName == serverJSON.Name && row.isLatest {
+					return fmt.Errorf("failed to insert server: %w: %s already has a latest version", ErrAlreadyExists, serverJSON.Name)
+				}
+			}
+		}
+
+		state.servers[key] = serverRow{
+			serverName:  serverJSON.Name,
+			version:     serverJSON.Version,
+			status:      string(officialMeta.Status),
+			publishedAt: officialMeta.PublishedAt,
+			updatedAt:   officialMeta.UpdatedAt,
+			isLatest:    officialMeta.IsLatest,
+			value:       valueJSON,
+
+			deprecationMessage: officialMeta.DeprecationMessage,
+			replacedBy:         officialMeta.ReplacedBy,
+		}
+		if officialMeta.IsLatest {
+			tagKey := serverTagKey{name: serverJSON.Name, tag: TagLatest}
+			state.tags[tagKey] = apiv0.ServerTag{Tag: TagLatest, Version: serverJSON.Version, UpdatedAt: officialMeta.PublishedAt}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Return the complete ServerResponse
+	serverResponse := &apiv0.ServerResponse{
+		Server: *serverJSON,
+		Meta: apiv0.ResponseMeta{
+			Official: officialMeta,
+		},
+	}
+
+	return serverResponse, nil
+}
+
+// UpdateServer updates an existing server record with new server details
+func (db *Memory) UpdateServer(ctx context.Context, tx pgx.Tx, serverName, version string, serverJSON *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Validate inputs
+	if serverJSON == nil {
+		return nil, fmt.Errorf("serverJSON is required")
+	}
+
+	// Ensure the serverJSON matches the provided serverName and version
+	if serverJSON.Name != serverName || serverJSON.Version != version {
+		return nil, fmt.Errorf("%w: server name and version in JSON must match parameters", ErrInvalidInput)
+	}
+
+	// Marshal updated ServerJSON
+	valueJSON, err := json.Marshal(serverJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal updated server: %w", err)
+	}
+
+	var updated serverRow
+	err = db.write(ctx, tx, func(state *memoryState) error {
+		key := serverKey{name: serverName, version: version}
+		row, ok := state.servers[key]
+		if !ok {
+			return ErrNotFound
+		}
+
+		// Update only the JSON data (keep existing metadata columns)
+		row.value = valueJSON
+		row.updatedAt = time.Now()
+		state.servers[key] = row
+		updated = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.toServerResponse()
+}
+
+// SetServerStatus updates the status of a specific server version
+func (db *Memory) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName, version string, status string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Enforce the same values as the check_status_valid constraint
+	if !isValidStatus(model.Status(status)) {
+		return nil, fmt.Errorf("failed to update server status: %w: invalid status %q", ErrInvalidInput, status)
+	}
+
+	var updated serverRow
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		key := serverKey{name: serverName, version: version}
+		row, ok := state.servers[key]
+		if !ok {
+			return ErrNotFound
+		}
+
+		row.status = status
+		row.updatedAt = time.Now()
+		if model.Status(status) == model.StatusActive {
+			row.deprecationMessage, row.replacedBy = "", ""
+		}
+		state.servers[key] = row
+		updated = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.toServerResponse()
+}
+
+// DeprecateServer marks a server version as deprecated, replacing its deprecation message and successor
+func (db *Memory) DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, message, replacedBy string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var updated serverRow
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		key := serverKey{name: serverName, version: version}
+		row, ok := state.servers[key]
+		if !ok {
+			return ErrNotFound
+		}
+
+		row.status = string(model.StatusDeprecated)
+		row.updatedAt = time.Now()
+		row.deprecationMessage, row.replacedBy = message, replacedBy
+		state.servers[key] = row
+		updated = row
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated.toServerResponse()
+}
+
+// InTransaction executes a function within a database transaction.
+// The function works on a private copy of the data which is only published on success,
+// so returning an error rolls back every change made through the transaction.
+// Transactions are serialized, so starting another one, or writing without a transaction, with the
+// context passed to fn fails with ErrNestedTransaction rather than waiting for fn to return.
+func (db *Memory) InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if open, ok := ctx.Value(memoryTxKey{}).(*memoryTx); ok && open.db == db && !open.closed {
+		return ErrNestedTransaction
+	}
+
+	db.txMu.Lock()
+	defer db.txMu.Unlock()
+
+	db.mu.RLock()
+	tx := &memoryTx{db: db, state: db.state.clone()}
+	db.mu.RUnlock()
+	defer func() { tx.closed = true }()
+
+	if err := fn(context.WithValue(ctx, memoryTxKey{}, tx), tx); err != nil {
+		return err
+	}
+
+	db.mu.Lock()
+	db.state = tx.state
+	db.mu.Unlock()
+
+	return nil
+}
+
+// AcquirePublishLock acquires an exclusive lock for publishing a server.
+// Transactions are already serialized by InTransaction, so holding a transaction is sufficient.
+func (db *Memory) AcquirePublishLock(ctx context.Context, tx pgx.Tx, _ string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if _, err := asMemoryTx(tx); err != nil {
+		return fmt.Errorf("failed to acquire publish lock: %w", err)
+	}
+
+	return nil
+}
+
+// GetCurrentLatestVersion retrieves the current latest version of a server by server name
+func (db *Memory) GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, serverName string) (*apiv0.ServerResponse, error) {
+	return db.GetServerByName(ctx, tx, serverName)
+}
+
+// CountServerVersions counts the number of versions for a server
+func (db *Memory) CountServerVersions(ctx context.Context, tx pgx.Tx, serverName string) (int, error) {
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	count := 0
+	for _, row := range state.servers {
+		if row.serverName == serverName {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// CheckVersionExists checks if a specific version exists for a server
+func (db *Memory) CheckVersionExists(ctx context.Context, tx pgx.Tx, serverName, version string) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return false, err
+	}
+	defer release()
+
+	_, exists := state.servers[serverKey{name: serverName, version: version}]
+	return exists, nil
+}
+
+// UnmarkAsLatest marks the current latest version of a server as no longer latest
+func (db *Memory) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		for key, row := range state.servers {
+			if row.serverName == serverName && row.isLatest {
+				row.isLatest = false
+				state.servers[key] = row
+			}
+		}
+		delete(state.tags, serverTagKey{name: serverName, tag: TagLatest})
+		return nil
+	})
+}
+
+// GetServerByNameAndTag retrieves the version of a server that a dist-tag points at
+func (db *Memory) GetServerByNameAndTag(ctx context.Context, tx pgx.Tx, serverName, tag string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	serverTag, ok := state.tags[serverTagKey{name: serverName, tag: tag}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	row, ok := state.servers[serverKey{name: serverName, version: serverTag.Version}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return row.toServerResponse()
+}
+
+// ListServerTags retrieves the dist-tags of a server, ordered by tag name
+func (db *Memory) ListServerTags(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerTag, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var tags []*apiv0.ServerTag
+	for key, serverTag := range state.tags {
+		if key.name == serverName {
+			tags = append(tags, &serverTag)
+		}
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Tag < tags[j].Tag })
+
+	return tags, nil
+}
+
+// SetServerTag creates a dist-tag or moves it to another existing version of the server
+func (db *Memory) SetServerTag(ctx context.Context, tx pgx.Tx, serverName, tag, version string) (*apiv0.ServerTag, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var serverTag apiv0.ServerTag
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		if _, ok := state.servers[serverKey{name: serverName, version: version}]; !ok {
+			return ErrNotFound
+		}
+
+		serverTag = apiv0.ServerTag{Tag: tag, Version: version, UpdatedAt: time.Now()}
+		state.tags[serverTagKey{name: serverName, tag: tag}] = serverTag
+
+		if tag == TagLatest {
+			for key, row := range state.servers {
+				if row.serverName == serverName && row.isLatest != (row.version == version) {
+					row.isLatest = row.version == version
+					state.servers[key] = row
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &serverTag, nil
+}
+
+// DeleteServerTag removes a dist-tag
+func (db *Memory) DeleteServerTag(ctx context.Context, tx pgx.Tx, serverName, tag string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		key := serverTagKey{name: serverName, tag: tag}
+		if _, ok := state.tags[key]; !ok {
+			return ErrNotFound
+		}
+		delete(state.tags, key)
+		return nil
+	})
+}
+
+// CreateAuditEvent appends an event to the audit log, assigning its ID and timestamp
+func (db *Memory) CreateAuditEvent(ctx context.Context, tx pgx.Tx, event *apiv0.AuditEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if event == nil || event.Action == "" {
+		return fmt.Errorf("%w: audit event action is required", ErrInvalidInput)
+	}
+	if auditActionConcernsVersion(event.Action) && (event.ServerName == "" || event.Version == "") {
+		return fmt.Errorf("%w: audit events of %s require a server name and version", ErrInvalidInput, event.Action)
+	}
+
+	// Enforce the same values as the check_audit_action_valid constraint
+	switch event.Action {
+	case AuditActionPublish, AuditActionEdit, AuditActionStatusChange, AuditActionTagSet, AuditActionTagDelete,
+		AuditActionNamespaceBlock, AuditActionNamespaceUnblock, AuditActionRemoteURLRelease,
+		AuditActionWebhookSubscribe, AuditActionWebhookUnsubscribe:
+	default:
+		return fmt.Errorf("failed to insert audit event: %w: invalid action %q", ErrInvalidInput, event.Action)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		state.lastAuditID++
+		event.ID = state.lastAuditID
+		event.CreatedAt = time.Now()
+		state.auditEvents = append(state.auditEvents, *event)
+		return nil
+	})
+}
+
+// ListAuditEvents retrieves audit events, newest first, with cursor-based pagination and optional filtering
+func (db *Memory) ListAuditEvents(ctx context.Context, tx pgx.Tx, filter *AuditEventFilter, cursor string, limit int) ([]*apiv0.AuditEvent, string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	var cursorID int64
+	if cursor != "" {
+		var err error
+		if cursorID, err = parseAuditCursor(cursor); err != nil {
+			return nil, "", err
+		}
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer release()
+
+	var results []*apiv0.AuditEvent
+	for i := len(state.auditEvents) - 1; i >= 0 && len(results) < limit; i-- {
+		event := state.auditEvents[i]
+		if cursorID != 0 && event.ID >= cursorID {
+			continue
+		}
+		if !matchesAuditFilter(&event, filter) {
+			continue
+		}
+		results = append(results, &event)
+	}
+
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		nextCursor = formatAuditCursor(results[len(results)-1].ID)
+	}
+
+	return results, nextCursor, nil
+}
+
+// matchesAuditFilter reports whether an audit event matches every set field of the filter
+func matchesAuditFilter(event *apiv0.AuditEvent, filter *AuditEventFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.ServerName != nil && event.ServerName != *filter.ServerName {
+		return false
+	}
+	if filter.ActorSubject != nil && event.ActorSubject != *filter.ActorSubject {
+		return false
+	}
+	if filter.Action != nil && event.Action != *filter.Action {
+		return false
+	}
+	if filter.Since != nil && event.CreatedAt.Before(*filter.Since) {
+		return false
+	}
+	if filter.Until != nil && !event.CreatedAt.Before(*filter.Until) {
+		return false
+	}
+	return true
+}
+
+// CreateChangeEvent appends an event to the change feed, assigning its sequence number and timestamp.
+// Transactions are serialized, so sequence numbers are always committed in order.
+func (db *Memory) CreateChangeEvent(ctx context.Context, tx pgx.Tx, event *apiv0.ChangeEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if event == nil || event.Type == "" || event.ServerName == "" || event.Version == "" {
+		return fmt.Errorf("%w: change event type, server name and version are required", ErrInvalidInput)
+	}
+
+	// Enforce the same values as the check_change_type_valid constraint
+	switch event.Type {
+	case ChangeTypePublish, ChangeTypeEdit, ChangeTypeStatusChange, ChangeTypeLatestChange:
+	default:
+		return fmt.Errorf("failed to insert change event: %w: invalid change type %q", ErrInvalidInput, event.Type)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		// Enforce the same reference as the fk_change_events_server constraint
+		if _, ok := state.servers[serverKey{name: event.ServerName, version: event.Version}]; !ok {
+			return fmt.Errorf("failed to insert change event: %w: unknown server version", ErrInvalidInput)
+		}
+
+		state.lastChange++
+		event.Sequence = state.lastChange
+		event.ChangedAt = time.Now()
+		stored := *event
+		stored.Server = nil
+		state.changes = append(state.changes, stored)
+		return nil
+	})
+}
+
+// ListChangeEvents retrieves change events after the given sequence number, in sequence order,
+// together with the current state of each changed server version
+func (db *Memory) ListChangeEvents(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]*apiv0.ChangeEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Sequence numbers are dense and start at 1, so the first event after the cursor is at index after
+	start := int(min(max(after, 0), int64(len(state.changes))))
+
+	var results []*apiv0.ChangeEvent
+	for _, change := range state.changes[start:] {
+		if len(results) >= limit {
+			break
+		}
+
+		server, err := state.servers[serverKey{name: change.ServerName, version: change.Version}].toServerResponse()
+		if err != nil {
+			return nil, err
+		}
+		change.Server = server
+		results = append(results, &change)
+	}
+
+	return results, nil
+}
+
+// CreateWebhookSubscription stores a webhook subscription with its secret, assigning its ID and creation time
+func (db *Memory) CreateWebhookSubscription(ctx context.Context, tx pgx.Tx, subscription *apiv0.WebhookSubscription) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if subscription == nil || subscription.URL == "" || subscription.NamespacePattern == "" || subscription.Secret == "" {
+		return fmt.Errorf("%w: webhook subscription URL, namespace pattern and secret are required", ErrInvalidInput)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		state.lastWebhookSubscription++
+		subscription.ID = state.lastWebhookSubscription
+		subscription.CreatedAt = time.Now()
+		state.webhookSubscriptions[subscription.ID] = *subscription
+		return nil
+	})
+}
+
+// ListWebhookSubscriptions retrieves all webhook subscriptions in ID order, without their secrets
+func (db *Memory) ListWebhookSubscriptions(ctx context.Context, tx pgx.Tx) ([]*apiv0.WebhookSubscription, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	results := make([]*apiv0.WebhookSubscription, 0, len(state.webhookSubscriptions))
+	for _, subscription := range state.webhookSubscriptions {
+		subscription.Secret = ""
+		results = append(results, &subscription)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID < results[j].ID })
+
+	return results, nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription without its secret
+func (db *Memory) GetWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) (*apiv0.WebhookSubscription, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	subscription, ok := state.webhookSubscriptions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	subscription.Secret = ""
+
+	return &subscription, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription together with its deliveries
+func (db *Memory) DeleteWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		if _, ok := state.webhookSubscriptions[id]; !ok {
+			return ErrNotFound
+		}
+		delete(state.webhookSubscriptions, id)
+
+		// Mirror ON DELETE CASCADE on webhook_deliveries
+		for deliveryID, row := range state.webhookDeliveries {
+			if row.delivery.SubscriptionID == id {
+				delete(state.webhookDeliveries, deliveryID)
+			}
+		}
+		return nil
+	})
+}
+
+// CreateWebhookDelivery queues an event for a subscription, to be attempted right away
+func (db *Memory) CreateWebhookDelivery(ctx context.Context, tx pgx.Tx, delivery *apiv0.WebhookDelivery, payload []byte) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if delivery == nil || delivery.SubscriptionID == 0 || delivery.EventType == "" || delivery.ServerName == "" || delivery.Version == "" {
+		return fmt.Errorf("%w: webhook delivery subscription, event type, server name and version are required", ErrInvalidInput)
+	}
+
+	// Enforce the same values as the check_webhook_event_type_valid constraint
+	switch delivery.EventType {
+	case WebhookEventPublished, WebhookEventUpdated, WebhookEventDeprecated, WebhookEventDeleted:
+	default:
+		return fmt.Errorf("failed to insert webhook delivery: %w: invalid event type %q", ErrInvalidInput, delivery.EventType)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		// Enforce the same reference as the webhook_deliveries foreign key
+		if _, ok := state.webhookSubscriptions[delivery.SubscriptionID]; !ok {
+			return fmt.Errorf("failed to insert webhook delivery: %w: unknown webhook subscription", ErrInvalidInput)
+		}
+
+		now := time.Now()
+		state.lastWebhookDelivery++
+		row := webhookDeliveryRow{
+			delivery: apiv0.WebhookDelivery{
+				ID:             state.lastWebhookDelivery,
+				SubscriptionID: delivery.SubscriptionID,
+				EventType:      delivery.EventType,
+				ServerName:     delivery.ServerName,
+				Version:        delivery.Version,
+				Status:         WebhookDeliveryPending,
+				CreatedAt:      now,
+			},
+			nextAttemptAt: now,
+			payload:       payload,
+		}
+		state.webhookDeliveries[row.delivery.ID] = row
+		*delivery = *row.toWebhookDelivery()
+		return nil
+	})
+}
+
+// ListWebhookDeliveries retrieves the deliveries of a subscription, newest first, optionally only those with a status
+func (db *Memory) ListWebhookDeliveries(ctx context.Context, tx pgx.Tx, subscriptionID int64, status *string, limit int) ([]*apiv0.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var results []*apiv0.WebhookDelivery
+	for _, row := range state.webhookDeliveries {
+		if row.delivery.SubscriptionID != subscriptionID || (status != nil && row.delivery.Status != *status) {
+			continue
+		}
+		results = append(results, row.toWebhookDelivery())
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].ID > results[j].ID })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// ClaimWebhookDeliveries leases up to limit pending deliveries that are due by pushing their next
+// attempt past the lease. Transactions are serialized, so no two callers claim the same delivery.
+func (db *Memory) ClaimWebhookDeliveries(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]*WebhookJob, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var jobs []*WebhookJob
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		now := time.Now()
+		var due []webhookDeliveryRow
+		for _, row := range state.webhookDeliveries {
+			if row.delivery.Status == WebhookDeliveryPending && !row.nextAttemptAt.After(now) {
+				due = append(due, row)
+			}
+		}
+		sort.Slice(due, func(i, j int) bool {
+			if !due[i].nextAttemptAt.Equal(due[j].nextAttemptAt) {
+				return due[i].nextAttemptAt.Before(due[j].nextAttemptAt)
+			}
+			return due[i].delivery.ID < due[j].delivery.ID
+		})
+		if len(due) > limit {
+			due = due[:limit]
+		}
+
+		jobs = make([]*WebhookJob, 0, len(due))
+		for _, row := range due {
+			row.nextAttemptAt = now.Add(lease)
+			state.webhookDeliveries[row.delivery.ID] = row
+			subscription := state.webhookSubscriptions[row.delivery.SubscriptionID]
+			jobs = append(jobs, &WebhookJob{
+				Delivery: *row.toWebhookDelivery(),
+				URL:      subscription.URL,
+				Secret:   subscription.Secret,
+				Payload:  row.payload,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// RecordWebhookAttempt counts an attempt at a delivery and stores its outcome
+func (db *Memory) RecordWebhookAttempt(ctx context.Context, tx pgx.Tx, deliveryID int64, attempt *WebhookAttempt) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !isValidWebhookDeliveryStatus(attempt.Status) {
+		return fmt.Errorf("%w: invalid webhook delivery status %q", ErrInvalidInput, attempt.Status)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		row, ok := state.webhookDeliveries[deliveryID]
+		if !ok {
+			return ErrNotFound
+		}
+
+		row.delivery.Status = attempt.Status
+		row.delivery.Attempts++
+		row.delivery.LastStatusCode = attempt.StatusCode
+		row.delivery.LastError = attempt.Error
+		row.nextAttemptAt = attempt.NextAttemptAt
+		if attempt.Status == WebhookDeliveryDelivered {
+			deliveredAt := time.Now()
+			row.delivery.DeliveredAt = &deliveredAt
+		}
+		state.webhookDeliveries[deliveryID] = row
+		return nil
+	})
+}
+
+// RedeliverWebhookDelivery makes a delivery pending again with a fresh set of attempts
+func (db *Memory) RedeliverWebhookDelivery(ctx context.Context, tx pgx.Tx, deliveryID int64) (*apiv0.WebhookDelivery, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var delivery *apiv0.WebhookDelivery
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		row, ok := state.webhookDeliveries[deliveryID]
+		if !ok {
+			return ErrNotFound
+		}
+
+		row.delivery.Status = WebhookDeliveryPending
+		row.delivery.Attempts = 0
+		row.nextAttemptAt = time.Now()
+		state.webhookDeliveries[deliveryID] = row
+		delivery = row.toWebhookDelivery()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// SetNamespaceBlock blocks a namespace, replacing any existing block of it
+func (db *Memory) SetNamespaceBlock(ctx context.Context, tx pgx.Tx, block *apiv0.NamespaceBlock) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if block == nil || block.Namespace == "" || block.Reason == "" || block.CreatedBy == "" {
+		return fmt.Errorf("%w: namespace block namespace, reason and creator are required", ErrInvalidInput)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		block.CreatedAt = time.Now()
+		stored := *block
+		if block.ExpiresAt != nil {
+			expiresAt := *block.ExpiresAt
+			stored.ExpiresAt = &expiresAt
+		}
+		state.namespaceBlocks[block.Namespace] = stored
+		return nil
+	})
+}
+
+// ListNamespaceBlocks retrieves namespace blocks in namespace order, including those that have expired if requested
+func (db *Memory) ListNamespaceBlocks(ctx context.Context, tx pgx.Tx, includeExpired bool) ([]*apiv0.NamespaceBlock, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	now := time.Now()
+	results := make([]*apiv0.NamespaceBlock, 0, len(state.namespaceBlocks))
+	for _, block := range state.namespaceBlocks {
+		if !includeExpired && block.ExpiresAt != nil && !block.ExpiresAt.After(now) {
+			continue
+		}
+		results = append(results, &block)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Namespace < results[j].Namespace })
+
+	return results, nil
+}
+
+// DeleteNamespaceBlock unblocks a namespace
+func (db *Memory) DeleteNamespaceBlock(ctx context.Context, tx pgx.Tx, namespace string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		if _, ok := state.namespaceBlocks[namespace]; !ok {
+			return ErrNotFound
+		}
+		delete(state.namespaceBlocks, namespace)
+		return nil
+	})
+}
+
+// ClaimRemoteURLs claims canonical remote URLs for a server, leaving claims held by other servers in place.
+// It returns the URLs that other servers hold, mapped to the name of the server holding each.
+func (db *Memory) ClaimRemoteURLs(ctx context.Context, tx pgx.Tx, serverName string, urls []string) (map[string]string, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	conflicts := map[string]string{}
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		now := time.Now()
+		for _, url := range urls {
+			if claim, ok := state.remoteURLClaims[url]; ok {
+				if claim.ServerName != serverName {
+					conflicts[url] = claim.ServerName
+				}
+				continue
+			}
+			state.remoteURLClaims[url] = apiv0.RemoteURLClaim{URL: url, ServerName: serverName, ClaimedAt: now}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return conflicts, nil
+}
+
+// ReleaseRemoteURLClaims releases the claims of a server on every URL not in keep
+func (db *Memory) ReleaseRemoteURLClaims(ctx context.Context, tx pgx.Tx, serverName string, keep []string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	kept := make(map[string]bool, len(keep))
+	for _, url := range keep {
+		kept[url] = true
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		for url, claim := range state.remoteURLClaims {
+			if claim.ServerName == serverName && !kept[url] {
+				delete(state.remoteURLClaims, url)
+			}
+		}
+		return nil
+	})
+}
+
+// ListRemoteURLClaims retrieves remote URL claims in URL order
+func (db *Memory) ListRemoteURLClaims(ctx context.Context, tx pgx.Tx, filter *RemoteURLClaimFilter) ([]*apiv0.RemoteURLClaim, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var results []*apiv0.RemoteURLClaim
+	for _, claim := range state.remoteURLClaims {
+		if filter != nil {
+			if filter.URL != nil && claim.URL != *filter.URL {
+				continue
+			}
+			if filter.ServerName != nil && claim.ServerName != *filter.ServerName {
+				continue
+			}
+		}
+		results = append(results, &claim)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].URL < results[j].URL })
+
+	return results, nil
+}
+
+// DeleteRemoteURLClaim releases the claim on a canonical URL, whichever server holds it
+func (db *Memory) DeleteRemoteURLClaim(ctx context.Context, tx pgx.Tx, url string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		if _, ok := state.remoteURLClaims[url]; !ok {
+			return ErrNotFound
+		}
+		delete(state.remoteURLClaims, url)
+		return nil
+	})
+}
+
+// RecordPackageValidation records the outcome of checking a package, counting consecutive failures from
+// the previous outcome. Inconclusive failures keep the previous count. It fills in the failure count and
+// last pass of validation.
+func (db *Memory) RecordPackageValidation(ctx context.Context, tx pgx.Tx, validation *apiv0.PackageValidation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if validation == nil || validation.ServerName == "" || validation.Version == "" || validation.RegistryType == "" || validation.Identifier == "" {
+		return fmt.Errorf("%w: package validation server, version, registry type and identifier are required", ErrInvalidInput)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		key := packageValidationKey{validation.ServerName, validation.Version, validation.RegistryType, validation.Identifier}
+		previous, exists := state.packageValidations[key]
+
+		validation.ConsecutiveFailures = 0
+		validation.LastPassedAt = nil
+		// Only failures can be inconclusive
+		validation.Inconclusive = validation.Inconclusive && !validation.Passed
+		if validation.Passed {
+			checkedAt := validation.CheckedAt
+			validation.LastPassedAt = &checkedAt
+		} else {
+			if !validation.Inconclusive {
+				validation.ConsecutiveFailures = 1
+			}
+			if exists {
+				validation.ConsecutiveFailures += previous.ConsecutiveFailures
+				validation.LastPassedAt = previous.LastPassedAt
+			}
+		}
+
+		state.packageValidations[key] = *validation
+		return nil
+	})
+}
+
+// ListPackageValidations retrieves package validations ordered by server name, version, registry type and identifier
+func (db *Memory) ListPackageValidations(ctx context.Context, tx pgx.Tx, filter *PackageValidationFilter) ([]*apiv0.PackageValidation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var results []*apiv0.PackageValidation
+	for _, validation := range state.packageValidations {
+		if filter != nil {
+			if filter.ServerName != nil && validation.ServerName != *filter.ServerName {
+				continue
+			}
+			if filter.FailingOnly && validation.Passed {
+				continue
+			}
+		}
+		results = append(results, &validation)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.ServerName != b.ServerName {
+			return a.ServerName < b.ServerName
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		if a.RegistryType != b.RegistryType {
+			return a.RegistryType < b.RegistryType
+		}
+		return a.Identifier < b.Identifier
+	})
+
+	return results, nil
+}
+
+// DeletePackageValidationsCheckedBefore removes package validations last checked before the given time
+func (db *Memory) DeletePackageValidationsCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		for key, validation := range state.packageValidations {
+			if validation.CheckedAt.Before(before) {
+				delete(state.packageValidations, key)
+			}
+		}
+		return nil
+	})
+}
+
+// RecordRemoteProbe records the outcome of probing a remote. Failed probes keep the protocol version, latency
+// and success time of the last probe that succeeded, which are filled in on probe.
+func (db *Memory) RecordRemoteProbe(ctx context.Context, tx pgx.Tx, probe *RemoteProbe) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if probe == nil || probe.ServerName == "" || probe.Version == "" || probe.URL == "" || probe.Status == "" {
+		return fmt.Errorf("%w: remote probe server, version, URL and status are required", ErrInvalidInput)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		key := remoteProbeKey{probe.ServerName, probe.Version, probe.URL}
+		if probe.Status == apiv0.RemoteStatusUp {
+			checkedAt := probe.CheckedAt
+			probe.LastSuccessAt = &checkedAt
+		} else {
+			probe.ProtocolVersion, probe.LatencyMs, probe.LastSuccessAt = "", nil, nil
+			if previous, ok := state.remoteProbes[key]; ok {
+				probe.ProtocolVersion, probe.LatencyMs, probe.LastSuccessAt = previous.ProtocolVersion, previous.LatencyMs, previous.LastSuccessAt
+			}
+		}
+
+		state.remoteProbes[key] = *probe
+		return nil
+	})
+}
+
+// ListRemoteProbes retrieves remote probes ordered by server name, version and URL
+func (db *Memory) ListRemoteProbes(ctx context.Context, tx pgx.Tx, filter *RemoteProbeFilter) ([]*RemoteProbe, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var serverNames map[string]bool
+	if filter != nil && filter.ServerNames != nil {
+		serverNames = make(map[string]bool, len(filter.ServerNames))
+		for _, name := range filter.ServerNames {
+			serverNames[name] = true
+		}
+	}
+
+	var results []*RemoteProbe
+	for _, probe := range state.remoteProbes {
+		if serverNames != nil && !serverNames[probe.ServerName] {
+			continue
+		}
+		results = append(results, &probe)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.ServerName != b.ServerName {
+			return a.ServerName < b.ServerName
+		}
+		if a.Version != b.Version {
+			return a.Version < b.Version
+		}
+		return a.URL < b.URL
+	})
+
+	return results, nil
+}
+
+// DeleteRemoteProbesCheckedBefore removes remote probes last checked before the given time
+func (db *Memory) DeleteRemoteProbesCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		for key, probe := range state.remoteProbes {
+			if probe.CheckedAt.Before(before) {
+				delete(state.remoteProbes, key)
+			}
+		}
+		return nil
+	})
+}
+
+// RecordServerCapabilities records the capabilities of a server version. Failed introspections keep the
+// capabilities and update time of the last one that succeeded, which are filled in on capabilities.
+func (db *Memory) RecordServerCapabilities(ctx context.Context, tx pgx.Tx, capabilities *apiv0.ServerCapabilities) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if capabilities == nil || capabilities.ServerName == "" || capabilities.Version == "" || capabilities.Source == "" {
+		return fmt.Errorf("%w: server capabilities server, version and source are required", ErrInvalidInput)
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		key := serverKey{name: capabilities.ServerName, version: capabilities.Version}
+		if capabilities.Error == "" {
+			updatedAt := capabilities.CheckedAt
+			capabilities.UpdatedAt = &updatedAt
+		} else {
+			capabilities.Tools, capabilities.Prompts, capabilities.Resources, capabilities.UpdatedAt = nil, nil, nil, nil
+			if previous, ok := state.serverCapabilities[key]; ok {
+				capabilities.Tools, capabilities.Prompts, capabilities.Resources = previous.Tools, previous.Prompts, previous.Resources
+				capabilities.UpdatedAt = previous.UpdatedAt
+			}
+		}
+		if capabilities.Tools == nil {
+			capabilities.Tools = []apiv0.CapabilityTool{}
+		}
+		if capabilities.Prompts == nil {
+			capabilities.Prompts = []apiv0.CapabilityPrompt{}
+		}
+		if capabilities.Resources == nil {
+			capabilities.Resources = []apiv0.CapabilityResource{}
+		}
+
+		state.serverCapabilities[key] = *capabilities
+		return nil
+	})
+}
+
+// GetServerCapabilities retrieves the capabilities recorded for a server version
+func (db *Memory) GetServerCapabilities(ctx context.Context, tx pgx.Tx, serverName, version string) (*apiv0.ServerCapabilities, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	capabilities, ok := state.serverCapabilities[serverKey{name: serverName, version: version}]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &capabilities, nil
+}
+
+// ListServerCapabilities retrieves server capabilities ordered by server name and version
+func (db *Memory) ListServerCapabilities(ctx context.Context, tx pgx.Tx, filter *ServerCapabilitiesFilter) ([]*apiv0.ServerCapabilities, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	state, release, err := db.read(tx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	var results []*apiv0.ServerCapabilities
+	for _, capabilities := range state.serverCapabilities {
+		if filter != nil && filter.ServerName != nil && capabilities.ServerName != *filter.ServerName {
+			continue
+		}
+		results = append(results, &capabilities)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.ServerName != b.ServerName {
+			return a.ServerName < b.ServerName
+		}
+		return a.Version < b.Version
+	})
+
+	return results, nil
+}
+
+// TakeRateLimitToken refills the token bucket for key and takes a token from it if one is available
+func (db *Memory) TakeRateLimitToken(ctx context.Context, tx pgx.Tx, key string, capacity, refillPerSecond float64) (float64, bool, error) {
+	if ctx.Err() != nil {
+		return 0, false, ctx.Err()
+	}
+	if len(key) > maxRateLimitKeyLength {
+		return 0, false, fmt.Errorf("%w: rate limit key is longer than %d characters", ErrInvalidInput, maxRateLimitKeyLength)
+	}
+
+	var tokens float64
+	var taken bool
+	err := db.write(ctx, tx, func(state *memoryState) error {
+		now := time.Now()
+		tokens = capacity
+		if bucket, ok := state.rateLimitBuckets[key]; ok {
+			tokens = min(capacity, bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*refillPerSecond)
+		}
+
+		// Like the PostgreSQL implementation, a bucket without a token is left untouched
+		if tokens < 1 {
+			return nil
+		}
+		tokens--
+		taken = true
+		state.rateLimitBuckets[key] = rateLimitBucket{tokens: tokens, updatedAt: now}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return tokens, taken, nil
+}
+
+// DeleteIdleRateLimitBuckets removes token buckets last taken from before the given time
+func (db *Memory) DeleteIdleRateLimitBuckets(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	return db.write(ctx, tx, func(state *memoryState) error {
+		for key, bucket := range state.rateLimitBuckets {
+			if bucket.updatedAt.Before(before) {
+				delete(state.rateLimitBuckets, key)
+			}
+		}
+		return nil
+	})
+}
+
+// Close closes the database connection
+func (db *Memory) Close() error {
+	return nil
+}
+
+// isValidStatus reports whether the status is one of the known server lifecycle statuses
+func isValidStatus(status model.Status) bool {
+	switch status {
+	case model.StatusActive, model.StatusDeprecated, model.StatusDeleted:
+		return true
+	default:
+		return false
+	}
+}
+
+// isValidWebhookDeliveryStatus reports whether the status is one of the known webhook delivery statuses
+func isValidWebhookDeliveryStatus(status string) bool {
+	switch status {
+	case WebhookDeliveryPending, WebhookDeliveryDelivered, WebhookDeliveryDead:
+		return true
+	default:
+		return false
+	}
+}
+
+// memoryTx is the pgx.Tx handed to InTransaction callbacks by the in-memory database.
+// It only carries the transaction's working copy; SQL methods are not supported.
+type memoryTx struct {
+	db     *Memory
+	state  *memoryState
+	closed bool
+}
+
+// asMemoryTx converts a transaction passed through the Database interface back into a memoryTx
+func asMemoryTx(tx pgx.Tx) (*memoryTx, error) {
+	mtx, ok := tx.(*memoryTx)
+	if !ok || mtx == nil {
+		return nil, fmt.Errorf("%w: transaction was not created by the in-memory database", ErrInvalidInput)
+	}
+	if mtx.closed {
+		return nil, pgx.ErrTxClosed
+	}
+	return mtx, nil
+}
+
+func (tx *memoryTx) Begin(_ context.Context) (pgx.Tx, error) {
+	return nil, errMemoryTxSQL
+}
+
+// Commit and Rollback are driven by Memory.InTransaction, which publishes or discards the working copy
+func (tx *memoryTx) Commit(_ context.Context) error {
+	return errMemoryTxSQL
+}
+
+func (tx *memoryTx) Rollback(_ context.Context) error {
+	return errMemoryTxSQL
+}
+
+func (tx *memoryTx) CopyFrom(_ context.Context, _ pgx.Identifier, _ []string, _ pgx.CopyFromSource) (int64, error) {
+	return 0, errMemoryTxSQL
+}
+
+func (tx *memoryTx) SendBatch(_ context.Context, _ *pgx.Batch) pgx.BatchResults {
+	return memoryBatchResults{}
+}
+
+func (tx *memoryTx) LargeObjects() pgx.LargeObjects {
+	return pgx.LargeObjects{}
+}
+
+func (tx *memoryTx) Prepare(_ context.Context, _, _ string) (*pgconn.StatementDescription, error) {
+	return nil, errMemoryTxSQL
+}
+
+func (tx *memoryTx) Exec(_ context.Context, _ string, _ ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errMemoryTxSQL
+}
+
+func (tx *memoryTx) Query(_ context.Context, _ string, _ ...any) (pgx.Rows, error) {
+	return nil, errMemoryTxSQL
+}
+
+func (tx *memoryTx) QueryRow(_ context.Context, _ string, _ ...any) pgx.Row {
+	return memoryRow{}
+}
+
+func (tx *memoryTx) Conn() *pgx.Conn {
+	return nil
+}
+
+// memoryBatchResults reports errMemoryTxSQL for every batched statement
+type memoryBatchResults struct{}
+
+func (memoryBatchResults) Exec() (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errMemoryTxSQL
+}
+
+func (memoryBatchResults) Query() (pgx.Rows, error) {
+	return nil, errMemoryTxSQL
+}
+
+func (memoryBatchResults) QueryRow() pgx.Row {
+	return memoryRow{}
+}
+
+func (memoryBatchResults) Close() error {
+	return nil
+}
+
+// memoryRow reports errMemoryTxSQL when scanned
+type memoryRow struct{}
+
+func (memoryRow) Scan(_ ...any) error {
+	return errMemoryTxSQL
+}
@@ -0,0 +1,1344 @@
+package database_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createMemoryServer(t *testing.T, db database.Database, name, version string, isLatest bool, publishedAt time.Time, remotes ...string) {
+	t.Helper()
+
+	serverJSON := &apiv0.ServerJSON{
+		Name:        name,
+		Description: "In-memory test server",
+		Version:     version,
+	}
+	for _, remote := range remotes {
+		serverJSON.Remotes = append(serverJSON.Remotes, model.Transport{Type: "streamable-http", URL: remote})
+	}
+
+	_, err := db.CreateServer(context.Background(), nil, serverJSON, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: publishedAt,
+		UpdatedAt:   publishedAt,
+		IsLatest:    isLatest,
+	})
+	require.NoError(t, err)
+}
+
+func TestMemory_CreateAndGetServer(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	now := time.Now()
+	createMemoryServer(t, db, "com.example/memory-server", "1.0.0", false, now.Add(-time.Hour))
+	createMemoryServer(t, db, "com.example/memory-server", "2.0.0", true, now)
+
+	t.Run("get latest by name", func(t *testing.T) {
+		result, err := db.GetServerByName(ctx, nil, "com.example/memory-server")
+		require.NoError(t, err)
+		assert.Equal(t, "2.0.0", result.Server.Version)
+		assert.True(t, result.Meta.Official.IsLatest)
+		assert.Equal(t, model.StatusActive, result.Meta.Official.Status)
+	})
+
+	t.Run("get by name and version", func(t *testing.T) {
+		result, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/memory-server", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", result.Server.Version)
+		assert.False(t, result.Meta.Official.IsLatest)
+	})
+
+	t.Run("get all versions ordered by publish time", func(t *testing.T) {
+		results, err := db.GetAllVersionsByServerName(ctx, nil, "com.example/memory-server")
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "2.0.0", results[0].Server.Version)
+		assert.Equal(t, "1.0.0", results[1].Server.Version)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := db.GetServerByName(ctx, nil, "com.example/missing")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		_, err = db.GetServerByNameAndVersion(ctx, nil, "com.example/memory-server", "9.9.9")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		_, err = db.GetAllVersionsByServerName(ctx, nil, "com.example/missing")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("duplicate version is rejected", func(t *testing.T) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{Name: "com.example/memory-server", Version: "1.0.0"}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: now,
+			UpdatedAt:   now,
+		})
+		assert.ErrorIs(t, err, database.ErrAlreadyExists)
+	})
+
+	t.Run("second latest version is rejected", func(t *testing.T) {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{Name: "com.example/memory-server", Version: "3.0.0"}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: now,
+			UpdatedAt:   now,
+			IsLatest:    true,
+		})
+		assert.ErrorIs(t, err, database.ErrAlreadyExists)
+	})
+
+	t.Run("helper methods", func(t *testing.T) {
+		count, err := db.CountServerVersions(ctx, nil, "com.example/memory-server")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		exists, err := db.CheckVersionExists(ctx, nil, "com.example/memory-server", "1.0.0")
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		require.NoError(t, db.UnmarkAsLatest(ctx, nil, "com.example/memory-server"))
+		_, err = db.GetCurrentLatestVersion(ctx, nil, "com.example/memory-server")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestMemory_ListServers(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	now := time.Now()
+	createMemoryServer(t, db, "com.example/server-a", "1.0.0", false, now.Add(-3*time.Hour), "https://a.example.com/mcp")
+	createMemoryServer(t, db, "com.example/server-a", "1.1.0", true, now.Add(-2*time.Hour), "https://a.example.com/mcp")
+	createMemoryServer(t, db, "com.example/server-b", "1.0.0", true, now.Add(-1*time.Hour), "https://b.example.com/mcp")
+	createMemoryServer(t, db, "org.other/server-c", "2.0.0", true, now)
+
+	_, err := db.SetServerStatus(ctx, nil, "org.other/server-c", "2.0.0", string(model.StatusDeprecated))
+	require.NoError(t, err)
+
+	name := "com.example/server-a"
+	remoteURL := "https://b.example.com/mcp"
+	substring := "SERVER-"
+	version := "1.0.0"
+	isLatest := true
+	updatedSince := now.Add(-90 * time.Minute)
+
+	tests := []struct {
+		name          string
+		filter        *database.ServerFilter
+		cursor        string
+		limit         int
+		expectedNames []string
+	}{
+		{
+			name:          "list all in name and version order",
+			limit:         10,
+			expectedNames: []string{"com.example/server-a@1.0.0", "com.example/server-a@1.1.0", "com.example/server-b@1.0.0", "org.other/server-c@2.0.0"},
+		},
+		{
+			name:          "filter by name",
+			filter:        &database.ServerFilter{Name: &name},
+			limit:         10,
+			expectedNames: []string{"com.example/server-a@1.0.0", "com.example/server-a@1.1.0"},
+		},
+		{
+			name:          "filter by remote URL",
+			filter:        &database.ServerFilter{RemoteURL: &remoteURL},
+			limit:         10,
+			expectedNames: []string{"com.example/server-b@1.0.0"},
+		},
+		{
+			name:          "filter by case-insensitive substring",
+			filter:        &database.ServerFilter{SubstringName: &substring},
+			limit:         10,
+			expectedNames: []string{"com.example/server-a@1.0.0", "com.example/server-a@1.1.0", "com.example/server-b@1.0.0", "org.other/server-c@2.0.0"},
+		},
+		{
+			name:          "filter by version",
+			filter:        &database.ServerFilter{Version: &version},
+			limit:         10,
+			expectedNames: []string{"com.example/server-a@1.0.0", "com.example/server-b@1.0.0"},
+		},
+		{
+			name:          "filter by latest",
+			filter:        &database.ServerFilter{IsLatest: &isLatest},
+			limit:         10,
+			expectedNames: []string{"com.example/server-a@1.1.0", "com.example/server-b@1.0.0", "org.other/server-c@2.0.0"},
+		},
+		{
+			name:          "filter by updated since",
+			filter:        &database.ServerFilter{UpdatedSince: &updatedSince},
+			limit:         10,
+			expectedNames: []string{"com.example/server-b@1.0.0", "org.other/server-c@2.0.0"},
+		},
+		{
+			name:          "sort by published time descending",
+			filter:        &database.ServerFilter{SortBy: database.SortByPublishedAt, SortOrder: database.SortOrderDesc},
+			limit:         10,
+			expectedNames: []string{"org.other/server-c@2.0.0", "com.example/server-b@1.0.0", "com.example/server-a@1.1.0", "com.example/server-a@1.0.0"},
+		},
+		{
+			name:          "sort by name descending",
+			filter:        &database.ServerFilter{SortBy: database.SortByName, SortOrder: database.SortOrderDesc},
+			limit:         10,
+			expectedNames: []string{"org.other/server-c@2.0.0", "com.example/server-b@1.0.0", "com.example/server-a@1.1.0", "com.example/server-a@1.0.0"},
+		},
+		{
+			name:          "name cursor",
+			cursor:        database.NameListPosition("com.example/server-a", "1.0.0"),
+			limit:         2,
+			expectedNames: []string{"com.example/server-a@1.1.0", "com.example/server-b@1.0.0"},
+		},
+		{
+			name:          "name-only cursor",
+			cursor:        database.NameListPosition("com.example/server-a", ""),
+			limit:         10,
+			expectedNames: []string{"com.example/server-b@1.0.0", "org.other/server-c@2.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, nextCursor, err := db.ListServers(ctx, nil, tt.filter, tt.cursor, tt.limit)
+			require.NoError(t, err)
+
+			actual := make([]string, len(results))
+			for i, result := range results {
+				actual[i] = result.Server.Name + "@" + result.Server.Version
+			}
+			assert.Equal(t, tt.expectedNames, actual)
+
+			if len(results) == tt.limit {
+				assert.NotEmpty(t, nextCursor)
+			} else {
+				assert.Empty(t, nextCursor)
+			}
+		})
+	}
+
+	t.Run("paginate through everything", func(t *testing.T) {
+		var seen []string
+		cursor := ""
+		for {
+			results, nextCursor, err := db.ListServers(ctx, nil, nil, cursor, 1)
+			require.NoError(t, err)
+			for _, result := range results {
+				seen = append(seen, result.Server.Name+"@"+result.Server.Version)
+			}
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+		assert.Len(t, seen, 4)
+	})
+
+	t.Run("paginate by updated time", func(t *testing.T) {
+		filter := &database.ServerFilter{SortBy: database.SortByUpdatedAt, SortOrder: database.SortOrderDesc}
+		var seen []string
+		cursor := ""
+		for {
+			results, nextCursor, err := db.ListServers(ctx, nil, filter, cursor, 3)
+			require.NoError(t, err)
+			for _, result := range results {
+				seen = append(seen, result.Server.Name+"@"+result.Server.Version)
+			}
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+		assert.Equal(t, []string{"org.other/server-c@2.0.0", "com.example/server-b@1.0.0", "com.example/server-a@1.1.0", "com.example/server-a@1.0.0"}, seen)
+	})
+
+	t.Run("cursor from a different sort order is rejected", func(t *testing.T) {
+		_, nextCursor, err := db.ListServers(ctx, nil, &database.ServerFilter{SortBy: database.SortByPublishedAt}, "", 1)
+		require.NoError(t, err)
+
+		_, _, err = db.ListServers(ctx, nil, &database.ServerFilter{SortBy: database.SortByPublishedAt, SortOrder: database.SortOrderDesc}, nextCursor, 1)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+
+		_, _, err = db.ListServers(ctx, nil, &database.ServerFilter{SortBy: database.SortByUpdatedAt}, "com.example/server-a:1.0.0", 1)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("plain cursors are left to the service", func(t *testing.T) {
+		_, _, err := db.ListServers(ctx, nil, nil, "com.example/server-a:1.0.0", 1)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestMemory_ListServerFilters(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	now := time.Now()
+	servers := []struct {
+		serverJSON  apiv0.ServerJSON
+		publishedAt time.Time
+		status      model.Status
+	}{
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "io.github.acme/npm-stdio", Description: "npm package", Version: "1.0.0",
+				Packages: []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@acme/npm-stdio", Version: "1.0.0", Transport: model.Transport{Type: model.TransportTypeStdio}}},
+			},
+			publishedAt: now.Add(-3 * time.Hour),
+			status:      model.StatusActive,
+		},
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "io.github.acme-labs/pypi-http", Description: "pypi package", Version: "1.0.0",
+				Packages: []model.Package{{RegistryType: model.RegistryTypePyPI, Identifier: "acme-http", Version: "1.0.0", Transport: model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "http://localhost:8000/mcp"}}},
+			},
+			publishedAt: now.Add(-2 * time.Hour),
+			status:      model.StatusDeprecated,
+		},
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "com.example/remote-sse", Description: "remote only", Version: "1.0.0",
+				Remotes: []model.Transport{{Type: model.TransportTypeSSE, URL: "https://example.com/sse"}},
+			},
+			publishedAt: now.Add(-1 * time.Hour),
+			status:      model.StatusDeleted,
+		},
+	}
+	for _, server := range servers {
+		_, err := db.CreateServer(ctx, nil, &server.serverJSON, &apiv0.RegistryExtensions{
+			Status:      server.status,
+			PublishedAt: server.publishedAt,
+			UpdatedAt:   server.publishedAt,
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	npm := model.RegistryTypeNPM
+	oci := model.RegistryTypeOCI
+	npmPackage := "@acme/npm-stdio"
+	otherVersion := "2.0.0"
+	stdio := model.TransportTypeStdio
+	sse := model.TransportTypeSSE
+	active := string(model.StatusActive)
+	namespace := "io.github.acme"
+	publishedSince := now.Add(-2 * time.Hour)
+	publishedBefore := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name          string
+		filter        *database.ServerFilter
+		expectedNames []string
+	}{
+		{
+			name:          "filter by package registry type",
+			filter:        &database.ServerFilter{RegistryType: &npm},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "filter by unused registry type",
+			filter:        &database.ServerFilter{RegistryType: &oci},
+			expectedNames: []string{},
+		},
+		{
+			name:          "look up package identifier",
+			filter:        &database.ServerFilter{RegistryType: &npm, PackageID: &npmPackage},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "look up package identifier and version",
+			filter:        &database.ServerFilter{RegistryType: &npm, PackageID: &npmPackage, PackageVersion: &otherVersion},
+			expectedNames: []string{},
+		},
+		{
+			name:          "look up package identifier in another registry",
+			filter:        &database.ServerFilter{RegistryType: &oci, PackageID: &npmPackage},
+			expectedNames: []string{},
+		},
+		{
+			name:          "filter by package transport",
+			filter:        &database.ServerFilter{TransportType: &stdio},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "filter by remote transport",
+			filter:        &database.ServerFilter{TransportType: &sse},
+			expectedNames: []string{"com.example/remote-sse"},
+		},
+		{
+			name:          "filter by status",
+			filter:        &database.ServerFilter{Status: &active},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "hide deprecated",
+			filter:        &database.ServerFilter{HideDeprecated: true},
+			expectedNames: []string{"com.example/remote-sse", "io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "filter by namespace prefix",
+			filter:        &database.ServerFilter{NamespacePrefix: &namespace},
+			expectedNames: []string{"io.github.acme-labs/pypi-http", "io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "filter by published since",
+			filter:        &database.ServerFilter{PublishedSince: &publishedSince},
+			expectedNames: []string{"com.example/remote-sse", "io.github.acme-labs/pypi-http"},
+		},
+		{
+			name:          "filter by published before",
+			filter:        &database.ServerFilter{PublishedBefore: &publishedBefore},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+		{
+			name:          "combine filters",
+			filter:        &database.ServerFilter{RegistryType: &npm, TransportType: &stdio, HideDeprecated: true},
+			expectedNames: []string{"io.github.acme/npm-stdio"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := db.ListServers(ctx, nil, tt.filter, "", 10)
+			require.NoError(t, err)
+
+			actual := make([]string, len(results))
+			for i, result := range results {
+				actual[i] = result.Server.Name
+			}
+			assert.Equal(t, tt.expectedNames, actual)
+		})
+	}
+}
+
+func TestMemory_RawListings(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour)
+	createMemoryServer(t, db, "com.example/raw-a", "1.0.0", false, base, "https://raw-a.example.com/mcp")
+	createMemoryServer(t, db, "com.example/raw-a", "1.1.0", true, base.Add(time.Minute))
+	createMemoryServer(t, db, "com.example/raw-b", "2.0.0", true, base.Add(2*time.Minute))
+
+	assertSameJSON := func(t *testing.T, decoded, raw []*apiv0.ServerResponse) {
+		t.Helper()
+		require.Len(t, raw, len(decoded))
+		for i := range decoded {
+			decodedJSON, err := json.Marshal(decoded[i].Server)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(decodedJSON), string(raw[i].RawServer))
+			assert.Equal(t, decoded[i].Meta, raw[i].Meta)
+		}
+	}
+
+	decoded, decodedCursor, err := db.ListServers(ctx, nil, nil, "", 2)
+	require.NoError(t, err)
+	raw, rawCursor, err := db.ListServersRaw(ctx, nil, nil, "", 2)
+	require.NoError(t, err)
+	assertSameJSON(t, decoded, raw)
+	assert.Equal(t, decodedCursor, rawCursor)
+
+	decoded, err = db.GetAllVersionsByServerName(ctx, nil, "com.example/raw-a")
+	require.NoError(t, err)
+	raw, err = db.GetAllVersionsByServerNameRaw(ctx, nil, "com.example/raw-a")
+	require.NoError(t, err)
+	assertSameJSON(t, decoded, raw)
+
+	_, err = db.GetAllVersionsByServerNameRaw(ctx, nil, "com.example/missing")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestMemory_UpdateServerAndStatus(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	publishedAt := time.Now().Add(-time.Hour)
+	createMemoryServer(t, db, "com.example/update-server", "1.0.0", true, publishedAt)
+
+	updated, err := db.UpdateServer(ctx, nil, "com.example/update-server", "1.0.0", &apiv0.ServerJSON{
+		Name:        "com.example/update-server",
+		Description: "Updated description",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Updated description", updated.Server.Description)
+	assert.True(t, updated.Meta.Official.UpdatedAt.After(publishedAt))
+	assert.True(t, updated.Meta.Official.IsLatest)
+
+	_, err = db.UpdateServer(ctx, nil, "com.example/update-server", "1.0.0", &apiv0.ServerJSON{
+		Name:    "com.example/other-server",
+		Version: "1.0.0",
+	})
+	assert.ErrorIs(t, err, database.ErrInvalidInput)
+
+	_, err = db.UpdateServer(ctx, nil, "com.example/missing", "1.0.0", &apiv0.ServerJSON{
+		Name:    "com.example/missing",
+		Version: "1.0.0",
+	})
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	deprecated, err := db.SetServerStatus(ctx, nil, "com.example/update-server", "1.0.0", string(model.StatusDeprecated))
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, deprecated.Meta.Official.Status)
+	assert.Equal(t, "Updated description", deprecated.Server.Description)
+
+	_, err = db.SetServerStatus(ctx, nil, "com.example/update-server", "1.0.0", "bogus")
+	assert.ErrorIs(t, err, database.ErrInvalidInput)
+}
+
+func TestMemory_DeprecateServer(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	createMemoryServer(t, db, "com.example/deprecated", "1.0.0", true, time.Now())
+
+	result, err := db.DeprecateServer(ctx, nil, "com.example/deprecated", "1.0.0", "Moved to v2", "com.example/deprecated-v2")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, result.Meta.Official.Status)
+	assert.Equal(t, "Moved to v2", result.Meta.Official.DeprecationMessage)
+	assert.Equal(t, "com.example/deprecated-v2", result.Meta.Official.ReplacedBy)
+
+	stored, err := db.GetServerByNameAndVersion(ctx, nil, "com.example/deprecated", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "Moved to v2", stored.Meta.Official.DeprecationMessage)
+
+	result, err = db.SetServerStatus(ctx, nil, "com.example/deprecated", "1.0.0", string(model.StatusActive))
+	require.NoError(t, err)
+	assert.Empty(t, result.Meta.Official.DeprecationMessage)
+	assert.Empty(t, result.Meta.Official.ReplacedBy)
+
+	_, err = db.DeprecateServer(ctx, nil, "com.example/missing", "1.0.0", "", "")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestMemory_ServerTags(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	now := time.Now()
+	createMemoryServer(t, db, "com.example/tagged", "1.0.0", false, now.Add(-time.Hour))
+	createMemoryServer(t, db, "com.example/tagged", "1.1.0", true, now)
+	createMemoryServer(t, db, "com.example/other", "1.0.0", true, now)
+
+	// Publishing a latest version tags it
+	server, err := db.GetServerByNameAndTag(ctx, nil, "com.example/tagged", database.TagLatest)
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", server.Server.Version)
+
+	_, err = db.SetServerTag(ctx, nil, "com.example/tagged", "beta", "1.0.0")
+	require.NoError(t, err)
+	_, err = db.SetServerTag(ctx, nil, "com.example/tagged", "beta", "9.9.9")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	// Moving latest moves the flag
+	tag, err := db.SetServerTag(ctx, nil, "com.example/tagged", database.TagLatest, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", tag.Version)
+	latest, err := db.GetServerByName(ctx, nil, "com.example/tagged")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", latest.Server.Version)
+
+	tagFilter := database.TagLatest
+	results, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Tag: &tagFilter}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "com.example/other", results[0].Server.Name)
+	assert.Equal(t, "1.0.0", results[1].Server.Version)
+
+	tags, err := db.ListServerTags(ctx, nil, "com.example/tagged")
+	require.NoError(t, err)
+	require.Len(t, tags, 2)
+	assert.Equal(t, "beta", tags[0].Tag)
+	assert.Equal(t, database.TagLatest, tags[1].Tag)
+
+	require.NoError(t, db.DeleteServerTag(ctx, nil, "com.example/tagged", "beta"))
+	assert.ErrorIs(t, db.DeleteServerTag(ctx, nil, "com.example/tagged", "beta"), database.ErrNotFound)
+	_, err = db.GetServerByNameAndTag(ctx, nil, "com.example/tagged", "beta")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	// Unmarking the latest version also removes its tag
+	require.NoError(t, db.UnmarkAsLatest(ctx, nil, "com.example/tagged"))
+	_, err = db.GetServerByNameAndTag(ctx, nil, "com.example/tagged", database.TagLatest)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestMemory_TransactionHandling(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	newServer := func(name string) (*apiv0.ServerJSON, *apiv0.RegistryExtensions) {
+		return &apiv0.ServerJSON{
+			Name:        name,
+			Description: "Transaction test server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: time.Now(),
+			UpdatedAt:   time.Now(),
+			IsLatest:    true,
+		}
+	}
+
+	t.Run("successful transaction", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			serverJSON, officialMeta := newServer("com.example/transaction-success")
+			_, err := db.CreateServer(ctx, tx, serverJSON, officialMeta)
+			return err
+		})
+		require.NoError(t, err)
+
+		_, err = db.GetServerByName(ctx, nil, "com.example/transaction-success")
+		assert.NoError(t, err)
+	})
+
+	t.Run("failed transaction rollback", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			serverJSON, officialMeta := newServer("com.example/transaction-rollback")
+			if _, err := db.CreateServer(ctx, tx, serverJSON, officialMeta); err != nil {
+				return err
+			}
+
+			// Writes are visible inside the transaction
+			if _, err := db.GetServerByName(ctx, tx, serverJSON.Name); err != nil {
+				return err
+			}
+
+			// Existing rows modified in the transaction are restored too
+			if err := db.UnmarkAsLatest(ctx, tx, "com.example/transaction-success"); err != nil {
+				return err
+			}
+
+			return assert.AnError
+		})
+		assert.Equal(t, assert.AnError, err)
+
+		_, err = db.GetServerByName(ctx, nil, "com.example/transaction-rollback")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		result, err := db.GetServerByName(ctx, nil, "com.example/transaction-success")
+		require.NoError(t, err)
+		assert.True(t, result.Meta.Official.IsLatest)
+	})
+
+	t.Run("uncommitted writes are invisible outside the transaction", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			serverJSON, officialMeta := newServer("com.example/transaction-isolated")
+			if _, err := db.CreateServer(ctx, tx, serverJSON, officialMeta); err != nil {
+				return err
+			}
+
+			_, err := db.GetServerByName(ctx, nil, serverJSON.Name)
+			assert.ErrorIs(t, err, database.ErrNotFound)
+			return nil
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("transaction cannot be used after it ends", func(t *testing.T) {
+		var leaked pgx.Tx
+		err := db.InTransaction(ctx, func(_ context.Context, tx pgx.Tx) error {
+			leaked = tx
+			return nil
+		})
+		require.NoError(t, err)
+
+		_, err = db.GetServerByName(ctx, leaked, "com.example/transaction-success")
+		assert.ErrorIs(t, err, pgx.ErrTxClosed)
+	})
+
+	t.Run("publish lock requires a transaction", func(t *testing.T) {
+		assert.Error(t, db.AcquirePublishLock(ctx, nil, "com.example/transaction-success"))
+	})
+
+	t.Run("nested transactions fail instead of deadlocking", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			nested := db.InTransaction(ctx, func(_ context.Context, _ pgx.Tx) error { return nil })
+			assert.ErrorIs(t, nested, database.ErrNestedTransaction)
+
+			// Writes without a transaction would start one too
+			assert.ErrorIs(t, db.UnmarkAsLatest(ctx, nil, "com.example/transaction-success"), database.ErrNestedTransaction)
+
+			// Reads without a transaction see the committed state
+			_, err := db.GetServerByName(ctx, nil, "com.example/transaction-success")
+			return err
+		})
+		require.NoError(t, err)
+
+		// The context of a finished transaction can start new ones
+		var finished context.Context
+		require.NoError(t, db.InTransaction(ctx, func(ctx context.Context, _ pgx.Tx) error {
+			finished = ctx
+			return nil
+		}))
+		assert.NoError(t, db.InTransaction(finished, func(_ context.Context, _ pgx.Tx) error { return nil }))
+	})
+}
+
+func TestMemory_ConcurrentTransactions(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	const workers = 10
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+				if err := db.AcquirePublishLock(ctx, tx, "com.example/concurrent"); err != nil {
+					return err
+				}
+				count, err := db.CountServerVersions(ctx, tx, "com.example/concurrent")
+				if err != nil {
+					return err
+				}
+				_, err = db.CreateServer(ctx, tx, &apiv0.ServerJSON{
+					Name: "com.example/concurrent",
+					// Versions only stay unique if transactions are serialized
+					Version: fmt.Sprintf("1.0.%d", count),
+				}, &apiv0.RegistryExtensions{
+					Status:      model.StatusActive,
+					PublishedAt: time.Now(),
+					UpdatedAt:   time.Now(),
+				})
+				return err
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	count, err := db.CountServerVersions(ctx, nil, "com.example/concurrent")
+	require.NoError(t, err)
+	assert.Equal(t, workers, count)
+}
+
+func TestMemory_FullTextSearch(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	servers := []apiv0.ServerJSON{
+		{Name: "io.github.user/weather", Title: "Weather", Description: "Current conditions and forecast data", Version: "1.0.0"},
+		{Name: "com.example/climate", Description: "Historical weather records", Version: "1.0.0"},
+		{Name: "com.example/maps", Title: "Maps", Description: "Geocoding and routing", Version: "1.0.0"},
+	}
+	for i := range servers {
+		_, err := db.CreateServer(ctx, nil, &servers[i], &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: time.Now(),
+			UpdatedAt:   time.Now(),
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	search := func(query, cursor string, limit int) ([]string, string) {
+		results, nextCursor, err := db.ListServers(ctx, nil, &database.ServerFilter{Query: &query}, cursor, limit)
+		require.NoError(t, err)
+		names := make([]string, len(results))
+		for i, result := range results {
+			names[i] = result.Server.Name
+		}
+		return names, nextCursor
+	}
+
+	t.Run("matches description and orders by relevance", func(t *testing.T) {
+		names, _ := search("weather", "", 10)
+		assert.Equal(t, []string{"io.github.user/weather", "com.example/climate"}, names)
+	})
+
+	t.Run("all terms must match", func(t *testing.T) {
+		names, _ := search("weather forecast", "", 10)
+		assert.Equal(t, []string{"io.github.user/weather"}, names)
+	})
+
+	t.Run("partial words match", func(t *testing.T) {
+		names, _ := search("geocod", "", 10)
+		assert.Equal(t, []string{"com.example/maps"}, names)
+	})
+
+	t.Run("search operators are honored", func(t *testing.T) {
+		// Excluded terms and phrases are not bypassed by partial word matching
+		names, _ := search("weather -forecast", "", 10)
+		assert.Equal(t, []string{"com.example/climate"}, names)
+		names, _ = search(`"historical weather"`, "", 10)
+		assert.Equal(t, []string{"com.example/climate"}, names)
+		names, _ = search(`"weather historical"`, "", 10)
+		assert.Empty(t, names)
+		names, _ = search("geocod -routing", "", 10)
+		assert.Empty(t, names)
+
+		names, _ = search("maps or climate", "", 10)
+		assert.ElementsMatch(t, []string{"com.example/maps", "com.example/climate"}, names)
+		names, _ = search("-forecast", "", 10)
+		assert.ElementsMatch(t, []string{"com.example/maps", "com.example/climate"}, names)
+	})
+
+	t.Run("cursor continues in relevance order", func(t *testing.T) {
+		first, nextCursor := search("weather", "", 1)
+		require.NotEmpty(t, nextCursor)
+		second, _ := search("weather", nextCursor, 1)
+		assert.Equal(t, []string{"io.github.user/weather"}, first)
+		assert.Equal(t, []string{"com.example/climate"}, second)
+	})
+
+	t.Run("malformed cursor is rejected", func(t *testing.T) {
+		query := "weather"
+		_, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Query: &query}, "com.example/climate:1.0.0", 10)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestMemory_AuditEvents(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	for i, name := range []string{"com.example/audit-a", "com.example/audit-b", "com.example/audit-a"} {
+		event := &apiv0.AuditEvent{
+			Action:       database.AuditActionPublish,
+			ServerName:   name,
+			Version:      fmt.Sprintf("1.0.%d", i),
+			ActorMethod:  "github-at",
+			ActorSubject: "publisher",
+			After:        map[string]any{"description": "Audited server"},
+		}
+		require.NoError(t, db.CreateAuditEvent(ctx, nil, event))
+		assert.Equal(t, int64(i+1), event.ID)
+		assert.False(t, event.CreatedAt.IsZero())
+	}
+
+	t.Run("pages newest first", func(t *testing.T) {
+		page, cursor, err := db.ListAuditEvents(ctx, nil, nil, "", 2)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Equal(t, int64(3), page[0].ID)
+		assert.Equal(t, int64(2), page[1].ID)
+		require.NotEmpty(t, cursor)
+
+		page, _, err = db.ListAuditEvents(ctx, nil, nil, cursor, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, int64(1), page[0].ID)
+	})
+
+	t.Run("filters by server name", func(t *testing.T) {
+		name := "com.example/audit-a"
+		events, _, err := db.ListAuditEvents(ctx, nil, &database.AuditEventFilter{ServerName: &name}, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+	})
+
+	t.Run("rejects malformed cursor", func(t *testing.T) {
+		_, _, err := db.ListAuditEvents(ctx, nil, nil, "not-a-cursor", 10)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("events roll back with their transaction", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			require.NoError(t, db.CreateAuditEvent(ctx, tx, &apiv0.AuditEvent{
+				Action:      database.AuditActionEdit,
+				ServerName:  "com.example/audit-a",
+				Version:     "1.0.0",
+				ActorMethod: "github-at",
+			}))
+			return fmt.Errorf("abort")
+		})
+		require.Error(t, err)
+
+		events, _, err := db.ListAuditEvents(ctx, nil, nil, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 3)
+	})
+
+	t.Run("rejects unknown actions", func(t *testing.T) {
+		err := db.CreateAuditEvent(ctx, nil, &apiv0.AuditEvent{
+			Action:      "rename",
+			ServerName:  "com.example/audit-a",
+			Version:     "1.0.0",
+			ActorMethod: "github-at",
+		})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("events of mutations without a server version", func(t *testing.T) {
+		block := &apiv0.AuditEvent{
+			Action:      database.AuditActionNamespaceBlock,
+			ActorMethod: "github-at",
+			After:       map[string]any{"namespace": "com.evil"},
+		}
+		require.NoError(t, db.CreateAuditEvent(ctx, nil, block))
+		assert.NotZero(t, block.ID)
+
+		err := db.CreateAuditEvent(ctx, nil, &apiv0.AuditEvent{Action: database.AuditActionTagSet, ServerName: "com.example/audit-a", ActorMethod: "github-at"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestMemory_ChangeEvents(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	createMemoryServer(t, db, "com.example/feed-server", "1.0.0", true, time.Now())
+
+	for _, changeType := range []string{database.ChangeTypePublish, database.ChangeTypeEdit, database.ChangeTypeStatusChange} {
+		event := &apiv0.ChangeEvent{Type: changeType, ServerName: "com.example/feed-server", Version: "1.0.0"}
+		require.NoError(t, db.CreateChangeEvent(ctx, nil, event))
+		assert.False(t, event.ChangedAt.IsZero())
+	}
+
+	t.Run("resumes after a sequence number", func(t *testing.T) {
+		changes, err := db.ListChangeEvents(ctx, nil, 0, 2)
+		require.NoError(t, err)
+		require.Len(t, changes, 2)
+		assert.Equal(t, int64(1), changes[0].Sequence)
+		assert.Equal(t, database.ChangeTypePublish, changes[0].Type)
+		require.NotNil(t, changes[0].Server)
+		assert.Equal(t, "com.example/feed-server", changes[0].Server.Server.Name)
+
+		changes, err = db.ListChangeEvents(ctx, nil, changes[1].Sequence, 2)
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		assert.Equal(t, int64(3), changes[0].Sequence)
+		assert.Equal(t, database.ChangeTypeStatusChange, changes[0].Type)
+
+		changes, err = db.ListChangeEvents(ctx, nil, 3, 2)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("rejects changes to unknown servers", func(t *testing.T) {
+		err := db.CreateChangeEvent(ctx, nil, &apiv0.ChangeEvent{Type: database.ChangeTypeEdit, ServerName: "com.example/missing", Version: "1.0.0"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("changes roll back with their transaction", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			require.NoError(t, db.CreateChangeEvent(ctx, tx, &apiv0.ChangeEvent{Type: database.ChangeTypeEdit, ServerName: "com.example/feed-server", Version: "1.0.0"}))
+			return fmt.Errorf("abort")
+		})
+		require.Error(t, err)
+
+		changes, err := db.ListChangeEvents(ctx, nil, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, changes, 3)
+	})
+}
+
+func TestMemory_Webhooks(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	subscription := &apiv0.WebhookSubscription{URL: "https://hooks.example.com/mcp", NamespacePattern: "com.example/*", Secret: "secret", OwnerMethod: "github-at", OwnerSubject: "example"}
+	require.NoError(t, db.CreateWebhookSubscription(ctx, nil, subscription))
+	assert.NotZero(t, subscription.ID)
+
+	t.Run("secrets are only returned to dispatchers", func(t *testing.T) {
+		subscriptions, err := db.ListWebhookSubscriptions(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Empty(t, subscriptions[0].Secret)
+
+		stored, err := db.GetWebhookSubscription(ctx, nil, subscription.ID)
+		require.NoError(t, err)
+		assert.Equal(t, "com.example/*", stored.NamespacePattern)
+		assert.Empty(t, stored.Secret)
+	})
+
+	var ids []int64
+	for _, version := range []string{"1.0.0", "1.1.0"} {
+		delivery := &apiv0.WebhookDelivery{SubscriptionID: subscription.ID, EventType: database.WebhookEventPublished, ServerName: "com.example/weather", Version: version}
+		require.NoError(t, db.CreateWebhookDelivery(ctx, nil, delivery, []byte(`{}`)))
+		assert.Equal(t, database.WebhookDeliveryPending, delivery.Status)
+		require.NotNil(t, delivery.NextAttemptAt)
+		ids = append(ids, delivery.ID)
+	}
+
+	t.Run("due deliveries are claimed once per lease", func(t *testing.T) {
+		jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 1, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, ids[0], jobs[0].Delivery.ID)
+		assert.Equal(t, "secret", jobs[0].Secret)
+		assert.Equal(t, []byte(`{}`), jobs[0].Payload)
+
+		jobs, err = db.ClaimWebhookDeliveries(ctx, nil, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, ids[1], jobs[0].Delivery.ID)
+
+		jobs, err = db.ClaimWebhookDeliveries(ctx, nil, 10, time.Hour)
+		require.NoError(t, err)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("attempts are recorded", func(t *testing.T) {
+		require.NoError(t, db.RecordWebhookAttempt(ctx, nil, ids[0], &database.WebhookAttempt{Status: database.WebhookDeliveryDelivered, StatusCode: 200}))
+		require.NoError(t, db.RecordWebhookAttempt(ctx, nil, ids[1], &database.WebhookAttempt{Status: database.WebhookDeliveryDead, StatusCode: 500, Error: "unexpected status 500"}))
+		assert.ErrorIs(t, db.RecordWebhookAttempt(ctx, nil, 999, &database.WebhookAttempt{Status: database.WebhookDeliveryDead}), database.ErrNotFound)
+		assert.ErrorIs(t, db.RecordWebhookAttempt(ctx, nil, ids[0], &database.WebhookAttempt{Status: "lost"}), database.ErrInvalidInput)
+
+		deliveries, err := db.ListWebhookDeliveries(ctx, nil, subscription.ID, nil, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 2)
+		assert.Equal(t, ids[1], deliveries[0].ID, "newest first")
+		assert.Equal(t, database.WebhookDeliveryDead, deliveries[0].Status)
+		assert.Equal(t, "unexpected status 500", deliveries[0].LastError)
+		assert.Nil(t, deliveries[0].NextAttemptAt)
+		assert.NotNil(t, deliveries[1].DeliveredAt)
+
+		dead := database.WebhookDeliveryDead
+		deliveries, err = db.ListWebhookDeliveries(ctx, nil, subscription.ID, &dead, 10)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, ids[1], deliveries[0].ID)
+	})
+
+	t.Run("dead deliveries can be redelivered", func(t *testing.T) {
+		delivery, err := db.RedeliverWebhookDelivery(ctx, nil, ids[1])
+		require.NoError(t, err)
+		assert.Equal(t, database.WebhookDeliveryPending, delivery.Status)
+		assert.Zero(t, delivery.Attempts)
+
+		jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, ids[1], jobs[0].Delivery.ID)
+
+		_, err = db.RedeliverWebhookDelivery(ctx, nil, 999)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("deliveries need a subscription", func(t *testing.T) {
+		err := db.CreateWebhookDelivery(ctx, nil, &apiv0.WebhookDelivery{SubscriptionID: 999, EventType: database.WebhookEventPublished, ServerName: "com.example/weather", Version: "1.0.0"}, []byte(`{}`))
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("deleting a subscription deletes its deliveries", func(t *testing.T) {
+		require.NoError(t, db.DeleteWebhookSubscription(ctx, nil, subscription.ID))
+		assert.ErrorIs(t, db.DeleteWebhookSubscription(ctx, nil, subscription.ID), database.ErrNotFound)
+
+		deliveries, err := db.ListWebhookDeliveries(ctx, nil, subscription.ID, nil, 10)
+		require.NoError(t, err)
+		assert.Empty(t, deliveries)
+		_, err = db.RedeliverWebhookDelivery(ctx, nil, ids[0])
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+func TestMemory_RateLimitBuckets(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	// A bucket of two tokens that refills slowly enough not to matter during the test
+	for i, expected := range []bool{true, true, false} {
+		tokens, taken, err := db.TakeRateLimitToken(ctx, nil, "publish|subject:example", 2, 0.001)
+		require.NoError(t, err)
+		assert.Equal(t, expected, taken, "request %d", i)
+		assert.Less(t, tokens, 2.0)
+	}
+
+	// Other keys have buckets of their own
+	_, taken, err := db.TakeRateLimitToken(ctx, nil, "publish|subject:other", 2, 0.001)
+	require.NoError(t, err)
+	assert.True(t, taken)
+
+	// Deleting idle buckets refills them
+	require.NoError(t, db.DeleteIdleRateLimitBuckets(ctx, nil, time.Now().Add(time.Second)))
+	tokens, taken, err := db.TakeRateLimitToken(ctx, nil, "publish|subject:example", 2, 0.001)
+	require.NoError(t, err)
+	assert.True(t, taken)
+	assert.InDelta(t, 1.0, tokens, 0.01)
+}
+
+func TestMemory_NamespaceBlocks(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "io.github.spammer", Reason: "Spam", CreatedBy: "oidc:admin"}))
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "com.example", Reason: "Old", CreatedBy: "oidc:admin", ExpiresAt: &expired}))
+	assert.ErrorIs(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "io.github.other", CreatedBy: "oidc:admin"}), database.ErrInvalidInput)
+
+	blocks, err := db.ListNamespaceBlocks(ctx, nil, false)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "io.github.spammer", blocks[0].Namespace)
+	assert.False(t, blocks[0].CreatedAt.IsZero())
+
+	blocks, err = db.ListNamespaceBlocks(ctx, nil, true)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "com.example", blocks[0].Namespace)
+
+	// Blocking again replaces the block
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "com.example", Reason: "Again", CreatedBy: "oidc:other"}))
+	blocks, err = db.ListNamespaceBlocks(ctx, nil, false)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "Again", blocks[0].Reason)
+	assert.Nil(t, blocks[0].ExpiresAt)
+
+	require.NoError(t, db.DeleteNamespaceBlock(ctx, nil, "com.example"))
+	assert.ErrorIs(t, db.DeleteNamespaceBlock(ctx, nil, "com.example"), database.ErrNotFound)
+}
+
+func TestMemory_RemoteURLClaims(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	conflicts, err := db.ClaimRemoteURLs(ctx, nil, "com.example/weather", []string{"https://weather.example.com/mcp", "https://weather.example.com/sse"})
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// Claims held by other servers are reported and left in place
+	conflicts, err = db.ClaimRemoteURLs(ctx, nil, "com.example/other", []string{"https://weather.example.com/sse", "https://other.example.com/mcp"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"https://weather.example.com/sse": "com.example/weather"}, conflicts)
+
+	serverName := "com.example/weather"
+	claims, err := db.ListRemoteURLClaims(ctx, nil, &database.RemoteURLClaimFilter{ServerName: &serverName})
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+	assert.Equal(t, "https://weather.example.com/mcp", claims[0].URL)
+	assert.False(t, claims[0].ClaimedAt.IsZero())
+
+	require.NoError(t, db.ReleaseRemoteURLClaims(ctx, nil, serverName, []string{"https://weather.example.com/sse"}))
+	claims, err = db.ListRemoteURLClaims(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+	assert.Equal(t, "https://other.example.com/mcp", claims[0].URL)
+	assert.Equal(t, "https://weather.example.com/sse", claims[1].URL)
+
+	require.NoError(t, db.DeleteRemoteURLClaim(ctx, nil, "https://weather.example.com/sse"))
+	assert.ErrorIs(t, db.DeleteRemoteURLClaim(ctx, nil, "https://weather.example.com/sse"), database.ErrNotFound)
+}
+
+func TestMemory_PackageValidations(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	record := func(identifier string, passed bool, checkedAt time.Time) *apiv0.PackageValidation {
+		t.Helper()
+		validation := &apiv0.PackageValidation{
+			ServerName:   "com.example/weather",
+			Version:      "1.0.0",
+			RegistryType: "npm",
+			Identifier:   identifier,
+			Passed:       passed,
+			CheckedAt:    checkedAt,
+		}
+		if !passed {
+			validation.Error = "package not found"
+		}
+		require.NoError(t, db.RecordPackageValidation(ctx, nil, validation))
+		return validation
+	}
+
+	start := time.Now()
+	passed := record("@example/weather", true, start)
+	assert.Zero(t, passed.ConsecutiveFailures)
+	require.NotNil(t, passed.LastPassedAt)
+
+	// Failures are counted from the last check that passed, which is kept
+	failed := record("@example/weather", false, start.Add(time.Minute))
+	assert.Equal(t, 1, failed.ConsecutiveFailures)
+	require.NotNil(t, failed.LastPassedAt)
+	assert.WithinDuration(t, start, *failed.LastPassedAt, time.Millisecond)
+	failed = record("@example/weather", false, start.Add(2*time.Minute))
+	assert.Equal(t, 2, failed.ConsecutiveFailures)
+
+	record("@example/weather-cli", true, start.Add(2*time.Minute))
+	assert.ErrorIs(t, db.RecordPackageValidation(ctx, nil, &apiv0.PackageValidation{ServerName: "com.example/weather"}), database.ErrInvalidInput)
+
+	validations, err := db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{FailingOnly: true})
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.Equal(t, "@example/weather", validations[0].Identifier)
+	assert.Equal(t, 2, validations[0].ConsecutiveFailures)
+	assert.Equal(t, "package not found", validations[0].Error)
+
+	serverName := "com.example/other"
+	validations, err = db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{ServerName: &serverName})
+	require.NoError(t, err)
+	assert.Empty(t, validations)
+
+	// Inconclusive failures keep the count
+	inconclusive := &apiv0.PackageValidation{
+		ServerName:   "com.example/weather",
+		Version:      "1.0.0",
+		RegistryType: "npm",
+		Identifier:   "@example/weather",
+		Error:        "npm returned status 503",
+		Inconclusive: true,
+		CheckedAt:    start.Add(150 * time.Second),
+	}
+	require.NoError(t, db.RecordPackageValidation(ctx, nil, inconclusive))
+	assert.Equal(t, 2, inconclusive.ConsecutiveFailures)
+	validations, err = db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{FailingOnly: true})
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.True(t, validations[0].Inconclusive)
+	assert.Equal(t, 2, validations[0].ConsecutiveFailures)
+
+	// A passing check resets the count
+	passed = record("@example/weather", true, start.Add(3*time.Minute))
+	assert.False(t, passed.Inconclusive)
+	assert.Zero(t, passed.ConsecutiveFailures)
+
+	require.NoError(t, db.DeletePackageValidationsCheckedBefore(ctx, nil, start.Add(3*time.Minute)))
+	validations, err = db.ListPackageValidations(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.Equal(t, "@example/weather", validations[0].Identifier)
+}
+
+func TestMemory_RemoteProbes(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	record := func(serverName, url, status string, checkedAt time.Time) *database.RemoteProbe {
+		t.Helper()
+		probe := &database.RemoteProbe{
+			ServerName: serverName,
+			Version:    "1.0.0",
+			RemoteHealth: apiv0.RemoteHealth{
+				URL:           url,
+				TransportType: "streamable-http",
+				Status:        status,
+				CheckedAt:     checkedAt,
+			},
+		}
+		if status == apiv0.RemoteStatusUp {
+			latencyMs := 42
+			probe.ProtocolVersion, probe.LatencyMs = "2025-06-18", &latencyMs
+		} else {
+			probe.Error = "connection refused"
+		}
+		require.NoError(t, db.RecordRemoteProbe(ctx, nil, probe))
+		return probe
+	}
+
+	start := time.Now()
+	up := record("com.example/weather", "https://weather.example.com/mcp", apiv0.RemoteStatusUp, start)
+	require.NotNil(t, up.LastSuccessAt)
+
+	// Failed probes keep what the last successful probe found
+	down := record("com.example/weather", "https://weather.example.com/mcp", apiv0.RemoteStatusDown, start.Add(time.Minute))
+	assert.Equal(t, "2025-06-18", down.ProtocolVersion)
+	require.NotNil(t, down.LatencyMs)
+	assert.Equal(t, 42, *down.LatencyMs)
+	require.NotNil(t, down.LastSuccessAt)
+	assert.WithinDuration(t, start, *down.LastSuccessAt, time.Millisecond)
+
+	record("com.example/calendar", "https://calendar.example.com/mcp", apiv0.RemoteStatusSkipped, start.Add(2*time.Minute))
+	assert.ErrorIs(t, db.RecordRemoteProbe(ctx, nil, &database.RemoteProbe{ServerName: "com.example/weather"}), database.ErrInvalidInput)
+
+	probes, err := db.ListRemoteProbes(ctx, nil, &database.RemoteProbeFilter{ServerNames: []string{"com.example/weather", "com.example/other"}})
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, apiv0.RemoteStatusDown, probes[0].Status)
+	assert.Equal(t, "connection refused", probes[0].Error)
+
+	probes, err = db.ListRemoteProbes(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, probes, 2)
+	assert.Equal(t, "com.example/calendar", probes[0].ServerName)
+	assert.Nil(t, probes[0].LastSuccessAt)
+
+	require.NoError(t, db.DeleteRemoteProbesCheckedBefore(ctx, nil, start.Add(2*time.Minute)))
+	probes, err = db.ListRemoteProbes(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, "com.example/calendar", probes[0].ServerName)
+}
+
+func TestMemory_ServerCapabilities(t *testing.T) {
+	db := database.NewMemory()
+	ctx := context.Background()
+
+	for _, name := range []string{"com.example/issues", "com.example/calendar"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Remote server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+		require.NoError(t, err)
+	}
+
+	start := time.Now()
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{
+		ServerName: "com.example/issues",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceIntrospection,
+		Tools:      []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}},
+		Resources:  []apiv0.CapabilityResource{{URI: "repo://issues", Name: "issues"}},
+		CheckedAt:  start,
+	}))
+
+	// Failed introspections keep what the last successful one found
+	failed := &apiv0.ServerCapabilities{
+		ServerName: "com.example/issues",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceIntrospection,
+		Error:      "initialize: unexpected status 503",
+		CheckedAt:  start.Add(time.Minute),
+	}
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, failed))
+	assert.Equal(t, []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}}, failed.Tools)
+	require.NotNil(t, failed.UpdatedAt)
+	assert.WithinDuration(t, start, *failed.UpdatedAt, time.Millisecond)
+
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{
+		ServerName: "com.example/calendar",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceManifest,
+		Prompts:    []apiv0.CapabilityPrompt{{Name: "plan_week"}},
+		CheckedAt:  start,
+	}))
+	assert.ErrorIs(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{ServerName: "com.example/issues"}), database.ErrInvalidInput)
+
+	capabilities, err := db.GetServerCapabilities(ctx, nil, "com.example/issues", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "initialize: unexpected status 503", capabilities.Error)
+	assert.Len(t, capabilities.Resources, 1)
+	assert.NotNil(t, capabilities.Prompts)
+	_, err = db.GetServerCapabilities(ctx, nil, "com.example/issues", "2.0.0")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	name := "com.example/calendar"
+	list, err := db.ListServerCapabilities(ctx, nil, &database.ServerCapabilitiesFilter{ServerName: &name})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, apiv0.CapabilitySourceManifest, list[0].Source)
+
+	list, err = db.ListServerCapabilities(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "com.example/calendar", list[0].ServerName)
+
+	for capability, expected := range map[string]string{
+		"create_issue":  "com.example/issues",
+		"repo://issues": "com.example/issues",
+		"issues":        "com.example/issues",
+		"plan_week":     "com.example/calendar",
+	} {
+		servers, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Capability: &capability}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, servers, 1, capability)
+		assert.Equal(t, expected, servers[0].Server.Name)
+	}
+	capability := "create"
+	servers, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Capability: &capability}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, servers)
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"crypto/sha256"
 	"embed"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
@@ -10,6 +12,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -17,11 +20,39 @@ import (
 //go:embed migrations/*.sql
 var migrationFiles embed.FS
 
+// downMigrationSuffix marks the optional script that reverts the migration with the same prefix
+const downMigrationSuffix = ".down.sql"
+
+// Migration errors
+var (
+	ErrMigrationChecksumMismatch = errors.New("applied migration has been modified")
+	ErrPendingMigrations         = errors.New("database schema has pending migrations")
+	ErrNoDownMigration           = errors.New("migration cannot be reverted: no down script")
+)
+
 // Migration represents a database migration
 type Migration struct {
-	Version int
-	Name    string
-	SQL     string
+	Version  int
+	Name     string
+	SQL      string
+	DownSQL  string // optional script reverting SQL, empty when the migration is irreversible
+	Checksum string // hex SHA-256 of SQL, recorded when applied to detect later edits
+}
+
+// MigrationStatus describes a known migration and whether it has been applied to the database
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool // applied with a different checksum than the embedded file
+
+	unrecorded bool // applied before checksums were tracked
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	appliedAt time.Time
+	checksum  *string // nil for migrations applied before checksums were recorded
 }
 
 // Migrator handles database migrations
@@ -43,26 +74,52 @@ func (m *Migrator) ensureMigrationsTable(ctx context.Context) error {
 			applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
 		)
 	`
-	_, err := m.conn.Exec(ctx, query)
+	if _, err := m.conn.Exec(ctx, query); err != nil {
+		return err
+	}
+
+	// Checksums were added after the table was first created
+	_, err := m.conn.Exec(ctx, "ALTER TABLE schema_migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64)")
 	return err
 }
 
-// getAppliedMigrations returns a map of already applied migration versions
-func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[int]bool, error) {
-	query := "SELECT version FROM schema_migrations ORDER BY version"
+// getAppliedMigrations returns the already applied migrations by version. It only reads, so a database
+// without the migrations table, or with one from before checksums were tracked, is read as it is.
+func (m *Migrator) getAppliedMigrations(ctx context.Context) (map[int]appliedMigration, error) {
+	var tableExists, hasChecksums bool
+	err := m.conn.QueryRow(ctx, `
+		SELECT to_regclass('schema_migrations') IS NOT NULL,
+		       EXISTS (
+		           SELECT 1 FROM information_schema.columns
+		           WHERE table_schema = current_schema() AND table_name = 'schema_migrations' AND column_name = 'checksum'
+		       )
+	`).Scan(&tableExists, &hasChecksums)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up migrations table: %w", err)
+	}
+
+	applied := make(map[int]appliedMigration)
+	if !tableExists {
+		return applied, nil
+	}
+
+	query := "SELECT version, applied_at, checksum FROM schema_migrations ORDER BY version"
+	if !hasChecksums {
+		query = "SELECT version, applied_at, NULL::VARCHAR FROM schema_migrations ORDER BY version"
+	}
 	rows, err := m.conn.Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query applied migrations: %w", err)
 	}
 	defer rows.Close()
 
-	applied := make(map[int]bool)
 	for rows.Next() {
 		var version int
-		if err := rows.Scan(&version); err != nil {
+		var row appliedMigration
+		if err := rows.Scan(&version, &row.appliedAt, &row.checksum); err != nil {
 			return nil, fmt.Errorf("failed to scan migration version: %w", err)
 		}
-		applied[version] = true
+		applied[version] = row
 	}
 
 	return applied, rows.Err()
@@ -75,7 +132,8 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
 	}
 
-	var migrations []Migration
+	byVersion := make(map[int]*Migration)
+	downScripts := make(map[int]string)
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
 			continue
@@ -101,11 +159,35 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 			return nil, fmt.Errorf("failed to read migration file %s: %w", name, err)
 		}
 
-		migrations = append(migrations, Migration{
-			Version: version,
-			Name:    strings.TrimSuffix(name, ".sql"),
-			SQL:     string(content),
-		})
+		if strings.HasSuffix(name, downMigrationSuffix) {
+			downScripts[version] = string(content)
+			continue
+		}
+
+		if existing, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, existing.Name, name)
+		}
+		checksum := sha256.Sum256(content)
+		byVersion[version] = &Migration{
+			Version:  version,
+			Name:     strings.TrimSuffix(name, ".sql"),
+			SQL:      string(content),
+			Checksum: hex.EncodeToString(checksum[:]),
+		}
+	}
+
+	// Pair down scripts with the migrations they revert
+	for version, downSQL := range downScripts {
+		migration, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down script for migration %d has no matching up migration", version)
+		}
+		migration.DownSQL = downSQL
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		migrations = append(migrations, *migration)
 	}
 
 	// Sort migrations by version
@@ -116,36 +198,104 @@ func (m *Migrator) loadMigrations() ([]Migration, error) {
 	return migrations, nil
 }
 
-// Migrate runs all pending migrations
-func (m *Migrator) Migrate(ctx context.Context) error {
-	// Ensure the migrations table exists
-	if err := m.ensureMigrationsTable(ctx); err != nil {
-		return fmt.Errorf("failed to create migrations table: %w", err)
-	}
-
+// Status reports every known migration and whether it has been applied, in version order.
+// It does not change the database: without a migrations table, every migration is pending.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
 	// Get applied migrations
 	applied, err := m.getAppliedMigrations(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
 	}
 
 	// Load all migration files
 	migrations, err := m.loadMigrations()
 	if err != nil {
-		return fmt.Errorf("failed to load migrations: %w", err)
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	statuses := make([]MigrationStatus, len(migrations))
+	for i, migration := range migrations {
+		statuses[i] = MigrationStatus{Migration: migration}
+		if row, ok := applied[migration.Version]; ok {
+			appliedAt := row.appliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+			statuses[i].Modified = row.checksum != nil && *row.checksum != migration.Checksum
+			statuses[i].unrecorded = row.checksum == nil
+		}
+	}
+
+	return statuses, nil
+}
+
+// verifyChecksums fails if any applied migration differs from the embedded file. Unless dryRun is
+// set, migrations applied before checksums were tracked have their current checksum recorded instead.
+func (m *Migrator) verifyChecksums(ctx context.Context, statuses []MigrationStatus, dryRun bool) error {
+	var modified []string
+	for _, status := range statuses {
+		if status.Modified {
+			modified = append(modified, status.Name)
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s", ErrMigrationChecksumMismatch, strings.Join(modified, ", "))
+	}
+
+	if dryRun {
+		return nil
+	}
+	for _, status := range statuses {
+		if !status.unrecorded {
+			continue
+		}
+		if _, err := m.conn.Exec(ctx,
+			"UPDATE schema_migrations SET checksum = $1 WHERE version = $2 AND checksum IS NULL",
+			status.Checksum, status.Version); err != nil {
+			return fmt.Errorf("failed to record migration checksums: %w", err)
+		}
+	}
+	return nil
+}
+
+// Migrate runs all pending migrations
+func (m *Migrator) Migrate(ctx context.Context) error {
+	_, err := m.MigrateUp(ctx, 0, false)
+	return err
+}
+
+// MigrateUp applies pending migrations in version order, stopping after version target when it is
+// non-zero. With dryRun set, the migrations that would be applied are returned without running them.
+func (m *Migrator) MigrateUp(ctx context.Context, target int, dryRun bool) ([]Migration, error) {
+	// Dry runs only read, like Status
+	if !dryRun {
+		if err := m.ensureMigrationsTable(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create migrations table: %w", err)
+		}
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verifyChecksums(ctx, statuses, dryRun); err != nil {
+		return nil, err
 	}
 
 	// Find pending migrations
 	var pending []Migration
-	for _, migration := range migrations {
-		if !applied[migration.Version] {
-			pending = append(pending, migration)
+	for _, status := range statuses {
+		if status.Applied || (target > 0 && status.Version > target) {
+			continue
 		}
+		pending = append(pending, status.Migration)
 	}
 
 	if len(pending) == 0 {
 		log.Println("No pending migrations")
-		return nil
+		return nil, nil
+	}
+	if dryRun {
+		return pending, nil
 	}
 
 	log.Printf("Applying %d pending migrations", len(pending))
@@ -153,12 +303,79 @@ func (m *Migrator) Migrate(ctx context.Context) error {
 	// Apply each pending migration in a transaction
 	for _, migration := range pending {
 		if err := m.applyMigration(ctx, migration); err != nil {
-			return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+			return nil, fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
 		}
 		log.Printf("Applied migration %d: %s", migration.Version, migration.Name)
 	}
 
 	log.Println("All migrations applied successfully")
+	return pending, nil
+}
+
+// MigrateDown reverts applied migrations newer than version target, newest first. Every migration
+// to revert must have a down script; this is checked before anything is run. With dryRun set, the
+// migrations that would be reverted are returned without running them.
+func (m *Migrator) MigrateDown(ctx context.Context, target int, dryRun bool) ([]Migration, error) {
+	if !dryRun {
+		if err := m.ensureMigrationsTable(ctx); err != nil {
+			return nil, fmt.Errorf("failed to create migrations table: %w", err)
+		}
+	}
+
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.verifyChecksums(ctx, statuses, dryRun); err != nil {
+		return nil, err
+	}
+
+	var toRevert []Migration
+	for i := len(statuses) - 1; i >= 0; i-- {
+		status := statuses[i]
+		if !status.Applied || status.Version <= target {
+			continue
+		}
+		if status.DownSQL == "" {
+			return nil, fmt.Errorf("%w: %s", ErrNoDownMigration, status.Name)
+		}
+		toRevert = append(toRevert, status.Migration)
+	}
+
+	if len(toRevert) == 0 || dryRun {
+		return toRevert, nil
+	}
+
+	for _, migration := range toRevert {
+		if err := m.revertMigration(ctx, migration); err != nil {
+			return nil, fmt.Errorf("failed to revert migration %d (%s): %w", migration.Version, migration.Name, err)
+		}
+		log.Printf("Reverted migration %d: %s", migration.Version, migration.Name)
+	}
+
+	return toRevert, nil
+}
+
+// CheckUpToDate fails if the database has pending or modified migrations, without changing anything
+func (m *Migrator) CheckUpToDate(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if status.Modified {
+			return fmt.Errorf("%w: %s", ErrMigrationChecksumMismatch, status.Name)
+		}
+		if !status.Applied {
+			pending = append(pending, status.Name)
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s (run `registry migrate up`)", ErrPendingMigrations, strings.Join(pending, ", "))
+	}
+
 	return nil
 }
 
@@ -182,11 +399,38 @@ func (m *Migrator) applyMigration(ctx context.Context, migration Migration) erro
 
 	// Record the migration as applied
 	_, err = tx.Exec(ctx,
-		"INSERT INTO schema_migrations (version, name) VALUES ($1, $2)",
-		migration.Version, migration.Name)
+		"INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)",
+		migration.Version, migration.Name, migration.Checksum)
 	if err != nil {
 		return fmt.Errorf("failed to record migration: %w", err)
 	}
 
 	return tx.Commit(ctx)
 }
+
+// revertMigration runs the down script of a single migration in a transaction
+func (m *Migrator) revertMigration(ctx context.Context, migration Migration) error {
+	tx, err := m.conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(ctx); err != nil && !errors.Is(err, pgx.ErrTxClosed) {
+			log.Printf("Failed to rollback migration transaction: %v", err)
+		}
+	}()
+
+	// Execute the down SQL
+	_, err = tx.Exec(ctx, migration.DownSQL)
+	if err != nil {
+		return fmt.Errorf("failed to execute down migration SQL: %w", err)
+	}
+
+	// Forget the migration so it is applied again by the next migrate up
+	_, err = tx.Exec(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+	if err != nil {
+		return fmt.Errorf("failed to record migration revert: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,34 @@
+//nolint:testpackage // Tests the unexported loading of embedded migration files
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := NewMigrator(nil).loadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	checksums := make(map[string]bool)
+	for i, migration := range migrations {
+		// Versions are dense and ordered, and down scripts are never loaded as up migrations
+		assert.Equal(t, i+1, migration.Version, "migration %s", migration.Name)
+		assert.NotContains(t, migration.Name, ".down")
+		assert.NotEmpty(t, migration.SQL)
+
+		assert.Len(t, migration.Checksum, 64, "migration %s", migration.Name)
+		assert.False(t, checksums[migration.Checksum], "migration %s has a duplicate checksum", migration.Name)
+		checksums[migration.Checksum] = true
+	}
+
+	byName := make(map[string]Migration)
+	for _, migration := range migrations {
+		byName[migration.Name] = migration
+	}
+	assert.Empty(t, byName["001_initial_schema"].DownSQL, "data migrations are irreversible")
+	assert.Contains(t, byName["015_add_change_events"].DownSQL, "DROP TABLE IF EXISTS change_events")
+}
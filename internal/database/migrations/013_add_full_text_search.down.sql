@@ -0,0 +1,11 @@
+-- Revert 013_add_full_text_search
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_search_text_trgm;
+DROP INDEX IF EXISTS idx_servers_search_vector;
+
+ALTER TABLE servers DROP COLUMN IF EXISTS search_text;
+ALTER TABLE servers DROP COLUMN IF EXISTS search_vector;
+
+COMMIT;
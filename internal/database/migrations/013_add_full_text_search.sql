@@ -0,0 +1,26 @@
+-- Add full-text search over server name, title and description
+-- The search columns are generated from the JSONB value, so they stay in sync with
+-- publishes and edits without any application changes
+
+BEGIN;
+
+-- Weighted document for ranked full-text search
+-- Punctuation in names (e.g. io.github.user/weather-api) is replaced with spaces so each
+-- segment is indexed as a separate word instead of a single host/path token
+ALTER TABLE servers ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', regexp_replace(coalesce(value->>'name', ''), '[./_-]+', ' ', 'g')), 'A') ||
+    setweight(to_tsvector('english', coalesce(value->>'title', '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(value->>'description', '')), 'B')
+) STORED;
+
+-- Plain text document for trigram matching of partial words and typos
+ALTER TABLE servers ADD COLUMN search_text TEXT GENERATED ALWAYS AS (
+    coalesce(value->>'name', '') || ' ' ||
+    coalesce(value->>'title', '') || ' ' ||
+    coalesce(value->>'description', '')
+) STORED;
+
+CREATE INDEX idx_servers_search_vector ON servers USING GIN (search_vector);
+CREATE INDEX idx_servers_search_text_trgm ON servers USING GIN (search_text gin_trgm_ops);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Revert 014_add_audit_events
+-- This discards the audit log
+
+BEGIN;
+
+DROP TABLE IF EXISTS audit_events;
+DROP FUNCTION IF EXISTS prevent_audit_event_changes();
+
+COMMIT;
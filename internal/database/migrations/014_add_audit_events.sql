@@ -0,0 +1,45 @@
+-- Add an append-only audit log of registry mutations
+-- Events are written by the application in the same transaction as the mutation they describe.
+-- Events of mutations that do not concern a server version leave version empty, and server_name too
+-- when they concern no server.
+
+BEGIN;
+
+CREATE TABLE audit_events (
+    id BIGSERIAL PRIMARY KEY,
+    action VARCHAR(50) NOT NULL,
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    actor_method VARCHAR(50) NOT NULL,
+    actor_subject VARCHAR(255) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_audit_action_valid CHECK (action IN (
+        'publish', 'edit', 'status_change',
+        'tag_set', 'tag_delete',
+        'namespace_block', 'namespace_unblock',
+        'remote_url_release',
+        'webhook_subscribe', 'webhook_unsubscribe'
+    ))
+);
+
+-- Events are listed newest first, optionally narrowed to a server or an actor
+CREATE INDEX idx_audit_events_server_name ON audit_events (server_name, id DESC);
+CREATE INDEX idx_audit_events_actor_subject ON audit_events (actor_subject, id DESC);
+CREATE INDEX idx_audit_events_created_at ON audit_events (created_at);
+
+-- Reject any attempt to rewrite history
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trg_audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW
+    EXECUTE FUNCTION prevent_audit_event_changes();
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Revert 015_add_change_events
+-- This discards the change feed, so mirrors must resynchronize from scratch if it is reapplied
+
+BEGIN;
+
+DROP TABLE IF EXISTS change_events;
+
+COMMIT;
@@ -0,0 +1,17 @@
+-- Add a sequence-numbered change feed for exact incremental sync
+-- Unlike updated_at, sequence numbers are handed out under a global lock that is held until
+-- commit, so a reader that has seen sequence N can never later observe a change below N
+
+BEGIN;
+
+CREATE TABLE change_events (
+    seq BIGSERIAL PRIMARY KEY,
+    change_type VARCHAR(50) NOT NULL,
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT check_change_type_valid CHECK (change_type IN ('publish', 'edit', 'status_change', 'latest_change')),
+    CONSTRAINT fk_change_events_server FOREIGN KEY (server_name, version) REFERENCES servers (server_name, version)
+);
+
+COMMIT;
@@ -0,0 +1,11 @@
+-- Revert 016_add_sort_indexes
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_published_at;
+DROP INDEX IF EXISTS idx_servers_updated_at;
+
+CREATE INDEX idx_servers_published_at ON servers (published_at DESC);
+CREATE INDEX idx_servers_updated_at ON servers (updated_at DESC);
+
+COMMIT;
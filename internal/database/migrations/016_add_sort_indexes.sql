@@ -0,0 +1,13 @@
+-- Support keyset pagination when listing servers by publish or update time
+-- The name and version columns make the index order match the ORDER BY used for pagination,
+-- so pages can be read from the index in either direction
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_published_at;
+DROP INDEX IF EXISTS idx_servers_updated_at;
+
+CREATE INDEX idx_servers_published_at ON servers (published_at, server_name, version);
+CREATE INDEX idx_servers_updated_at ON servers (updated_at, server_name, version);
+
+COMMIT;
@@ -0,0 +1,10 @@
+-- Revert 017_add_list_filter_indexes
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_package_registry_types;
+DROP INDEX IF EXISTS idx_servers_package_transport_types;
+DROP INDEX IF EXISTS idx_servers_remote_types;
+DROP INDEX IF EXISTS idx_servers_name_pattern;
+
+COMMIT;
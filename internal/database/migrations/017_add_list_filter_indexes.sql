@@ -0,0 +1,20 @@
+-- Support filtering server listings by package registry, transport type and namespace
+-- The JSONB expression indexes hold just the values being filtered on, so containment
+-- queries against them stay small compared to indexing the whole packages/remotes arrays.
+-- The expressions must match the ones used by ListServers exactly for the planner to use them.
+
+BEGIN;
+
+CREATE INDEX idx_servers_package_registry_types ON servers
+    USING GIN (jsonb_path_query_array(value, '$.packages[*].registryType'));
+
+CREATE INDEX idx_servers_package_transport_types ON servers
+    USING GIN (jsonb_path_query_array(value, '$.packages[*].transport.type'));
+
+CREATE INDEX idx_servers_remote_types ON servers
+    USING GIN (jsonb_path_query_array(value, '$.remotes[*].type'));
+
+-- Prefix matches with LIKE can only use a btree index under the C collation or with pattern ops
+CREATE INDEX idx_servers_name_pattern ON servers (server_name text_pattern_ops);
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Revert 018_add_package_lookup_index
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_json_packages;
+
+CREATE INDEX idx_servers_json_packages ON servers USING GIN ((value->'packages'));
+
+COMMIT;
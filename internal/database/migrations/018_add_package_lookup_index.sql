@@ -0,0 +1,12 @@
+-- Support reverse lookup from a package identifier to the servers that ship it
+-- Lookups are containment queries on the packages array; jsonb_path_ops indexes only support
+-- containment but are much smaller and faster for it than the default GIN operator class,
+-- so this replaces the general-purpose packages index
+
+BEGIN;
+
+DROP INDEX IF EXISTS idx_servers_json_packages;
+
+CREATE INDEX idx_servers_json_packages ON servers USING GIN ((value->'packages') jsonb_path_ops);
+
+COMMIT;
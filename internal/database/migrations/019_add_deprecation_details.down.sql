@@ -0,0 +1,8 @@
+-- Revert 019_add_deprecation_details
+
+BEGIN;
+
+ALTER TABLE servers DROP COLUMN IF EXISTS replaced_by;
+ALTER TABLE servers DROP COLUMN IF EXISTS deprecation_message;
+
+COMMIT;
@@ -0,0 +1,9 @@
+-- Let publishers explain a deprecation and point users at a successor server
+-- Both columns are only meaningful while status is deprecated and are cleared when a version is reactivated
+
+BEGIN;
+
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS deprecation_message TEXT;
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS replaced_by VARCHAR(255);
+
+COMMIT;
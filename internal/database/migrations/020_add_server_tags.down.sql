@@ -0,0 +1,7 @@
+-- Revert 020_add_server_tags
+
+BEGIN;
+
+DROP TABLE IF EXISTS server_tags;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Add npm-style dist-tags: named pointers such as latest, next or beta from a server to one of its versions
+-- is_latest is kept in sync with the latest tag by the application, so existing queries and the
+-- isLatest metadata keep working
+
+BEGIN;
+
+CREATE TABLE server_tags (
+    server_name VARCHAR(255) NOT NULL,
+    tag VARCHAR(100) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (server_name, tag),
+    CONSTRAINT fk_server_tags_server FOREIGN KEY (server_name, version) REFERENCES servers (server_name, version)
+);
+
+-- Every server starts with its current latest version tagged as latest
+INSERT INTO server_tags (server_name, tag, version, updated_at)
+SELECT server_name, 'latest', version, updated_at
+FROM servers
+WHERE is_latest = true;
+
+COMMIT;
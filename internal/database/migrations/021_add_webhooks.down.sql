@@ -0,0 +1,9 @@
+-- Revert 021_add_webhooks
+-- This drops every subscription and any deliveries that have not been sent yet
+
+BEGIN;
+
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
+
+COMMIT;
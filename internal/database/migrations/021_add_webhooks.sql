@@ -0,0 +1,39 @@
+-- Add webhook subscriptions and an outbox of deliveries to them
+-- Deliveries are written in the same transaction as the change they describe, so an event is
+-- queued if and only if the change commits. Workers lease due rows with FOR UPDATE SKIP LOCKED.
+
+BEGIN;
+
+CREATE TABLE webhook_subscriptions (
+    id BIGSERIAL PRIMARY KEY,
+    url TEXT NOT NULL,
+    namespace_pattern VARCHAR(255) NOT NULL DEFAULT '*',
+    secret TEXT NOT NULL,
+    owner_method VARCHAR(50) NOT NULL,
+    owner_subject VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    subscription_id BIGINT NOT NULL REFERENCES webhook_subscriptions (id) ON DELETE CASCADE,
+    event_type VARCHAR(50) NOT NULL,
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_status_code INTEGER,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT check_webhook_event_type_valid CHECK (event_type IN ('server.published', 'server.updated', 'server.deprecated', 'server.deleted')),
+    CONSTRAINT check_webhook_delivery_status_valid CHECK (status IN ('pending', 'delivered', 'dead'))
+);
+
+-- Workers only ever look for pending deliveries that are due
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX idx_webhook_deliveries_subscription ON webhook_deliveries (subscription_id, id DESC);
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Revert 022_add_rate_limit_buckets
+
+BEGIN;
+
+DROP TABLE IF EXISTS rate_limit_buckets;
+
+COMMIT;
@@ -0,0 +1,16 @@
+-- Add token buckets for rate limits shared by all replicas
+-- The table is unlogged: buckets are cheap to lose on a crash (limits just start over) and are
+-- written on every rate-limited request, so skipping the WAL matters more than durability.
+
+BEGIN;
+
+CREATE UNLOGGED TABLE rate_limit_buckets (
+    key VARCHAR(512) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- Idle buckets are full, so they are periodically deleted
+CREATE INDEX idx_rate_limit_buckets_updated_at ON rate_limit_buckets (updated_at);
+
+COMMIT;
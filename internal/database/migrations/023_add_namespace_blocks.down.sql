@@ -0,0 +1,8 @@
+-- Revert 023_add_namespace_blocks
+-- This lifts every block
+
+BEGIN;
+
+DROP TABLE IF EXISTS namespace_blocks;
+
+COMMIT;
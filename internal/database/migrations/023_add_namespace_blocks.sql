@@ -0,0 +1,14 @@
+-- Move the namespace denylist from code into the database, so that admins can block a namespace
+-- without a release. Expired blocks are kept for the record but no longer enforced.
+
+BEGIN;
+
+CREATE TABLE namespace_blocks (
+    namespace VARCHAR(255) PRIMARY KEY,
+    reason TEXT NOT NULL,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE
+);
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Revert 024_add_remote_url_claims
+-- Conflicts are then no longer detected, since the application checks them against this table
+
+BEGIN;
+
+DROP TABLE IF EXISTS remote_url_claims;
+
+COMMIT;
@@ -0,0 +1,42 @@
+-- Track which server each remote URL belongs to, so that publishing checks conflicts with an index
+-- lookup instead of scanning the remotes of every server. URLs are stored canonicalized by the
+-- application: surrounding ASCII whitespace trimmed, scheme and host lowercased, default ports
+-- (443 for https, 80 for http) dropped, trailing slashes trimmed from the path and template
+-- variables such as {tenant} replaced with {}. The backfill below applies the same rules.
+
+BEGIN;
+
+CREATE TABLE remote_url_claims (
+    url TEXT PRIMARY KEY,
+    server_name VARCHAR(255) NOT NULL,
+    claimed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_remote_url_claims_server_name ON remote_url_claims (server_name);
+
+-- Claim the remotes of servers that have not been deleted. Where servers already share a URL,
+-- the one that published it first keeps it.
+WITH remotes AS (
+    SELECT s.server_name, s.published_at,
+           regexp_replace(btrim(remote->>'url', E' \t\n\r\f\x0B'), '\{[^}]*\}', '{}', 'g') AS url
+    FROM servers s, jsonb_array_elements(COALESCE(s.value->'remotes', '[]'::jsonb)) AS remote
+    WHERE s.status <> 'deleted' AND COALESCE(remote->>'url', '') <> ''
+), parts AS (
+    SELECT server_name, published_at,
+           regexp_replace(regexp_replace(
+               COALESCE(lower(substring(url from '^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*')), ''),
+               '^(https://.*):443$', '\1'), '^(http://.*):80$', '\1') AS origin,
+           regexp_replace(url, '^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*', '') AS rest
+    FROM remotes
+)
+INSERT INTO remote_url_claims (url, server_name, claimed_at)
+SELECT DISTINCT ON (canonical) canonical, server_name, COALESCE(published_at, NOW())
+FROM (
+    SELECT server_name, published_at,
+           origin || rtrim(split_part(rest, '?', 1), '/') || COALESCE(substring(rest from '\?.*$'), '') AS canonical
+    FROM parts
+) canonicalized
+ORDER BY canonical, published_at
+ON CONFLICT (url) DO NOTHING;
+
+COMMIT;
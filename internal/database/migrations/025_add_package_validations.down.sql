@@ -0,0 +1,8 @@
+-- Revert 025_add_package_validations
+-- Failure counts start over if the table is added back
+
+BEGIN;
+
+DROP TABLE IF EXISTS package_validations;
+
+COMMIT;
@@ -0,0 +1,28 @@
+-- Record the outcome of periodically re-checking package registry ownership of latest active versions,
+-- which is otherwise only checked at publish time. consecutive_failures counts failed checks since the
+-- last one that passed, and decides when a version is deprecated automatically. Inconclusive checks,
+-- which failed because of the package registry rather than the package (such as the registry timing out
+-- or returning a server error), keep the count as it was, so registry outages do not deprecate versions.
+
+BEGIN;
+
+CREATE TABLE package_validations (
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    registry_type VARCHAR(50) NOT NULL,
+    identifier TEXT NOT NULL,
+    package_version VARCHAR(255) NOT NULL DEFAULT '',
+    passed BOOLEAN NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    inconclusive BOOLEAN NOT NULL DEFAULT FALSE,
+    consecutive_failures INTEGER NOT NULL DEFAULT 0,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_passed_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (server_name, version, registry_type, identifier),
+    CONSTRAINT fk_package_validations_server FOREIGN KEY (server_name, version) REFERENCES servers (server_name, version)
+);
+
+-- The admin endpoint lists failing packages
+CREATE INDEX idx_package_validations_failing ON package_validations (server_name, version) WHERE NOT passed;
+
+COMMIT;
@@ -0,0 +1,8 @@
+-- Revert 026_add_remote_probes
+-- Servers are listed without remote health until the table is added back and the remotes probed again
+
+BEGIN;
+
+DROP TABLE IF EXISTS remote_probes;
+
+COMMIT;
@@ -0,0 +1,22 @@
+-- Record the outcome of probing the remotes of latest active versions with an MCP initialize handshake.
+-- status, error and checked_at describe the latest probe, while protocol_version, latency_ms and
+-- last_success_at are kept from the latest probe that succeeded.
+
+BEGIN;
+
+CREATE TABLE remote_probes (
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    transport_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('up', 'down', 'skipped')),
+    error TEXT NOT NULL DEFAULT '',
+    protocol_version VARCHAR(50) NOT NULL DEFAULT '',
+    latency_ms INTEGER,
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_success_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (server_name, version, url),
+    CONSTRAINT fk_remote_probes_server FOREIGN KEY (server_name, version) REFERENCES servers (server_name, version)
+);
+
+COMMIT;
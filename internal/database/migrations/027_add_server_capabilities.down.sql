@@ -0,0 +1,8 @@
+-- Revert 027_add_server_capabilities
+-- Capabilities are forgotten and the capability filter matches nothing until versions are introspected again
+
+BEGIN;
+
+DROP TABLE IF EXISTS server_capabilities;
+
+COMMIT;
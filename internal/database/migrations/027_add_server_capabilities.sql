@@ -0,0 +1,25 @@
+-- Record the tools, prompts and resources of server versions, read from the capabilities manifest in their
+-- publisher-provided metadata or listed by their remotes. names holds every tool, prompt and resource name
+-- (and resource URI) so that server listings can be filtered by capability through the GIN index.
+-- Failed introspections only update error and checked_at, keeping the capabilities found before.
+
+BEGIN;
+
+CREATE TABLE server_capabilities (
+    server_name VARCHAR(255) NOT NULL,
+    version VARCHAR(255) NOT NULL,
+    source VARCHAR(20) NOT NULL CHECK (source IN ('manifest', 'introspection')),
+    tools JSONB NOT NULL DEFAULT '[]',
+    prompts JSONB NOT NULL DEFAULT '[]',
+    resources JSONB NOT NULL DEFAULT '[]',
+    names TEXT[] NOT NULL DEFAULT '{}',
+    error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (server_name, version),
+    CONSTRAINT fk_server_capabilities_server FOREIGN KEY (server_name, version) REFERENCES servers (server_name, version)
+);
+
+CREATE INDEX idx_server_capabilities_names ON server_capabilities USING GIN (names);
+
+COMMIT;
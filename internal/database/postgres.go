@@ -19,9 +19,29 @@ import (
 
 // PostgreSQL is an implementation of the Database interface using PostgreSQL
 type PostgreSQL struct {
-	pool *pgxpool.Pool
+	pool    *pgxpool.Pool
+	replica *pgxpool.Pool // optional read replica, nil when all traffic goes to the primary
+	retry   RetryPolicy
+	onRetry RetryObserver
 }
 
+// Options configures the PostgreSQL connection pools and startup behavior
+type Options struct {
+	MaxConns        int32  // size of the primary pool, defaultMaxConns when zero
+	ReplicaURI      string // optional read replica that serves non-transactional reads
+	ReplicaMaxConns int32  // size of the replica pool, defaultMaxConns when zero
+	SkipMigrations  bool   // leave migrations to the migrate command, only checking that none are pending
+
+	// Transient transaction failures are retried under this policy, calling OnRetry before each retry
+	RetryPolicy RetryPolicy
+	OnRetry     RetryObserver
+}
+
+const (
+	defaultMaxConns = 30 // Handle good concurrent load
+	defaultMinConns = 5  // Keep connections warm for fast response
+)
+
 // Executor is an interface for executing queries (satisfied by both pgx.Tx and pgxpool.Pool)
 type Executor interface {
 	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
@@ -37,17 +57,28 @@ func (db *PostgreSQL) getExecutor(tx pgx.Tx) Executor {
 	return db.pool
 }
 
-// NewPostgreSQL creates a new instance of the PostgreSQL database
-func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, error) {
+// getReader returns the executor for a read-only query. Reads outside a transaction go to the
+// replica when one is configured, so they may briefly lag behind writes to the primary.
+func (db *PostgreSQL) getReader(tx pgx.Tx) Executor {
+	if tx == nil && db.replica != nil {
+		return db.replica
+	}
+	return db.getExecutor(tx)
+}
+
+// newPool creates and pings a connection pool with stability-focused defaults
+func newPool(ctx context.Context, connectionURI string, maxConns int32) (*pgxpool.Pool, error) {
 	// Parse connection config for pool settings
 	config, err := pgxpool.ParseConfig(connectionURI)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse PostgreSQL config: %w", err)
 	}
 
-	// Configure pool for stability-focused defaults
-	config.MaxConns = 30                      // Handle good concurrent load
-	config.MinConns = 5                       // Keep connections warm for fast response
+	if maxConns <= 0 {
+		maxConns = defaultMaxConns
+	}
+	config.MaxConns = maxConns
+	config.MinConns = min(defaultMinConns, maxConns)
 	config.MaxConnIdleTime = 30 * time.Minute // Keep connections available for bursts
 	config.MaxConnLifetime = 2 * time.Hour    // Refresh connections regularly for stability
 
@@ -59,24 +90,89 @@ func NewPostgreSQL(ctx context.Context, connectionURI string) (*PostgreSQL, erro
 
 	// Test the connection
 	if err = pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("failed to ping PostgreSQL: %w", err)
 	}
 
-	// Run migrations using a single connection from the pool
+	return pool, nil
+}
+
+// NewPostgreSQL creates a new instance of the PostgreSQL database
+func NewPostgreSQL(ctx context.Context, connectionURI string, options Options) (*PostgreSQL, error) {
+	pool, err := newPool(ctx, connectionURI, options.MaxConns)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := runMigrations(ctx, pool, options.SkipMigrations); err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	db := &PostgreSQL{
+		pool:    pool,
+		retry:   options.RetryPolicy.withDefaults(),
+		onRetry: options.OnRetry,
+	}
+
+	if options.ReplicaURI != "" {
+		db.replica, err = newPool(ctx, options.ReplicaURI, options.ReplicaMaxConns)
+		if err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("failed to connect to read replica: %w", err)
+		}
+	}
+
+	return db, nil
+}
+
+// runMigrations applies pending migrations using a single connection from the pool, or with
+// skip set only verifies that the schema is up to date
+func runMigrations(ctx context.Context, pool *pgxpool.Pool, skip bool) error {
 	conn, err := pool.Acquire(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to acquire connection for migrations: %w", err)
+		return fmt.Errorf("failed to acquire connection for migrations: %w", err)
 	}
 	defer conn.Release()
 
 	migrator := NewMigrator(conn.Conn())
-	if err := migrator.Migrate(ctx); err != nil {
-		return nil, fmt.Errorf("failed to run database migrations: %w", err)
+	if skip {
+		err = migrator.CheckUpToDate(ctx)
+	} else {
+		err = migrator.Migrate(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run database migrations: %w", err)
+	}
+	return nil
+}
+
+// HasReplica reports whether non-transactional reads are served by a read replica
+func (db *PostgreSQL) HasReplica() bool {
+	return db.replica != nil
+}
+
+// ReplicaLag reports how far the read replica is behind the primary. A replica that has replayed
+// everything it received reports zero, as does a replica URI that points at a primary.
+func (db *PostgreSQL) ReplicaLag(ctx context.Context) (time.Duration, error) {
+	if db.replica == nil {
+		return 0, nil
+	}
+
+	query := `
+		SELECT CASE
+			WHEN NOT pg_is_in_recovery() THEN 0
+			WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+			ELSE COALESCE(EXTRACT(EPOCH FROM NOW() - pg_last_xact_replay_timestamp()), 0)
+		END::float8
+	`
+
+	var seconds float64
+	if err := db.replica.QueryRow(ctx, query).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("failed to query replica lag: %w", err)
 	}
 
-	return &PostgreSQL{
-		pool: pool,
-	}, nil
+	return time.Duration(seconds * float64(time.Second)), nil
 }
 
 func (db *PostgreSQL) ListServers(
@@ -85,6 +181,29 @@ func (db *PostgreSQL) ListServers(
 	filter *ServerFilter,
 	cursor string,
 	limit int,
+) ([]*apiv0.ServerResponse, string, error) {
+	return db.listServers(ctx, tx, filter, cursor, limit, false)
+}
+
+// ListServersRaw lists servers like ListServers, but passes the stored JSONB through without decoding it
+func (db *PostgreSQL) ListServersRaw(
+	ctx context.Context,
+	tx pgx.Tx,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+) ([]*apiv0.ServerResponse, string, error) {
+	return db.listServers(ctx, tx, filter, cursor, limit, true)
+}
+
+// listServers implements ListServers and ListServersRaw, decoding the JSONB value of each row unless raw is set
+func (db *PostgreSQL) listServers(
+	ctx context.Context,
+	tx pgx.Tx,
+	filter *ServerFilter,
+	cursor string,
+	limit int,
+	raw bool,
 ) ([]*apiv0.ServerResponse, string, error) {
 	if limit <= 0 {
 		limit = 10
@@ -98,6 +217,7 @@ func (db *PostgreSQL) ListServers(
 	var whereConditions []string
 	args := []any{}
 	argIndex := 1
+	queryArg := 0
 
 	// Add filters using dedicated columns for better performance
 	if filter != nil {
@@ -131,25 +251,115 @@ func (db *PostgreSQL) ListServers(
 			args = append(args, *filter.IsLatest)
 			argIndex++
 		}
+		if filter.Tag != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM server_tags WHERE server_tags.server_name = servers.server_name AND server_tags.version = servers.version AND server_tags.tag = $%d)", argIndex))
+			args = append(args, *filter.Tag)
+			argIndex++
+		}
+		if filter.Query != nil {
+			// Match whole words via the tsvector index, or partial words via the trigram index. Trigrams
+			// know nothing of phrases, alternatives and exclusions, so queries using them only match words.
+			if parseSearchQuery(*filter.Query).operators {
+				whereConditions = append(whereConditions, fmt.Sprintf("search_vector @@ websearch_to_tsquery('english', $%d)", argIndex))
+			} else {
+				whereConditions = append(whereConditions, fmt.Sprintf("(search_vector @@ websearch_to_tsquery('english', $%d) OR $%d <%% search_text)", argIndex, argIndex))
+			}
+			args = append(args, *filter.Query)
+			queryArg = argIndex
+			argIndex++
+		}
+		if filter.RegistryType != nil {
+			// Matches the expression index on package registry types
+			whereConditions = append(whereConditions, fmt.Sprintf("jsonb_path_query_array(value, '$.packages[*].registryType') @> jsonb_build_array($%d::text)", argIndex))
+			args = append(args, *filter.RegistryType)
+			argIndex++
+		}
+		if filter.PackageID != nil {
+			// A single containment check so that all fields must match within the same package,
+			// which the jsonb_path_ops index on packages can answer directly
+			whereConditions = append(whereConditions, fmt.Sprintf("value->'packages' @> $%d::jsonb", argIndex))
+			args = append(args, packageContainment(filter))
+			argIndex++
+		}
+		if filter.TransportType != nil {
+			// Local packages and remotes both count, each matching its own expression index
+			whereConditions = append(whereConditions, fmt.Sprintf("(jsonb_path_query_array(value, '$.packages[*].transport.type') @> jsonb_build_array($%d::text) OR jsonb_path_query_array(value, '$.remotes[*].type') @> jsonb_build_array($%d::text))", argIndex, argIndex))
+			args = append(args, *filter.TransportType)
+			argIndex++
+		}
+		if filter.Status != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", argIndex))
+			args = append(args, *filter.Status)
+			argIndex++
+		}
+		if filter.HideDeprecated {
+			whereConditions = append(whereConditions, fmt.Sprintf("status <> $%d", argIndex))
+			args = append(args, string(model.StatusDeprecated))
+			argIndex++
+		}
+		if filter.NamespacePrefix != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("server_name LIKE $%d", argIndex))
+			args = append(args, escapeLikePattern(*filter.NamespacePrefix)+"%")
+			argIndex++
+		}
+		if filter.PublishedSince != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("published_at >= $%d", argIndex))
+			args = append(args, *filter.PublishedSince)
+			argIndex++
+		}
+		if filter.PublishedBefore != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("published_at < $%d", argIndex))
+			args = append(args, *filter.PublishedBefore)
+			argIndex++
+		}
+		if filter.Capability != nil {
+			// Matches the GIN index on capability names
+			whereConditions = append(whereConditions, fmt.Sprintf("EXISTS (SELECT 1 FROM server_capabilities WHERE server_capabilities.server_name = servers.server_name AND server_capabilities.version = servers.version AND server_capabilities.names @> ARRAY[$%d::text])", argIndex))
+			args = append(args, *filter.Capability)
+			argIndex++
+		}
+	}
+
+	// Full-text search results are ordered by relevance unless another ordering was requested
+	if filter != nil && filter.Query != nil && filter.SortBy == "" {
+		return db.searchServers(ctx, tx, whereConditions, args, argIndex, queryArg, cursor, limit, raw)
+	}
+
+	sort, err := resolveServerSort(filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Keyset pagination: continue after the (sort column, server_name, version) of the cursor
+	sortColumns := "server_name, version"
+	if sort.by != SortByName {
+		sortColumns = sort.by + ", " + sortColumns
+	}
+	comparison := ">"
+	if sort.desc {
+		comparison = "<"
 	}
 
-	// Add cursor pagination using compound serverName:version cursor
 	if cursor != "" {
-		// Parse cursor format: "serverName:version"
-		parts := strings.SplitN(cursor, ":", 2)
-		if len(parts) == 2 {
-			cursorServerName := parts[0]
-			cursorVersion := parts[1]
-
-			// Use compound condition: (server_name > cursor_name) OR (server_name = cursor_name AND version > cursor_version)
-			whereConditions = append(whereConditions, fmt.Sprintf("(server_name > $%d OR (server_name = $%d AND version > $%d))", argIndex, argIndex+1, argIndex+2))
-			args = append(args, cursorServerName, cursorServerName, cursorVersion)
-			argIndex += 3
-		} else {
-			// Fallback for malformed cursor - treat as server name only for backwards compatibility
+		position, err := parseListCursor(cursor, sort)
+		if err != nil {
+			return nil, "", err
+		}
+
+		switch {
+		case position.Version == "":
+			// Name-only cursors predate compound cursors
 			whereConditions = append(whereConditions, fmt.Sprintf("server_name > $%d", argIndex))
-			args = append(args, cursor)
+			args = append(args, position.Name)
 			argIndex++
+		case sort.by == SortByName:
+			whereConditions = append(whereConditions, fmt.Sprintf("(server_name, version) %s ($%d, $%d)", comparison, argIndex, argIndex+1))
+			args = append(args, position.Name, position.Version)
+			argIndex += 2
+		default:
+			whereConditions = append(whereConditions, fmt.Sprintf("(%s) %s ($%d, $%d, $%d)", sortColumns, comparison, argIndex, argIndex+1, argIndex+2))
+			args = append(args, *position.Time, position.Name, position.Version)
+			argIndex += 3
 		}
 	}
 
@@ -161,15 +371,15 @@ func (db *PostgreSQL) ListServers(
 
 	// Query servers table with hybrid column/JSON data
 	query := fmt.Sprintf(`
-        SELECT server_name, version, status, published_at, updated_at, is_latest, value
+        SELECT %s
         FROM servers
         %s
-        ORDER BY server_name, version
+        ORDER BY %s
         LIMIT $%d
-    `, whereClause, argIndex)
+    `, serverColumns, whereClause, sortOrderBy(sortColumns, sort.desc), argIndex)
 	args = append(args, limit)
 
-	rows, err := db.getExecutor(tx).Query(ctx, query, args...)
+	rows, err := db.getReader(tx).Query(ctx, query, args...)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to query servers: %w", err)
 	}
@@ -177,35 +387,152 @@ func (db *PostgreSQL) ListServers(
 
 	var results []*apiv0.ServerResponse
 	for rows.Next() {
-		var serverName, version, status string
-		var publishedAt, updatedAt time.Time
-		var isLatest bool
-		var valueJSON []byte
-
-		err := rows.Scan(&serverName, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+		row, err := scanServerRow(rows)
 		if err != nil {
 			return nil, "", fmt.Errorf("failed to scan server row: %w", err)
 		}
 
-		// Parse the ServerJSON from JSONB
-		var serverJSON apiv0.ServerJSON
-		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-			return nil, "", fmt.Errorf("failed to unmarshal server JSON: %w", err)
+		serverResponse, err := serverResponseFromRow(row, raw)
+		if err != nil {
+			return nil, "", err
+		}
+		results = append(results, serverResponse)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	// Determine next cursor from the sort key of the last result
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		lastResult := results[len(results)-1]
+		nextCursor = formatListCursor(sort, sortTime(lastResult, sort.by), lastResult.Server.Name, lastResult.Server.Version)
+	}
+
+	return results, nextCursor, nil
+}
+
+// serverColumns are the servers columns read by scanServerRow, in scan order
+const serverColumns = `server_name, version, status, published_at, updated_at, is_latest, value,
+		COALESCE(deprecation_message, ''), COALESCE(replaced_by, '')`
+
+// scanServerRow scans a row selected with serverColumns, followed by any extra columns
+func scanServerRow(row pgx.Row, extra ...any) (serverRow, error) {
+	var r serverRow
+	dest := append([]any{&r.serverName, &r.version, &r.status, &r.publishedAt, &r.updatedAt, &r.isLatest, &r.value, &r.deprecationMessage, &r.replacedBy}, extra...)
+	err := row.Scan(dest...)
+	return r, err
+}
+
+// serverResponseFromRow builds the response for a scanned servers row. Raw responses keep the JSONB
+// value as returned by PostgreSQL in RawServer, skipping the decode into ServerJSON.
+func serverResponseFromRow(row serverRow, raw bool) (*apiv0.ServerResponse, error) {
+	if raw {
+		return row.toRawServerResponse(), nil
+	}
+	return row.toServerResponse()
+}
+
+// sortOrderBy builds an ORDER BY list that applies the same direction to every column,
+// matching the row comparison used for keyset pagination
+func sortOrderBy(sortColumns string, desc bool) string {
+	if !desc {
+		return sortColumns
+	}
+	return strings.ReplaceAll(sortColumns, ",", " DESC,") + " DESC"
+}
+
+// sortTime returns the timestamp a server is sorted by, or the zero time when sorting by name
+func sortTime(server *apiv0.ServerResponse, sortBy string) time.Time {
+	switch sortBy {
+	case SortByPublishedAt:
+		return server.Meta.Official.PublishedAt
+	case SortByUpdatedAt:
+		return server.Meta.Official.UpdatedAt
+	default:
+		return time.Time{}
+	}
+}
+
+// escapeLikePattern escapes the LIKE wildcards in a literal so it can be used as a prefix pattern
+func escapeLikePattern(literal string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(literal)
+}
+
+// packageContainment builds the JSONB array used to find servers shipping the package described by a filter
+func packageContainment(filter *ServerFilter) string {
+	pkg := map[string]string{"identifier": *filter.PackageID}
+	if filter.RegistryType != nil {
+		pkg["registryType"] = *filter.RegistryType
+	}
+	if filter.PackageVersion != nil {
+		pkg["version"] = *filter.PackageVersion
+	}
+
+	// Marshalling a slice of string maps cannot fail
+	data, _ := json.Marshal([]map[string]string{pkg})
+	return string(data)
+}
+
+// searchServers runs a full-text search ListServers query, ordering results by relevance.
+// queryArg is the placeholder index of the search text within args.
+func (db *PostgreSQL) searchServers(
+	ctx context.Context,
+	tx pgx.Tx,
+	whereConditions []string,
+	args []any,
+	argIndex int,
+	queryArg int,
+	cursor string,
+	limit int,
+	raw bool,
+) ([]*apiv0.ServerResponse, string, error) {
+	rankExpr := fmt.Sprintf("(ts_rank(search_vector, websearch_to_tsquery('english', $%d)) + word_similarity($%d, search_text))::float8", queryArg, queryArg)
+
+	// Add cursor pagination using compound rank:serverName:version cursor
+	cursorCondition := ""
+	if cursor != "" {
+		cursorRank, cursorServerName, cursorVersion, err := parseSearchCursor(cursor)
+		if err != nil {
+			return nil, "", err
 		}
+		cursorCondition = fmt.Sprintf("WHERE (search_rank < $%d OR (search_rank = $%d AND (server_name > $%d OR (server_name = $%d AND version > $%d))))", argIndex, argIndex, argIndex+1, argIndex+1, argIndex+2)
+		args = append(args, cursorRank, cursorServerName, cursorVersion)
+		argIndex += 3
+	}
+
+	query := fmt.Sprintf(`
+        SELECT %s, search_rank
+        FROM (
+            SELECT *, %s AS search_rank
+            FROM servers
+            WHERE %s
+        ) AS ranked
+        %s
+        ORDER BY search_rank DESC, server_name, version
+        LIMIT $%d
+    `, serverColumns, rankExpr, strings.Join(whereConditions, " AND "), cursorCondition, argIndex)
+	args = append(args, limit)
+
+	rows, err := db.getReader(tx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to search servers: %w", err)
+	}
+	defer rows.Close()
 
-		// Build ServerResponse with separated metadata
-		serverResponse := &apiv0.ServerResponse{
-			Server: serverJSON,
-			Meta: apiv0.ResponseMeta{
-				Official: &apiv0.RegistryExtensions{
-					Status:      model.Status(status),
-					PublishedAt: publishedAt,
-					UpdatedAt:   updatedAt,
-					IsLatest:    isLatest,
-				},
-			},
+	var results []*apiv0.ServerResponse
+	var lastRank float64
+	for rows.Next() {
+		row, err := scanServerRow(rows, &lastRank)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan server row: %w", err)
 		}
 
+		serverResponse, err := serverResponseFromRow(row, raw)
+		if err != nil {
+			return nil, "", err
+		}
 		results = append(results, serverResponse)
 	}
 
@@ -213,11 +540,11 @@ func (db *PostgreSQL) ListServers(
 		return nil, "", fmt.Errorf("error iterating rows: %w", err)
 	}
 
-	// Determine next cursor using compound serverName:version format
+	// Determine next cursor, including the rank of the last result
 	nextCursor := ""
 	if len(results) > 0 && len(results) >= limit {
 		lastResult := results[len(results)-1]
-		nextCursor = lastResult.Server.Name + ":" + lastResult.Server.Version
+		nextCursor = formatSearchCursor(lastRank, lastResult.Server.Name, lastResult.Server.Version)
 	}
 
 	return results, nextCursor, nil
@@ -230,19 +557,14 @@ func (db *PostgreSQL) GetServerByName(ctx context.Context, tx pgx.Tx, serverName
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND is_latest = true
 		ORDER BY published_at DESC
 		LIMIT 1
 	`
 
-	var name, version, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, serverName).Scan(&name, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+	row, err := scanServerRow(db.getReader(tx).QueryRow(ctx, query, serverName))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -250,26 +572,7 @@ func (db *PostgreSQL) GetServerByName(ctx context.Context, tx pgx.Tx, serverName
 		return nil, fmt.Errorf("failed to get server by name: %w", err)
 	}
 
-	// Parse the ServerJSON from JSONB
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-	}
-
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
-	}
-
-	return serverResponse, nil
+	return row.toServerResponse()
 }
 
 // GetServerByNameAndVersion retrieves a specific version of a server by server name and version
@@ -279,18 +582,13 @@ func (db *PostgreSQL) GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx,
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND version = $2
 		LIMIT 1
 	`
 
-	var name, vers, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, serverName, version).Scan(&name, &vers, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+	row, err := scanServerRow(db.getReader(tx).QueryRow(ctx, query, serverName, version))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -298,42 +596,34 @@ func (db *PostgreSQL) GetServerByNameAndVersion(ctx context.Context, tx pgx.Tx,
 		return nil, fmt.Errorf("failed to get server by name and version: %w", err)
 	}
 
-	// Parse the ServerJSON from JSONB
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-	}
-
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
-	}
-
-	return serverResponse, nil
+	return row.toServerResponse()
 }
 
 // GetAllVersionsByServerName retrieves all versions of a server by server name
 func (db *PostgreSQL) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	return db.getAllVersionsByServerName(ctx, tx, serverName, false)
+}
+
+// GetAllVersionsByServerNameRaw retrieves all versions of a server like GetAllVersionsByServerName,
+// but passes the stored JSONB through without decoding it
+func (db *PostgreSQL) GetAllVersionsByServerNameRaw(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerResponse, error) {
+	return db.getAllVersionsByServerName(ctx, tx, serverName, true)
+}
+
+// getAllVersionsByServerName implements GetAllVersionsByServerName and GetAllVersionsByServerNameRaw
+func (db *PostgreSQL) getAllVersionsByServerName(ctx context.Context, tx pgx.Tx, serverName string, raw bool) ([]*apiv0.ServerResponse, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
 	}
 
 	query := `
-		SELECT server_name, version, status, published_at, updated_at, is_latest, value
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1
 		ORDER BY published_at DESC
 	`
 
-	rows, err := db.getExecutor(tx).Query(ctx, query, serverName)
+	rows, err := db.getReader(tx).Query(ctx, query, serverName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query server versions: %w", err)
 	}
@@ -341,35 +631,15 @@ func (db *PostgreSQL) GetAllVersionsByServerName(ctx context.Context, tx pgx.Tx,
 
 	var results []*apiv0.ServerResponse
 	for rows.Next() {
-		var name, version, status string
-		var publishedAt, updatedAt time.Time
-		var isLatest bool
-		var valueJSON []byte
-
-		err := rows.Scan(&name, &version, &status, &publishedAt, &updatedAt, &isLatest, &valueJSON)
+		row, err := scanServerRow(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan server row: %w", err)
 		}
 
-		// Parse the ServerJSON from JSONB
-		var serverJSON apiv0.ServerJSON
-		if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-		}
-
-		// Build ServerResponse with separated metadata
-		serverResponse := &apiv0.ServerResponse{
-			Server: serverJSON,
-			Meta: apiv0.ResponseMeta{
-				Official: &apiv0.RegistryExtensions{
-					Status:      model.Status(status),
-					PublishedAt: publishedAt,
-					UpdatedAt:   updatedAt,
-					IsLatest:    isLatest,
-				},
-			},
+		serverResponse, err := serverResponseFromRow(row, raw)
+		if err != nil {
+			return nil, err
 		}
-
 		results = append(results, serverResponse)
 	}
 
@@ -405,10 +675,17 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *a
 		return nil, fmt.Errorf("failed to marshal server JSON: %w", err)
 	}
 
-	// Insert the new server version using composite primary key
+	// Insert the new server version using composite primary key, pointing the latest tag at it in the
+	// same statement when it is the latest version
 	insertQuery := `
-		INSERT INTO servers (server_name, version, status, published_at, updated_at, is_latest, value)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		WITH inserted AS (
+			INSERT INTO servers (server_name, version, status, published_at, updated_at, is_latest, value, deprecation_message, replaced_by)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''), NULLIF($9, ''))
+			RETURNING server_name, version, is_latest, published_at
+		)
+		INSERT INTO server_tags (server_name, tag, version, updated_at)
+		SELECT server_name, $10::varchar, version, published_at FROM inserted WHERE is_latest
+		ON CONFLICT (server_name, tag) DO UPDATE SET version = EXCLUDED.version, updated_at = EXCLUDED.updated_at
 	`
 
 	_, err = db.getExecutor(tx).Exec(ctx, insertQuery,
@@ -419,6 +696,9 @@ func (db *PostgreSQL) CreateServer(ctx context.Context, tx pgx.Tx, serverJSON *a
 		officialMeta.UpdatedAt,
 		officialMeta.IsLatest,
 		valueJSON,
+		officialMeta.DeprecationMessage,
+		officialMeta.ReplacedBy,
+		TagLatest,
 	)
 
 	if err != nil {
@@ -463,14 +743,10 @@ func (db *PostgreSQL) UpdateServer(ctx context.Context, tx pgx.Tx, serverName, v
 		UPDATE servers
 		SET value = $1, updated_at = NOW()
 		WHERE server_name = $2 AND version = $3
-		RETURNING server_name, version, status, published_at, updated_at, is_latest
+		RETURNING ` + serverColumns + `
 	`
 
-	var name, vers, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-
-	err = db.getExecutor(tx).QueryRow(ctx, query, valueJSON, serverName, version).Scan(&name, &vers, &status, &publishedAt, &updatedAt, &isLatest)
+	row, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, valueJSON, serverName, version))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -481,14 +757,7 @@ func (db *PostgreSQL) UpdateServer(ctx context.Context, tx pgx.Tx, serverName, v
 	// Return the updated ServerResponse
 	serverResponse := &apiv0.ServerResponse{
 		Server: *serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(status),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+		Meta:   row.responseMeta(),
 	}
 
 	return serverResponse, nil
@@ -500,20 +769,17 @@ func (db *PostgreSQL) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName
 		return nil, ctx.Err()
 	}
 
-	// Update the status column
+	// Update the status column, dropping the deprecation details of reactivated versions
 	query := `
 		UPDATE servers
-		SET status = $1, updated_at = NOW()
+		SET status = $1::varchar, updated_at = NOW(),
+		    deprecation_message = CASE WHEN $1::varchar = 'active' THEN NULL ELSE deprecation_message END,
+		    replaced_by = CASE WHEN $1::varchar = 'active' THEN NULL ELSE replaced_by END
 		WHERE server_name = $2 AND version = $3
-		RETURNING server_name, version, status, value, published_at, updated_at, is_latest
+		RETURNING ` + serverColumns + `
 	`
 
-	var name, vers, currentStatus string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var valueJSON []byte
-
-	err := db.getExecutor(tx).QueryRow(ctx, query, status, serverName, version).Scan(&name, &vers, &currentStatus, &valueJSON, &publishedAt, &updatedAt, &isLatest)
+	row, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, status, serverName, version))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -521,38 +787,76 @@ func (db *PostgreSQL) SetServerStatus(ctx context.Context, tx pgx.Tx, serverName
 		return nil, fmt.Errorf("failed to update server status: %w", err)
 	}
 
-	// Unmarshal the JSON data
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(valueJSON, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+	return row.toServerResponse()
+}
+
+// DeprecateServer marks a server version as deprecated, replacing its deprecation message and successor
+func (db *PostgreSQL) DeprecateServer(ctx context.Context, tx pgx.Tx, serverName, version, message, replacedBy string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
 	}
 
-	// Return the updated ServerResponse
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				Status:      model.Status(currentStatus),
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
+	query := `
+		UPDATE servers
+		SET status = 'deprecated', updated_at = NOW(), deprecation_message = NULLIF($1, ''), replaced_by = NULLIF($2, '')
+		WHERE server_name = $3 AND version = $4
+		RETURNING ` + serverColumns + `
+	`
+
+	row, err := scanServerRow(db.getExecutor(tx).QueryRow(ctx, query, message, replacedBy, serverName, version))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to deprecate server: %w", err)
 	}
 
-	return serverResponse, nil
+	return row.toServerResponse()
 }
 
-// InTransaction executes a function within a database transaction
+// InTransaction executes a function within a database transaction. Serialization failures, deadlocks
+// and lost connections are retried with jittered backoff under the retry policy, so fn may run more
+// than once and must not have effects outside the transaction that are unsafe to repeat.
 func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
 	if ctx.Err() != nil {
 		return ctx.Err()
 	}
 
-	tx, err := db.pool.Begin(ctx)
+	started := time.Now()
+	for attempt := 1; ; attempt++ {
+		reason, err := db.runTransaction(ctx, fn)
+		if err == nil || reason == "" {
+			return err
+		}
+
+		// Give up once the attempts or time budget are spent, or the caller has gone away
+		if attempt >= db.retry.MaxAttempts || time.Since(started) >= db.retry.Budget || ctx.Err() != nil {
+			return err
+		}
+
+		if db.onRetry != nil {
+			db.onRetry(ctx, reason)
+		}
+		log.Printf("retrying transaction after %s: %v", reason, err)
+
+		timer := time.NewTimer(db.retry.delay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// runTransaction makes a single attempt at a transaction, returning the reason it is safe to retry
+// when it failed transiently
+func (db *PostgreSQL) runTransaction(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) (string, error) {
+	pgTx, err := db.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return retryReason(err, false, false), fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	tx := &feedTx{Tx: pgTx}
 	//nolint:contextcheck // Intentionally using separate context for rollback to ensure cleanup even if request is cancelled
 	defer func() {
 		rollbackCtx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
@@ -563,14 +867,18 @@ func (db *PostgreSQL) InTransaction(ctx context.Context, fn func(ctx context.Con
 	}()
 
 	if err := fn(ctx, tx); err != nil {
-		return err
+		return retryReason(err, tx.Conn().IsClosed(), false), err
+	}
+
+	if err := db.writeChangeEvents(ctx, tx); err != nil {
+		return retryReason(err, tx.Conn().IsClosed(), false), err
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return retryReason(err, tx.Conn().IsClosed(), true), fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return "", nil
 }
 
 // AcquirePublishLock acquires an exclusive advisory lock for publishing a server
@@ -615,19 +923,12 @@ func (db *PostgreSQL) GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, se
 	executor := db.getExecutor(tx)
 
 	query := `
-		SELECT server_name, version, status, value, published_at, updated_at, is_latest
+		SELECT ` + serverColumns + `
 		FROM servers
 		WHERE server_name = $1 AND is_latest = true
 	`
 
-	row := executor.QueryRow(ctx, query, serverName)
-
-	var name, version, status string
-	var publishedAt, updatedAt time.Time
-	var isLatest bool
-	var jsonValue []byte
-
-	err := row.Scan(&name, &version, &status, &jsonValue, &publishedAt, &updatedAt, &isLatest)
+	row, err := scanServerRow(executor.QueryRow(ctx, query, serverName))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrNotFound
@@ -635,25 +936,7 @@ func (db *PostgreSQL) GetCurrentLatestVersion(ctx context.Context, tx pgx.Tx, se
 		return nil, fmt.Errorf("failed to scan server row: %w", err)
 	}
 
-	// Parse the JSON value to get the server details
-	var serverJSON apiv0.ServerJSON
-	if err := json.Unmarshal(jsonValue, &serverJSON); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
-	}
-
-	// Build ServerResponse with separated metadata
-	serverResponse := &apiv0.ServerResponse{
-		Server: serverJSON,
-		Meta: apiv0.ResponseMeta{
-			Official: &apiv0.RegistryExtensions{
-				PublishedAt: publishedAt,
-				UpdatedAt:   updatedAt,
-				IsLatest:    isLatest,
-			},
-		},
-	}
-
-	return serverResponse, nil
+	return row.toServerResponse()
 }
 
 // CountServerVersions counts the number of versions for a server
@@ -702,9 +985,14 @@ func (db *PostgreSQL) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName
 
 	executor := db.getExecutor(tx)
 
-	query := `UPDATE servers SET is_latest = false WHERE server_name = $1 AND is_latest = true`
+	query := `
+		WITH untagged AS (
+			DELETE FROM server_tags WHERE server_name = $1 AND tag = $2
+		)
+		UPDATE servers SET is_latest = false WHERE server_name = $1 AND is_latest = true
+	`
 
-	_, err := executor.Exec(ctx, query, serverName)
+	_, err := executor.Exec(ctx, query, serverName, TagLatest)
 	if err != nil {
 		return fmt.Errorf("failed to unmark latest version: %w", err)
 	}
@@ -712,8 +1000,1292 @@ func (db *PostgreSQL) UnmarkAsLatest(ctx context.Context, tx pgx.Tx, serverName
 	return nil
 }
 
-// Close closes the database connection
-func (db *PostgreSQL) Close() error {
-	db.pool.Close()
+// GetServerByNameAndTag retrieves the version of a server that a dist-tag points at
+func (db *PostgreSQL) GetServerByNameAndTag(ctx context.Context, tx pgx.Tx, serverName, tag string) (*apiv0.ServerResponse, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT ` + serverColumns + `
+		FROM servers
+		WHERE (server_name, version) = (SELECT server_name, version FROM server_tags WHERE server_name = $1 AND tag = $2)
+	`
+
+	row, err := scanServerRow(db.getReader(tx).QueryRow(ctx, query, serverName, tag))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get server by tag: %w", err)
+	}
+
+	return row.toServerResponse()
+}
+
+// ListServerTags retrieves the dist-tags of a server, ordered by tag name
+func (db *PostgreSQL) ListServerTags(ctx context.Context, tx pgx.Tx, serverName string) ([]*apiv0.ServerTag, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT tag, version, updated_at
+		FROM server_tags
+		WHERE server_name = $1
+		ORDER BY tag
+	`
+
+	rows, err := db.getReader(tx).Query(ctx, query, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server tags: %w", err)
+	}
+	defer rows.Close()
+
+	var tags []*apiv0.ServerTag
+	for rows.Next() {
+		var tag apiv0.ServerTag
+		if err := rows.Scan(&tag.Tag, &tag.Version, &tag.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan server tag: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// SetServerTag creates a dist-tag or moves it to another existing version of the server.
+// Moving the latest tag also moves the is_latest flag, which takes several statements, so
+// without a transaction the change runs in one of its own.
+func (db *PostgreSQL) SetServerTag(ctx context.Context, tx pgx.Tx, serverName, tag, version string) (*apiv0.ServerTag, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	if tx == nil {
+		return InTransactionT(ctx, db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerTag, error) {
+			return db.SetServerTag(ctx, tx, serverName, tag, version)
+		})
+	}
+
+	// Only tag versions that exist, which also keeps the foreign key from failing the transaction
+	query := `
+		INSERT INTO server_tags (server_name, tag, version, updated_at)
+		SELECT server_name, $2::varchar, version, NOW()
+		FROM servers
+		WHERE server_name = $1 AND version = $3
+		ON CONFLICT (server_name, tag) DO UPDATE SET version = EXCLUDED.version, updated_at = EXCLUDED.updated_at
+		RETURNING tag, version, updated_at
+	`
+
+	var serverTag apiv0.ServerTag
+	err := tx.QueryRow(ctx, query, serverName, tag, version).Scan(&serverTag.Tag, &serverTag.Version, &serverTag.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to set server tag: %w", err)
+	}
+
+	if tag == TagLatest {
+		// Unmark first: idx_unique_latest_per_server is checked row by row
+		if _, err := tx.Exec(ctx, `UPDATE servers SET is_latest = false WHERE server_name = $1 AND is_latest = true AND version <> $2`, serverName, version); err != nil {
+			return nil, fmt.Errorf("failed to unmark latest version: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `UPDATE servers SET is_latest = true WHERE server_name = $1 AND version = $2`, serverName, version); err != nil {
+			return nil, fmt.Errorf("failed to mark latest version: %w", err)
+		}
+	}
+
+	return &serverTag, nil
+}
+
+// DeleteServerTag removes a dist-tag
+func (db *PostgreSQL) DeleteServerTag(ctx context.Context, tx pgx.Tx, serverName, tag string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM server_tags WHERE server_name = $1 AND tag = $2`, serverName, tag)
+	if err != nil {
+		return fmt.Errorf("failed to delete server tag: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateAuditEvent appends an event to the audit log, assigning its ID and timestamp
+func (db *PostgreSQL) CreateAuditEvent(ctx context.Context, tx pgx.Tx, event *apiv0.AuditEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if event == nil || event.Action == "" {
+		return fmt.Errorf("%w: audit event action is required", ErrInvalidInput)
+	}
+	if auditActionConcernsVersion(event.Action) && (event.ServerName == "" || event.Version == "") {
+		return fmt.Errorf("%w: audit events of %s require a server name and version", ErrInvalidInput, event.Action)
+	}
+
+	// A nil diff map is stored as SQL NULL rather than a JSON null
+	var beforeJSON, afterJSON []byte
+	var err error
+	if event.Before != nil {
+		if beforeJSON, err = json.Marshal(event.Before); err != nil {
+			return fmt.Errorf("failed to marshal audit event before: %w", err)
+		}
+	}
+	if event.After != nil {
+		if afterJSON, err = json.Marshal(event.After); err != nil {
+			return fmt.Errorf("failed to marshal audit event after: %w", err)
+		}
+	}
+
+	query := `
+		INSERT INTO audit_events (action, server_name, version, actor_method, actor_subject, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`
+
+	err = db.getExecutor(tx).QueryRow(ctx, query,
+		event.Action,
+		event.ServerName,
+		event.Version,
+		event.ActorMethod,
+		event.ActorSubject,
+		beforeJSON,
+		afterJSON,
+	).Scan(&event.ID, &event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit event: %w", err)
+	}
+
+	return nil
+}
+
+// ListAuditEvents retrieves audit events, newest first, with cursor-based pagination and optional filtering
+func (db *PostgreSQL) ListAuditEvents(ctx context.Context, tx pgx.Tx, filter *AuditEventFilter, cursor string, limit int) ([]*apiv0.AuditEvent, string, error) {
+	if limit <= 0 {
+		limit = 10
+	}
+
+	if ctx.Err() != nil {
+		return nil, "", ctx.Err()
+	}
+
+	var whereConditions []string
+	args := []any{}
+	argIndex := 1
+
+	if filter != nil {
+		if filter.ServerName != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("server_name = $%d", argIndex))
+			args = append(args, *filter.ServerName)
+			argIndex++
+		}
+		if filter.ActorSubject != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("actor_subject = $%d", argIndex))
+			args = append(args, *filter.ActorSubject)
+			argIndex++
+		}
+		if filter.Action != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("action = $%d", argIndex))
+			args = append(args, *filter.Action)
+			argIndex++
+		}
+		if filter.Since != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("created_at >= $%d", argIndex))
+			args = append(args, *filter.Since)
+			argIndex++
+		}
+		if filter.Until != nil {
+			whereConditions = append(whereConditions, fmt.Sprintf("created_at < $%d", argIndex))
+			args = append(args, *filter.Until)
+			argIndex++
+		}
+	}
+
+	// The cursor is the ID of the last event on the previous page
+	if cursor != "" {
+		cursorID, err := parseAuditCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		whereConditions = append(whereConditions, fmt.Sprintf("id < $%d", argIndex))
+		args = append(args, cursorID)
+		argIndex++
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+        SELECT id, action, server_name, version, actor_method, actor_subject, before, after, created_at
+        FROM audit_events
+        %s
+        ORDER BY id DESC
+        LIMIT $%d
+    `, whereClause, argIndex)
+	args = append(args, limit)
+
+	rows, err := db.getReader(tx).Query(ctx, query, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.AuditEvent
+	for rows.Next() {
+		var event apiv0.AuditEvent
+		var beforeJSON, afterJSON []byte
+
+		err := rows.Scan(&event.ID, &event.Action, &event.ServerName, &event.Version, &event.ActorMethod, &event.ActorSubject, &beforeJSON, &afterJSON, &event.CreatedAt)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan audit event row: %w", err)
+		}
+
+		if beforeJSON != nil {
+			if err := json.Unmarshal(beforeJSON, &event.Before); err != nil {
+				return nil, "", fmt.Errorf("failed to unmarshal audit event before: %w", err)
+			}
+		}
+		if afterJSON != nil {
+			if err := json.Unmarshal(afterJSON, &event.After); err != nil {
+				return nil, "", fmt.Errorf("failed to unmarshal audit event after: %w", err)
+			}
+		}
+
+		results = append(results, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	nextCursor := ""
+	if len(results) > 0 && len(results) >= limit {
+		nextCursor = formatAuditCursor(results[len(results)-1].ID)
+	}
+
+	return results, nextCursor, nil
+}
+
+// changeFeedLockClass is the first key of the advisory lock that orders change feed writes.
+// Two-key advisory locks live in a separate key space from the single-key publish locks.
+const changeFeedLockClass = 1
+
+// feedTx is a transaction run by InTransaction, which holds back its change events until it is about to commit
+type feedTx struct {
+	pgx.Tx
+	changes []*apiv0.ChangeEvent
+}
+
+// CreateChangeEvent appends an event to the change feed, assigning its sequence number and timestamp.
+// Sequence numbers are drawn under a global lock that is held until commit, so they become visible in
+// order and readers resuming from a sequence never skip a change. Within InTransaction the event is only
+// written when the transaction is about to commit, so that the lock serializes just the end of mutating
+// transactions rather than everything they do after their first change. The cost is that concurrent
+// commits of mutating transactions still wait on each other for the few inserts and the commit itself.
+func (db *PostgreSQL) CreateChangeEvent(ctx context.Context, tx pgx.Tx, event *apiv0.ChangeEvent) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if event == nil || event.Type == "" || event.ServerName == "" || event.Version == "" {
+		return fmt.Errorf("%w: change event type, server name and version are required", ErrInvalidInput)
+	}
+
+	if ftx, ok := tx.(*feedTx); ok {
+		ftx.changes = append(ftx.changes, event)
+		return nil
+	}
+
+	query := `
+		WITH feed_lock AS (SELECT pg_advisory_xact_lock($1, 0))
+		INSERT INTO change_events (change_type, server_name, version)
+		SELECT $2, $3, $4 FROM feed_lock
+		RETURNING seq, changed_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query, changeFeedLockClass, event.Type, event.ServerName, event.Version).Scan(&event.Sequence, &event.ChangedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert change event: %w", err)
+	}
+
+	return nil
+}
+
+// writeChangeEvents writes the change events held back by a transaction under the change feed lock,
+// as the last statements before it commits
+func (db *PostgreSQL) writeChangeEvents(ctx context.Context, tx *feedTx) error {
+	if len(tx.changes) == 0 {
+		return nil
+	}
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1, 0)", changeFeedLockClass); err != nil {
+		return fmt.Errorf("failed to acquire change feed lock: %w", err)
+	}
+
+	for _, event := range tx.changes {
+		err := tx.QueryRow(ctx, `
+			INSERT INTO change_events (change_type, server_name, version)
+			VALUES ($1, $2, $3)
+			RETURNING seq, changed_at
+		`, event.Type, event.ServerName, event.Version).Scan(&event.Sequence, &event.ChangedAt)
+		if err != nil {
+			return fmt.Errorf("failed to insert change event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ListChangeEvents retrieves change events after the given sequence number, in sequence order,
+// together with the current state of each changed server version
+func (db *PostgreSQL) ListChangeEvents(ctx context.Context, tx pgx.Tx, after int64, limit int) ([]*apiv0.ChangeEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT c.seq, c.change_type, c.server_name, c.version, c.changed_at,
+		       s.status, s.published_at, s.updated_at, s.is_latest, s.value,
+		       COALESCE(s.deprecation_message, ''), COALESCE(s.replaced_by, '')
+		FROM change_events c
+		JOIN servers s ON s.server_name = c.server_name AND s.version = c.version
+		WHERE c.seq > $1
+		ORDER BY c.seq
+		LIMIT $2
+	`
+
+	rows, err := db.getReader(tx).Query(ctx, query, after, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query change events: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ChangeEvent
+	for rows.Next() {
+		var event apiv0.ChangeEvent
+		var row serverRow
+
+		err := rows.Scan(&event.Sequence, &event.Type, &event.ServerName, &event.Version, &event.ChangedAt,
+			&row.status, &row.publishedAt, &row.updatedAt, &row.isLatest, &row.value, &row.deprecationMessage, &row.replacedBy)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan change event row: %w", err)
+		}
+
+		if event.Server, err = row.toServerResponse(); err != nil {
+			return nil, err
+		}
+
+		results = append(results, &event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// webhookDeliveryColumns are the columns scanned by scanWebhookDelivery, in order
+const webhookDeliveryColumns = `id, subscription_id, event_type, server_name, version, status, attempts, next_attempt_at,
+	COALESCE(last_status_code, 0), COALESCE(last_error, ''), created_at, delivered_at`
+
+// scanWebhookDelivery scans a row of webhookDeliveryColumns, followed by any extra destinations
+func scanWebhookDelivery(row pgx.Row, extra ...any) (*apiv0.WebhookDelivery, error) {
+	var delivery apiv0.WebhookDelivery
+	var nextAttemptAt time.Time
+	dest := append([]any{
+		&delivery.ID, &delivery.SubscriptionID, &delivery.EventType, &delivery.ServerName, &delivery.Version,
+		&delivery.Status, &delivery.Attempts, &nextAttemptAt, &delivery.LastStatusCode, &delivery.LastError,
+		&delivery.CreatedAt, &delivery.DeliveredAt,
+	}, extra...)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if delivery.Status == WebhookDeliveryPending {
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+	return &delivery, nil
+}
+
+// CreateWebhookSubscription stores a webhook subscription with its secret, assigning its ID and creation time
+func (db *PostgreSQL) CreateWebhookSubscription(ctx context.Context, tx pgx.Tx, subscription *apiv0.WebhookSubscription) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if subscription == nil || subscription.URL == "" || subscription.NamespacePattern == "" || subscription.Secret == "" {
+		return fmt.Errorf("%w: webhook subscription URL, namespace pattern and secret are required", ErrInvalidInput)
+	}
+
+	query := `
+		INSERT INTO webhook_subscriptions (url, namespace_pattern, secret, owner_method, owner_subject)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query,
+		subscription.URL,
+		subscription.NamespacePattern,
+		subscription.Secret,
+		subscription.OwnerMethod,
+		subscription.OwnerSubject,
+	).Scan(&subscription.ID, &subscription.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// ListWebhookSubscriptions retrieves all webhook subscriptions in ID order, without their secrets
+func (db *PostgreSQL) ListWebhookSubscriptions(ctx context.Context, tx pgx.Tx) ([]*apiv0.WebhookSubscription, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Publishes read subscriptions inside their transaction, so this stays on the primary
+	rows, err := db.getExecutor(tx).Query(ctx, `
+		SELECT id, url, namespace_pattern, owner_method, owner_subject, created_at
+		FROM webhook_subscriptions
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.WebhookSubscription
+	for rows.Next() {
+		var subscription apiv0.WebhookSubscription
+		if err := rows.Scan(&subscription.ID, &subscription.URL, &subscription.NamespacePattern,
+			&subscription.OwnerMethod, &subscription.OwnerSubject, &subscription.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook subscription row: %w", err)
+		}
+		results = append(results, &subscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetWebhookSubscription retrieves a webhook subscription without its secret
+func (db *PostgreSQL) GetWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) (*apiv0.WebhookSubscription, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var subscription apiv0.WebhookSubscription
+	err := db.getExecutor(tx).QueryRow(ctx, `
+		SELECT id, url, namespace_pattern, owner_method, owner_subject, created_at
+		FROM webhook_subscriptions
+		WHERE id = $1
+	`, id).Scan(&subscription.ID, &subscription.URL, &subscription.NamespacePattern,
+		&subscription.OwnerMethod, &subscription.OwnerSubject, &subscription.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription; its deliveries are removed by the foreign key
+func (db *PostgreSQL) DeleteWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// CreateWebhookDelivery queues an event for a subscription, to be attempted right away
+func (db *PostgreSQL) CreateWebhookDelivery(ctx context.Context, tx pgx.Tx, delivery *apiv0.WebhookDelivery, payload []byte) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if delivery == nil || delivery.SubscriptionID == 0 || delivery.EventType == "" || delivery.ServerName == "" || delivery.Version == "" {
+		return fmt.Errorf("%w: webhook delivery subscription, event type, server name and version are required", ErrInvalidInput)
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (subscription_id, event_type, server_name, version, payload)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + webhookDeliveryColumns
+
+	created, err := scanWebhookDelivery(db.getExecutor(tx).QueryRow(ctx, query,
+		delivery.SubscriptionID, delivery.EventType, delivery.ServerName, delivery.Version, payload))
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook delivery: %w", err)
+	}
+	*delivery = *created
+
+	return nil
+}
+
+// ListWebhookDeliveries retrieves the deliveries of a subscription, newest first, optionally only those with a status
+func (db *PostgreSQL) ListWebhookDeliveries(ctx context.Context, tx pgx.Tx, subscriptionID int64, status *string, limit int) ([]*apiv0.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE subscription_id = $1 AND ($2::varchar IS NULL OR status = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`
+
+	rows, err := db.getReader(tx).Query(ctx, query, subscriptionID, status, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		results = append(results, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// ClaimWebhookDeliveries leases up to limit pending deliveries that are due by pushing their next
+// attempt past the lease. Rows locked by another worker are skipped rather than waited for.
+func (db *PostgreSQL) ClaimWebhookDeliveries(ctx context.Context, tx pgx.Tx, limit int, lease time.Duration) ([]*WebhookJob, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		WITH due AS (
+			SELECT id FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at, id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = NOW() + make_interval(secs => $2)
+		FROM due, webhook_subscriptions s
+		WHERE d.id = due.id AND s.id = d.subscription_id
+		RETURNING d.id, d.subscription_id, d.event_type, d.server_name, d.version, d.status, d.attempts, d.next_attempt_at,
+			COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.created_at, d.delivered_at,
+			d.payload, s.url, s.secret
+	`
+
+	rows, err := db.getExecutor(tx).Query(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*WebhookJob
+	for rows.Next() {
+		var job WebhookJob
+		delivery, err := scanWebhookDelivery(rows, &job.Payload, &job.URL, &job.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		job.Delivery = *delivery
+		jobs = append(jobs, &job)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// RecordWebhookAttempt counts an attempt at a delivery and stores its outcome
+func (db *PostgreSQL) RecordWebhookAttempt(ctx context.Context, tx pgx.Tx, deliveryID int64, attempt *WebhookAttempt) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if !isValidWebhookDeliveryStatus(attempt.Status) {
+		return fmt.Errorf("%w: invalid webhook delivery status %q", ErrInvalidInput, attempt.Status)
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2,
+		    attempts = attempts + 1,
+		    next_attempt_at = $3,
+		    last_status_code = NULLIF($4, 0),
+		    last_error = NULLIF($5, ''),
+		    delivered_at = CASE WHEN $2 = 'delivered' THEN NOW() ELSE delivered_at END
+		WHERE id = $1
+	`
+
+	result, err := db.getExecutor(tx).Exec(ctx, query, deliveryID, attempt.Status, attempt.NextAttemptAt, attempt.StatusCode, attempt.Error)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RedeliverWebhookDelivery makes a delivery pending again with a fresh set of attempts
+func (db *PostgreSQL) RedeliverWebhookDelivery(ctx context.Context, tx pgx.Tx, deliveryID int64) (*apiv0.WebhookDelivery, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1
+		RETURNING ` + webhookDeliveryColumns
+
+	delivery, err := scanWebhookDelivery(db.getExecutor(tx).QueryRow(ctx, query, deliveryID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// SetNamespaceBlock blocks a namespace, replacing any existing block of it
+func (db *PostgreSQL) SetNamespaceBlock(ctx context.Context, tx pgx.Tx, block *apiv0.NamespaceBlock) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if block == nil || block.Namespace == "" || block.Reason == "" || block.CreatedBy == "" {
+		return fmt.Errorf("%w: namespace block namespace, reason and creator are required", ErrInvalidInput)
+	}
+
+	query := `
+		INSERT INTO namespace_blocks (namespace, reason, created_by, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (namespace) DO UPDATE
+		SET reason = EXCLUDED.reason, created_by = EXCLUDED.created_by, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		RETURNING created_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query, block.Namespace, block.Reason, block.CreatedBy, block.ExpiresAt).Scan(&block.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set namespace block: %w", err)
+	}
+
+	return nil
+}
+
+// ListNamespaceBlocks retrieves namespace blocks in namespace order, including those that have expired if requested
+func (db *PostgreSQL) ListNamespaceBlocks(ctx context.Context, tx pgx.Tx, includeExpired bool) ([]*apiv0.NamespaceBlock, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Blocks are enforced as soon as they are set, so this reads from the primary rather than a lagging replica
+	rows, err := db.getExecutor(tx).Query(ctx, `
+		SELECT namespace, reason, created_by, created_at, expires_at
+		FROM namespace_blocks
+		WHERE $1 OR expires_at IS NULL OR expires_at > NOW()
+		ORDER BY namespace
+	`, includeExpired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query namespace blocks: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.NamespaceBlock
+	for rows.Next() {
+		var block apiv0.NamespaceBlock
+		if err := rows.Scan(&block.Namespace, &block.Reason, &block.CreatedBy, &block.CreatedAt, &block.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan namespace block row: %w", err)
+		}
+		results = append(results, &block)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeleteNamespaceBlock unblocks a namespace
+func (db *PostgreSQL) DeleteNamespaceBlock(ctx context.Context, tx pgx.Tx, namespace string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM namespace_blocks WHERE namespace = $1`, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to delete namespace block: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// ClaimRemoteURLs claims canonical remote URLs for a server, leaving claims held by other servers in place.
+// It returns the URLs that other servers hold, mapped to the name of the server holding each. The primary key
+// on url makes concurrent claims of the same URL wait for each other, so only one of them can succeed.
+func (db *PostgreSQL) ClaimRemoteURLs(ctx context.Context, tx pgx.Tx, serverName string, urls []string) (map[string]string, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	conflicts := map[string]string{}
+	if len(urls) == 0 {
+		return conflicts, nil
+	}
+
+	executor := db.getExecutor(tx)
+	_, err := executor.Exec(ctx, `
+		INSERT INTO remote_url_claims (url, server_name)
+		SELECT unnest($1::text[]), $2
+		ON CONFLICT (url) DO NOTHING
+	`, urls, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim remote URLs: %w", err)
+	}
+
+	rows, err := executor.Query(ctx, `
+		SELECT url, server_name
+		FROM remote_url_claims
+		WHERE url = ANY($1) AND server_name <> $2
+	`, urls, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query remote URL claims: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var url, owner string
+		if err := rows.Scan(&url, &owner); err != nil {
+			return nil, fmt.Errorf("failed to scan remote URL claim row: %w", err)
+		}
+		conflicts[url] = owner
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// ReleaseRemoteURLClaims releases the claims of a server on every URL not in keep
+func (db *PostgreSQL) ReleaseRemoteURLClaims(ctx context.Context, tx pgx.Tx, serverName string, keep []string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if keep == nil {
+		keep = []string{}
+	}
+
+	_, err := db.getExecutor(tx).Exec(ctx, `
+		DELETE FROM remote_url_claims
+		WHERE server_name = $1 AND NOT (url = ANY($2))
+	`, serverName, keep)
+	if err != nil {
+		return fmt.Errorf("failed to release remote URL claims: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemoteURLClaims retrieves remote URL claims in URL order
+func (db *PostgreSQL) ListRemoteURLClaims(ctx context.Context, tx pgx.Tx, filter *RemoteURLClaimFilter) ([]*apiv0.RemoteURLClaim, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var url, serverName *string
+	if filter != nil {
+		url, serverName = filter.URL, filter.ServerName
+	}
+
+	rows, err := db.getReader(tx).Query(ctx, `
+		SELECT url, server_name, claimed_at
+		FROM remote_url_claims
+		WHERE ($1::text IS NULL OR url = $1) AND ($2::text IS NULL OR server_name = $2)
+		ORDER BY url
+	`, url, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query remote URL claims: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.RemoteURLClaim
+	for rows.Next() {
+		var claim apiv0.RemoteURLClaim
+		if err := rows.Scan(&claim.URL, &claim.ServerName, &claim.ClaimedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan remote URL claim row: %w", err)
+		}
+		results = append(results, &claim)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeleteRemoteURLClaim releases the claim on a canonical URL, whichever server holds it
+func (db *PostgreSQL) DeleteRemoteURLClaim(ctx context.Context, tx pgx.Tx, url string) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	result, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM remote_url_claims WHERE url = $1`, url)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote URL claim: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// RecordPackageValidation records the outcome of checking a package, counting consecutive failures from
+// the previous outcome. Inconclusive failures keep the previous count. It fills in the failure count and
+// last pass of validation.
+func (db *PostgreSQL) RecordPackageValidation(ctx context.Context, tx pgx.Tx, validation *apiv0.PackageValidation) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if validation == nil || validation.ServerName == "" || validation.Version == "" || validation.RegistryType == "" || validation.Identifier == "" {
+		return fmt.Errorf("%w: package validation server, version, registry type and identifier are required", ErrInvalidInput)
+	}
+
+	// Only failures can be inconclusive
+	validation.Inconclusive = validation.Inconclusive && !validation.Passed
+
+	query := `
+		INSERT INTO package_validations (server_name, version, registry_type, identifier, package_version, passed, error,
+			inconclusive, consecutive_failures, checked_at, last_passed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, CASE WHEN $6 OR $8 THEN 0 ELSE 1 END, $9, CASE WHEN $6 THEN $9 END)
+		ON CONFLICT (server_name, version, registry_type, identifier) DO UPDATE
+		SET package_version = EXCLUDED.package_version,
+			passed = EXCLUDED.passed,
+			error = EXCLUDED.error,
+			inconclusive = EXCLUDED.inconclusive,
+			consecutive_failures = CASE
+				WHEN EXCLUDED.passed THEN 0
+				WHEN EXCLUDED.inconclusive THEN package_validations.consecutive_failures
+				ELSE package_validations.consecutive_failures + 1
+			END,
+			checked_at = EXCLUDED.checked_at,
+			last_passed_at = COALESCE(EXCLUDED.last_passed_at, package_validations.last_passed_at)
+		RETURNING consecutive_failures, last_passed_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query,
+		validation.ServerName, validation.Version, validation.RegistryType, validation.Identifier, validation.PackageVersion,
+		validation.Passed, validation.Error, validation.Inconclusive, validation.CheckedAt,
+	).Scan(&validation.ConsecutiveFailures, &validation.LastPassedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record package validation: %w", err)
+	}
+
+	return nil
+}
+
+// ListPackageValidations retrieves package validations ordered by server name, version, registry type and identifier
+func (db *PostgreSQL) ListPackageValidations(ctx context.Context, tx pgx.Tx, filter *PackageValidationFilter) ([]*apiv0.PackageValidation, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var serverName *string
+	failingOnly := false
+	if filter != nil {
+		serverName, failingOnly = filter.ServerName, filter.FailingOnly
+	}
+
+	rows, err := db.getReader(tx).Query(ctx, `
+		SELECT server_name, version, registry_type, identifier, package_version, passed, error,
+			inconclusive, consecutive_failures, checked_at, last_passed_at
+		FROM package_validations
+		WHERE ($1::text IS NULL OR server_name = $1) AND (NOT $2 OR NOT passed)
+		ORDER BY server_name, version, registry_type, identifier
+	`, serverName, failingOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query package validations: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.PackageValidation
+	for rows.Next() {
+		var validation apiv0.PackageValidation
+		if err := rows.Scan(&validation.ServerName, &validation.Version, &validation.RegistryType, &validation.Identifier,
+			&validation.PackageVersion, &validation.Passed, &validation.Error, &validation.Inconclusive, &validation.ConsecutiveFailures,
+			&validation.CheckedAt, &validation.LastPassedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan package validation row: %w", err)
+		}
+		results = append(results, &validation)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeletePackageValidationsCheckedBefore removes package validations last checked before the given time
+func (db *PostgreSQL) DeletePackageValidationsCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM package_validations WHERE checked_at < $1`, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete package validations: %w", err)
+	}
+
+	return nil
+}
+
+// RecordRemoteProbe records the outcome of probing a remote. Failed probes keep the protocol version, latency
+// and success time of the last probe that succeeded, which are filled in on probe.
+func (db *PostgreSQL) RecordRemoteProbe(ctx context.Context, tx pgx.Tx, probe *RemoteProbe) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if probe == nil || probe.ServerName == "" || probe.Version == "" || probe.URL == "" || probe.Status == "" {
+		return fmt.Errorf("%w: remote probe server, version, URL and status are required", ErrInvalidInput)
+	}
+
+	up := probe.Status == apiv0.RemoteStatusUp
+	query := `
+		INSERT INTO remote_probes (server_name, version, url, transport_type, status, error, protocol_version, latency_ms,
+			checked_at, last_success_at)
+		VALUES ($1, $2, $3, $4, $5, $6, CASE WHEN $9 THEN $7 ELSE '' END, CASE WHEN $9 THEN $8::integer END,
+			$10, CASE WHEN $9 THEN $10::timestamptz END)
+		ON CONFLICT (server_name, version, url) DO UPDATE
+		SET transport_type = EXCLUDED.transport_type,
+			status = EXCLUDED.status,
+			error = EXCLUDED.error,
+			protocol_version = CASE WHEN $9 THEN EXCLUDED.protocol_version ELSE remote_probes.protocol_version END,
+			latency_ms = CASE WHEN $9 THEN EXCLUDED.latency_ms ELSE remote_probes.latency_ms END,
+			checked_at = EXCLUDED.checked_at,
+			last_success_at = COALESCE(EXCLUDED.last_success_at, remote_probes.last_success_at)
+		RETURNING protocol_version, latency_ms, last_success_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query,
+		probe.ServerName, probe.Version, probe.URL, probe.TransportType, probe.Status, probe.Error,
+		probe.ProtocolVersion, probe.LatencyMs, up, probe.CheckedAt,
+	).Scan(&probe.ProtocolVersion, &probe.LatencyMs, &probe.LastSuccessAt)
+	if err != nil {
+		return fmt.Errorf("failed to record remote probe: %w", err)
+	}
+
+	return nil
+}
+
+// ListRemoteProbes retrieves remote probes ordered by server name, version and URL
+func (db *PostgreSQL) ListRemoteProbes(ctx context.Context, tx pgx.Tx, filter *RemoteProbeFilter) ([]*RemoteProbe, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var serverNames []string
+	if filter != nil && filter.ServerNames != nil {
+		serverNames = filter.ServerNames
+	}
+
+	rows, err := db.getReader(tx).Query(ctx, `
+		SELECT server_name, version, url, transport_type, status, error, protocol_version, latency_ms, checked_at, last_success_at
+		FROM remote_probes
+		WHERE $1::text[] IS NULL OR server_name = ANY($1)
+		ORDER BY server_name, version, url
+	`, serverNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query remote probes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*RemoteProbe
+	for rows.Next() {
+		var probe RemoteProbe
+		if err := rows.Scan(&probe.ServerName, &probe.Version, &probe.URL, &probe.TransportType, &probe.Status, &probe.Error,
+			&probe.ProtocolVersion, &probe.LatencyMs, &probe.CheckedAt, &probe.LastSuccessAt); err != nil {
+			return nil, fmt.Errorf("failed to scan remote probe row: %w", err)
+		}
+		results = append(results, &probe)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// DeleteRemoteProbesCheckedBefore removes remote probes last checked before the given time
+func (db *PostgreSQL) DeleteRemoteProbesCheckedBefore(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	_, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM remote_probes WHERE checked_at < $1`, before)
+	if err != nil {
+		return fmt.Errorf("failed to delete remote probes: %w", err)
+	}
+
+	return nil
+}
+
+// RecordServerCapabilities records the capabilities of a server version. Failed introspections keep the
+// capabilities and update time of the last one that succeeded, which are filled in on capabilities.
+func (db *PostgreSQL) RecordServerCapabilities(ctx context.Context, tx pgx.Tx, capabilities *apiv0.ServerCapabilities) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if capabilities == nil || capabilities.ServerName == "" || capabilities.Version == "" || capabilities.Source == "" {
+		return fmt.Errorf("%w: server capabilities server, version and source are required", ErrInvalidInput)
+	}
+
+	succeeded := capabilities.Error == ""
+	tools, prompts, resources := capabilities.Tools, capabilities.Prompts, capabilities.Resources
+	if tools == nil {
+		tools = []apiv0.CapabilityTool{}
+	}
+	if prompts == nil {
+		prompts = []apiv0.CapabilityPrompt{}
+	}
+	if resources == nil {
+		resources = []apiv0.CapabilityResource{}
+	}
+
+	query := `
+		INSERT INTO server_capabilities (server_name, version, source, tools, prompts, resources, names, error, checked_at, updated_at)
+		VALUES ($1, $2, $3, CASE WHEN $10 THEN $4::jsonb ELSE '[]' END, CASE WHEN $10 THEN $5::jsonb ELSE '[]' END,
+			CASE WHEN $10 THEN $6::jsonb ELSE '[]' END, CASE WHEN $10 THEN $7::text[] ELSE '{}' END,
+			$8, $9, CASE WHEN $10 THEN $9::timestamptz END)
+		ON CONFLICT (server_name, version) DO UPDATE
+		SET source = EXCLUDED.source,
+			tools = CASE WHEN $10 THEN EXCLUDED.tools ELSE server_capabilities.tools END,
+			prompts = CASE WHEN $10 THEN EXCLUDED.prompts ELSE server_capabilities.prompts END,
+			resources = CASE WHEN $10 THEN EXCLUDED.resources ELSE server_capabilities.resources END,
+			names = CASE WHEN $10 THEN EXCLUDED.names ELSE server_capabilities.names END,
+			error = EXCLUDED.error,
+			checked_at = EXCLUDED.checked_at,
+			updated_at = COALESCE(EXCLUDED.updated_at, server_capabilities.updated_at)
+		RETURNING tools, prompts, resources, updated_at
+	`
+
+	err := db.getExecutor(tx).QueryRow(ctx, query,
+		capabilities.ServerName, capabilities.Version, capabilities.Source, tools, prompts, resources,
+		capabilityNames(capabilities), capabilities.Error, capabilities.CheckedAt, succeeded,
+	).Scan(&capabilities.Tools, &capabilities.Prompts, &capabilities.Resources, &capabilities.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record server capabilities: %w", err)
+	}
+
+	return nil
+}
+
+// capabilityNames returns the names of the tools, prompts and resources and the URIs of the resources,
+// which the capability filter matches
+func capabilityNames(capabilities *apiv0.ServerCapabilities) []string {
+	names := []string{}
+	for _, tool := range capabilities.Tools {
+		names = append(names, tool.Name)
+	}
+	for _, prompt := range capabilities.Prompts {
+		names = append(names, prompt.Name)
+	}
+	for _, resource := range capabilities.Resources {
+		names = append(names, resource.URI)
+		if resource.Name != "" {
+			names = append(names, resource.Name)
+		}
+	}
+	return names
+}
+
+// GetServerCapabilities retrieves the capabilities recorded for a server version
+func (db *PostgreSQL) GetServerCapabilities(ctx context.Context, tx pgx.Tx, serverName, version string) (*apiv0.ServerCapabilities, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var capabilities apiv0.ServerCapabilities
+	err := db.getReader(tx).QueryRow(ctx, `
+		SELECT server_name, version, source, tools, prompts, resources, error, checked_at, updated_at
+		FROM server_capabilities
+		WHERE server_name = $1 AND version = $2
+	`, serverName, version).Scan(&capabilities.ServerName, &capabilities.Version, &capabilities.Source, &capabilities.Tools,
+		&capabilities.Prompts, &capabilities.Resources, &capabilities.Error, &capabilities.CheckedAt, &capabilities.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get server capabilities: %w", err)
+	}
+
+	return &capabilities, nil
+}
+
+// ListServerCapabilities retrieves server capabilities ordered by server name and version
+func (db *PostgreSQL) ListServerCapabilities(ctx context.Context, tx pgx.Tx, filter *ServerCapabilitiesFilter) ([]*apiv0.ServerCapabilities, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var serverName *string
+	if filter != nil {
+		serverName = filter.ServerName
+	}
+
+	rows, err := db.getReader(tx).Query(ctx, `
+		SELECT server_name, version, source, tools, prompts, resources, error, checked_at, updated_at
+		FROM server_capabilities
+		WHERE $1::text IS NULL OR server_name = $1
+		ORDER BY server_name, version
+	`, serverName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query server capabilities: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*apiv0.ServerCapabilities
+	for rows.Next() {
+		var capabilities apiv0.ServerCapabilities
+		if err := rows.Scan(&capabilities.ServerName, &capabilities.Version, &capabilities.Source, &capabilities.Tools,
+			&capabilities.Prompts, &capabilities.Resources, &capabilities.Error, &capabilities.CheckedAt, &capabilities.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan server capabilities row: %w", err)
+		}
+		results = append(results, &capabilities)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// maxRateLimitKeyLength is the length of rate_limit_buckets.key
+const maxRateLimitKeyLength = 512
+
+// TakeRateLimitToken refills the token bucket for key and takes a token from it if one is available.
+// A bucket without a token is left untouched, which is equivalent to storing its refilled tokens since
+// the refill is computed from when a token was last taken. It runs as a single statement, so a bucket
+// deleted as idle in the meantime is simply created again.
+func (db *PostgreSQL) TakeRateLimitToken(ctx context.Context, tx pgx.Tx, key string, capacity, refillPerSecond float64) (float64, bool, error) {
+	if ctx.Err() != nil {
+		return 0, false, ctx.Err()
+	}
+	if len(key) > maxRateLimitKeyLength {
+		return 0, false, fmt.Errorf("%w: rate limit key is longer than %d characters", ErrInvalidInput, maxRateLimitKeyLength)
+	}
+
+	executor := db.getExecutor(tx)
+
+	// New buckets start full. Taking a token moves updated_at to NOW(), which is how the returned row tells
+	// whether one was taken; the refill of the returned row is then zero, leaving the tokens after the take.
+	// NOW() does not advance within a transaction, so a bucket is taken from at most once per transaction.
+	var tokens float64
+	var taken bool
+	err := executor.QueryRow(ctx, `
+		INSERT INTO rate_limit_buckets AS b (key, tokens, updated_at)
+		VALUES ($1, $2::double precision - 1, NOW())
+		ON CONFLICT (key) DO UPDATE
+		SET tokens = CASE
+		        WHEN LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision) >= 1
+		        THEN LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision) - 1
+		        ELSE b.tokens
+		    END,
+		    updated_at = CASE
+		        WHEN LEAST($2::double precision, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at)::double precision * $3::double precision) >= 1
+		        THEN NOW()
+		        ELSE b.updated_at
+		    END
+		RETURNING LEAST($2::double precision, tokens + EXTRACT(EPOCH FROM NOW() - updated_at)::double precision * $3::double precision),
+		    updated_at = NOW()
+	`, key, capacity, refillPerSecond).Scan(&tokens, &taken)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+
+	return tokens, taken, nil
+}
+
+// DeleteIdleRateLimitBuckets removes token buckets last taken from before the given time
+func (db *PostgreSQL) DeleteIdleRateLimitBuckets(ctx context.Context, tx pgx.Tx, before time.Time) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if _, err := db.getExecutor(tx).Exec(ctx, `DELETE FROM rate_limit_buckets WHERE updated_at < $1`, before); err != nil {
+		return fmt.Errorf("failed to delete idle rate limit buckets: %w", err)
+	}
+
+	return nil
+}
+
+// Close closes the database connection
+func (db *PostgreSQL) Close() error {
+	db.pool.Close()
+	if db.replica != nil {
+		db.replica.Close()
+	}
 	return nil
 }
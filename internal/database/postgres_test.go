@@ -2,7 +2,12 @@ package database_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -359,7 +364,7 @@ func TestPostgreSQL_ListServers(t *testing.T) {
 		{
 			name:   "test cursor pagination",
 			filter: nil,
-			cursor: "com.example/server-a",
+			cursor: database.NameListPosition("com.example/server-a", ""),
 			limit:  10,
 			// Should return servers after 'server-a' alphabetically
 			expectedCount: 2,
@@ -546,6 +551,135 @@ func TestPostgreSQL_SetServerStatus(t *testing.T) {
 	}
 }
 
+func TestPostgreSQL_DeprecateServer(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	serverName := "com.example/deprecation-test-server"
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Name:        serverName,
+		Description: "A server for deprecation testing",
+		Version:     "1.0.0",
+	}, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: time.Now(),
+		UpdatedAt:   time.Now(),
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+
+	result, err := db.DeprecateServer(ctx, nil, serverName, "1.0.0", "Moved to v2", "com.example/deprecation-test-server-v2")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, result.Meta.Official.Status)
+	assert.Equal(t, "Moved to v2", result.Meta.Official.DeprecationMessage)
+	assert.Equal(t, "com.example/deprecation-test-server-v2", result.Meta.Official.ReplacedBy)
+	assert.Equal(t, "A server for deprecation testing", result.Server.Description)
+
+	// Every read path returns the deprecation details
+	stored, err := db.GetServerByNameAndVersion(ctx, nil, serverName, "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, result.Meta.Official.DeprecationMessage, stored.Meta.Official.DeprecationMessage)
+	assert.Equal(t, result.Meta.Official.ReplacedBy, stored.Meta.Official.ReplacedBy)
+
+	listed, _, err := db.ListServersRaw(ctx, nil, &database.ServerFilter{Name: &serverName}, "", 10)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "Moved to v2", listed[0].Meta.Official.DeprecationMessage)
+
+	// Deleting keeps the details, reactivating clears them
+	result, err = db.SetServerStatus(ctx, nil, serverName, "1.0.0", string(model.StatusDeleted))
+	require.NoError(t, err)
+	assert.Equal(t, "Moved to v2", result.Meta.Official.DeprecationMessage)
+
+	result, err = db.SetServerStatus(ctx, nil, serverName, "1.0.0", string(model.StatusActive))
+	require.NoError(t, err)
+	assert.Empty(t, result.Meta.Official.DeprecationMessage)
+	assert.Empty(t, result.Meta.Official.ReplacedBy)
+
+	// Empty details are stored as absent
+	result, err = db.DeprecateServer(ctx, nil, serverName, "1.0.0", "", "")
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, result.Meta.Official.Status)
+	assert.Empty(t, result.Meta.Official.DeprecationMessage)
+
+	_, err = db.DeprecateServer(ctx, nil, "com.example/non-existent", "1.0.0", "", "")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func TestPostgreSQL_ServerTags(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	serverName := "com.example/tagged-server"
+	for i, version := range []string{"1.0.0", "1.1.0", "1.2.0-beta.1"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Name:        serverName,
+			Description: "A server with release channels",
+			Version:     version,
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: time.Now(),
+			UpdatedAt:   time.Now(),
+			IsLatest:    i == 1,
+		})
+		require.NoError(t, err)
+	}
+
+	t.Run("latest versions are tagged on insert", func(t *testing.T) {
+		server, err := db.GetServerByNameAndTag(ctx, nil, serverName, database.TagLatest)
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", server.Server.Version)
+		assert.True(t, server.Meta.Official.IsLatest)
+	})
+
+	t.Run("custom tags", func(t *testing.T) {
+		tag, err := db.SetServerTag(ctx, nil, serverName, "beta", "1.2.0-beta.1")
+		require.NoError(t, err)
+		assert.Equal(t, "beta", tag.Tag)
+		assert.Equal(t, "1.2.0-beta.1", tag.Version)
+		assert.False(t, tag.UpdatedAt.IsZero())
+
+		server, err := db.GetServerByNameAndTag(ctx, nil, serverName, "beta")
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.0-beta.1", server.Server.Version)
+		assert.False(t, server.Meta.Official.IsLatest)
+
+		_, err = db.SetServerTag(ctx, nil, serverName, "beta", "9.9.9")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		tags, err := db.ListServerTags(ctx, nil, serverName)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "beta", tags[0].Tag)
+		assert.Equal(t, database.TagLatest, tags[1].Tag)
+	})
+
+	t.Run("moving latest moves the flag", func(t *testing.T) {
+		_, err := db.SetServerTag(ctx, nil, serverName, database.TagLatest, "1.0.0")
+		require.NoError(t, err)
+
+		latest, err := db.GetServerByName(ctx, nil, serverName)
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Server.Version)
+
+		tagFilter := database.TagLatest
+		results, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Name: &serverName, Tag: &tagFilter}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "1.0.0", results[0].Server.Version)
+		assert.True(t, results[0].Meta.Official.IsLatest)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		require.NoError(t, db.DeleteServerTag(ctx, nil, serverName, "beta"))
+		assert.ErrorIs(t, db.DeleteServerTag(ctx, nil, serverName, "beta"), database.ErrNotFound)
+
+		require.NoError(t, db.UnmarkAsLatest(ctx, nil, serverName))
+		_, err := db.GetServerByNameAndTag(ctx, nil, serverName, database.TagLatest)
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
 func TestPostgreSQL_TransactionHandling(t *testing.T) {
 	db := database.NewTestDB(t)
 	ctx := context.Background()
@@ -610,6 +744,139 @@ func TestPostgreSQL_TransactionHandling(t *testing.T) {
 	})
 }
 
+func TestPostgreSQL_TransactionRetry(t *testing.T) {
+	var mu sync.Mutex
+	var retries []string
+	db := database.NewTestDBWithOptions(t, database.Options{
+		RetryPolicy: database.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond},
+		OnRetry: func(_ context.Context, reason string) {
+			mu.Lock()
+			defer mu.Unlock()
+			retries = append(retries, reason)
+		},
+	})
+	ctx := context.Background()
+	takeRetries := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		taken := retries
+		retries = nil
+		return taken
+	}
+
+	// raiseSerializationFailure fails the transaction the way a conflicting SERIALIZABLE transaction would
+	raiseSerializationFailure := func(ctx context.Context, tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "DO $$ BEGIN RAISE EXCEPTION 'forced conflict' USING ERRCODE = 'serialization_failure'; END $$")
+		return err
+	}
+
+	t.Run("serialization failure is retried", func(t *testing.T) {
+		attempts := 0
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			attempts++
+			if attempts == 1 {
+				return raiseSerializationFailure(ctx, tx)
+			}
+			_, err := db.CreateServer(ctx, tx, &apiv0.ServerJSON{
+				Name:        "com.example/retried-publish",
+				Description: "Published on the second attempt",
+				Version:     "1.0.0",
+			}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+			return err
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []string{database.RetryReasonSerializationFailure}, takeRetries())
+
+		_, err = db.GetServerByName(ctx, nil, "com.example/retried-publish")
+		assert.NoError(t, err)
+	})
+
+	t.Run("dropped connection is retried", func(t *testing.T) {
+		attempts := 0
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			attempts++
+			if attempts == 1 {
+				_, err := tx.Exec(ctx, "SELECT pg_terminate_backend(pg_backend_pid())")
+				return err
+			}
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []string{database.RetryReasonConnection}, takeRetries())
+	})
+
+	t.Run("deadlock is retried", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, "CREATE TABLE retry_counters (id INTEGER PRIMARY KEY, n INTEGER NOT NULL); INSERT INTO retry_counters VALUES (1, 0), (2, 0)")
+			return err
+		})
+		require.NoError(t, err)
+		takeRetries()
+
+		// Each transaction locks one row, waits until the other has locked the other row, then
+		// tries to lock it too. Only first attempts wait, so the retried victim runs straight through.
+		locked := []chan struct{}{make(chan struct{}), make(chan struct{})}
+		increment := func(first, second int) error {
+			attempts := 0
+			return db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+				attempts++
+				if _, err := tx.Exec(ctx, "UPDATE retry_counters SET n = n + 1 WHERE id = $1", first); err != nil {
+					return err
+				}
+				if attempts == 1 {
+					close(locked[first-1])
+					<-locked[second-1]
+				}
+				_, err := tx.Exec(ctx, "UPDATE retry_counters SET n = n + 1 WHERE id = $1", second)
+				return err
+			})
+		}
+
+		var wg sync.WaitGroup
+		errs := make([]error, 2)
+		wg.Add(2)
+		go func() { defer wg.Done(); errs[0] = increment(1, 2) }()
+		go func() { defer wg.Done(); errs[1] = increment(2, 1) }()
+		wg.Wait()
+
+		require.NoError(t, errs[0])
+		require.NoError(t, errs[1])
+		assert.Equal(t, []string{database.RetryReasonDeadlock}, takeRetries())
+
+		// Both transactions committed exactly once
+		var total int
+		err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			return tx.QueryRow(ctx, "SELECT SUM(n) FROM retry_counters").Scan(&total)
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 4, total)
+	})
+
+	t.Run("retries stop when the attempt budget is spent", func(t *testing.T) {
+		attempts := 0
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			attempts++
+			return raiseSerializationFailure(ctx, tx)
+		})
+		require.Error(t, err)
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, takeRetries(), 2)
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		attempts := 0
+		err := db.InTransaction(ctx, func(_ context.Context, _ pgx.Tx) error {
+			attempts++
+			return database.ErrInvalidVersion
+		})
+		assert.ErrorIs(t, err, database.ErrInvalidVersion)
+		assert.Equal(t, 1, attempts)
+		assert.Empty(t, takeRetries())
+	})
+}
+
 func TestPostgreSQL_ConcurrencyAndLocking(t *testing.T) {
 	db := database.NewTestDB(t)
 	ctx := context.Background()
@@ -874,6 +1141,675 @@ func TestPostgreSQL_EdgeCases(t *testing.T) {
 	})
 }
 
+func TestPostgreSQL_FullTextSearch(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	servers := []apiv0.ServerJSON{
+		{Name: "io.github.user/weather", Title: "Weather", Description: "Current conditions and forecasts", Version: "1.0.0"},
+		{Name: "com.example/climate", Description: "Historical weather records", Version: "1.0.0"},
+		{Name: "com.example/maps", Title: "Maps", Description: "Geocoding and routing", Version: "1.0.0"},
+	}
+	for i := range servers {
+		_, err := db.CreateServer(ctx, nil, &servers[i], &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: time.Now(),
+			UpdatedAt:   time.Now(),
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	search := func(query, cursor string, limit int) ([]string, string) {
+		results, nextCursor, err := db.ListServers(ctx, nil, &database.ServerFilter{Query: &query}, cursor, limit)
+		require.NoError(t, err)
+		names := make([]string, len(results))
+		for i, result := range results {
+			names[i] = result.Server.Name
+		}
+		return names, nextCursor
+	}
+
+	t.Run("matches name and description ordered by relevance", func(t *testing.T) {
+		names, _ := search("weather", "", 10)
+		assert.Equal(t, []string{"io.github.user/weather", "com.example/climate"}, names)
+	})
+
+	t.Run("stemmed words match", func(t *testing.T) {
+		names, _ := search("weather forecast", "", 10)
+		assert.Equal(t, []string{"io.github.user/weather"}, names)
+	})
+
+	t.Run("partial words match via trigrams", func(t *testing.T) {
+		names, _ := search("geocod", "", 10)
+		assert.Equal(t, []string{"com.example/maps"}, names)
+	})
+
+	t.Run("search operators are honored", func(t *testing.T) {
+		// Excluded terms and phrases are not bypassed by partial word matching
+		names, _ := search("weather -forecast", "", 10)
+		assert.Equal(t, []string{"com.example/climate"}, names)
+		names, _ = search(`"historical weather"`, "", 10)
+		assert.Equal(t, []string{"com.example/climate"}, names)
+		names, _ = search(`"weather historical"`, "", 10)
+		assert.Empty(t, names)
+		names, _ = search("geocod -routing", "", 10)
+		assert.Empty(t, names)
+
+		names, _ = search("maps or climate", "", 10)
+		assert.ElementsMatch(t, []string{"com.example/maps", "com.example/climate"}, names)
+		names, _ = search("-forecast", "", 10)
+		assert.ElementsMatch(t, []string{"com.example/maps", "com.example/climate"}, names)
+	})
+
+	t.Run("cursor continues in relevance order", func(t *testing.T) {
+		first, nextCursor := search("weather", "", 1)
+		require.NotEmpty(t, nextCursor)
+		second, _ := search("weather", nextCursor, 1)
+		assert.Equal(t, []string{"io.github.user/weather"}, first)
+		assert.Equal(t, []string{"com.example/climate"}, second)
+	})
+
+	t.Run("malformed cursor is rejected", func(t *testing.T) {
+		query := "weather"
+		_, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Query: &query}, "not-a-search-cursor", 10)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestPostgreSQL_SortedListing(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	// Two servers share a publish time so the name tiebreaker is exercised
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	servers := []struct {
+		name        string
+		publishedAt time.Time
+	}{
+		{"com.example/oldest", base},
+		{"com.example/tied-b", base.Add(time.Minute)},
+		{"com.example/tied-a", base.Add(time.Minute)},
+		{"com.example/newest", base.Add(2 * time.Minute)},
+	}
+	for _, server := range servers {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Name:        server.name,
+			Description: "Sorted server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: server.publishedAt,
+			UpdatedAt:   server.publishedAt,
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	listAll := func(filter *database.ServerFilter) []string {
+		var names []string
+		cursor := ""
+		for {
+			results, nextCursor, err := db.ListServers(ctx, nil, filter, cursor, 1)
+			require.NoError(t, err)
+			for _, result := range results {
+				names = append(names, result.Server.Name)
+			}
+			if nextCursor == "" {
+				return names
+			}
+			cursor = nextCursor
+		}
+	}
+
+	assert.Equal(t,
+		[]string{"com.example/newest", "com.example/tied-b", "com.example/tied-a", "com.example/oldest"},
+		listAll(&database.ServerFilter{SortBy: database.SortByPublishedAt, SortOrder: database.SortOrderDesc}))
+	assert.Equal(t,
+		[]string{"com.example/oldest", "com.example/tied-a", "com.example/tied-b", "com.example/newest"},
+		listAll(&database.ServerFilter{SortBy: database.SortByPublishedAt}))
+	assert.Equal(t,
+		[]string{"com.example/tied-b", "com.example/tied-a", "com.example/oldest", "com.example/newest"},
+		listAll(&database.ServerFilter{SortBy: database.SortByName, SortOrder: database.SortOrderDesc}))
+
+	t.Run("cursor from a different sort order is rejected", func(t *testing.T) {
+		_, nextCursor, err := db.ListServers(ctx, nil, &database.ServerFilter{SortBy: database.SortByPublishedAt}, "", 1)
+		require.NoError(t, err)
+
+		_, _, err = db.ListServers(ctx, nil, &database.ServerFilter{SortBy: database.SortByUpdatedAt}, nextCursor, 1)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestPostgreSQL_ListServerFilters(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	servers := []struct {
+		serverJSON  apiv0.ServerJSON
+		publishedAt time.Time
+		status      model.Status
+	}{
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "io.github.acme/weather-npm", Description: "Weather forecasts", Version: "1.0.0",
+				Packages: []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@acme/weather", Version: "1.0.0", Transport: model.Transport{Type: model.TransportTypeStdio}}},
+			},
+			publishedAt: base,
+			status:      model.StatusActive,
+		},
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "io.github.acme_x/weather-pypi", Description: "Weather forecasts", Version: "1.0.0",
+				Packages: []model.Package{{RegistryType: model.RegistryTypePyPI, Identifier: "acme-weather", Version: "1.0.0", Transport: model.Transport{Type: model.TransportTypeStdio}}},
+			},
+			publishedAt: base.Add(time.Minute),
+			status:      model.StatusDeprecated,
+		},
+		{
+			serverJSON: apiv0.ServerJSON{
+				Name: "io.github.acmex/weather-remote", Description: "Weather forecasts", Version: "1.0.0",
+				Remotes: []model.Transport{{Type: model.TransportTypeSSE, URL: "https://acmex.example.com/sse"}},
+			},
+			publishedAt: base.Add(2 * time.Minute),
+			status:      model.StatusActive,
+		},
+	}
+	for _, server := range servers {
+		_, err := db.CreateServer(ctx, nil, &server.serverJSON, &apiv0.RegistryExtensions{
+			Status:      server.status,
+			PublishedAt: server.publishedAt,
+			UpdatedAt:   server.publishedAt,
+			IsLatest:    true,
+		})
+		require.NoError(t, err)
+	}
+
+	npm := model.RegistryTypeNPM
+	npmPackage := "@acme/weather"
+	npmVersion := "1.0.0"
+	otherVersion := "2.0.0"
+	stdio := model.TransportTypeStdio
+	sse := model.TransportTypeSSE
+	deprecated := string(model.StatusDeprecated)
+	underscoreNamespace := "io.github.acme_"
+	query := "weather"
+	publishedSince := base.Add(time.Minute)
+	publishedBefore := base.Add(2 * time.Minute)
+
+	tests := []struct {
+		name          string
+		filter        *database.ServerFilter
+		expectedNames []string
+	}{
+		{
+			name:          "filter by package registry type",
+			filter:        &database.ServerFilter{RegistryType: &npm},
+			expectedNames: []string{"io.github.acme/weather-npm"},
+		},
+		{
+			name:          "look up package identifier and version",
+			filter:        &database.ServerFilter{RegistryType: &npm, PackageID: &npmPackage, PackageVersion: &npmVersion},
+			expectedNames: []string{"io.github.acme/weather-npm"},
+		},
+		{
+			name:          "look up package version that was never published",
+			filter:        &database.ServerFilter{RegistryType: &npm, PackageID: &npmPackage, PackageVersion: &otherVersion},
+			expectedNames: []string{},
+		},
+		{
+			name:          "filter by package transport",
+			filter:        &database.ServerFilter{TransportType: &stdio},
+			expectedNames: []string{"io.github.acme/weather-npm", "io.github.acme_x/weather-pypi"},
+		},
+		{
+			name:          "filter by remote transport",
+			filter:        &database.ServerFilter{TransportType: &sse},
+			expectedNames: []string{"io.github.acmex/weather-remote"},
+		},
+		{
+			name:          "filter by status",
+			filter:        &database.ServerFilter{Status: &deprecated},
+			expectedNames: []string{"io.github.acme_x/weather-pypi"},
+		},
+		{
+			name:          "hide deprecated",
+			filter:        &database.ServerFilter{HideDeprecated: true},
+			expectedNames: []string{"io.github.acme/weather-npm", "io.github.acmex/weather-remote"},
+		},
+		{
+			name:          "namespace prefix treats wildcards literally",
+			filter:        &database.ServerFilter{NamespacePrefix: &underscoreNamespace},
+			expectedNames: []string{"io.github.acme_x/weather-pypi"},
+		},
+		{
+			name:          "filter by publish time range",
+			filter:        &database.ServerFilter{PublishedSince: &publishedSince, PublishedBefore: &publishedBefore},
+			expectedNames: []string{"io.github.acme_x/weather-pypi"},
+		},
+		{
+			name:          "filters combine with full-text search",
+			filter:        &database.ServerFilter{Query: &query, TransportType: &stdio, HideDeprecated: true},
+			expectedNames: []string{"io.github.acme/weather-npm"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, _, err := db.ListServers(ctx, nil, tt.filter, "", 10)
+			require.NoError(t, err)
+
+			actual := make([]string, len(results))
+			for i, result := range results {
+				actual[i] = result.Server.Name
+			}
+			// Name order depends on the database collation, so only membership is checked
+			assert.ElementsMatch(t, tt.expectedNames, actual)
+		})
+	}
+}
+
+func TestPostgreSQL_RawListings(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	base := time.Now().Add(-time.Hour).Truncate(time.Microsecond)
+	for i := 0; i < 5; i++ {
+		serverJSON := apiv0.ServerJSON{
+			Name:        fmt.Sprintf("com.example/raw-%d", i%3),
+			Description: "Forecasts & <alerts>",
+			Version:     fmt.Sprintf("1.0.%d", i),
+			Packages:    []model.Package{{RegistryType: model.RegistryTypeNPM, Identifier: "@example/raw", Version: "1.0.0", Transport: model.Transport{Type: model.TransportTypeStdio}}},
+			Remotes:     []model.Transport{{Type: model.TransportTypeSSE, URL: fmt.Sprintf("https://raw-%d.example.com/sse", i)}},
+		}
+		_, err := db.CreateServer(ctx, nil, &serverJSON, &apiv0.RegistryExtensions{
+			Status:      model.StatusActive,
+			PublishedAt: base.Add(time.Duration(i) * time.Minute),
+			UpdatedAt:   base.Add(time.Duration(i) * time.Minute),
+			IsLatest:    i >= 2,
+		})
+		require.NoError(t, err)
+	}
+
+	assertSameJSON := func(t *testing.T, decoded, raw []*apiv0.ServerResponse) {
+		t.Helper()
+		require.Len(t, raw, len(decoded))
+		for i := range decoded {
+			assert.Equal(t, decoded[i].Server.Name, raw[i].Server.Name)
+			assert.Equal(t, decoded[i].Server.Version, raw[i].Server.Version)
+
+			decodedJSON, err := json.Marshal(decoded[i].Server)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(decodedJSON), string(raw[i].RawServer))
+			assert.Equal(t, decoded[i].Meta, raw[i].Meta)
+		}
+	}
+
+	t.Run("list pages match the decoded listing", func(t *testing.T) {
+		filter := &database.ServerFilter{SortBy: database.SortByPublishedAt, SortOrder: "desc"}
+		cursor := ""
+		for {
+			decoded, decodedCursor, err := db.ListServers(ctx, nil, filter, cursor, 2)
+			require.NoError(t, err)
+			raw, rawCursor, err := db.ListServersRaw(ctx, nil, filter, cursor, 2)
+			require.NoError(t, err)
+
+			assertSameJSON(t, decoded, raw)
+			assert.Equal(t, decodedCursor, rawCursor)
+			if rawCursor == "" {
+				break
+			}
+			cursor = rawCursor
+		}
+	})
+
+	t.Run("search matches the decoded search", func(t *testing.T) {
+		filter := &database.ServerFilter{Query: stringPtr("forecasts")}
+		decoded, decodedCursor, err := db.ListServers(ctx, nil, filter, "", 3)
+		require.NoError(t, err)
+		raw, rawCursor, err := db.ListServersRaw(ctx, nil, filter, "", 3)
+		require.NoError(t, err)
+
+		assertSameJSON(t, decoded, raw)
+		assert.Equal(t, decodedCursor, rawCursor)
+	})
+
+	t.Run("all versions match the decoded versions", func(t *testing.T) {
+		decoded, err := db.GetAllVersionsByServerName(ctx, nil, "com.example/raw-0")
+		require.NoError(t, err)
+		raw, err := db.GetAllVersionsByServerNameRaw(ctx, nil, "com.example/raw-0")
+		require.NoError(t, err)
+		assertSameJSON(t, decoded, raw)
+
+		_, err = db.GetAllVersionsByServerNameRaw(ctx, nil, "com.example/missing")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+}
+
+// BenchmarkPostgreSQL_ListServers pages through a 50k-server registry the way a full sync does, and
+// encodes each page as the list endpoint would, with and without decoding the stored documents
+func BenchmarkPostgreSQL_ListServers(b *testing.B) {
+	const servers, pageSize = 50000, 100
+
+	db := database.NewTestDBWithOptions(b, database.Options{})
+	ctx := context.Background()
+
+	publishedAt := time.Now().Add(-time.Hour)
+	err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		rows := make([][]any, servers)
+		for i := range rows {
+			value, err := json.Marshal(apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        fmt.Sprintf("io.github.bench-%05d/weather", i),
+				Description: "Weather forecasts and severe weather alerts",
+				Version:     "1.0.0",
+				Repository:  &model.Repository{URL: fmt.Sprintf("https://github.com/bench-%05d/weather", i), Source: "github"},
+				Packages: []model.Package{{
+					RegistryType: model.RegistryTypeNPM,
+					Identifier:   fmt.Sprintf("@bench-%05d/weather", i),
+					Version:      "1.0.0",
+					Transport:    model.Transport{Type: model.TransportTypeStdio},
+					EnvironmentVariables: []model.KeyValueInput{
+						{Name: "WEATHER_API_KEY", InputWithVariables: model.InputWithVariables{Input: model.Input{Description: "API key", IsRequired: true, IsSecret: true}}},
+					},
+				}},
+				Remotes: []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: fmt.Sprintf("https://bench-%05d.example.com/mcp", i)}},
+			})
+			if err != nil {
+				return err
+			}
+			rows[i] = []any{fmt.Sprintf("io.github.bench-%05d/weather", i), "1.0.0", string(model.StatusActive), publishedAt, publishedAt, true, value}
+		}
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{"servers"},
+			[]string{"server_name", "version", "status", "published_at", "updated_at", "is_latest", "value"},
+			pgx.CopyFromRows(rows))
+		return err
+	})
+	require.NoError(b, err)
+
+	listAll := func(b *testing.B, list func(ctx context.Context, tx pgx.Tx, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)) {
+		b.Helper()
+		enc := json.NewEncoder(io.Discard)
+		enc.SetEscapeHTML(false)
+		cursor, total := "", 0
+		for {
+			results, nextCursor, err := list(ctx, nil, nil, cursor, pageSize)
+			if err != nil {
+				b.Fatal(err)
+			}
+			// Encoded like the list endpoint, which writes out stored documents in place of decoded servers
+			type entry struct {
+				Server any                `json:"server"`
+				Meta   apiv0.ResponseMeta `json:"_meta"`
+			}
+			page := make([]entry, len(results))
+			for i, result := range results {
+				page[i] = entry{Server: result.Server, Meta: result.Meta}
+				if result.RawServer != nil {
+					page[i].Server = result.RawServer
+				}
+			}
+			if err := enc.Encode(map[string]any{"servers": page, "metadata": apiv0.Metadata{NextCursor: nextCursor, Count: len(page)}}); err != nil {
+				b.Fatal(err)
+			}
+			total += len(results)
+			if nextCursor == "" {
+				break
+			}
+			cursor = nextCursor
+		}
+		if total != servers {
+			b.Fatalf("listed %d servers, expected %d", total, servers)
+		}
+	}
+
+	b.Run("decode", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			listAll(b, db.ListServers)
+		}
+	})
+
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			listAll(b, db.ListServersRaw)
+		}
+	})
+}
+
+func TestPostgreSQL_AuditEvents(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	for i, name := range []string{"com.example/audit-a", "com.example/audit-b", "com.example/audit-a"} {
+		event := &apiv0.AuditEvent{
+			Action:       database.AuditActionPublish,
+			ServerName:   name,
+			Version:      fmt.Sprintf("1.0.%d", i),
+			ActorMethod:  "github-at",
+			ActorSubject: "publisher",
+			After:        map[string]any{"description": "Audited server"},
+		}
+		require.NoError(t, db.CreateAuditEvent(ctx, nil, event))
+		assert.NotZero(t, event.ID)
+		assert.False(t, event.CreatedAt.IsZero())
+	}
+
+	t.Run("pages newest first", func(t *testing.T) {
+		page, cursor, err := db.ListAuditEvents(ctx, nil, nil, "", 2)
+		require.NoError(t, err)
+		require.Len(t, page, 2)
+		assert.Greater(t, page[0].ID, page[1].ID)
+		assert.Equal(t, "Audited server", page[0].After["description"])
+		assert.Nil(t, page[0].Before)
+		require.NotEmpty(t, cursor)
+
+		page, _, err = db.ListAuditEvents(ctx, nil, nil, cursor, 2)
+		require.NoError(t, err)
+		require.Len(t, page, 1)
+		assert.Equal(t, "1.0.0", page[0].Version)
+	})
+
+	t.Run("filters by server name and time", func(t *testing.T) {
+		events, _, err := db.ListAuditEvents(ctx, nil, &database.AuditEventFilter{
+			ServerName: stringPtr("com.example/audit-a"),
+			Since:      timePtr(time.Now().Add(-time.Hour)),
+		}, "", 10)
+		require.NoError(t, err)
+		assert.Len(t, events, 2)
+
+		events, _, err = db.ListAuditEvents(ctx, nil, &database.AuditEventFilter{
+			Until: timePtr(time.Now().Add(-time.Hour)),
+		}, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, events)
+	})
+
+	t.Run("audit log is append-only", func(t *testing.T) {
+		err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+			_, err := tx.Exec(ctx, "DELETE FROM audit_events")
+			return err
+		})
+		assert.ErrorContains(t, err, "append-only")
+	})
+
+	t.Run("events of mutations without a server version", func(t *testing.T) {
+		block := &apiv0.AuditEvent{
+			Action:      database.AuditActionNamespaceBlock,
+			ActorMethod: "github-at",
+			After:       map[string]any{"namespace": "com.evil"},
+		}
+		require.NoError(t, db.CreateAuditEvent(ctx, nil, block))
+		assert.NotZero(t, block.ID)
+
+		err := db.CreateAuditEvent(ctx, nil, &apiv0.AuditEvent{Action: database.AuditActionTagSet, ServerName: "com.example/audit-a", ActorMethod: "github-at"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
+
+func TestPostgreSQL_ChangeEvents(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Name:        "com.example/feed-server",
+		Description: "Server in the change feed",
+		Version:     "1.0.0",
+	}, &apiv0.RegistryExtensions{
+		Status:      model.StatusActive,
+		PublishedAt: time.Now(),
+		UpdatedAt:   time.Now(),
+		IsLatest:    true,
+	})
+	require.NoError(t, err)
+
+	var sequences []int64
+	for _, changeType := range []string{database.ChangeTypePublish, database.ChangeTypeEdit, database.ChangeTypeStatusChange} {
+		event := &apiv0.ChangeEvent{Type: changeType, ServerName: "com.example/feed-server", Version: "1.0.0"}
+		require.NoError(t, db.CreateChangeEvent(ctx, nil, event))
+		sequences = append(sequences, event.Sequence)
+	}
+	assert.Less(t, sequences[0], sequences[1])
+	assert.Less(t, sequences[1], sequences[2])
+
+	changes, err := db.ListChangeEvents(ctx, nil, sequences[0], 10)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, sequences[1], changes[0].Sequence)
+	assert.Equal(t, database.ChangeTypeEdit, changes[0].Type)
+	require.NotNil(t, changes[0].Server)
+	assert.Equal(t, "Server in the change feed", changes[0].Server.Server.Description)
+	assert.True(t, changes[0].Server.Meta.Official.IsLatest)
+
+	// Concurrent writers still produce a gap-free, ordered feed
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+				return db.CreateChangeEvent(ctx, tx, &apiv0.ChangeEvent{Type: database.ChangeTypeEdit, ServerName: "com.example/feed-server", Version: "1.0.0"})
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	changes, err = db.ListChangeEvents(ctx, nil, 0, 100)
+	require.NoError(t, err)
+	require.Len(t, changes, 13)
+	for i := 1; i < len(changes); i++ {
+		assert.Greater(t, changes[i].Sequence, changes[i-1].Sequence)
+	}
+
+	// Events of a transaction are written when it commits, and not at all when it rolls back
+	held := &apiv0.ChangeEvent{Type: database.ChangeTypeEdit, ServerName: "com.example/feed-server", Version: "1.0.0"}
+	err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		require.NoError(t, db.CreateChangeEvent(ctx, tx, held))
+		assert.Zero(t, held.Sequence)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Greater(t, held.Sequence, changes[len(changes)-1].Sequence)
+
+	err = db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		require.NoError(t, db.CreateChangeEvent(ctx, tx, &apiv0.ChangeEvent{Type: database.ChangeTypeEdit, ServerName: "com.example/feed-server", Version: "1.0.0"}))
+		return database.ErrInvalidInput
+	})
+	require.ErrorIs(t, err, database.ErrInvalidInput)
+	changes, err = db.ListChangeEvents(ctx, nil, held.Sequence, 100)
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestPostgreSQL_Webhooks(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	subscription := &apiv0.WebhookSubscription{URL: "https://hooks.example.com/mcp", NamespacePattern: "*", Secret: "secret", OwnerMethod: "github-at", OwnerSubject: "example"}
+	require.NoError(t, db.CreateWebhookSubscription(ctx, nil, subscription))
+	assert.NotZero(t, subscription.ID)
+
+	stored, err := db.GetWebhookSubscription(ctx, nil, subscription.ID)
+	require.NoError(t, err)
+	assert.Equal(t, subscription.URL, stored.URL)
+	assert.Empty(t, stored.Secret)
+
+	var ids []int64
+	for i := 0; i < 5; i++ {
+		delivery := &apiv0.WebhookDelivery{SubscriptionID: subscription.ID, EventType: database.WebhookEventUpdated, ServerName: "com.example/weather", Version: "1.0.0"}
+		require.NoError(t, db.CreateWebhookDelivery(ctx, nil, delivery, []byte(`{"type":"server.updated"}`)))
+		assert.Equal(t, database.WebhookDeliveryPending, delivery.Status)
+		ids = append(ids, delivery.ID)
+	}
+
+	// Concurrent dispatchers never claim the same delivery
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		claimed = map[int64]int{}
+	)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 2, time.Hour)
+			assert.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, job := range jobs {
+				claimed[job.Delivery.ID]++
+				assert.Equal(t, "secret", job.Secret)
+				assert.JSONEq(t, `{"type":"server.updated"}`, string(job.Payload))
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, claimed, 5)
+	for id, count := range claimed {
+		assert.Equal(t, 1, count, "delivery %d", id)
+	}
+
+	require.NoError(t, db.RecordWebhookAttempt(ctx, nil, ids[0], &database.WebhookAttempt{Status: database.WebhookDeliveryDelivered, StatusCode: 204}))
+	require.NoError(t, db.RecordWebhookAttempt(ctx, nil, ids[1], &database.WebhookAttempt{Status: database.WebhookDeliveryDead, StatusCode: 500, Error: "unexpected status 500"}))
+	require.NoError(t, db.RecordWebhookAttempt(ctx, nil, ids[2], &database.WebhookAttempt{Status: database.WebhookDeliveryPending, Error: "connection refused", NextAttemptAt: time.Now().Add(-time.Second)}))
+	assert.ErrorIs(t, db.RecordWebhookAttempt(ctx, nil, -1, &database.WebhookAttempt{Status: database.WebhookDeliveryDead}), database.ErrNotFound)
+
+	dead := database.WebhookDeliveryDead
+	deliveries, err := db.ListWebhookDeliveries(ctx, nil, subscription.ID, &dead, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, 500, deliveries[0].LastStatusCode)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+
+	deliveries, err = db.ListWebhookDeliveries(ctx, nil, subscription.ID, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 5)
+	assert.Equal(t, ids[4], deliveries[0].ID)
+	assert.NotNil(t, deliveries[4].DeliveredAt)
+
+	// The retried delivery is due again, the others are still leased
+	jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 10, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, ids[2], jobs[0].Delivery.ID)
+	assert.Equal(t, "connection refused", jobs[0].Delivery.LastError)
+
+	redelivered, err := db.RedeliverWebhookDelivery(ctx, nil, ids[1])
+	require.NoError(t, err)
+	assert.Equal(t, database.WebhookDeliveryPending, redelivered.Status)
+	assert.Zero(t, redelivered.Attempts)
+
+	require.NoError(t, db.DeleteWebhookSubscription(ctx, nil, subscription.ID))
+	deliveries, err = db.ListWebhookDeliveries(ctx, nil, subscription.ID, nil, 10)
+	require.NoError(t, err)
+	assert.Empty(t, deliveries)
+	_, err = db.GetWebhookSubscription(ctx, nil, subscription.ID)
+	assert.ErrorIs(t, err, database.ErrNotFound)
+}
+
 func TestPostgreSQL_PerformanceScenarios(t *testing.T) {
 	db := database.NewTestDB(t)
 	ctx := context.Background()
@@ -967,3 +1903,364 @@ func boolPtr(b bool) *bool {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestPostgreSQL_RateLimitBuckets(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	// Concurrent requests never take more tokens than the bucket holds
+	var (
+		wg    sync.WaitGroup
+		taken atomic.Int32
+	)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := db.TakeRateLimitToken(ctx, nil, "auth|ip:192.0.2.1", 5, 0.001)
+			assert.NoError(t, err)
+			if ok {
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(5), taken.Load())
+
+	tokens, ok, err := db.TakeRateLimitToken(ctx, nil, "auth|ip:192.0.2.1", 5, 0.001)
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Less(t, tokens, 1.0)
+
+	// Deleting idle buckets refills them
+	require.NoError(t, db.DeleteIdleRateLimitBuckets(ctx, nil, time.Now().Add(time.Minute)))
+	tokens, ok, err = db.TakeRateLimitToken(ctx, nil, "auth|ip:192.0.2.1", 5, 0.001)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.InDelta(t, 4.0, tokens, 0.01)
+
+	// Taking tokens while idle buckets are deleted never fails
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, _, err := db.TakeRateLimitToken(ctx, nil, "auth|ip:192.0.2.2", 1, 0.001)
+			assert.NoError(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, db.DeleteIdleRateLimitBuckets(ctx, nil, time.Now().Add(time.Minute)))
+		}()
+	}
+	wg.Wait()
+
+	_, _, err = db.TakeRateLimitToken(ctx, nil, strings.Repeat("a", 513), 5, 0.001)
+	assert.ErrorIs(t, err, database.ErrInvalidInput)
+}
+
+func TestPostgreSQL_NamespaceBlocks(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Minute)
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "io.github.spammer", Reason: "Spam", CreatedBy: "oidc:admin"}))
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "com.example", Reason: "Old", CreatedBy: "oidc:admin", ExpiresAt: &expired}))
+
+	blocks, err := db.ListNamespaceBlocks(ctx, nil, false)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, "io.github.spammer", blocks[0].Namespace)
+	assert.Equal(t, "oidc:admin", blocks[0].CreatedBy)
+	assert.Nil(t, blocks[0].ExpiresAt)
+
+	blocks, err = db.ListNamespaceBlocks(ctx, nil, true)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	require.NotNil(t, blocks[0].ExpiresAt)
+	assert.WithinDuration(t, expired, *blocks[0].ExpiresAt, time.Millisecond)
+
+	// Blocking again replaces the block
+	require.NoError(t, db.SetNamespaceBlock(ctx, nil, &apiv0.NamespaceBlock{Namespace: "com.example", Reason: "Again", CreatedBy: "oidc:other"}))
+	blocks, err = db.ListNamespaceBlocks(ctx, nil, false)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	assert.Equal(t, "Again", blocks[0].Reason)
+
+	require.NoError(t, db.DeleteNamespaceBlock(ctx, nil, "com.example"))
+	assert.ErrorIs(t, db.DeleteNamespaceBlock(ctx, nil, "com.example"), database.ErrNotFound)
+}
+
+func TestPostgreSQL_RemoteURLClaims(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	conflicts, err := db.ClaimRemoteURLs(ctx, nil, "com.example/weather", []string{"https://weather.example.com/mcp", "https://weather.example.com/sse"})
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+
+	// Claiming again is a no-op, and claims held by other servers are reported and left in place
+	conflicts, err = db.ClaimRemoteURLs(ctx, nil, "com.example/weather", []string{"https://weather.example.com/mcp"})
+	require.NoError(t, err)
+	assert.Empty(t, conflicts)
+	conflicts, err = db.ClaimRemoteURLs(ctx, nil, "com.example/other", []string{"https://weather.example.com/sse", "https://other.example.com/mcp"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"https://weather.example.com/sse": "com.example/weather"}, conflicts)
+
+	url := "https://weather.example.com/sse"
+	claims, err := db.ListRemoteURLClaims(ctx, nil, &database.RemoteURLClaimFilter{URL: &url})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	assert.Equal(t, "com.example/weather", claims[0].ServerName)
+
+	// Keeping nothing releases every claim of the server
+	require.NoError(t, db.ReleaseRemoteURLClaims(ctx, nil, "com.example/weather", nil))
+	claims, err = db.ListRemoteURLClaims(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	assert.Equal(t, "https://other.example.com/mcp", claims[0].URL)
+
+	require.NoError(t, db.DeleteRemoteURLClaim(ctx, nil, "https://other.example.com/mcp"))
+	assert.ErrorIs(t, db.DeleteRemoteURLClaim(ctx, nil, "https://other.example.com/mcp"), database.ErrNotFound)
+}
+
+func TestPostgreSQL_PackageValidations(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	// Validations belong to published versions
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/weather",
+		Description: "Weather server",
+		Version:     "1.0.0",
+	}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+	require.NoError(t, err)
+
+	record := func(identifier string, passed bool, checkedAt time.Time) *apiv0.PackageValidation {
+		t.Helper()
+		validation := &apiv0.PackageValidation{
+			ServerName:   "com.example/weather",
+			Version:      "1.0.0",
+			RegistryType: "npm",
+			Identifier:   identifier,
+			Passed:       passed,
+			CheckedAt:    checkedAt,
+		}
+		if !passed {
+			validation.Error = "package not found"
+		}
+		require.NoError(t, db.RecordPackageValidation(ctx, nil, validation))
+		return validation
+	}
+
+	start := time.Now()
+	passed := record("@example/weather", true, start)
+	assert.Zero(t, passed.ConsecutiveFailures)
+	require.NotNil(t, passed.LastPassedAt)
+
+	// Failures are counted from the last check that passed, which is kept
+	failed := record("@example/weather", false, start.Add(time.Minute))
+	assert.Equal(t, 1, failed.ConsecutiveFailures)
+	require.NotNil(t, failed.LastPassedAt)
+	assert.WithinDuration(t, start, *failed.LastPassedAt, time.Millisecond)
+	failed = record("@example/weather", false, start.Add(2*time.Minute))
+	assert.Equal(t, 2, failed.ConsecutiveFailures)
+
+	record("@example/weather-cli", true, start.Add(2*time.Minute))
+	assert.ErrorIs(t, db.RecordPackageValidation(ctx, nil, &apiv0.PackageValidation{ServerName: "com.example/weather"}), database.ErrInvalidInput)
+
+	validations, err := db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{FailingOnly: true})
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.Equal(t, "@example/weather", validations[0].Identifier)
+	assert.Equal(t, 2, validations[0].ConsecutiveFailures)
+	assert.Equal(t, "package not found", validations[0].Error)
+
+	serverName := "com.example/other"
+	validations, err = db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{ServerName: &serverName})
+	require.NoError(t, err)
+	assert.Empty(t, validations)
+
+	// Inconclusive failures keep the count
+	inconclusive := &apiv0.PackageValidation{
+		ServerName:   "com.example/weather",
+		Version:      "1.0.0",
+		RegistryType: "npm",
+		Identifier:   "@example/weather",
+		Error:        "npm returned status 503",
+		Inconclusive: true,
+		CheckedAt:    start.Add(150 * time.Second),
+	}
+	require.NoError(t, db.RecordPackageValidation(ctx, nil, inconclusive))
+	assert.Equal(t, 2, inconclusive.ConsecutiveFailures)
+	validations, err = db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{FailingOnly: true})
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.True(t, validations[0].Inconclusive)
+	assert.Equal(t, 2, validations[0].ConsecutiveFailures)
+
+	// A passing check resets the count
+	passed = record("@example/weather", true, start.Add(3*time.Minute))
+	assert.False(t, passed.Inconclusive)
+	assert.Zero(t, passed.ConsecutiveFailures)
+
+	require.NoError(t, db.DeletePackageValidationsCheckedBefore(ctx, nil, start.Add(3*time.Minute)))
+	validations, err = db.ListPackageValidations(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, validations, 1)
+	assert.Equal(t, "@example/weather", validations[0].Identifier)
+}
+
+func TestPostgreSQL_RemoteProbes(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	// Probes belong to published versions
+	for _, name := range []string{"com.example/weather", "com.example/calendar"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Remote server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+		require.NoError(t, err)
+	}
+
+	record := func(serverName, url, status string, checkedAt time.Time) *database.RemoteProbe {
+		t.Helper()
+		probe := &database.RemoteProbe{
+			ServerName: serverName,
+			Version:    "1.0.0",
+			RemoteHealth: apiv0.RemoteHealth{
+				URL:           url,
+				TransportType: "streamable-http",
+				Status:        status,
+				CheckedAt:     checkedAt,
+			},
+		}
+		if status == apiv0.RemoteStatusUp {
+			latencyMs := 42
+			probe.ProtocolVersion, probe.LatencyMs = "2025-06-18", &latencyMs
+		} else {
+			probe.Error = "connection refused"
+		}
+		require.NoError(t, db.RecordRemoteProbe(ctx, nil, probe))
+		return probe
+	}
+
+	start := time.Now()
+	up := record("com.example/weather", "https://weather.example.com/mcp", apiv0.RemoteStatusUp, start)
+	require.NotNil(t, up.LastSuccessAt)
+
+	// Failed probes keep what the last successful probe found
+	down := record("com.example/weather", "https://weather.example.com/mcp", apiv0.RemoteStatusDown, start.Add(time.Minute))
+	assert.Equal(t, "2025-06-18", down.ProtocolVersion)
+	require.NotNil(t, down.LatencyMs)
+	assert.Equal(t, 42, *down.LatencyMs)
+	require.NotNil(t, down.LastSuccessAt)
+	assert.WithinDuration(t, start, *down.LastSuccessAt, time.Millisecond)
+
+	record("com.example/calendar", "https://calendar.example.com/mcp", apiv0.RemoteStatusSkipped, start.Add(2*time.Minute))
+	assert.ErrorIs(t, db.RecordRemoteProbe(ctx, nil, &database.RemoteProbe{ServerName: "com.example/weather"}), database.ErrInvalidInput)
+
+	probes, err := db.ListRemoteProbes(ctx, nil, &database.RemoteProbeFilter{ServerNames: []string{"com.example/weather", "com.example/other"}})
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, apiv0.RemoteStatusDown, probes[0].Status)
+	assert.Equal(t, "connection refused", probes[0].Error)
+
+	probes, err = db.ListRemoteProbes(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, probes, 2)
+	assert.Equal(t, "com.example/calendar", probes[0].ServerName)
+	assert.Nil(t, probes[0].LastSuccessAt)
+
+	require.NoError(t, db.DeleteRemoteProbesCheckedBefore(ctx, nil, start.Add(2*time.Minute)))
+	probes, err = db.ListRemoteProbes(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, "com.example/calendar", probes[0].ServerName)
+}
+
+func TestPostgreSQL_ServerCapabilities(t *testing.T) {
+	db := database.NewTestDB(t)
+	ctx := context.Background()
+
+	// Capabilities belong to published versions
+	for _, name := range []string{"com.example/issues", "com.example/calendar"} {
+		_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Remote server",
+			Version:     "1.0.0",
+		}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+		require.NoError(t, err)
+	}
+
+	start := time.Now()
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{
+		ServerName: "com.example/issues",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceIntrospection,
+		Tools:      []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}},
+		Resources:  []apiv0.CapabilityResource{{URI: "repo://issues", Name: "issues"}},
+		CheckedAt:  start,
+	}))
+
+	// Failed introspections keep what the last successful one found
+	failed := &apiv0.ServerCapabilities{
+		ServerName: "com.example/issues",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceIntrospection,
+		Error:      "initialize: unexpected status 503",
+		CheckedAt:  start.Add(time.Minute),
+	}
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, failed))
+	assert.Equal(t, []apiv0.CapabilityTool{{Name: "create_issue", Description: "Create an issue"}}, failed.Tools)
+	require.NotNil(t, failed.UpdatedAt)
+	assert.WithinDuration(t, start, *failed.UpdatedAt, time.Millisecond)
+
+	require.NoError(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{
+		ServerName: "com.example/calendar",
+		Version:    "1.0.0",
+		Source:     apiv0.CapabilitySourceManifest,
+		Prompts:    []apiv0.CapabilityPrompt{{Name: "plan_week"}},
+		CheckedAt:  start,
+	}))
+	assert.ErrorIs(t, db.RecordServerCapabilities(ctx, nil, &apiv0.ServerCapabilities{ServerName: "com.example/issues"}), database.ErrInvalidInput)
+
+	capabilities, err := db.GetServerCapabilities(ctx, nil, "com.example/issues", "1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "initialize: unexpected status 503", capabilities.Error)
+	assert.Len(t, capabilities.Resources, 1)
+	assert.NotNil(t, capabilities.Prompts)
+	_, err = db.GetServerCapabilities(ctx, nil, "com.example/issues", "2.0.0")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+
+	name := "com.example/calendar"
+	list, err := db.ListServerCapabilities(ctx, nil, &database.ServerCapabilitiesFilter{ServerName: &name})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, apiv0.CapabilitySourceManifest, list[0].Source)
+
+	list, err = db.ListServerCapabilities(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, list, 2)
+	assert.Equal(t, "com.example/calendar", list[0].ServerName)
+
+	for capability, expected := range map[string]string{
+		"create_issue":  "com.example/issues",
+		"repo://issues": "com.example/issues",
+		"issues":        "com.example/issues",
+		"plan_week":     "com.example/calendar",
+	} {
+		servers, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Capability: &capability}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, servers, 1, capability)
+		assert.Equal(t, expected, servers[0].Server.Name)
+	}
+	capability := "create"
+	servers, _, err := db.ListServers(ctx, nil, &database.ServerFilter{Capability: &capability}, "", 10)
+	require.NoError(t, err)
+	assert.Empty(t, servers)
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Reasons a transaction is retried, as reported to a RetryObserver
+const (
+	RetryReasonSerializationFailure = "serialization_failure"
+	RetryReasonDeadlock             = "deadlock"
+	RetryReasonConnection           = "connection"
+)
+
+// RetryPolicy bounds how InTransaction retries transactions that failed for transient reasons.
+// Zero fields take the defaults from defaultRetryPolicy.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts including the first, 1 disables retries
+	BaseDelay   time.Duration // upper bound of the jittered delay before the first retry, doubling per retry
+	MaxDelay    time.Duration // cap on the upper bound of a single delay
+	Budget      time.Duration // no retry is started once this much time has passed since the first attempt
+}
+
+var defaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   20 * time.Millisecond,
+	MaxDelay:    500 * time.Millisecond,
+	Budget:      3 * time.Second,
+}
+
+// RetryObserver is told about every transaction retry, for example to count retries in metrics
+type RetryObserver func(ctx context.Context, reason string)
+
+// withDefaults fills zero fields of the policy from defaultRetryPolicy
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaultRetryPolicy.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaultRetryPolicy.BaseDelay
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = defaultRetryPolicy.MaxDelay
+	}
+	if p.Budget <= 0 {
+		p.Budget = defaultRetryPolicy.Budget
+	}
+	return p
+}
+
+// delay returns the wait before the given retry (1 for the first retry), using full jitter so
+// that transactions which conflicted with each other do not retry in lockstep
+func (p RetryPolicy) delay(retry int) time.Duration {
+	ceiling := p.BaseDelay
+	for i := 1; i < retry && ceiling < p.MaxDelay; i++ {
+		ceiling *= 2
+	}
+	ceiling = min(ceiling, p.MaxDelay)
+	return rand.N(ceiling) + 1
+}
+
+// retryReason classifies an error from a transaction, returning the reason it is safe to retry the
+// whole transaction, or "" when it is not. connClosed reports whether the transaction's connection
+// was lost, and committing reports whether the error came from COMMIT: a connection lost during
+// commit leaves the outcome unknown, so that case is never retried.
+func retryReason(err error, connClosed, committing bool) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == "40001":
+			return RetryReasonSerializationFailure
+		case pgErr.Code == "40P01":
+			return RetryReasonDeadlock
+		case committing:
+			return ""
+		case pgErr.Code == "57P01", pgErr.Code == "57P02", pgErr.Code == "57P03", strings.HasPrefix(pgErr.Code, "08"):
+			// Server shutdown during failover, or a connection exception
+			return RetryReasonConnection
+		}
+		return ""
+	}
+
+	// Cancellation and deadlines are the caller giving up, not a transient failure
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ""
+	}
+	if pgconn.SafeToRetry(err) {
+		return RetryReasonConnection
+	}
+	if connClosed && !committing {
+		return RetryReasonConnection
+	}
+	return ""
+}
@@ -0,0 +1,102 @@
+//nolint:testpackage // Tests the unexported classification of transaction errors
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		connClosed bool
+		committing bool
+		expected   string
+	}{
+		{
+			name:     "serialization failure",
+			err:      fmt.Errorf("failed to insert server: %w", &pgconn.PgError{Code: "40001"}),
+			expected: RetryReasonSerializationFailure,
+		},
+		{
+			name:       "serialization failure on commit",
+			err:        &pgconn.PgError{Code: "40001"},
+			committing: true,
+			expected:   RetryReasonSerializationFailure,
+		},
+		{
+			name:     "deadlock",
+			err:      &pgconn.PgError{Code: "40P01"},
+			expected: RetryReasonDeadlock,
+		},
+		{
+			name:       "server shut down during failover",
+			err:        &pgconn.PgError{Code: "57P01"},
+			connClosed: true,
+			expected:   RetryReasonConnection,
+		},
+		{
+			name:       "connection lost while committing",
+			err:        &pgconn.PgError{Code: "57P01"},
+			connClosed: true,
+			committing: true,
+			expected:   "",
+		},
+		{
+			name:       "connection dropped mid-transaction",
+			err:        io.ErrUnexpectedEOF,
+			connClosed: true,
+			expected:   RetryReasonConnection,
+		},
+		{
+			name:       "connection dropped while committing",
+			err:        io.ErrUnexpectedEOF,
+			connClosed: true,
+			committing: true,
+			expected:   "",
+		},
+		{
+			name:     "unique violation",
+			err:      &pgconn.PgError{Code: "23505"},
+			expected: "",
+		},
+		{
+			name:     "application error",
+			err:      ErrInvalidVersion,
+			expected: "",
+		},
+		{
+			name:       "cancelled by the caller",
+			err:        context.Canceled,
+			connClosed: true,
+			expected:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, retryReason(tt.err, tt.connClosed, tt.committing))
+		})
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond}.withDefaults()
+	assert.Equal(t, defaultRetryPolicy.MaxAttempts, policy.MaxAttempts)
+	assert.Equal(t, defaultRetryPolicy.Budget, policy.Budget)
+
+	for i := 0; i < 100; i++ {
+		assert.LessOrEqual(t, policy.delay(1), 10*time.Millisecond)
+		assert.LessOrEqual(t, policy.delay(2), 20*time.Millisecond)
+		assert.LessOrEqual(t, policy.delay(10), 50*time.Millisecond)
+		assert.LessOrEqual(t, policy.delay(100), 50*time.Millisecond)
+		assert.Positive(t, policy.delay(1))
+	}
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// serverRow mirrors a row of the servers table: metadata columns plus the JSONB value
+type serverRow struct {
+	serverName  string
+	version     string
+	status      string
+	publishedAt time.Time
+	updatedAt   time.Time
+	isLatest    bool
+	value       []byte
+
+	deprecationMessage string
+	replacedBy         string
+}
+
+// toServerResponse decodes a stored row into a ServerResponse with separated metadata
+func (r serverRow) toServerResponse() (*apiv0.ServerResponse, error) {
+	var serverJSON apiv0.ServerJSON
+	if err := json.Unmarshal(r.value, &serverJSON); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal server JSON: %w", err)
+	}
+
+	return &apiv0.ServerResponse{
+		Server: serverJSON,
+		Meta:   r.responseMeta(),
+	}, nil
+}
+
+// toRawServerResponse wraps a stored row in a ServerResponse that keeps the stored value undecoded
+func (r serverRow) toRawServerResponse() *apiv0.ServerResponse {
+	return apiv0.NewRawServerResponse(r.serverName, r.version, r.value, r.responseMeta())
+}
+
+// responseMeta builds the official registry metadata from the metadata columns of a row
+func (r serverRow) responseMeta() apiv0.ResponseMeta {
+	return apiv0.ResponseMeta{
+		Official: &apiv0.RegistryExtensions{
+			Status:      model.Status(r.status),
+			PublishedAt: r.publishedAt,
+			UpdatedAt:   r.updatedAt,
+			IsLatest:    r.isLatest,
+
+			DeprecationMessage: r.deprecationMessage,
+			ReplacedBy:         r.replacedBy,
+		},
+	}
+}
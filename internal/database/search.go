@@ -0,0 +1,72 @@
+package database
+
+import (
+	"strings"
+	"unicode"
+)
+
+// searchQuery is a full-text search query in the web search syntax of websearch_to_tsquery:
+// "quoted phrases", alternatives joined by or, and -excluded terms or phrases
+type searchQuery struct {
+	// groups must each match one of their alternatives, which are lowercase words or phrases
+	groups [][]string
+	// excluded are the words and phrases that must not match
+	excluded []string
+	// operators reports whether the query uses quotes, or or exclusions. Partial words only match
+	// queries without operators, so that operators are never bypassed.
+	operators bool
+}
+
+// parseSearchQuery parses a search query the way websearch_to_tsquery does, without stemming
+func parseSearchQuery(query string) searchQuery {
+	var q searchQuery
+	negate, alternative := false, false
+
+	rest := strings.ToLower(query)
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			break
+		}
+
+		var term string
+		switch rest[0] {
+		case '-':
+			negate, q.operators = true, true
+			rest = rest[1:]
+			continue
+		case '"':
+			end := strings.IndexByte(rest[1:], '"')
+			if end < 0 {
+				term, rest = rest[1:], ""
+			} else {
+				term, rest = rest[1:end+1], rest[end+2:]
+			}
+			term = strings.Join(strings.Fields(term), " ")
+			q.operators = true
+		default:
+			end := strings.IndexFunc(rest, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+			if end < 0 {
+				end = len(rest)
+			}
+			term, rest = rest[:end], rest[end:]
+			if term == "or" && !negate && len(q.groups) > 0 {
+				alternative, q.operators = true, true
+				continue
+			}
+		}
+
+		switch {
+		case term == "":
+		case negate:
+			q.excluded = append(q.excluded, term)
+		case alternative:
+			q.groups[len(q.groups)-1] = append(q.groups[len(q.groups)-1], term)
+		default:
+			q.groups = append(q.groups, []string{term})
+		}
+		negate, alternative = false, false
+	}
+
+	return q
+}
@@ -0,0 +1,320 @@
+// Package introspection periodically records the tools, prompts and resources of published servers
+package introspection
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/mcpclient"
+	"github.com/modelcontextprotocol/registry/internal/safehttp"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// pageSize is the number of servers listed per query
+	pageSize = 100
+	// maxPages bounds how many pages of tools, prompts or resources are read from a server
+	maxPages = 20
+	// maxItems bounds how many tools, prompts or resources are recorded for a server version
+	maxItems = 500
+	// maxDescriptionLength bounds the descriptions that are recorded
+	maxDescriptionLength = 2000
+	// maxErrorLength bounds how much of a failed introspection's error is kept
+	maxErrorLength = 512
+)
+
+// Options configures an Introspector. Zero values are replaced with defaults.
+type Options struct {
+	// Interval is how often the capabilities of each version are recorded again
+	Interval time.Duration
+	// Concurrency is the number of versions introspected at the same time
+	Concurrency int
+	// Timeout bounds the introspection of a single version
+	Timeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = 24 * time.Hour
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+	return o
+}
+
+// Introspector records the tools, prompts and resources of latest active versions, from the capabilities
+// manifest in their publisher-provided metadata when there is one, or else by listing them from the first
+// remote that can be connected to without values the publisher did not provide. Several introspectors can
+// share a database: versions recorded recently by any of them are skipped.
+type Introspector struct {
+	db     database.Database
+	client *http.Client
+	opts   Options
+}
+
+// NewIntrospector creates an introspector connecting to remotes with client, or with a client that does not
+// follow redirects or connect to non-public addresses when client is nil
+func NewIntrospector(db database.Database, client *http.Client, opts Options) *Introspector {
+	if client == nil {
+		client = safehttp.NewClient(0)
+	}
+	return &Introspector{db: db, client: client, opts: opts.withDefaults()}
+}
+
+// Run introspects versions that are due, at startup and then every tenth of the interval, until ctx is canceled
+func (i *Introspector) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(i.opts.Interval/10, time.Second))
+	defer ticker.Stop()
+
+	for {
+		if n, err := i.IntrospectDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to introspect servers: %v", err)
+		} else if n > 0 {
+			log.Printf("Recorded the capabilities of %d server version(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// IntrospectDue records the capabilities of latest active versions that have not been recorded within the
+// interval. Capabilities of older versions are kept. It returns the number of versions recorded.
+func (i *Introspector) IntrospectDue(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	previous, err := i.db.ListServerCapabilities(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	checkedAt := make(map[versionKey]time.Time, len(previous))
+	for _, capabilities := range previous {
+		checkedAt[versionKey{capabilities.ServerName, capabilities.Version}] = capabilities.CheckedAt
+	}
+	dueBefore := start.Add(-i.opts.Interval + i.opts.Interval/10)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		recorded int
+		errs     []error
+	)
+	slots := make(chan struct{}, i.opts.Concurrency)
+
+	latest, active := true, string(model.StatusActive)
+	filter := &database.ServerFilter{IsLatest: &latest, Status: &active}
+	cursor := ""
+	for {
+		servers, next, err := i.db.ListServers(ctx, nil, filter, cursor, pageSize)
+		if err != nil {
+			wg.Wait()
+			return recorded, err
+		}
+
+		for _, server := range servers {
+			if last, ok := checkedAt[versionKey{server.Server.Name, server.Server.Version}]; ok && last.After(dueBefore) {
+				continue
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return recorded, ctx.Err()
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				ok, err := i.introspectServer(ctx, &server.Server)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, err)
+				} else if ok {
+					recorded++
+				}
+			}()
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return recorded, fmt.Errorf("%d server version(s) could not be introspected, first error: %w", len(errs), errs[0])
+	}
+	return recorded, nil
+}
+
+// versionKey identifies a server version, like the server_capabilities primary key
+type versionKey struct {
+	serverName string
+	version    string
+}
+
+// introspectServer records the capabilities of a server version. It reports false for versions with
+// neither a manifest nor a remote that can be connected to, for which nothing is recorded.
+func (i *Introspector) introspectServer(ctx context.Context, server *apiv0.ServerJSON) (bool, error) {
+	capabilities := &apiv0.ServerCapabilities{ServerName: server.Name, Version: server.Version}
+
+	manifest, ok, err := server.Meta.CapabilitiesManifest()
+	switch {
+	case ok:
+		capabilities.Source = apiv0.CapabilitySourceManifest
+		if err != nil {
+			capabilities.Error = "invalid capabilities manifest: " + err.Error()
+		} else {
+			capabilities.Tools, capabilities.Prompts, capabilities.Resources = manifest.Tools, manifest.Prompts, manifest.Resources
+		}
+	default:
+		remote, remoteURL, headers, found := publicRemote(server.Remotes)
+		if !found {
+			return false, nil
+		}
+		capabilities.Source = apiv0.CapabilitySourceIntrospection
+		if err := i.introspect(ctx, remote.Type, remoteURL, headers, capabilities); err != nil {
+			if ctx.Err() != nil {
+				// Canceled introspections say nothing about the server
+				return false, ctx.Err()
+			}
+			capabilities.Error = err.Error()
+			log.Printf("Failed to introspect %s %s through %s: %v", server.Name, server.Version, remote.URL, err)
+		}
+	}
+	capabilities.CheckedAt = time.Now()
+	normalize(capabilities)
+
+	if err := i.db.RecordServerCapabilities(ctx, nil, capabilities); err != nil {
+		return false, fmt.Errorf("failed to record capabilities of %s %s: %w", server.Name, server.Version, err)
+	}
+	return true, nil
+}
+
+// publicRemote returns the first streamable HTTP or SSE remote that can be connected to with the values,
+// defaults and choices its publisher provided, along with its resolved URL and headers
+func publicRemote(remotes []model.Transport) (model.Transport, string, http.Header, bool) {
+	for _, remote := range remotes {
+		if remote.Type != model.TransportTypeStreamableHTTP && remote.Type != model.TransportTypeSSE {
+			continue
+		}
+		if remoteURL, headers, err := mcpclient.ResolveRemote(remote); err == nil {
+			return remote, remoteURL, headers, true
+		}
+	}
+	return model.Transport{}, "", nil, false
+}
+
+// introspect connects to a remote and lists the tools, prompts and resources it declared during initialization
+func (i *Introspector) introspect(ctx context.Context, transportType, remoteURL string, headers http.Header, capabilities *apiv0.ServerCapabilities) error {
+	ctx, cancel := context.WithTimeout(ctx, i.opts.Timeout)
+	defer cancel()
+
+	session, err := mcpclient.Connect(ctx, i.client, transportType, remoteURL, headers)
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if session.Capabilities.Tools != nil {
+		if capabilities.Tools, err = list[apiv0.CapabilityTool](ctx, session, "tools/list", "tools"); err != nil {
+			return err
+		}
+	}
+	if session.Capabilities.Prompts != nil {
+		if capabilities.Prompts, err = list[apiv0.CapabilityPrompt](ctx, session, "prompts/list", "prompts"); err != nil {
+			return err
+		}
+	}
+	if session.Capabilities.Resources != nil {
+		if capabilities.Resources, err = list[apiv0.CapabilityResource](ctx, session, "resources/list", "resources"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list reads the pages of a paginated MCP list method, stopping after maxPages pages or maxItems items
+func list[T any](ctx context.Context, session *mcpclient.Session, method, field string) ([]T, error) {
+	var items []T
+	cursor := ""
+	for range maxPages {
+		params := map[string]any{}
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+
+		var page map[string]json.RawMessage
+		if err := session.Call(ctx, method, params, &page); err != nil {
+			return nil, err
+		}
+		var pageItems []T
+		if raw, ok := page[field]; ok {
+			if err := json.Unmarshal(raw, &pageItems); err != nil {
+				return nil, fmt.Errorf("invalid %s result: %w", method, err)
+			}
+		}
+		items = append(items, pageItems...)
+
+		cursor = ""
+		if raw, ok := page["nextCursor"]; ok {
+			_ = json.Unmarshal(raw, &cursor)
+		}
+		if cursor == "" || len(items) >= maxItems {
+			break
+		}
+	}
+	return items, nil
+}
+
+// normalize bounds what is recorded for a server version, dropping nameless entries
+func normalize(capabilities *apiv0.ServerCapabilities) {
+	var tools []apiv0.CapabilityTool
+	for _, tool := range capabilities.Tools {
+		if tool.Name != "" && len(tools) < maxItems {
+			tool.Description = truncate(tool.Description, maxDescriptionLength)
+			tools = append(tools, tool)
+		}
+	}
+	var prompts []apiv0.CapabilityPrompt
+	for _, prompt := range capabilities.Prompts {
+		if prompt.Name != "" && len(prompts) < maxItems {
+			prompt.Description = truncate(prompt.Description, maxDescriptionLength)
+			prompts = append(prompts, prompt)
+		}
+	}
+	var resources []apiv0.CapabilityResource
+	for _, resource := range capabilities.Resources {
+		if resource.URI != "" && len(resources) < maxItems {
+			resource.Description = truncate(resource.Description, maxDescriptionLength)
+			resources = append(resources, resource)
+		}
+	}
+	capabilities.Tools, capabilities.Prompts, capabilities.Resources = tools, prompts, resources
+	capabilities.Error = truncate(capabilities.Error, maxErrorLength)
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return strings.ToValidUTF8(s[:length], "")
+}
@@ -0,0 +1,240 @@
+package introspection_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/introspection"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// stubMCPServer answers for the remotes published in the test, telling them apart by host
+type stubMCPServer struct {
+	mu       sync.Mutex
+	down     bool
+	requests []string
+}
+
+func (s *stubMCPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params struct {
+			Cursor string `json:"cursor"`
+		} `json:"params"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "expected a JSON-RPC message", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, r.Host+" "+request.Method+" "+request.Params.Cursor)
+	down := s.down
+	s.mu.Unlock()
+	if down || r.Host != "issues.example.com" {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	if request.ID == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	var result string
+	switch {
+	case request.Method == "initialize":
+		result = `{"protocolVersion":"2025-06-18","capabilities":{"tools":{"listChanged":true},"resources":{}},"serverInfo":{"name":"issues","version":"1.1.0"}}`
+	case request.Method == "tools/list" && request.Params.Cursor == "":
+		result = `{"tools":[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}}],"nextCursor":"page-2"}`
+	case request.Method == "tools/list" && request.Params.Cursor == "page-2":
+		result = `{"tools":[{"name":"close_issue","inputSchema":{"type":"object"}},{"name":"","description":"Nameless"}]}`
+	case request.Method == "resources/list":
+		result = `{"resources":[{"uri":"repo://issues","name":"issues","mimeType":"application/json"}]}`
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, request.ID)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"result":%s}`, request.ID, result)
+}
+
+// methods returns the requests received from a host, without the initialization handshake
+func (s *stubMCPServer) methods(host string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var methods []string
+	for _, request := range s.requests {
+		if strings.HasPrefix(request, host+" ") && !strings.Contains(request, " initialize") && !strings.Contains(request, "notifications/") {
+			methods = append(methods, strings.TrimSpace(strings.TrimPrefix(request, host+" ")))
+		}
+	}
+	return methods
+}
+
+// stubTransport sends every request to the stub server, keeping the host of the remote URL
+type stubTransport struct {
+	target *url.URL
+}
+
+func (t stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = req.URL.Host
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestIntrospector(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+	registry := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	stub := &stubMCPServer{}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := &http.Client{Transport: stubTransport{target: target}}
+
+	publish := func(name, version string, meta *apiv0.ServerMeta, remotes ...model.Transport) {
+		t.Helper()
+		_, err := registry.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A remote server",
+			Version:     version,
+			Remotes:     remotes,
+			Meta:        meta,
+		})
+		require.NoError(t, err)
+	}
+	publish("com.example/issues", "1.0.0", nil, model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://old.issues.example.com/mcp"})
+	publish("com.example/issues", "1.1.0", nil,
+		model.Transport{
+			Type:    model.TransportTypeStreamableHTTP,
+			URL:     "https://private.issues.example.com/mcp",
+			Headers: []model.KeyValueInput{{Name: "Authorization", InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true, IsSecret: true}}}},
+		},
+		model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://issues.example.com/mcp"},
+	)
+	publish("com.example/calendar", "1.0.0",
+		&apiv0.ServerMeta{PublisherProvided: map[string]interface{}{
+			apiv0.CapabilitiesManifestKey: map[string]interface{}{
+				"tools":   []interface{}{map[string]interface{}{"name": "create_event", "description": "Create a calendar event"}},
+				"prompts": []interface{}{map[string]interface{}{"name": "plan_week"}},
+			},
+		}},
+		model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://calendar.example.com/mcp"},
+	)
+	publish("com.example/private", "1.0.0", nil,
+		model.Transport{
+			Type:      model.TransportTypeStreamableHTTP,
+			URL:       "https://{tenant}.private.example.com/mcp",
+			Variables: map[string]model.Input{"tenant": {IsRequired: true}},
+		},
+	)
+
+	introspector := introspection.NewIntrospector(db, client, introspection.Options{Interval: time.Hour, Timeout: 5 * time.Second})
+
+	t.Run("latest active versions are introspected or read from their manifest", func(t *testing.T) {
+		recorded, err := introspector.IntrospectDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, recorded)
+
+		issues, err := db.GetServerCapabilities(ctx, nil, "com.example/issues", "1.1.0")
+		require.NoError(t, err)
+		assert.Equal(t, apiv0.CapabilitySourceIntrospection, issues.Source)
+		assert.Empty(t, issues.Error)
+		assert.Equal(t, []apiv0.CapabilityTool{
+			{Name: "create_issue", Description: "Create an issue"},
+			{Name: "close_issue"},
+		}, issues.Tools)
+		assert.Empty(t, issues.Prompts)
+		assert.Equal(t, []apiv0.CapabilityResource{{URI: "repo://issues", Name: "issues", MimeType: "application/json"}}, issues.Resources)
+		require.NotNil(t, issues.UpdatedAt)
+
+		// Prompts were not declared, so they are not listed
+		assert.Equal(t, []string{"tools/list", "tools/list page-2", "resources/list"}, stub.methods("issues.example.com"))
+
+		calendar, err := db.GetServerCapabilities(ctx, nil, "com.example/calendar", "1.0.0")
+		require.NoError(t, err)
+		assert.Equal(t, apiv0.CapabilitySourceManifest, calendar.Source)
+		assert.Equal(t, []apiv0.CapabilityTool{{Name: "create_event", Description: "Create a calendar event"}}, calendar.Tools)
+		assert.Equal(t, []apiv0.CapabilityPrompt{{Name: "plan_week"}}, calendar.Prompts)
+		// Servers with a manifest are not connected to
+		assert.Empty(t, stub.methods("calendar.example.com"))
+
+		_, err = db.GetServerCapabilities(ctx, nil, "com.example/issues", "1.0.0")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+		_, err = db.GetServerCapabilities(ctx, nil, "com.example/private", "1.0.0")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("versions introspected within the interval are skipped", func(t *testing.T) {
+		recorded, err := introspector.IntrospectDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, recorded)
+	})
+
+	t.Run("failed introspections keep the last capabilities", func(t *testing.T) {
+		stub.mu.Lock()
+		stub.down = true
+		stub.mu.Unlock()
+
+		// An introspector with a short interval finds every version due again
+		introspector := introspection.NewIntrospector(db, client, introspection.Options{Interval: time.Nanosecond, Timeout: 5 * time.Second})
+		recorded, err := introspector.IntrospectDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, recorded)
+
+		issues, err := db.GetServerCapabilities(ctx, nil, "com.example/issues", "1.1.0")
+		require.NoError(t, err)
+		assert.Equal(t, "initialize: unexpected status 503", issues.Error)
+		assert.Len(t, issues.Tools, 2)
+		require.NotNil(t, issues.UpdatedAt)
+		assert.True(t, issues.UpdatedAt.Before(issues.CheckedAt))
+	})
+
+	t.Run("capabilities are served by version or tag and filter listings", func(t *testing.T) {
+		capabilities, err := registry.GetServerCapabilities(ctx, "com.example/issues", "latest")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", capabilities.Version)
+
+		_, err = registry.GetServerCapabilities(ctx, "com.example/issues", "1.0.0")
+		assert.ErrorIs(t, err, service.ErrCapabilitiesNotRecorded)
+		_, err = registry.GetServerCapabilities(ctx, "com.example/missing", "1.0.0")
+		require.ErrorIs(t, err, database.ErrNotFound)
+		assert.NotErrorIs(t, err, service.ErrCapabilitiesNotRecorded)
+
+		for capability, expected := range map[string][]string{
+			"close_issue":   {"com.example/issues@1.1.0"},
+			"repo://issues": {"com.example/issues@1.1.0"},
+			"plan_week":     {"com.example/calendar@1.0.0"},
+			"Create":        nil,
+		} {
+			servers, _, err := registry.ListServers(ctx, &database.ServerFilter{Capability: &capability}, "", 30)
+			require.NoError(t, err)
+			var names []string
+			for _, server := range servers {
+				names = append(names, server.Server.Name+"@"+server.Server.Version)
+			}
+			assert.Equal(t, expected, names, capability)
+		}
+	})
+}
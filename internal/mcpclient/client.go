@@ -0,0 +1,336 @@
+// Package mcpclient connects to remote MCP servers over the streamable HTTP and legacy HTTP+SSE transports
+package mcpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// ClientProtocolVersion is the MCP protocol version offered in the initialize request
+	ClientProtocolVersion = "2025-06-18"
+	// maxMessageBytes bounds the size of a single response or server-sent event line
+	maxMessageBytes = 4 << 20
+	// sessionHeader carries the session a streamable HTTP server assigned during initialization
+	sessionHeader = "Mcp-Session-Id"
+	// protocolVersionHeader tells streamable HTTP servers the negotiated protocol version after initialization
+	protocolVersionHeader = "Mcp-Protocol-Version"
+	// endSessionTimeout bounds the request ending a streamable HTTP session
+	endSessionTimeout = 5 * time.Second
+)
+
+// ErrUnsupportedTransport is returned when connecting to a remote whose transport cannot be used over HTTP
+var ErrUnsupportedTransport = errors.New("unsupported transport")
+
+// clientInfo identifies the registry to the servers it connects to
+var clientInfo = map[string]string{"name": "mcp-registry", "version": "1.0.0"}
+
+// ServerCapabilities lists which features a server declared during initialization
+type ServerCapabilities struct {
+	Tools     *json.RawMessage `json:"tools,omitempty"`
+	Prompts   *json.RawMessage `json:"prompts,omitempty"`
+	Resources *json.RawMessage `json:"resources,omitempty"`
+}
+
+// Session is an initialized connection to an MCP server. Requests must not be sent concurrently.
+type Session struct {
+	// ProtocolVersion is the protocol version the server chose
+	ProtocolVersion string
+	// Capabilities are the features the server declared
+	Capabilities ServerCapabilities
+	// Latency is how long the server took to answer the initialize request
+	Latency time.Duration
+
+	client        *http.Client
+	transportType string
+	// endpoint receives requests: the server URL for streamable HTTP, or the announced message endpoint for SSE
+	endpoint  string
+	headers   http.Header
+	sessionID string
+	lastID    int
+
+	// events is the open stream of an SSE session, and closeStream closes it
+	events      *eventReader
+	closeStream func()
+}
+
+// Connect initializes a session with the server at remoteURL, sending headers with every request.
+// SSE sessions keep their event stream open until ctx is canceled or the session is closed.
+func Connect(ctx context.Context, client *http.Client, transportType, remoteURL string, headers http.Header) (*Session, error) {
+	s := &Session{client: client, transportType: transportType, endpoint: remoteURL, headers: headers}
+
+	switch transportType {
+	case model.TransportTypeStreamableHTTP:
+	case model.TransportTypeSSE:
+		if err := s.openStream(ctx, remoteURL); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedTransport, transportType)
+	}
+
+	start := time.Now()
+	var result struct {
+		ProtocolVersion string             `json:"protocolVersion"`
+		Capabilities    ServerCapabilities `json:"capabilities"`
+	}
+	err := s.Call(ctx, "initialize", map[string]any{
+		"protocolVersion": ClientProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      clientInfo,
+	}, &result)
+	if err == nil && result.ProtocolVersion == "" {
+		err = errors.New("initialize result has no protocol version")
+	}
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.Latency = time.Since(start)
+	s.ProtocolVersion = result.ProtocolVersion
+	s.Capabilities = result.Capabilities
+
+	if err := s.Notify(ctx, "notifications/initialized"); err != nil {
+		s.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close ends the session. Streamable HTTP servers are asked to discard it, which they may refuse.
+func (s *Session) Close() {
+	if s.closeStream != nil {
+		s.closeStream()
+		return
+	}
+	if s.sessionID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), endSessionTimeout)
+	defer cancel()
+	req, err := s.newRequest(ctx, http.MethodDelete, nil)
+	if err != nil {
+		return
+	}
+	if resp, err := s.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
+
+// rpcError is a JSON-RPC error returned by a server
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// rpcResponse is a JSON-RPC message received from a server, which may also be a request or notification
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+// Call sends a request and decodes its result into result, which may be nil
+func (s *Session) Call(ctx context.Context, method string, params any, result any) error {
+	s.lastID++
+	id := strconv.Itoa(s.lastID)
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": s.lastID, "method": method, "params": params})
+	if err != nil {
+		return fmt.Errorf("invalid %s params: %w", method, err)
+	}
+
+	var response *rpcResponse
+	if s.events != nil {
+		err = s.post(ctx, body)
+		if err == nil {
+			response, err = readResponse(s.events, id)
+		}
+	} else {
+		response, err = s.postStreamable(ctx, body, id)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", method, err)
+	}
+
+	if response.Error != nil {
+		return fmt.Errorf("%s failed with error %d: %s", method, response.Error.Code, response.Error.Message)
+	}
+	if result != nil {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// Notify sends a notification, which servers do not answer
+func (s *Session) Notify(ctx context.Context, method string) error {
+	body, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "method": method})
+	if err != nil {
+		return err
+	}
+	return s.post(ctx, body)
+}
+
+// openStream opens the event stream of an SSE server and waits for the message endpoint it announces
+func (s *Session) openStream(ctx context.Context, remoteURL string) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remoteURL, nil)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	s.setHeaders(req)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		cancel()
+		return err
+	}
+	s.closeStream = func() {
+		cancel()
+		resp.Body.Close()
+	}
+	if resp.StatusCode != http.StatusOK {
+		s.closeStream()
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	s.events = newEventReader(resp.Body)
+	event, data, err := s.events.next()
+	if err == nil && event != "endpoint" {
+		err = fmt.Errorf("expected an endpoint event, got %q", event)
+	}
+	if err != nil {
+		s.closeStream()
+		return fmt.Errorf("no endpoint event: %w", err)
+	}
+	endpoint, err := req.URL.Parse(strings.TrimSpace(data))
+	if err != nil {
+		s.closeStream()
+		return fmt.Errorf("invalid endpoint %q: %w", data, err)
+	}
+	s.endpoint = endpoint.String()
+	return nil
+}
+
+// post sends a message whose response, if any, is not read from the HTTP response
+func (s *Session) post(ctx context.Context, body []byte) error {
+	req, err := s.newRequest(ctx, http.MethodPost, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxMessageBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// postStreamable sends a request to a streamable HTTP server and reads the response with the given ID,
+// which comes as JSON or in an event stream
+func (s *Session) postStreamable(ctx context.Context, body []byte, id string) (*rpcResponse, error) {
+	req, err := s.newRequest(ctx, http.MethodPost, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json, text/event-stream")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if s.sessionID == "" {
+		s.sessionID = resp.Header.Get(sessionHeader)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		var response rpcResponse
+		if err := json.NewDecoder(io.LimitReader(resp.Body, maxMessageBytes)).Decode(&response); err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+		if string(response.ID) != id {
+			return nil, fmt.Errorf("response is for request %s", response.ID)
+		}
+		return &response, nil
+	case "text/event-stream":
+		return readResponse(newEventReader(resp.Body), id)
+	default:
+		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+}
+
+// readResponse reads message events until the response with the given ID
+func readResponse(events *eventReader, id string) (*rpcResponse, error) {
+	for {
+		event, data, err := events.next()
+		if err != nil {
+			return nil, err
+		}
+		if event != "message" {
+			continue
+		}
+
+		var response rpcResponse
+		if err := json.Unmarshal([]byte(data), &response); err != nil {
+			continue
+		}
+		if string(response.ID) == id {
+			return &response, nil
+		}
+	}
+}
+
+func (s *Session) newRequest(ctx context.Context, method string, body []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	s.setHeaders(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if s.sessionID != "" {
+		req.Header.Set(sessionHeader, s.sessionID)
+	}
+	if s.ProtocolVersion != "" && s.transportType == model.TransportTypeStreamableHTTP {
+		req.Header.Set(protocolVersionHeader, s.ProtocolVersion)
+	}
+	return req, nil
+}
+
+func (s *Session) setHeaders(req *http.Request) {
+	for name, values := range s.headers {
+		req.Header[name] = values
+	}
+	req.Header.Set("User-Agent", "mcp-registry")
+}
@@ -0,0 +1,232 @@
+package mcpclient_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/mcpclient"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// rpcRequest is a JSON-RPC request or notification received by the stub servers
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+}
+
+// respond answers the requests of the tests: initialize, and tools/list with one tool
+func respond(request rpcRequest) string {
+	switch request.Method {
+	case "initialize":
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"stub","version":"1.0.0"}}}`, request.ID)
+	case "tools/list":
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"tools":[{"name":"create_issue","description":"Create an issue","inputSchema":{"type":"object"}}]}}`, request.ID)
+	default:
+		return fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"error":{"code":-32601,"message":"method not found"}}`, request.ID)
+	}
+}
+
+func TestConnectStreamableHTTP(t *testing.T) {
+	var methods, protocolVersions, sessions []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			sessions = append(sessions, "ended "+r.Header.Get("Mcp-Session-Id"))
+			return
+		}
+		var request rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		methods = append(methods, request.Method)
+		protocolVersions = append(protocolVersions, r.Header.Get("Mcp-Protocol-Version"))
+		sessions = append(sessions, r.Header.Get("Mcp-Session-Id"))
+		assert.Equal(t, "secret", r.Header.Get("X-Api-Key"))
+
+		if request.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Mcp-Session-Id", "session-1")
+		// Answer tools/list through an event stream, after a notification
+		if request.Method == "tools/list" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{}}\n\n")
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", respond(request))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, respond(request))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	session, err := mcpclient.Connect(ctx, server.Client(), model.TransportTypeStreamableHTTP, server.URL, http.Header{"X-Api-Key": {"secret"}})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-06-18", session.ProtocolVersion)
+	assert.NotNil(t, session.Capabilities.Tools)
+	assert.Nil(t, session.Capabilities.Prompts)
+
+	var result struct {
+		Tools []struct {
+			Name string `json:"name"`
+		} `json:"tools"`
+	}
+	require.NoError(t, session.Call(ctx, "tools/list", map[string]any{}, &result))
+	require.Len(t, result.Tools, 1)
+	assert.Equal(t, "create_issue", result.Tools[0].Name)
+
+	err = session.Call(ctx, "prompts/list", map[string]any{}, nil)
+	require.Error(t, err)
+	assert.Equal(t, "prompts/list failed with error -32601: method not found", err.Error())
+
+	session.Close()
+	assert.Equal(t, []string{"initialize", "notifications/initialized", "tools/list", "prompts/list"}, methods)
+	// The negotiated protocol version and the session are sent after initialization
+	assert.Equal(t, []string{"", "2025-06-18", "2025-06-18", "2025-06-18"}, protocolVersions)
+	assert.Equal(t, []string{"", "session-1", "session-1", "session-1", "ended session-1"}, sessions)
+}
+
+func TestConnectSSE(t *testing.T) {
+	responses := make(chan string, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /sse", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: endpoint\ndata: /messages?session=1\n\n")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case response := <-responses:
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", response)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("POST /messages", func(w http.ResponseWriter, r *http.Request) {
+		var request rpcRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "1", r.URL.Query().Get("session"))
+		if request.ID != nil {
+			responses <- respond(request)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	session, err := mcpclient.Connect(ctx, server.Client(), model.TransportTypeSSE, server.URL+"/sse", nil)
+	require.NoError(t, err)
+	defer session.Close()
+	assert.Equal(t, "2025-06-18", session.ProtocolVersion)
+
+	var result struct {
+		Tools []struct {
+			Description string `json:"description"`
+		} `json:"tools"`
+	}
+	require.NoError(t, session.Call(ctx, "tools/list", map[string]any{}, &result))
+	require.Len(t, result.Tools, 1)
+	assert.Equal(t, "Create an issue", result.Tools[0].Description)
+}
+
+func TestConnectFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	_, err := mcpclient.Connect(ctx, server.Client(), model.TransportTypeStreamableHTTP, server.URL, nil)
+	require.Error(t, err)
+	assert.Equal(t, "initialize: unexpected status 503", err.Error())
+
+	_, err = mcpclient.Connect(ctx, server.Client(), model.TransportTypeSSE, server.URL, nil)
+	require.Error(t, err)
+	assert.Equal(t, "unexpected status 503", err.Error())
+
+	_, err = mcpclient.Connect(ctx, server.Client(), model.TransportTypeStdio, server.URL, nil)
+	assert.ErrorIs(t, err, mcpclient.ErrUnsupportedTransport)
+}
+
+func TestResolveRemote(t *testing.T) {
+	header := func(name string, input model.Input, variables map[string]model.Input) model.KeyValueInput {
+		return model.KeyValueInput{Name: name, InputWithVariables: model.InputWithVariables{Input: input, Variables: variables}}
+	}
+
+	tests := []struct {
+		name            string
+		remote          model.Transport
+		expectedURL     string
+		expectedHeaders http.Header
+		expectedError   string
+	}{
+		{
+			name:            "plain URL",
+			remote:          model.Transport{URL: "https://api.example.com/mcp"},
+			expectedURL:     "https://api.example.com/mcp",
+			expectedHeaders: http.Header{},
+		},
+		{
+			name: "variables use their value, default or first choice",
+			remote: model.Transport{
+				URL: "https://{tenant}.example.com/{region}/{stage}/mcp",
+				Variables: map[string]model.Input{
+					"tenant": {Value: "acme"},
+					"region": {Default: "eu", Choices: []string{"us", "eu"}},
+					"stage":  {Choices: []string{"prod", "staging"}},
+				},
+			},
+			expectedURL:     "https://acme.example.com/eu/prod/mcp",
+			expectedHeaders: http.Header{},
+		},
+		{
+			name: "headers use their value or default, filling in their own variables",
+			remote: model.Transport{
+				URL: "https://api.example.com/mcp",
+				Headers: []model.KeyValueInput{
+					header("X-Api-Version", model.Input{Default: "2"}, nil),
+					header("X-Client", model.Input{Value: "registry-{env}"}, map[string]model.Input{"env": {Default: "prod"}}),
+					header("X-Trace", model.Input{}, nil),
+				},
+			},
+			expectedURL:     "https://api.example.com/mcp",
+			expectedHeaders: http.Header{"X-Api-Version": {"2"}, "X-Client": {"registry-prod"}},
+		},
+		{
+			name:          "URL variables without a value",
+			remote:        model.Transport{URL: "https://{tenant}.example.com/mcp", Variables: map[string]model.Input{"tenant": {IsRequired: true}}},
+			expectedError: "URL variable tenant needs a value that was not published",
+		},
+		{
+			name: "required headers without a value",
+			remote: model.Transport{
+				URL: "https://api.example.com/mcp",
+				Headers: []model.KeyValueInput{
+					header("Authorization", model.Input{IsRequired: true, Value: "Bearer {token}"}, map[string]model.Input{"token": {IsSecret: true}}),
+				},
+			},
+			expectedError: "header Authorization needs a value that was not published",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteURL, headers, err := mcpclient.ResolveRemote(tt.remote)
+			if tt.expectedError != "" {
+				require.ErrorIs(t, err, mcpclient.ErrMissingValue)
+				assert.Equal(t, tt.expectedError, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedURL, remoteURL)
+			assert.Equal(t, tt.expectedHeaders, headers)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package mcpclient
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// eventReader reads server-sent events
+type eventReader struct {
+	scanner *bufio.Scanner
+}
+
+func newEventReader(r io.Reader) *eventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxMessageBytes)
+	return &eventReader{scanner: scanner}
+}
+
+// next returns the type and data of the next event. Events without a type are message events.
+func (r *eventReader) next() (string, string, error) {
+	event := ""
+	var data []string
+	for r.scanner.Scan() {
+		line := r.scanner.Text()
+		if line == "" {
+			if data == nil {
+				event = ""
+				continue
+			}
+			if event == "" {
+				event = "message"
+			}
+			return event, strings.Join(data, "\n"), nil
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return "", "", err
+	}
+	return "", "", io.EOF
+}
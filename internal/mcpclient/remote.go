@@ -0,0 +1,71 @@
+package mcpclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ErrMissingValue is returned by ResolveRemote for remotes needing a value their publisher did not provide
+var ErrMissingValue = errors.New("needs a value that was not published")
+
+// templateVariable matches a variable of a remote URL or header value, such as {tenant}
+var templateVariable = regexp.MustCompile(`\{([^}]*)\}`)
+
+// ResolveRemote fills in the URL template variables and headers of a remote from the values, defaults and
+// choices its publisher provided, so that anyone can connect to it. Optional headers without a value are left out.
+func ResolveRemote(remote model.Transport) (string, http.Header, error) {
+	remoteURL, err := substitute(remote.URL, remote.Variables)
+	if err != nil {
+		return "", nil, fmt.Errorf("URL %w", err)
+	}
+
+	headers := http.Header{}
+	for _, header := range remote.Headers {
+		value := header.Value
+		if value == "" {
+			value = header.Default
+		}
+		if value != "" {
+			value, err = substitute(value, header.Variables)
+		}
+		if value == "" || err != nil {
+			if header.IsRequired {
+				return "", nil, fmt.Errorf("header %s %w", header.Name, ErrMissingValue)
+			}
+			continue
+		}
+		headers.Set(header.Name, value)
+	}
+
+	return remoteURL, headers, nil
+}
+
+// substitute replaces the {variables} of s with their published values, defaults or first choices
+func substitute(s string, variables map[string]model.Input) (string, error) {
+	var missing string
+	result := templateVariable.ReplaceAllStringFunc(s, func(match string) string {
+		name := match[1 : len(match)-1]
+		variable := variables[name]
+		switch {
+		case variable.Value != "" && !strings.Contains(variable.Value, "{"):
+			return variable.Value
+		case variable.Default != "":
+			return variable.Default
+		case len(variable.Choices) > 0:
+			return variable.Choices[0]
+		}
+		if missing == "" {
+			missing = name
+		}
+		return match
+	})
+	if missing != "" {
+		return "", fmt.Errorf("variable %s %w", missing, ErrMissingValue)
+	}
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// DatabaseStore keeps token buckets in the database, so that limits apply across all replicas
+type DatabaseStore struct {
+	db database.Database
+
+	mu        sync.Mutex
+	lastPrune time.Time
+	maxWindow time.Duration
+}
+
+// NewDatabaseStore creates a store keeping the buckets of policies in db
+func NewDatabaseStore(db database.Database, policies []Policy) *DatabaseStore {
+	store := &DatabaseStore{db: db, lastPrune: time.Now()}
+	for _, policy := range policies {
+		store.maxWindow = max(store.maxWindow, policy.Window)
+	}
+	return store
+}
+
+// Take refills the bucket for key and takes a token from it if one is available
+func (s *DatabaseStore) Take(ctx context.Context, key string, policy Policy) (*Result, error) {
+	tokens, allowed, err := s.db.TakeRateLimitToken(ctx, nil, key, float64(policy.Requests), policy.refillRate())
+	if errors.Is(err, database.ErrInvalidInput) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.prune(ctx)
+	return newResult(policy, tokens, allowed), nil
+}
+
+// prune deletes buckets that have been idle for longer than any window, which makes them full,
+// at most once per prune interval per replica
+func (s *DatabaseStore) prune(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = time.Now()
+	before := s.lastPrune.Add(-s.maxWindow)
+	s.mu.Unlock()
+
+	if err := s.db.DeleteIdleRateLimitBuckets(ctx, nil, before); err != nil {
+		log.Printf("Failed to delete idle rate limit buckets: %v", err)
+	}
+}
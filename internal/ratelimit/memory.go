@@ -0,0 +1,69 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pruneInterval is how often idle buckets are dropped from memory
+const pruneInterval = time.Minute
+
+type memoryBucket struct {
+	tokens    float64
+	updatedAt time.Time
+	window    time.Duration
+}
+
+// MemoryStore keeps token buckets in process memory. Limits apply per replica.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*memoryBucket), now: time.Now}
+}
+
+// Take refills the bucket for key and takes a token from it if one is available
+func (s *MemoryStore) Take(_ context.Context, key string, policy Policy) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &memoryBucket{tokens: float64(policy.Requests), updatedAt: now}
+		s.buckets[key] = bucket
+	}
+	bucket.window = policy.Window
+
+	tokens := min(float64(policy.Requests), bucket.tokens+now.Sub(bucket.updatedAt).Seconds()*policy.refillRate())
+	if tokens < 1 {
+		// Leaving the bucket as it was is equivalent to storing the refilled tokens
+		return newResult(policy, tokens, false), nil
+	}
+
+	bucket.tokens = tokens - 1
+	bucket.updatedAt = now
+	return newResult(policy, bucket.tokens, true), nil
+}
+
+// prune drops buckets that have been idle long enough to be full, which is the same as having no bucket
+func (s *MemoryStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	s.lastPrune = now
+
+	for key, bucket := range s.buckets {
+		if now.Sub(bucket.updatedAt) >= bucket.window {
+			delete(s.buckets, key)
+		}
+	}
+}
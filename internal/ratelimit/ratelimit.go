@@ -0,0 +1,160 @@
+// Package ratelimit limits request rates with token buckets, kept in memory or in the database
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyType selects who a policy counts requests for
+type KeyType string
+
+const (
+	// KeySubject counts requests per authenticated token holder, falling back to the client IP
+	KeySubject KeyType = "subject"
+	// KeyNamespace counts requests per server namespace, falling back to the token holder
+	KeyNamespace KeyType = "namespace"
+	// KeyIP counts requests per client IP
+	KeyIP KeyType = "ip"
+)
+
+// DefaultRoute is the policy name that applies to routes without a policy of their own
+const DefaultRoute = "*"
+
+// Policy allows bursts of up to Requests requests, refilled evenly over Window
+type Policy struct {
+	Route    string // operation tag (such as publish) or operation ID (such as get-server) the policy applies to
+	Requests int
+	Window   time.Duration
+	Key      KeyType
+}
+
+// refillRate returns the number of tokens added to a bucket per second
+func (p Policy) refillRate() float64 {
+	return float64(p.Requests) / p.Window.Seconds()
+}
+
+// Result is the outcome of taking a token for a request
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next request is allowed, zero when this one was
+}
+
+// newResult describes a bucket holding tokens after a request was allowed or not
+func newResult(p Policy, tokens float64, allowed bool) *Result {
+	rate := p.refillRate()
+	result := &Result{
+		Allowed:   allowed,
+		Limit:     p.Requests,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((float64(p.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((1 - tokens) / rate)
+	}
+	return result
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Ceil(max(seconds, 0) * float64(time.Second)))
+}
+
+// ErrInvalidKey is returned by stores for bucket keys they cannot keep
+var ErrInvalidKey = errors.New("invalid rate limit key")
+
+// Store keeps token buckets
+type Store interface {
+	// Take refills the bucket for key according to the policy and takes a token from it if one is available
+	Take(ctx context.Context, key string, policy Policy) (*Result, error)
+}
+
+// Limiter applies per-route policies to requests
+type Limiter struct {
+	store    Store
+	policies map[string]Policy
+}
+
+// NewLimiter creates a limiter enforcing the policies with buckets kept in store
+func NewLimiter(store Store, policies []Policy) *Limiter {
+	byRoute := make(map[string]Policy, len(policies))
+	for _, policy := range policies {
+		byRoute[policy.Route] = policy
+	}
+	return &Limiter{store: store, policies: byRoute}
+}
+
+// Policy returns the policy of the first of routes that has one, or the default policy
+func (l *Limiter) Policy(routes ...string) (Policy, bool) {
+	for _, route := range routes {
+		if policy, ok := l.policies[route]; ok {
+			return policy, true
+		}
+	}
+	policy, ok := l.policies[DefaultRoute]
+	return policy, ok
+}
+
+// Allow takes a token for a request identified by key, such as a client IP or token subject, under a policy.
+// Each policy has its own buckets. Keys are hashed, since they come from requests and can be arbitrarily long.
+func (l *Limiter) Allow(ctx context.Context, policy Policy, key string) (*Result, error) {
+	sum := sha256.Sum256([]byte(key))
+	return l.store.Take(ctx, policy.Route+"|"+hex.EncodeToString(sum[:]), policy)
+}
+
+// policyPattern matches one entry of a policy list, such as publish=60/1h:subject
+var policyPattern = regexp.MustCompile(`^([a-z0-9*][a-z0-9.-]*)=([0-9]+)/([0-9a-z.]+)(?::([a-z]+))?$`)
+
+// ParsePolicies parses a comma-separated list of policies of the form route=requests/window[:key],
+// such as "auth=20/1m:ip,publish=60/1h:subject,*=1200/1m". The key defaults to ip.
+func ParsePolicies(spec string) ([]Policy, error) {
+	var policies []Policy
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		match := policyPattern.FindStringSubmatch(entry)
+		if match == nil {
+			return nil, fmt.Errorf("invalid rate limit policy %q: expected route=requests/window[:key]", entry)
+		}
+
+		requests, err := strconv.Atoi(match[2])
+		if err != nil || requests <= 0 {
+			return nil, fmt.Errorf("invalid rate limit policy %q: requests must be a positive number", entry)
+		}
+		window, err := time.ParseDuration(match[3])
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid rate limit policy %q: window must be a positive duration such as 1m", entry)
+		}
+
+		key := KeyType(match[4])
+		switch key {
+		case "":
+			key = KeyIP
+		case KeySubject, KeyNamespace, KeyIP:
+		default:
+			return nil, fmt.Errorf("invalid rate limit policy %q: key must be subject, namespace or ip", entry)
+		}
+
+		if seen[match[1]] {
+			return nil, fmt.Errorf("invalid rate limit policy %q: route %s has more than one policy", entry, match[1])
+		}
+		seen[match[1]] = true
+
+		policies = append(policies, Policy{Route: match[1], Requests: requests, Window: window, Key: key})
+	}
+	return policies, nil
+}
@@ -0,0 +1,133 @@
+//nolint:testpackage
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+func TestParsePolicies(t *testing.T) {
+	policies, err := ParsePolicies(" auth=20/1m:ip, publish=60/1h:subject,get-server-version=10/1s:namespace,*=1200/1m ")
+	require.NoError(t, err)
+	assert.Equal(t, []Policy{
+		{Route: "auth", Requests: 20, Window: time.Minute, Key: KeyIP},
+		{Route: "publish", Requests: 60, Window: time.Hour, Key: KeySubject},
+		{Route: "get-server-version", Requests: 10, Window: time.Second, Key: KeyNamespace},
+		{Route: "*", Requests: 1200, Window: time.Minute, Key: KeyIP},
+	}, policies)
+
+	policies, err = ParsePolicies("")
+	require.NoError(t, err)
+	assert.Empty(t, policies)
+
+	for _, spec := range []string{
+		"auth",
+		"auth=20",
+		"auth=0/1m",
+		"auth=20/0s",
+		"auth=20/soon",
+		"auth=20/1m:user",
+		"auth=20/1m,auth=30/1m",
+	} {
+		_, err := ParsePolicies(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestLimiterPolicy(t *testing.T) {
+	auth := Policy{Route: "auth", Requests: 20, Window: time.Minute, Key: KeyIP}
+	publish := Policy{Route: "publish-server", Requests: 60, Window: time.Hour, Key: KeySubject}
+
+	limiter := NewLimiter(NewMemoryStore(), []Policy{auth, publish})
+	policy, ok := limiter.Policy("publish-server", "publish")
+	assert.True(t, ok)
+	assert.Equal(t, publish, policy)
+	_, ok = limiter.Policy("list-servers", "servers")
+	assert.False(t, ok)
+
+	fallback := Policy{Route: DefaultRoute, Requests: 1200, Window: time.Minute, Key: KeyIP}
+	limiter = NewLimiter(NewMemoryStore(), []Policy{auth, fallback})
+	policy, ok = limiter.Policy("list-servers", "servers")
+	assert.True(t, ok)
+	assert.Equal(t, fallback, policy)
+}
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	policy := Policy{Route: "publish", Requests: 3, Window: 30 * time.Second, Key: KeySubject}
+	limiter := NewLimiter(store, []Policy{policy})
+
+	t.Run("bursts up to the limit", func(t *testing.T) {
+		for i := 2; i >= 0; i-- {
+			result, err := limiter.Allow(ctx, policy, "subject:alice")
+			require.NoError(t, err)
+			assert.True(t, result.Allowed)
+			assert.Equal(t, 3, result.Limit)
+			assert.Equal(t, i, result.Remaining)
+			assert.Zero(t, result.RetryAfter)
+		}
+
+		result, err := limiter.Allow(ctx, policy, "subject:alice")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+		assert.Equal(t, 10*time.Second, result.RetryAfter)
+		assert.Equal(t, 30*time.Second, result.Reset)
+	})
+
+	t.Run("keys have buckets of their own", func(t *testing.T) {
+		result, err := limiter.Allow(ctx, policy, "subject:bob")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	})
+
+	t.Run("buckets refill over the window", func(t *testing.T) {
+		now = now.Add(5 * time.Second)
+		result, err := limiter.Allow(ctx, policy, "subject:alice")
+		require.NoError(t, err)
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 5*time.Second, result.RetryAfter)
+
+		now = now.Add(5 * time.Second)
+		result, err = limiter.Allow(ctx, policy, "subject:alice")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 0, result.Remaining)
+	})
+
+	t.Run("idle buckets are pruned", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		_, err := limiter.Allow(ctx, policy, "subject:carol")
+		require.NoError(t, err)
+		assert.Len(t, store.buckets, 1)
+	})
+}
+
+func TestDatabaseStore(t *testing.T) {
+	ctx := context.Background()
+	policy := Policy{Route: "publish-server", Requests: 1, Window: time.Hour, Key: KeyNamespace}
+	store := NewDatabaseStore(database.NewMemory(), []Policy{policy})
+	limiter := NewLimiter(store, []Policy{policy})
+
+	// Keys come from requests and are hashed, so any namespace fits in a bucket key
+	namespace := "namespace:" + strings.Repeat("a", 10000)
+	result, err := limiter.Allow(ctx, policy, namespace)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = limiter.Allow(ctx, policy, namespace)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	_, err = store.Take(ctx, strings.Repeat("a", 1000), policy)
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
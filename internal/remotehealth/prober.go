@@ -0,0 +1,250 @@
+// Package remotehealth periodically checks that the remotes of published servers answer the MCP initialize handshake
+package remotehealth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/mcpclient"
+	"github.com/modelcontextprotocol/registry/internal/safehttp"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// pageSize is the number of servers listed per query
+	pageSize = 100
+	// maxErrorLength bounds how much of a failed probe's error is kept
+	maxErrorLength = 512
+)
+
+// Options configures a Prober. Zero values are replaced with defaults.
+type Options struct {
+	// Interval is how often each remote is probed
+	Interval time.Duration
+	// Concurrency is the number of remotes probed at the same time
+	Concurrency int
+	// Timeout bounds the handshake with a single remote
+	Timeout time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = 15 * time.Minute
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 8
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	return o
+}
+
+// Prober performs the MCP initialize handshake with the streamable HTTP and SSE remotes of latest active
+// versions, recording whether each remote answered, the protocol version it chose, and how long it took.
+// Several probers can share a database: remotes probed recently by any of them are skipped.
+type Prober struct {
+	db     database.Database
+	client *http.Client
+	opts   Options
+}
+
+// NewProber creates a prober connecting to remotes with client, or with a client that does not follow
+// redirects or connect to non-public addresses when client is nil
+func NewProber(db database.Database, client *http.Client, opts Options) *Prober {
+	if client == nil {
+		client = safehttp.NewClient(0)
+	}
+	return &Prober{db: db, client: client, opts: opts.withDefaults()}
+}
+
+// Run probes remotes that are due, at startup and then every tenth of the interval, until ctx is canceled
+func (p *Prober) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(p.opts.Interval/10, time.Second))
+	defer ticker.Stop()
+
+	for {
+		if n, err := p.ProbeDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to probe remotes: %v", err)
+		} else if n > 0 {
+			log.Printf("Probed %d remote(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProbeDue probes the remotes of latest active versions that have not been probed within the interval,
+// and forgets about remotes that no longer belong to a latest active version. It returns the number of
+// remotes probed.
+func (p *Prober) ProbeDue(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	previous, err := p.db.ListRemoteProbes(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	checkedAt := make(map[remoteKey]time.Time, len(previous))
+	for _, probe := range previous {
+		checkedAt[remoteKey{probe.ServerName, probe.Version, probe.URL}] = probe.CheckedAt
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		probed int
+		errs   []error
+	)
+	slots := make(chan struct{}, p.opts.Concurrency)
+
+	latest, active := true, string(model.StatusActive)
+	filter := &database.ServerFilter{IsLatest: &latest, Status: &active}
+	cursor := ""
+	for {
+		servers, next, err := p.db.ListServers(ctx, nil, filter, cursor, pageSize)
+		if err != nil {
+			wg.Wait()
+			return probed, err
+		}
+
+		for _, server := range servers {
+			for _, remote := range p.dueRemotes(server, checkedAt, start) {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					wg.Wait()
+					return probed, ctx.Err()
+				}
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer func() { <-slots }()
+					err := p.probeRemote(ctx, server.Server.Name, server.Server.Version, remote)
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						errs = append(errs, err)
+						return
+					}
+					probed++
+				}()
+			}
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return probed, fmt.Errorf("%d remote(s) could not be probed, first error: %w", len(errs), errs[0])
+	}
+
+	// Every remote of a latest active version was probed within the interval, so older results are for
+	// versions that are no longer latest or active
+	if err := p.db.DeleteRemoteProbesCheckedBefore(ctx, nil, start.Add(-p.opts.Interval)); err != nil {
+		return probed, err
+	}
+
+	return probed, nil
+}
+
+// remoteKey identifies a remote of a server version, like the remote_probes primary key
+type remoteKey struct {
+	serverName string
+	version    string
+	url        string
+}
+
+// dueRemotes returns the remotes of a server version that have not been probed within the interval.
+// A tenth of the interval is allowed as slack, so that remotes are probed on the tick after they are due.
+func (p *Prober) dueRemotes(server *apiv0.ServerResponse, checkedAt map[remoteKey]time.Time, now time.Time) []model.Transport {
+	dueBefore := now.Add(-p.opts.Interval + p.opts.Interval/10)
+
+	var due []model.Transport
+	seen := map[remoteKey]bool{}
+	for _, remote := range server.Server.Remotes {
+		key := remoteKey{server.Server.Name, server.Server.Version, remote.URL}
+		if seen[key] || remote.URL == "" {
+			continue
+		}
+		seen[key] = true
+		if last, ok := checkedAt[key]; ok && last.After(dueBefore) {
+			continue
+		}
+		due = append(due, remote)
+	}
+	return due
+}
+
+// probeRemote probes one remote of a server version and records the outcome
+func (p *Prober) probeRemote(ctx context.Context, serverName, version string, remote model.Transport) error {
+	probe := p.probe(ctx, remote)
+	if ctx.Err() != nil {
+		// Canceled probes say nothing about the remote
+		return ctx.Err()
+	}
+
+	record := &database.RemoteProbe{ServerName: serverName, Version: version, RemoteHealth: *probe}
+	if err := p.db.RecordRemoteProbe(ctx, nil, record); err != nil {
+		return fmt.Errorf("failed to record probe of %s: %w", remote.URL, err)
+	}
+	if probe.Status == apiv0.RemoteStatusDown {
+		log.Printf("Remote %s of %s %s is down: %s", remote.URL, serverName, version, probe.Error)
+	}
+	return nil
+}
+
+// probe performs the initialize handshake with a remote, filling in its URL template and headers first
+func (p *Prober) probe(ctx context.Context, remote model.Transport) *apiv0.RemoteHealth {
+	health := &apiv0.RemoteHealth{
+		URL:           remote.URL,
+		TransportType: remote.Type,
+	}
+
+	remoteURL, headers, err := mcpclient.ResolveRemote(remote)
+	if err == nil {
+		probeCtx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+		defer cancel()
+
+		var session *mcpclient.Session
+		session, err = mcpclient.Connect(probeCtx, p.client, remote.Type, remoteURL, headers)
+		if err == nil {
+			session.Close()
+			latencyMs := int(session.Latency.Milliseconds())
+			health.ProtocolVersion, health.LatencyMs = session.ProtocolVersion, &latencyMs
+		}
+	}
+	health.CheckedAt = time.Now()
+
+	switch {
+	case err == nil:
+		health.Status = apiv0.RemoteStatusUp
+		lastSuccessAt := health.CheckedAt
+		health.LastSuccessAt = &lastSuccessAt
+	case errors.Is(err, mcpclient.ErrMissingValue), errors.Is(err, mcpclient.ErrUnsupportedTransport):
+		health.Status = apiv0.RemoteStatusSkipped
+		health.Error = err.Error()
+	default:
+		health.Status = apiv0.RemoteStatusDown
+		health.Error = err.Error()
+	}
+	if len(health.Error) > maxErrorLength {
+		health.Error = strings.ToValidUTF8(health.Error[:maxErrorLength], "")
+	}
+	return health
+}
@@ -0,0 +1,335 @@
+package remotehealth_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/remotehealth"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// stubMCPServer answers the initialize handshake for the remotes published in the test, telling them
+// apart by host
+type stubMCPServer struct {
+	mu            sync.Mutex
+	calendarDown  bool
+	endedSessions []string
+	// sseResponses passes initialize responses from the message endpoint to the open SSE stream
+	sseResponses chan string
+}
+
+func (s *stubMCPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Host + r.URL.Path {
+	case "eu.weather.example.com/mcp":
+		s.serveStreamableJSON(w, r)
+	case "weather.example.com/sse":
+		s.serveSSEStream(w, r)
+	case "weather.example.com/messages":
+		id, ok := readInitialize(w, r)
+		if !ok {
+			return
+		}
+		s.sseResponses <- initializeResponse(id, "2024-11-05")
+		w.WriteHeader(http.StatusAccepted)
+	case "calendar.example.com/mcp":
+		s.mu.Lock()
+		down := s.calendarDown
+		s.mu.Unlock()
+		if down {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		s.serveStreamableEvents(w, r)
+	case "broken.example.com/mcp":
+		id, ok := readInitialize(w, r)
+		if !ok {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%s,"error":{"code":-32602,"message":"unsupported protocol version"}}`, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// serveStreamableJSON answers with a JSON response and starts a session, which the prober should end
+func (s *stubMCPServer) serveStreamableJSON(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodDelete {
+		s.mu.Lock()
+		s.endedSessions = append(s.endedSessions, r.Header.Get("Mcp-Session-Id"))
+		s.mu.Unlock()
+		return
+	}
+	if r.Header.Get("X-Api-Version") != "2" || r.Header.Get("X-Trace") != "" {
+		http.Error(w, "unexpected headers", http.StatusBadRequest)
+		return
+	}
+	id, ok := readInitialize(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Mcp-Session-Id", "session-1")
+	fmt.Fprint(w, initializeResponse(id, "2025-06-18"))
+}
+
+// serveStreamableEvents answers with an event stream carrying a notification before the response
+func (s *stubMCPServer) serveStreamableEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := readInitialize(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/message\",\"params\":{}}\n\n")
+	fmt.Fprintf(w, "data: %s\n\n", initializeResponse(id, "2025-03-26"))
+}
+
+// serveSSEStream announces the message endpoint and forwards the response to the initialize request
+func (s *stubMCPServer) serveSSEStream(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	fmt.Fprint(w, "event: endpoint\ndata: /messages?session=1\n\n")
+	w.(http.Flusher).Flush()
+
+	select {
+	case response := <-s.sseResponses:
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", response)
+	case <-r.Context().Done():
+	}
+}
+
+// readInitialize reads an initialize request and returns its ID. Notifications are accepted without a response.
+func readInitialize(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var request struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+	}
+	if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&request) != nil {
+		http.Error(w, "expected a JSON-RPC message", http.StatusBadRequest)
+		return "", false
+	}
+	if request.Method == "notifications/initialized" {
+		w.WriteHeader(http.StatusAccepted)
+		return "", false
+	}
+	if request.Method != "initialize" {
+		http.Error(w, "expected an initialize request", http.StatusBadRequest)
+		return "", false
+	}
+	return string(request.ID), true
+}
+
+func initializeResponse(id, protocolVersion string) string {
+	return fmt.Sprintf(`{"jsonrpc":"2.0","id":%s,"result":{"protocolVersion":%q,"capabilities":{},"serverInfo":{"name":"stub","version":"1.0.0"}}}`,
+		id, protocolVersion)
+}
+
+// stubTransport sends every request to the stub server, keeping the host of the remote URL
+type stubTransport struct {
+	target *url.URL
+}
+
+func (t stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = req.URL.Host
+	req.URL.Scheme, req.URL.Host = t.target.Scheme, t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestProber(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+	registry := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	stub := &stubMCPServer{sseResponses: make(chan string, 1)}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+	target, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	client := &http.Client{Transport: stubTransport{target: target}}
+
+	publish := func(name, version string, remotes ...model.Transport) {
+		t.Helper()
+		_, err := registry.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A remote server",
+			Version:     version,
+			Remotes:     remotes,
+		})
+		require.NoError(t, err)
+	}
+	publish("com.example/weather", "1.0.0", model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://old.weather.example.com/mcp"})
+	publish("com.example/weather", "1.1.0",
+		model.Transport{
+			Type: model.TransportTypeStreamableHTTP,
+			URL:  "https://{region}.weather.example.com/mcp",
+			Variables: map[string]model.Input{
+				"region": {Default: "eu", Choices: []string{"eu", "us"}},
+			},
+			Headers: []model.KeyValueInput{
+				{Name: "X-Api-Version", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "2"}}},
+				{Name: "X-Trace", InputWithVariables: model.InputWithVariables{Input: model.Input{Value: "{trace_id}"}}},
+			},
+		},
+		model.Transport{Type: model.TransportTypeSSE, URL: "https://weather.example.com/sse"},
+	)
+	publish("com.example/calendar", "1.0.0", model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://calendar.example.com/mcp"})
+	publish("com.example/broken", "1.0.0", model.Transport{Type: model.TransportTypeStreamableHTTP, URL: "https://broken.example.com/mcp"})
+	publish("com.example/private", "1.0.0",
+		model.Transport{
+			Type: model.TransportTypeStreamableHTTP,
+			URL:  "https://private.example.com/mcp",
+			Headers: []model.KeyValueInput{
+				{Name: "Authorization", InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true, IsSecret: true}}},
+			},
+		},
+		model.Transport{
+			Type:      model.TransportTypeStreamableHTTP,
+			URL:       "https://{tenant}.private.example.com/mcp",
+			Variables: map[string]model.Input{"tenant": {IsRequired: true}},
+		},
+	)
+
+	probes := func() map[string]apiv0.RemoteHealth {
+		t.Helper()
+		probes, err := db.ListRemoteProbes(ctx, nil, nil)
+		require.NoError(t, err)
+		byURL := make(map[string]apiv0.RemoteHealth, len(probes))
+		for _, probe := range probes {
+			byURL[probe.URL] = probe.RemoteHealth
+		}
+		return byURL
+	}
+
+	prober := remotehealth.NewProber(db, client, remotehealth.Options{Interval: time.Hour, Timeout: 5 * time.Second})
+
+	t.Run("remotes of latest active versions are probed", func(t *testing.T) {
+		probed, err := prober.ProbeDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 6, probed)
+
+		results := probes()
+		require.Len(t, results, 6)
+		assert.NotContains(t, results, "https://old.weather.example.com/mcp")
+
+		streamable := results["https://{region}.weather.example.com/mcp"]
+		assert.Equal(t, apiv0.RemoteStatusUp, streamable.Status, streamable.Error)
+		assert.Equal(t, "2025-06-18", streamable.ProtocolVersion)
+		assert.NotNil(t, streamable.LatencyMs)
+		assert.NotNil(t, streamable.LastSuccessAt)
+
+		sse := results["https://weather.example.com/sse"]
+		assert.Equal(t, apiv0.RemoteStatusUp, sse.Status, sse.Error)
+		assert.Equal(t, model.TransportTypeSSE, sse.TransportType)
+		assert.Equal(t, "2024-11-05", sse.ProtocolVersion)
+
+		calendar := results["https://calendar.example.com/mcp"]
+		assert.Equal(t, apiv0.RemoteStatusUp, calendar.Status, calendar.Error)
+		assert.Equal(t, "2025-03-26", calendar.ProtocolVersion)
+
+		broken := results["https://broken.example.com/mcp"]
+		assert.Equal(t, apiv0.RemoteStatusDown, broken.Status)
+		assert.Contains(t, broken.Error, "unsupported protocol version")
+		assert.Nil(t, broken.LastSuccessAt)
+
+		assert.Equal(t, apiv0.RemoteStatusSkipped, results["https://private.example.com/mcp"].Status)
+		assert.Equal(t, "header Authorization needs a value that was not published", results["https://private.example.com/mcp"].Error)
+		assert.Equal(t, apiv0.RemoteStatusSkipped, results["https://{tenant}.private.example.com/mcp"].Status)
+		assert.Contains(t, results["https://{tenant}.private.example.com/mcp"].Error, "variable tenant needs a value")
+
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		assert.Equal(t, []string{"session-1"}, stub.endedSessions)
+	})
+
+	t.Run("remotes probed within the interval are skipped", func(t *testing.T) {
+		probed, err := prober.ProbeDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, probed)
+	})
+
+	t.Run("remotes that go down keep their last success", func(t *testing.T) {
+		stub.mu.Lock()
+		stub.calendarDown = true
+		stub.mu.Unlock()
+
+		// A prober with a short interval finds every remote due again
+		prober := remotehealth.NewProber(db, client, remotehealth.Options{Interval: time.Nanosecond, Timeout: 5 * time.Second})
+		probed, err := prober.ProbeDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 6, probed)
+
+		calendar := probes()["https://calendar.example.com/mcp"]
+		assert.Equal(t, apiv0.RemoteStatusDown, calendar.Status)
+		assert.Equal(t, "initialize: unexpected status 503", calendar.Error)
+		assert.Equal(t, "2025-03-26", calendar.ProtocolVersion)
+		require.NotNil(t, calendar.LastSuccessAt)
+		assert.True(t, calendar.LastSuccessAt.Before(calendar.CheckedAt))
+	})
+
+	t.Run("list responses include the remote health of each version", func(t *testing.T) {
+		probing := service.NewRegistryService(db, &config.Config{EnableRemoteProbing: true})
+		servers, _, err := probing.ListServers(ctx, &database.ServerFilter{}, "", 30)
+		require.NoError(t, err)
+
+		health := map[string][]apiv0.RemoteHealth{}
+		for _, server := range servers {
+			health[server.Server.Name+"@"+server.Server.Version] = server.Meta.RemoteHealth
+		}
+		assert.Empty(t, health["com.example/weather@1.0.0"])
+		require.Len(t, health["com.example/weather@1.1.0"], 2)
+		assert.Equal(t, "https://weather.example.com/sse", health["com.example/weather@1.1.0"][0].URL)
+		require.Len(t, health["com.example/calendar@1.0.0"], 1)
+		assert.Equal(t, apiv0.RemoteStatusDown, health["com.example/calendar@1.0.0"][0].Status)
+
+		// Registries that do not probe remotes leave the metadata out
+		servers, _, err = registry.ListServers(ctx, &database.ServerFilter{}, "", 30)
+		require.NoError(t, err)
+		for _, server := range servers {
+			assert.Empty(t, server.Meta.RemoteHealth)
+		}
+	})
+}
+
+func TestProberDefaultClientRefusesInternalAddresses(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+
+	// A remote on loopback stands in for services on the registry's own network
+	server := httptest.NewServer(&stubMCPServer{})
+	defer server.Close()
+	remoteURL := server.URL + "/mcp"
+	_, err := db.CreateServer(ctx, nil, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/internal",
+		Description: "A remote server on an internal address",
+		Version:     "1.0.0",
+		Remotes:     []model.Transport{{Type: model.TransportTypeStreamableHTTP, URL: remoteURL}},
+	}, &apiv0.RegistryExtensions{Status: model.StatusActive, PublishedAt: time.Now(), UpdatedAt: time.Now(), IsLatest: true})
+	require.NoError(t, err)
+
+	prober := remotehealth.NewProber(db, nil, remotehealth.Options{Interval: time.Hour, Timeout: 5 * time.Second})
+	probed, err := prober.ProbeDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, probed)
+
+	probes, err := db.ListRemoteProbes(ctx, nil, nil)
+	require.NoError(t, err)
+	require.Len(t, probes, 1)
+	assert.Equal(t, apiv0.RemoteStatusDown, probes[0].Status)
+	assert.Contains(t, probes[0].Error, "non-public address")
+}
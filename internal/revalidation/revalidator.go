@@ -0,0 +1,284 @@
+// Package revalidation periodically re-checks the package registry ownership of published servers
+package revalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators"
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+const (
+	// pageSize is the number of servers listed per query
+	pageSize = 100
+	// maxDeprecationMessageLength matches the limit on deprecation messages set through the API
+	maxDeprecationMessageLength = 500
+)
+
+// actor is who automatic deprecations are attributed to in the audit log
+var actor = service.Actor{AuthMethod: service.SystemActorMethod, Subject: "package-revalidation"}
+
+// Options configures a Revalidator. Zero values are replaced with defaults, except DeprecateAfter.
+type Options struct {
+	// Interval is how often each package is checked
+	Interval time.Duration
+	// Concurrency is the number of servers checked at the same time
+	Concurrency int
+	// Timeout bounds the check of a single package
+	Timeout time.Duration
+	// DeprecateAfter is the number of consecutive failed checks of a package after which its version
+	// is deprecated. Inconclusive checks are not counted. Zero never deprecates.
+	DeprecateAfter int
+	// Validate checks that a package exists in its registry and belongs to the server. Errors matching
+	// registries.ErrRegistryUnavailable or context.DeadlineExceeded make the check inconclusive.
+	Validate func(ctx context.Context, pkg model.Package, serverName string) error
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = 24 * time.Hour
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 4
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 30 * time.Second
+	}
+	if o.Validate == nil {
+		o.Validate = validators.ValidatePackage
+	}
+	return o
+}
+
+// Revalidator re-runs the registry ownership checks of publishing for the packages of latest active
+// versions, recording the outcome of each check. Several revalidators can share a database: packages
+// checked recently by any of them are skipped.
+type Revalidator struct {
+	db       database.Database
+	registry service.RegistryService
+	opts     Options
+}
+
+// NewRevalidator creates a revalidator that deprecates versions through registry
+func NewRevalidator(db database.Database, registry service.RegistryService, opts Options) *Revalidator {
+	return &Revalidator{db: db, registry: registry, opts: opts.withDefaults()}
+}
+
+// Run revalidates packages that are due, at startup and then every tenth of the interval, until ctx is canceled
+func (r *Revalidator) Run(ctx context.Context) {
+	ticker := time.NewTicker(max(r.opts.Interval/10, time.Second))
+	defer ticker.Stop()
+
+	for {
+		if n, err := r.RevalidateDue(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to revalidate packages: %v", err)
+		} else if n > 0 {
+			log.Printf("Revalidated %d package(s)", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RevalidateDue checks the packages of latest active versions that have not been checked within the
+// interval, and forgets about packages that no longer belong to a latest active version. It returns
+// the number of packages checked.
+func (r *Revalidator) RevalidateDue(ctx context.Context) (int, error) {
+	start := time.Now()
+
+	previous, err := r.db.ListPackageValidations(ctx, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	checkedAt := make(map[packageKey]time.Time, len(previous))
+	for _, validation := range previous {
+		checkedAt[packageKey{validation.ServerName, validation.Version, validation.RegistryType, validation.Identifier}] = validation.CheckedAt
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		checked int
+		errs    []error
+	)
+	slots := make(chan struct{}, r.opts.Concurrency)
+
+	latest, active := true, string(model.StatusActive)
+	filter := &database.ServerFilter{IsLatest: &latest, Status: &active}
+	cursor := ""
+	for {
+		servers, next, err := r.db.ListServers(ctx, nil, filter, cursor, pageSize)
+		if err != nil {
+			wg.Wait()
+			return checked, err
+		}
+
+		for _, server := range servers {
+			due := r.duePackages(server, checkedAt, start)
+			if len(due) == 0 {
+				continue
+			}
+
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return checked, ctx.Err()
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() { <-slots }()
+				n, err := r.revalidateServer(ctx, server, due)
+				mu.Lock()
+				defer mu.Unlock()
+				checked += n
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}()
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return checked, fmt.Errorf("%d server(s) could not be revalidated, first error: %w", len(errs), errs[0])
+	}
+
+	// Every package of a latest active version was checked within the interval, so older results are
+	// for versions that are no longer latest or active
+	if err := r.db.DeletePackageValidationsCheckedBefore(ctx, nil, start.Add(-r.opts.Interval)); err != nil {
+		return checked, err
+	}
+
+	return checked, nil
+}
+
+// packageKey identifies a package of a server version, like the package_validations primary key
+type packageKey struct {
+	serverName   string
+	version      string
+	registryType string
+	identifier   string
+}
+
+// duePackages returns the packages of a server version that have not been checked within the interval.
+// A tenth of the interval is allowed as slack, so that packages are checked on the tick after they are due.
+func (r *Revalidator) duePackages(server *apiv0.ServerResponse, checkedAt map[packageKey]time.Time, now time.Time) []model.Package {
+	dueBefore := now.Add(-r.opts.Interval + r.opts.Interval/10)
+
+	var due []model.Package
+	seen := map[packageKey]bool{}
+	for _, pkg := range server.Server.Packages {
+		key := packageKey{server.Server.Name, server.Server.Version, pkg.RegistryType, pkg.Identifier}
+		if seen[key] || pkg.RegistryType == "" || pkg.Identifier == "" {
+			continue
+		}
+		seen[key] = true
+		if last, ok := checkedAt[key]; ok && last.After(dueBefore) {
+			continue
+		}
+		due = append(due, pkg)
+	}
+	return due
+}
+
+// revalidateServer checks packages of a server version one at a time, records the outcomes, and
+// deprecates the version if a package has failed too many checks in a row. Only definitive failures,
+// such as the package missing or naming another server, count; inconclusive checks leave the count as
+// it was. It returns the number of packages checked.
+func (r *Revalidator) revalidateServer(ctx context.Context, server *apiv0.ServerResponse, packages []model.Package) (int, error) {
+	var failing *apiv0.PackageValidation
+	for i, pkg := range packages {
+		validation := r.check(ctx, server.Server.Name, server.Server.Version, pkg)
+		if ctx.Err() != nil {
+			// Canceled checks say nothing about the package
+			return i, ctx.Err()
+		}
+		if err := r.db.RecordPackageValidation(ctx, nil, validation); err != nil {
+			return i, fmt.Errorf("failed to record validation of %s %s: %w", pkg.RegistryType, pkg.Identifier, err)
+		}
+		if validation.Inconclusive {
+			log.Printf("Package %s %s of %s %s could not be revalidated: %s",
+				pkg.RegistryType, pkg.Identifier, server.Server.Name, server.Server.Version, validation.Error)
+			continue
+		}
+		if !validation.Passed {
+			log.Printf("Package %s %s of %s %s failed revalidation (%d in a row): %s",
+				pkg.RegistryType, pkg.Identifier, server.Server.Name, server.Server.Version, validation.ConsecutiveFailures, validation.Error)
+			if r.opts.DeprecateAfter > 0 && validation.ConsecutiveFailures >= r.opts.DeprecateAfter && failing == nil {
+				failing = validation
+			}
+		}
+	}
+
+	if failing != nil {
+		if err := r.deprecate(ctx, failing); err != nil {
+			return len(packages), err
+		}
+	}
+	return len(packages), nil
+}
+
+// check runs the registry ownership check of one package
+func (r *Revalidator) check(ctx context.Context, serverName, version string, pkg model.Package) *apiv0.PackageValidation {
+	checkCtx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+	defer cancel()
+
+	err := r.opts.Validate(checkCtx, pkg, serverName)
+	validation := &apiv0.PackageValidation{
+		ServerName:     serverName,
+		Version:        version,
+		RegistryType:   pkg.RegistryType,
+		Identifier:     pkg.Identifier,
+		PackageVersion: pkg.Version,
+		Passed:         err == nil,
+		CheckedAt:      time.Now(),
+	}
+	if err != nil {
+		validation.Error = err.Error()
+		// The registry failing to answer says nothing about the package
+		validation.Inconclusive = errors.Is(err, registries.ErrRegistryUnavailable) || errors.Is(err, context.DeadlineExceeded)
+	}
+	return validation
+}
+
+// deprecate deprecates the version a failing package belongs to, explaining why to its users
+func (r *Revalidator) deprecate(ctx context.Context, failing *apiv0.PackageValidation) error {
+	message := fmt.Sprintf("Package %s %s failed registry ownership validation %d times in a row: %s",
+		failing.RegistryType, failing.Identifier, failing.ConsecutiveFailures, failing.Error)
+	if len(message) > maxDeprecationMessageLength {
+		message = strings.ToValidUTF8(message[:maxDeprecationMessageLength-3], "") + "..."
+	}
+
+	_, err := r.registry.UpdateServerStatus(service.WithActor(ctx, actor), failing.ServerName, failing.Version, &apiv0.StatusUpdateRequest{
+		Status:  model.StatusDeprecated,
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to deprecate %s %s: %w", failing.ServerName, failing.Version, err)
+	}
+
+	log.Printf("Deprecated %s %s after %d failed revalidations of package %s %s",
+		failing.ServerName, failing.Version, failing.ConsecutiveFailures, failing.RegistryType, failing.Identifier)
+	return nil
+}
@@ -0,0 +1,194 @@
+package revalidation_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/revalidation"
+	"github.com/modelcontextprotocol/registry/internal/service"
+	"github.com/modelcontextprotocol/registry/internal/validators/registries"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+var (
+	errPackageNotFound = errors.New("package not found")
+	errRegistryDown    = fmt.Errorf("%w: npm returned status 503", registries.ErrRegistryUnavailable)
+)
+
+// fakeRegistries answers ownership checks with the errors set for packages, passing the others
+type fakeRegistries struct {
+	mu       sync.Mutex
+	failures map[string]error
+	checks   int
+}
+
+func (f *fakeRegistries) validate(_ context.Context, pkg model.Package, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.checks++
+	return f.failures[pkg.Identifier]
+}
+
+func (f *fakeRegistries) setFailure(identifier string, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[identifier] = err
+}
+
+func TestRevalidator(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+	registry := service.NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	publish := func(name, version string, identifiers ...string) {
+		t.Helper()
+		server := &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A server with packages",
+			Version:     version,
+		}
+		for _, identifier := range identifiers {
+			server.Packages = append(server.Packages, model.Package{
+				RegistryType: model.RegistryTypeNPM,
+				Identifier:   identifier,
+				Version:      version,
+				Transport:    model.Transport{Type: model.TransportTypeStdio},
+			})
+		}
+		_, err := registry.CreateServer(ctx, server)
+		require.NoError(t, err)
+	}
+	publish("com.example/weather", "1.0.0", "@example/weather-old")
+	publish("com.example/weather", "1.1.0", "@example/weather", "@example/weather-cli")
+	publish("com.example/calendar", "1.0.0", "@example/calendar")
+	publish("com.example/remote", "1.0.0")
+
+	fake := &fakeRegistries{failures: map[string]error{"@example/calendar": errPackageNotFound}}
+	revalidator := revalidation.NewRevalidator(db, registry, revalidation.Options{
+		Interval:       time.Hour,
+		Concurrency:    2,
+		DeprecateAfter: 2,
+		Validate:       fake.validate,
+	})
+
+	failing := func() []*apiv0.PackageValidation {
+		validations, err := registry.ListPackageValidations(ctx, nil, true)
+		require.NoError(t, err)
+		return validations
+	}
+
+	t.Run("only packages of latest active versions are checked", func(t *testing.T) {
+		checked, err := revalidator.RevalidateDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, checked)
+
+		validations, err := registry.ListPackageValidations(ctx, nil, false)
+		require.NoError(t, err)
+		require.Len(t, validations, 3)
+		assert.Equal(t, "com.example/calendar", validations[0].ServerName)
+		assert.False(t, validations[0].Passed)
+		assert.Equal(t, "package not found", validations[0].Error)
+		assert.Equal(t, 1, validations[0].ConsecutiveFailures)
+		assert.Nil(t, validations[0].LastPassedAt)
+		assert.Equal(t, "1.1.0", validations[1].Version)
+		assert.True(t, validations[1].Passed)
+		assert.NotNil(t, validations[1].LastPassedAt)
+	})
+
+	t.Run("packages checked within the interval are skipped", func(t *testing.T) {
+		checked, err := revalidator.RevalidateDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, checked)
+		assert.Equal(t, 3, fake.checks)
+	})
+
+	t.Run("versions are deprecated after consecutive failures", func(t *testing.T) {
+		fake.setFailure("@example/weather-cli", errPackageNotFound)
+		// A revalidator with a short interval finds every package due again
+		revalidator := revalidation.NewRevalidator(db, registry, revalidation.Options{
+			Interval:       time.Nanosecond,
+			DeprecateAfter: 2,
+			Validate:       fake.validate,
+		})
+		checked, err := revalidator.RevalidateDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 3, checked)
+
+		calendar, err := registry.GetServerByName(ctx, "com.example/calendar")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusDeprecated, calendar.Meta.Official.Status)
+		assert.Contains(t, calendar.Meta.Official.DeprecationMessage, "npm @example/calendar failed registry ownership validation 2 times in a row")
+
+		weather, err := registry.GetServerByName(ctx, "com.example/weather")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, weather.Meta.Official.Status)
+
+		// The deprecation is attributed to the registry in the audit log
+		events, _, err := registry.ListAuditEvents(ctx, &database.AuditEventFilter{ServerName: stringPtr("com.example/calendar")}, "", 10)
+		require.NoError(t, err)
+		require.NotEmpty(t, events)
+		assert.Equal(t, "package-revalidation", events[0].ActorSubject)
+
+		validations := failing()
+		require.Len(t, validations, 2)
+		assert.Equal(t, "@example/weather-cli", validations[1].Identifier)
+		assert.Equal(t, 1, validations[1].ConsecutiveFailures)
+	})
+
+	t.Run("inconclusive checks do not count as failures", func(t *testing.T) {
+		revalidator := revalidation.NewRevalidator(db, registry, revalidation.Options{
+			Interval:       time.Nanosecond,
+			DeprecateAfter: 2,
+			Validate:       fake.validate,
+		})
+		for _, err := range []error{errRegistryDown, context.DeadlineExceeded} {
+			fake.setFailure("@example/weather-cli", err)
+			_, err := revalidator.RevalidateDue(ctx)
+			require.NoError(t, err)
+
+			// Only the weather packages are still checked
+			validations := failing()
+			require.Len(t, validations, 1)
+			assert.Equal(t, "@example/weather-cli", validations[0].Identifier)
+			assert.True(t, validations[0].Inconclusive)
+			assert.Equal(t, 1, validations[0].ConsecutiveFailures)
+		}
+
+		weather, err := registry.GetServerByName(ctx, "com.example/weather")
+		require.NoError(t, err)
+		assert.Equal(t, model.StatusActive, weather.Meta.Official.Status)
+	})
+
+	t.Run("a passing check resets the failure count and results of versions no longer checked are forgotten", func(t *testing.T) {
+		fake.setFailure("@example/weather-cli", nil)
+		revalidator := revalidation.NewRevalidator(db, registry, revalidation.Options{
+			Interval: time.Nanosecond,
+			Validate: fake.validate,
+		})
+		checked, err := revalidator.RevalidateDue(ctx)
+		require.NoError(t, err)
+		// The deprecated calendar server is no longer checked
+		assert.Equal(t, 2, checked)
+
+		assert.Empty(t, failing())
+		validations, err := registry.ListPackageValidations(ctx, stringPtr("com.example/weather"), false)
+		require.NoError(t, err)
+		require.Len(t, validations, 2)
+		assert.Zero(t, validations[1].ConsecutiveFailures)
+	})
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,70 @@
+package safehttp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/safehttp"
+)
+
+func TestControl(t *testing.T) {
+	for _, address := range []string{
+		"8.8.8.8:443",
+		"1.1.1.1:80",
+		"[2606:4700:4700::1111]:443",
+	} {
+		assert.NoError(t, safehttp.Control("tcp", address, nil), address)
+	}
+
+	for _, address := range []string{
+		"127.0.0.1:8080",
+		"[::1]:8080",
+		"169.254.169.254:80",
+		"[fe80::1]:80",
+		"10.0.0.5:443",
+		"172.16.3.4:443",
+		"192.168.1.1:443",
+		"[fd00::1]:443",
+		"100.64.0.1:443",
+		"0.0.0.0:80",
+		"[::]:80",
+		"[::ffff:127.0.0.1]:80",
+		"[::ffff:169.254.169.254]:80",
+		"[64:ff9b::a00:1]:80",
+		"224.0.0.1:80",
+		"255.255.255.255:80",
+		"not-an-address",
+	} {
+		assert.ErrorIs(t, safehttp.Control("tcp", address, nil), safehttp.ErrNonPublicAddress, address)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The test server listens on loopback, as an internal service would
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := safehttp.NewClient(0).Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	require.ErrorIs(t, err, safehttp.ErrNonPublicAddress)
+
+	// Host names are checked once resolved
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, "http://localhost:1/", nil)
+	require.NoError(t, err)
+	resp, err = safehttp.NewClient(0).Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	require.ErrorIs(t, err, safehttp.ErrNonPublicAddress)
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+)
+
+// SystemActorMethod identifies mutations made by the registry itself, such as seed imports
+const SystemActorMethod auth.Method = "system"
+
+// Actor identifies who performed a registry mutation
+type Actor struct {
+	AuthMethod auth.Method
+	Subject    string
+	Admin      bool // holds global edit permissions
+}
+
+type actorContextKey struct{}
+
+// ActorFromClaims builds the actor for an authenticated request
+func ActorFromClaims(claims *auth.JWTClaims) Actor {
+	actor := Actor{
+		AuthMethod: claims.AuthMethod,
+		Subject:    claims.AuthMethodSubject,
+	}
+	for _, perm := range claims.Permissions {
+		if perm.Action == auth.PermissionActionEdit && perm.ResourcePattern == "*" {
+			actor.Admin = true
+		}
+	}
+	return actor
+}
+
+// isPublisher reports whether the actor is a publisher rather than an admin or the registry itself.
+// Namespace blocks stop publishers from changing their servers, but not admins from taking them down.
+func (a Actor) isPublisher() bool {
+	return !a.Admin && a.AuthMethod != SystemActorMethod
+}
+
+// WithActor returns a context that attributes registry mutations to the given actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor attached to the context, or the system actor if there is none
+func ActorFromContext(ctx context.Context) Actor {
+	if actor, ok := ctx.Value(actorContextKey{}).(Actor); ok {
+		return actor
+	}
+	return Actor{AuthMethod: SystemActorMethod}
+}
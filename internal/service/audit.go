@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListAuditEvents returns audit log entries, newest first, with cursor-based pagination and optional filtering
+func (s *registryServiceImpl) ListAuditEvents(ctx context.Context, filter *database.AuditEventFilter, cursor string, limit int) ([]*apiv0.AuditEvent, string, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	return s.db.ListAuditEvents(ctx, nil, filter, cursor, limit)
+}
+
+// recordAuditEvent appends an audit event for a mutation of a server version within the
+// caller's transaction, so the event is only kept if the mutation commits.
+// before is nil for newly published versions.
+func (s *registryServiceImpl) recordAuditEvent(ctx context.Context, tx pgx.Tx, action string, before, after *apiv0.ServerResponse) error {
+	afterDoc, err := auditDocument(after)
+	if err != nil {
+		return err
+	}
+
+	var beforeDoc map[string]any
+	if before != nil {
+		if beforeDoc, err = auditDocument(before); err != nil {
+			return err
+		}
+		beforeDoc, afterDoc = diffAuditDocuments(beforeDoc, afterDoc)
+	}
+
+	actor := ActorFromContext(ctx)
+	event := &apiv0.AuditEvent{
+		Action:       action,
+		ServerName:   after.Server.Name,
+		Version:      after.Server.Version,
+		ActorMethod:  string(actor.AuthMethod),
+		ActorSubject: actor.Subject,
+		Before:       beforeDoc,
+		After:        afterDoc,
+	}
+
+	return s.db.CreateAuditEvent(ctx, tx, event)
+}
+
+// recordAuditChange appends an audit event for a mutation other than the publish, edit or status change of
+// a server version, within the caller's transaction. serverName and version are left empty when the mutation
+// does not concern them, and before or after is nil when the affected record did not exist before or after it.
+func (s *registryServiceImpl) recordAuditChange(ctx context.Context, tx pgx.Tx, action, serverName, version string, before, after map[string]any) error {
+	actor := ActorFromContext(ctx)
+	return s.db.CreateAuditEvent(ctx, tx, &apiv0.AuditEvent{
+		Action:       action,
+		ServerName:   serverName,
+		Version:      version,
+		ActorMethod:  string(actor.AuthMethod),
+		ActorSubject: actor.Subject,
+		Before:       before,
+		After:        after,
+	})
+}
+
+// auditDocument flattens a server into the document that audit diffs are computed over:
+// the top-level server.json fields plus the registry-managed status and deprecation details
+func auditDocument(server *apiv0.ServerResponse) (map[string]any, error) {
+	serverJSON, err := json.Marshal(server.Server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal server for audit: %w", err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(serverJSON, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal server for audit: %w", err)
+	}
+
+	if server.Meta.Official != nil {
+		doc["status"] = string(server.Meta.Official.Status)
+		if server.Meta.Official.DeprecationMessage != "" {
+			doc["deprecationMessage"] = server.Meta.Official.DeprecationMessage
+		}
+		if server.Meta.Official.ReplacedBy != "" {
+			doc["replacedBy"] = server.Meta.Official.ReplacedBy
+		}
+	}
+
+	return doc, nil
+}
+
+// diffAuditDocuments reduces two audit documents to the top-level fields that differ.
+// Fields missing from one side are omitted from that side of the diff.
+func diffAuditDocuments(before, after map[string]any) (map[string]any, map[string]any) {
+	beforeDiff := map[string]any{}
+	afterDiff := map[string]any{}
+
+	for key, beforeValue := range before {
+		afterValue, ok := after[key]
+		if !ok {
+			beforeDiff[key] = beforeValue
+			continue
+		}
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			beforeDiff[key] = beforeValue
+			afterDiff[key] = afterValue
+		}
+	}
+	for key, afterValue := range after {
+		if _, ok := before[key]; !ok {
+			afterDiff[key] = afterValue
+		}
+	}
+
+	return beforeDiff, afterDiff
+}
@@ -0,0 +1,121 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLog(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+	service := NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	publisherCtx := WithActor(ctx, ActorFromClaims(&auth.JWTClaims{
+		AuthMethod:        auth.MethodGitHubAT,
+		AuthMethodSubject: "testuser",
+	}))
+	adminCtx := WithActor(ctx, Actor{AuthMethod: auth.MethodOIDC, Subject: "admin@modelcontextprotocol.io"})
+
+	serverName := "io.github.testuser/audited-server"
+	server := &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Original description",
+		Version:     "1.0.0",
+	}
+
+	_, err := service.CreateServer(publisherCtx, server)
+	require.NoError(t, err)
+
+	edited := *server
+	edited.Description = "Edited description"
+	_, err = service.UpdateServer(publisherCtx, serverName, "1.0.0", &edited, nil)
+	require.NoError(t, err)
+
+	_, err = service.UpdateServer(adminCtx, serverName, "1.0.0", &edited, stringPtr(string(model.StatusDeleted)))
+	require.NoError(t, err)
+
+	// A failed mutation rolls back together with its audit event
+	_, err = service.CreateServer(publisherCtx, server)
+	require.ErrorIs(t, err, database.ErrInvalidVersion)
+
+	// Mutations without an authenticated actor, such as seed imports, are attributed to the system
+	_, err = service.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "io.github.testuser/seeded-server",
+		Description: "Seeded server",
+		Version:     "1.0.0",
+	})
+	require.NoError(t, err)
+
+	t.Run("records every committed mutation newest first", func(t *testing.T) {
+		events, _, err := service.ListAuditEvents(ctx, nil, "", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 4)
+
+		assert.Equal(t, database.AuditActionPublish, events[0].Action)
+		assert.Equal(t, string(SystemActorMethod), events[0].ActorMethod)
+		assert.Empty(t, events[0].ActorSubject)
+
+		statusChange := events[1]
+		assert.Equal(t, database.AuditActionStatusChange, statusChange.Action)
+		assert.Equal(t, string(auth.MethodOIDC), statusChange.ActorMethod)
+		assert.Equal(t, "admin@modelcontextprotocol.io", statusChange.ActorSubject)
+		assert.Equal(t, map[string]any{"status": "active"}, statusChange.Before)
+		assert.Equal(t, map[string]any{"status": "deleted"}, statusChange.After)
+
+		edit := events[2]
+		assert.Equal(t, database.AuditActionEdit, edit.Action)
+		assert.Equal(t, "testuser", edit.ActorSubject)
+		assert.Equal(t, map[string]any{"description": "Original description"}, edit.Before)
+		assert.Equal(t, map[string]any{"description": "Edited description"}, edit.After)
+
+		publish := events[3]
+		assert.Equal(t, database.AuditActionPublish, publish.Action)
+		assert.Equal(t, serverName, publish.ServerName)
+		assert.Equal(t, "1.0.0", publish.Version)
+		assert.Equal(t, string(auth.MethodGitHubAT), publish.ActorMethod)
+		assert.Nil(t, publish.Before)
+		assert.Equal(t, "Original description", publish.After["description"])
+		assert.Equal(t, "active", publish.After["status"])
+	})
+
+	t.Run("filters by server and action", func(t *testing.T) {
+		events, _, err := service.ListAuditEvents(ctx, &database.AuditEventFilter{
+			ServerName: stringPtr(serverName),
+			Action:     stringPtr(database.AuditActionPublish),
+		}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "testuser", events[0].ActorSubject)
+	})
+}
+
+func TestDiffAuditDocuments(t *testing.T) {
+	before := map[string]any{
+		"name":        "com.example/server",
+		"description": "Old",
+		"websiteUrl":  "https://example.com",
+		"remotes":     []any{map[string]any{"type": "sse", "url": "https://example.com/sse"}},
+	}
+	after := map[string]any{
+		"name":        "com.example/server",
+		"description": "New",
+		"title":       "Example",
+		"remotes":     []any{map[string]any{"type": "sse", "url": "https://example.com/sse"}},
+	}
+
+	beforeDiff, afterDiff := diffAuditDocuments(before, after)
+
+	assert.Equal(t, map[string]any{"description": "Old", "websiteUrl": "https://example.com"}, beforeDiff)
+	assert.Equal(t, map[string]any{"description": "New", "title": "Example"}, afterDiff)
+}
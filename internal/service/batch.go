@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// MaxBatchPublishSize bounds the number of servers published in one transaction
+const MaxBatchPublishSize = 100
+
+// Outcomes of a server in a batch publish
+const (
+	BatchStatusPublished    = "published"
+	BatchStatusFailed       = "failed"
+	BatchStatusNotPublished = "not_published"
+)
+
+// NewBatchPublishResponse returns a batch publish response with every server not published
+func NewBatchPublishResponse(reqs []*apiv0.ServerJSON) *apiv0.BatchPublishResponse {
+	response := &apiv0.BatchPublishResponse{Results: make([]apiv0.BatchPublishResult, len(reqs))}
+	for i, req := range reqs {
+		response.Results[i] = apiv0.BatchPublishResult{Name: req.Name, Version: req.Version, Status: BatchStatusNotPublished}
+	}
+	return response
+}
+
+// CreateServers publishes several server versions in a single transaction, so that either all of them
+// are published or none is. A server that cannot be published is reported in the response rather than
+// as an error.
+func (s *registryServiceImpl) CreateServers(ctx context.Context, reqs []*apiv0.ServerJSON) (*apiv0.BatchPublishResponse, error) {
+	if len(reqs) == 0 || len(reqs) > MaxBatchPublishSize {
+		return nil, fmt.Errorf("%w: a batch must contain between 1 and %d servers", database.ErrInvalidInput, MaxBatchPublishSize)
+	}
+
+	blocks, err := s.activeNamespaceBlocks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validation makes requests to package registries, so it runs once rather than on every retry of the
+	// transaction. Every server is validated, so that all invalid servers are reported at once.
+	response := NewBatchPublishResponse(reqs)
+	valid := true
+	for i, req := range reqs {
+		if err := s.validatePublishRequest(ctx, req, blocks, nil); err != nil {
+			if ctx.Err() != nil || !isServerError(err) {
+				return nil, err
+			}
+			response.Results[i].Status = BatchStatusFailed
+			response.Results[i].Error = err.Error()
+			valid = false
+		}
+	}
+	if !valid {
+		return response, nil
+	}
+
+	failed := -1
+	created, err := database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) ([]*apiv0.ServerResponse, error) {
+		// Start over when the transaction is retried
+		failed = -1
+		servers := make([]*apiv0.ServerResponse, 0, len(reqs))
+		for i, req := range reqs {
+			server, err := s.createServerInTransaction(ctx, tx, req, nil)
+			if err != nil {
+				failed = i
+				return nil, err
+			}
+			servers = append(servers, server)
+		}
+		return servers, nil
+	})
+
+	if err != nil {
+		// Errors outside of a server, such as failing to commit or to lock, are not reported per server
+		if failed < 0 || ctx.Err() != nil || !isServerError(err) {
+			return nil, err
+		}
+		response.Results[failed].Status = BatchStatusFailed
+		response.Results[failed].Error = err.Error()
+		return response, nil
+	}
+
+	response.Published = true
+	for i, server := range created {
+		response.Results[i].Status = BatchStatusPublished
+		response.Results[i].Server = server
+	}
+	return response, nil
+}
+
+// isServerError reports whether publishing failed because of the server being published, such as a failed
+// publish check or a server the database refused, rather than because of the database or the request context
+func isServerError(err error) bool {
+	var checkErr *publishCheckError
+	return errors.As(err, &checkErr) || errors.Is(err, database.ErrInvalidInput) || errors.Is(err, database.ErrAlreadyExists)
+}
@@ -0,0 +1,115 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateServers(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	newServer := func(name, version string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Server published in a batch",
+			Version:     version,
+		}
+	}
+
+	statuses := func(response *apiv0.BatchPublishResponse) []string {
+		result := make([]string, 0, len(response.Results))
+		for _, item := range response.Results {
+			result = append(result, item.Status)
+		}
+		return result
+	}
+
+	t.Run("every server is published", func(t *testing.T) {
+		response, err := service.CreateServers(ctx, []*apiv0.ServerJSON{
+			newServer("com.example/weather", "1.0.0"),
+			newServer("com.example/calendar", "2.0.0"),
+			newServer("com.example/weather", "1.1.0"),
+		})
+		require.NoError(t, err)
+
+		assert.True(t, response.Published)
+		assert.Equal(t, []string{BatchStatusPublished, BatchStatusPublished, BatchStatusPublished}, statuses(response))
+		require.NotNil(t, response.Results[1].Server)
+		assert.Equal(t, "com.example/calendar", response.Results[1].Server.Server.Name)
+
+		// Later versions in the batch see the earlier ones
+		latest, err := service.GetServerByName(ctx, "com.example/weather")
+		require.NoError(t, err)
+		assert.Equal(t, "1.1.0", latest.Server.Version)
+		versions, err := service.GetAllVersionsByServerName(ctx, "com.example/weather")
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+	})
+
+	t.Run("one failure publishes nothing", func(t *testing.T) {
+		response, err := service.CreateServers(ctx, []*apiv0.ServerJSON{
+			newServer("com.example/tasks", "1.0.0"),
+			newServer("com.example/weather", "1.0.0"),
+			newServer("com.example/notes", "1.0.0"),
+		})
+		require.NoError(t, err)
+
+		assert.False(t, response.Published)
+		assert.Equal(t, []string{BatchStatusNotPublished, BatchStatusFailed, BatchStatusNotPublished}, statuses(response))
+		assert.Contains(t, response.Results[1].Error, "cannot publish duplicate version")
+		assert.Nil(t, response.Results[0].Server)
+
+		_, err = service.GetServerByName(ctx, "com.example/tasks")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("duplicates within the batch fail", func(t *testing.T) {
+		response, err := service.CreateServers(ctx, []*apiv0.ServerJSON{
+			newServer("com.example/tasks", "1.0.0"),
+			newServer("com.example/tasks", "1.0.0"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{BatchStatusNotPublished, BatchStatusFailed}, statuses(response))
+
+		_, err = service.GetServerByName(ctx, "com.example/tasks")
+		require.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("every server failing validation is reported", func(t *testing.T) {
+		_, err := service.BlockNamespace(ctx, "com.spam", &apiv0.NamespaceBlockRequest{Reason: "Spam"})
+		require.NoError(t, err)
+
+		response, err := service.CreateServers(ctx, []*apiv0.ServerJSON{
+			newServer("com.spam/weather", "1.0.0"),
+			newServer("com.example/tasks", "1.0.0"),
+			newServer("com.spam.api/weather", "1.0.0"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{BatchStatusFailed, BatchStatusNotPublished, BatchStatusFailed}, statuses(response))
+		assert.Contains(t, response.Results[2].Error, "com.spam is blocked")
+	})
+
+	t.Run("database failures fail the batch", func(t *testing.T) {
+		unavailable := NewRegistryService(unavailableCountDatabase{database.NewMemory()}, &config.Config{EnableRegistryValidation: false})
+		response, err := unavailable.CreateServers(ctx, []*apiv0.ServerJSON{
+			newServer("com.example/tasks", "1.0.0"),
+		})
+		require.Error(t, err)
+		assert.Nil(t, response)
+	})
+
+	t.Run("empty batches are rejected", func(t *testing.T) {
+		_, err := service.CreateServers(ctx, nil)
+		require.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+}
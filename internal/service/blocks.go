@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// maxNamespaceLength matches the length of the namespace_blocks.namespace column
+const maxNamespaceLength = 255
+
+// BlockNamespace stops a namespace and its subdomains from getting publish tokens and publishing,
+// attributing the block to the actor in the context. Blocking a namespace again replaces its block.
+func (s *registryServiceImpl) BlockNamespace(ctx context.Context, namespace string, req *apiv0.NamespaceBlockRequest) (*apiv0.NamespaceBlock, error) {
+	if namespace == "" || len(namespace) > maxNamespaceLength || strings.ContainsAny(namespace, "/* \t\n") {
+		return nil, fmt.Errorf("%w: namespace must be a server name prefix such as io.github.user, without / or *", database.ErrInvalidInput)
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required to block a namespace", database.ErrInvalidInput)
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiresAt must be in the future", database.ErrInvalidInput)
+	}
+
+	actor := ActorFromContext(ctx)
+	block := &apiv0.NamespaceBlock{
+		Namespace: namespace,
+		Reason:    reason,
+		CreatedBy: fmt.Sprintf("%s:%s", actor.AuthMethod, actor.Subject),
+		ExpiresAt: req.ExpiresAt,
+	}
+	err := s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		previous, blocked, err := s.findNamespaceBlock(ctx, tx, namespace)
+		if err != nil {
+			return err
+		}
+		if err := s.db.SetNamespaceBlock(ctx, tx, block); err != nil {
+			return err
+		}
+
+		var before map[string]any
+		if blocked {
+			before = namespaceBlockAuditDocument(previous)
+		}
+		return s.recordAuditChange(ctx, tx, database.AuditActionNamespaceBlock, "", "", before, namespaceBlockAuditDocument(block))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// ListNamespaceBlocks returns the namespaces that are blocked, and those whose block expired if requested
+func (s *registryServiceImpl) ListNamespaceBlocks(ctx context.Context, includeExpired bool) ([]*apiv0.NamespaceBlock, error) {
+	return s.db.ListNamespaceBlocks(ctx, nil, includeExpired)
+}
+
+// UnblockNamespace lifts the block of a namespace
+func (s *registryServiceImpl) UnblockNamespace(ctx context.Context, namespace string) error {
+	return s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		previous, blocked, err := s.findNamespaceBlock(ctx, tx, namespace)
+		if err != nil {
+			return err
+		}
+		if !blocked {
+			return database.ErrNotFound
+		}
+		if err := s.db.DeleteNamespaceBlock(ctx, tx, namespace); err != nil {
+			return err
+		}
+		return s.recordAuditChange(ctx, tx, database.AuditActionNamespaceUnblock, "", "", namespaceBlockAuditDocument(previous), nil)
+	})
+}
+
+// findNamespaceBlock returns the block of a namespace, expired or not, and whether the namespace has one
+func (s *registryServiceImpl) findNamespaceBlock(ctx context.Context, tx pgx.Tx, namespace string) (*apiv0.NamespaceBlock, bool, error) {
+	blocks, err := s.db.ListNamespaceBlocks(ctx, tx, true)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, block := range blocks {
+		if block.Namespace == namespace {
+			return block, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// namespaceBlockAuditDocument describes a namespace block in the audit log
+func namespaceBlockAuditDocument(block *apiv0.NamespaceBlock) map[string]any {
+	doc := map[string]any{
+		"namespace": block.Namespace,
+		"reason":    block.Reason,
+		"createdBy": block.CreatedBy,
+	}
+	if block.ExpiresAt != nil {
+		doc["expiresAt"] = block.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	return doc
+}
+
+// BlockedNamespaces returns the namespaces that are currently blocked, so that the registry service can
+// serve as the blocklist for issuing tokens
+func (s *registryServiceImpl) BlockedNamespaces(ctx context.Context) ([]string, error) {
+	blocks, err := s.activeNamespaceBlocks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, len(blocks))
+	for i, block := range blocks {
+		namespaces[i] = block.Namespace
+	}
+	return namespaces, nil
+}
+
+// activeNamespaceBlocks returns the blocks in force. Outside a transaction they are read from the primary
+// in a transaction of their own, so that a block applies as soon as it is made rather than once replicas
+// catch up.
+func (s *registryServiceImpl) activeNamespaceBlocks(ctx context.Context, tx pgx.Tx) ([]*apiv0.NamespaceBlock, error) {
+	if tx != nil {
+		return s.db.ListNamespaceBlocks(ctx, tx, false)
+	}
+	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) ([]*apiv0.NamespaceBlock, error) {
+		return s.db.ListNamespaceBlocks(ctx, tx, false)
+	})
+}
+
+// checkNamespaceNotBlocked fails when the namespace of a server is blocked. Publishing, and changes of
+// publishers to their servers, check this in addition to token issuance, so that tokens issued before
+// the block stop working too.
+func (s *registryServiceImpl) checkNamespaceNotBlocked(ctx context.Context, tx pgx.Tx, serverName string) error {
+	blocks, err := s.activeNamespaceBlocks(ctx, tx)
+	if err != nil {
+		return err
+	}
+	return namespaceBlockedError(serverName, blocks)
+}
+
+// CheckNamespacesNotBlocked checks the namespaces of servers against the blocks in force, read once for all of them
+func (s *registryServiceImpl) CheckNamespacesNotBlocked(ctx context.Context, serverNames []string) ([]error, error) {
+	blocks, err := s.activeNamespaceBlocks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(serverNames))
+	for i, serverName := range serverNames {
+		errs[i] = namespaceBlockedError(serverName, blocks)
+	}
+	return errs, nil
+}
+
+// namespaceBlockedError returns an error matching database.ErrNamespaceBlocked when the namespace of a server
+// falls under one of blocks, and nil otherwise
+func namespaceBlockedError(serverName string, blocks []*apiv0.NamespaceBlock) error {
+	namespace, _, _ := strings.Cut(serverName, "/")
+	for _, block := range blocks {
+		if auth.IsNamespaceBlockedBy(namespace, block.Namespace) {
+			return fmt.Errorf("%w: %s is blocked (%s). Raise an issue at https://github.com/modelcontextprotocol/registry/ if you think this is a mistake",
+				database.ErrNamespaceBlocked, block.Namespace, block.Reason)
+		}
+	}
+
+	return nil
+}
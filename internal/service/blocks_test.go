@@ -0,0 +1,160 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespaceBlocks(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+	admin := WithActor(ctx, Actor{AuthMethod: auth.MethodOIDC, Subject: "admin@example.com"})
+
+	newServer := func(name string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A server",
+			Version:     "1.0.0",
+		}
+	}
+
+	block, err := service.BlockNamespace(admin, "com.evil-domain", &apiv0.NamespaceBlockRequest{Reason: " Publishing spam servers "})
+	require.NoError(t, err)
+	assert.Equal(t, "Publishing spam servers", block.Reason)
+	assert.Equal(t, "oidc:admin@example.com", block.CreatedBy)
+
+	t.Run("invalid blocks are rejected", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		for namespace, req := range map[string]apiv0.NamespaceBlockRequest{
+			"":                          {Reason: "Spam"},
+			"io.github.spammer/weather": {Reason: "Spam"},
+			"io.github.*":               {Reason: "Spam"},
+			"io.github.spammer":         {Reason: " "},
+			"io.github.spammer.old":     {Reason: "Spam", ExpiresAt: &past},
+		} {
+			_, err := service.BlockNamespace(admin, namespace, &req)
+			assert.ErrorIs(t, err, database.ErrInvalidInput, namespace)
+		}
+	})
+
+	t.Run("blocked namespaces and their subdomains cannot publish", func(t *testing.T) {
+		for _, name := range []string{"com.evil-domain/weather", "com.evil-domain.api/weather"} {
+			_, err := service.CreateServer(ctx, newServer(name))
+			require.ErrorIs(t, err, database.ErrNamespaceBlocked, name)
+			assert.Contains(t, err.Error(), "Publishing spam servers")
+		}
+
+		_, err := service.CreateServer(ctx, newServer("com.evil-domain-other/weather"))
+		require.NoError(t, err)
+	})
+
+	t.Run("publishers cannot change servers of a blocked namespace", func(t *testing.T) {
+		_, err := service.CreateServer(ctx, newServer("io.github.later/weather"))
+		require.NoError(t, err)
+		_, err = service.BlockNamespace(admin, "io.github.later", &apiv0.NamespaceBlockRequest{Reason: "Malware"})
+		require.NoError(t, err)
+
+		publisher := WithActor(ctx, ActorFromClaims(&auth.JWTClaims{
+			AuthMethod:        auth.MethodGitHubAT,
+			AuthMethodSubject: "later",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionPublish, ResourcePattern: "io.github.later/*"}},
+		}))
+		_, err = service.UpdateServer(publisher, "io.github.later/weather", "1.0.0", newServer("io.github.later/weather"), nil)
+		require.ErrorIs(t, err, database.ErrNamespaceBlocked)
+		_, err = service.UpdateServerStatus(publisher, "io.github.later/weather", "1.0.0", &apiv0.StatusUpdateRequest{Status: model.StatusDeprecated})
+		require.ErrorIs(t, err, database.ErrNamespaceBlocked)
+
+		// Admins can still take the servers down
+		takedown := WithActor(ctx, ActorFromClaims(&auth.JWTClaims{
+			AuthMethod:        auth.MethodOIDC,
+			AuthMethodSubject: "admin@example.com",
+			Permissions:       []auth.Permission{{Action: auth.PermissionActionEdit, ResourcePattern: "*"}},
+		}))
+		deleted := string(model.StatusDeleted)
+		_, err = service.UpdateServer(takedown, "io.github.later/weather", "1.0.0", newServer("io.github.later/weather"), &deleted)
+		require.NoError(t, err)
+	})
+
+	t.Run("dry runs report the block", func(t *testing.T) {
+		report, err := service.DryRunCreateServer(ctx, newServer("com.evil-domain/weather"))
+		require.NoError(t, err)
+		assert.False(t, report.WouldPublish)
+		require.Len(t, report.Checks, 1)
+		assert.Equal(t, "namespace_block", report.Checks[0].Name)
+		assert.False(t, report.Checks[0].Passed)
+	})
+
+	t.Run("namespaces of several servers are checked at once", func(t *testing.T) {
+		errs, err := service.CheckNamespacesNotBlocked(ctx, []string{"com.evil-domain.api/weather", "com.example/weather"})
+		require.NoError(t, err)
+		require.Len(t, errs, 2)
+		assert.ErrorIs(t, errs[0], database.ErrNamespaceBlocked)
+		assert.NoError(t, errs[1])
+	})
+
+	t.Run("expired blocks are not enforced", func(t *testing.T) {
+		soon := time.Now().Add(50 * time.Millisecond)
+		_, err := service.BlockNamespace(admin, "io.github.suspended", &apiv0.NamespaceBlockRequest{Reason: "Cooling off", ExpiresAt: &soon})
+		require.NoError(t, err)
+
+		namespaces, err := service.BlockedNamespaces(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"com.evil-domain", "io.github.later", "io.github.suspended"}, namespaces)
+
+		time.Sleep(100 * time.Millisecond)
+		namespaces, err = service.BlockedNamespaces(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"com.evil-domain", "io.github.later"}, namespaces)
+		_, err = service.CreateServer(ctx, newServer("io.github.suspended/weather"))
+		require.NoError(t, err)
+
+		blocks, err := service.ListNamespaceBlocks(ctx, true)
+		require.NoError(t, err)
+		assert.Len(t, blocks, 3)
+	})
+
+	t.Run("unblocked namespaces can publish again", func(t *testing.T) {
+		require.NoError(t, service.UnblockNamespace(ctx, "com.evil-domain"))
+		require.ErrorIs(t, service.UnblockNamespace(ctx, "com.evil-domain"), database.ErrNotFound)
+
+		_, err := service.CreateServer(ctx, newServer("com.evil-domain/weather"))
+		require.NoError(t, err)
+	})
+
+	t.Run("blocks and unblocks are audited", func(t *testing.T) {
+		_, err := service.BlockNamespace(admin, "io.github.spammer", &apiv0.NamespaceBlockRequest{Reason: "Spam"})
+		require.NoError(t, err)
+		_, err = service.BlockNamespace(admin, "io.github.spammer", &apiv0.NamespaceBlockRequest{Reason: "Repeated spam"})
+		require.NoError(t, err)
+		require.NoError(t, service.UnblockNamespace(admin, "io.github.spammer"))
+
+		subject := "admin@example.com"
+		events, _, err := service.ListAuditEvents(ctx, &database.AuditEventFilter{ActorSubject: &subject}, "", 3)
+		require.NoError(t, err)
+		require.Len(t, events, 3)
+
+		assert.Equal(t, database.AuditActionNamespaceUnblock, events[0].Action)
+		assert.Equal(t, "Repeated spam", events[0].Before["reason"])
+		assert.Nil(t, events[0].After)
+
+		assert.Equal(t, database.AuditActionNamespaceBlock, events[1].Action)
+		assert.Equal(t, "Spam", events[1].Before["reason"])
+		assert.Equal(t, "Repeated spam", events[1].After["reason"])
+
+		assert.Equal(t, database.AuditActionNamespaceBlock, events[2].Action)
+		assert.Nil(t, events[2].Before)
+		assert.Equal(t, "io.github.spammer", events[2].After["namespace"])
+		assert.Empty(t, events[2].ServerName)
+	})
+}
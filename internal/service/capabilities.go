@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ErrCapabilitiesNotRecorded is returned by GetServerCapabilities when the version exists but its capabilities
+// have not been read from a manifest or introspected
+var ErrCapabilitiesNotRecorded = fmt.Errorf("%w: capabilities have not been recorded for this version", database.ErrNotFound)
+
+// GetServerCapabilities retrieves the tools, prompts and resources recorded for a version of a server, given
+// by exact version or by a dist-tag pointing at it
+func (s *registryServiceImpl) GetServerCapabilities(ctx context.Context, serverName, versionOrTag string) (*apiv0.ServerCapabilities, error) {
+	server, err := s.GetServerByNameAndVersionOrTag(ctx, serverName, versionOrTag)
+	if err != nil {
+		return nil, err
+	}
+
+	capabilities, err := s.db.GetServerCapabilities(ctx, nil, serverName, server.Server.Version)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrCapabilitiesNotRecorded
+	}
+	return capabilities, err
+}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// ListChanges returns change feed events after the given sequence number, in sequence order
+func (s *registryServiceImpl) ListChanges(ctx context.Context, after int64, limit int) ([]*apiv0.ChangeEvent, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	return s.db.ListChangeEvents(ctx, nil, after, limit)
+}
+
+// recordChange appends a change feed event for a server version within the caller's transaction
+func (s *registryServiceImpl) recordChange(ctx context.Context, tx pgx.Tx, changeType, serverName, version string) error {
+	return s.db.CreateChangeEvent(ctx, tx, &apiv0.ChangeEvent{
+		Type:       changeType,
+		ServerName: serverName,
+		Version:    version,
+	})
+}
@@ -0,0 +1,72 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListChanges(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	serverName := "com.example/feed-server"
+	newServer := func(version string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Server in the change feed",
+			Version:     version,
+		}
+	}
+
+	_, err := service.CreateServer(ctx, newServer("1.0.0"))
+	require.NoError(t, err)
+	_, err = service.CreateServer(ctx, newServer("1.1.0"))
+	require.NoError(t, err)
+	// An older version does not take over latest, so no latest_change is recorded
+	_, err = service.CreateServer(ctx, newServer("0.9.0"))
+	require.NoError(t, err)
+	_, err = service.UpdateServer(ctx, serverName, "1.0.0", newServer("1.0.0"), stringPtr(string(model.StatusDeprecated)))
+	require.NoError(t, err)
+	_, err = service.UpdateServer(ctx, serverName, "1.1.0", newServer("1.1.0"), nil)
+	require.NoError(t, err)
+
+	// Failed mutations leave no trace in the feed
+	_, err = service.CreateServer(ctx, newServer("1.1.0"))
+	require.ErrorIs(t, err, database.ErrInvalidVersion)
+
+	changes, err := service.ListChanges(ctx, 0, 100)
+	require.NoError(t, err)
+
+	type change struct{ changeType, version string }
+	var got []change
+	for _, c := range changes {
+		got = append(got, change{c.Type, c.Version})
+	}
+	assert.Equal(t, []change{
+		{database.ChangeTypePublish, "1.0.0"},
+		{database.ChangeTypeLatestChange, "1.0.0"},
+		{database.ChangeTypePublish, "1.1.0"},
+		{database.ChangeTypePublish, "0.9.0"},
+		{database.ChangeTypeStatusChange, "1.0.0"},
+		{database.ChangeTypeEdit, "1.1.0"},
+	}, got)
+
+	// Each event carries the current state of the version
+	assert.False(t, changes[1].Server.Meta.Official.IsLatest)
+	assert.Equal(t, model.StatusDeprecated, changes[1].Server.Meta.Official.Status)
+
+	// Resuming from the last seen sequence only returns newer changes
+	resumed, err := service.ListChanges(ctx, changes[3].Sequence, 100)
+	require.NoError(t, err)
+	require.Len(t, resumed, 2)
+	assert.Equal(t, changes[4].Sequence, resumed[0].Sequence)
+}
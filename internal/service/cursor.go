@@ -0,0 +1,155 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+)
+
+// cursorFormatVersion is bumped whenever the meaning of cursorPayload changes, so that
+// cursors saved by clients are rejected cleanly rather than misinterpreted
+const cursorFormatVersion = 1
+
+// cursorPayload is the signed content of a pagination cursor returned to clients
+type cursorPayload struct {
+	Version    int    `json:"v"`
+	FilterHash string `json:"f"` // hash of the filters the cursor was issued for
+	Sort       string `json:"s"` // sort key and direction the cursor was issued for
+	Position   string `json:"p"` // database position of the last result on the page
+}
+
+// cursorCodec turns database positions into opaque, signed cursors and back
+type cursorCodec struct {
+	key []byte
+	// legacyUntil ends the deprecation window for the plain "serverName:version" cursors handed out before
+	// cursors were signed. Until then, clients that saved one can finish paginating listings in the default
+	// name order, after which such cursors are rejected as malformed.
+	legacyUntil time.Time
+}
+
+// newCursorCodec creates a codec keyed by the configured cursor secret, falling back to a
+// key derived from the JWT signing key so that replicas agree without extra configuration.
+// Without a cursor secret, rotating the JWT signing key invalidates every cursor handed out before.
+func newCursorCodec(cfg *config.Config) cursorCodec {
+	secret := cfg.CursorSecret
+	if secret == "" {
+		secret = "registry-cursor:" + cfg.JWTPrivateKey
+	}
+	key := sha256.Sum256([]byte(secret))
+	return cursorCodec{key: key[:], legacyUntil: cfg.CursorLegacyUntil}
+}
+
+// encode wraps a database position for the given filter. An empty position (no more pages) stays empty.
+func (c cursorCodec) encode(position string, filter *database.ServerFilter) string {
+	if position == "" {
+		return ""
+	}
+
+	// Marshalling a struct of strings cannot fail
+	payload, _ := json.Marshal(cursorPayload{
+		Version:    cursorFormatVersion,
+		FilterHash: hashServerFilter(filter),
+		Sort:       sortDescriptor(filter),
+		Position:   position,
+	})
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+}
+
+// decode verifies a cursor and returns the database position it carries.
+// Cursors that were modified, were issued for other filters or another sort order, or use an
+// unknown format are rejected with database.ErrInvalidInput.
+func (c cursorCodec) decode(cursor string, filter *database.ServerFilter) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+	// Legacy cursors always contain the / of the server name, which base64url never produces
+	if strings.Contains(cursor, "/") && time.Now().Before(c.legacyUntil) && acceptsLegacyCursor(filter) {
+		return decodeLegacyCursor(cursor), nil
+	}
+
+	encodedPayload, encodedSignature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return "", fmt.Errorf("%w: malformed cursor", database.ErrInvalidInput)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return "", fmt.Errorf("%w: malformed cursor", database.ErrInvalidInput)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || len(signature) != sha256.Size {
+		return "", fmt.Errorf("%w: malformed cursor", database.ErrInvalidInput)
+	}
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return "", fmt.Errorf("%w: cursor signature is invalid", database.ErrInvalidInput)
+	}
+
+	var decoded cursorPayload
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return "", fmt.Errorf("%w: malformed cursor", database.ErrInvalidInput)
+	}
+	if decoded.Version != cursorFormatVersion {
+		return "", fmt.Errorf("%w: cursor format is no longer supported, restart pagination without a cursor", database.ErrInvalidInput)
+	}
+	if decoded.FilterHash != hashServerFilter(filter) {
+		return "", fmt.Errorf("%w: cursor was issued for different filters", database.ErrInvalidInput)
+	}
+	if decoded.Sort != sortDescriptor(filter) {
+		return "", fmt.Errorf("%w: cursor was issued for a different sort order", database.ErrInvalidInput)
+	}
+
+	return decoded.Position, nil
+}
+
+// decodeLegacyCursor returns the database position of a legacy "serverName:version" cursor. Cursors
+// without a version, from before cursors carried one, are positioned after every version of the server.
+func decodeLegacyCursor(cursor string) string {
+	serverName, version, _ := strings.Cut(cursor, ":")
+	return database.NameListPosition(serverName, version)
+}
+
+// acceptsLegacyCursor reports whether a listing is in the name order that legacy cursors are positions in
+func acceptsLegacyCursor(filter *database.ServerFilter) bool {
+	if filter == nil {
+		return true
+	}
+	return filter.Query == nil &&
+		(filter.SortBy == "" || filter.SortBy == database.SortByName) &&
+		(filter.SortOrder == "" || filter.SortOrder == database.SortOrderAsc)
+}
+
+// sign computes the HMAC-SHA256 of a cursor payload
+func (c cursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// hashServerFilter summarizes the filtering (but not the ordering) of a listing
+func hashServerFilter(filter *database.ServerFilter) string {
+	var unsorted database.ServerFilter
+	if filter != nil {
+		unsorted = *filter
+	}
+	unsorted.SortBy, unsorted.SortOrder = "", ""
+
+	// Marshalling a struct of pointers to strings, bools and times cannot fail
+	data, _ := json.Marshal(unsorted)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:16])
+}
+
+// sortDescriptor describes the ordering of a listing as requested by the client
+func sortDescriptor(filter *database.ServerFilter) string {
+	if filter == nil {
+		return ":"
+	}
+	return filter.SortBy + ":" + filter.SortOrder
+}
@@ -0,0 +1,122 @@
+//nolint:testpackage
+package service
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorCodec(t *testing.T) {
+	codec := newCursorCodec(&config.Config{CursorSecret: "test-secret", CursorLegacyUntil: time.Now().Add(time.Hour)})
+	filter := &database.ServerFilter{
+		SubstringName: stringPtr("weather"),
+		SortBy:        database.SortByPublishedAt,
+		SortOrder:     database.SortOrderDesc,
+	}
+	position := "database-position"
+
+	cursor := codec.encode(position, filter)
+	require.NotEmpty(t, cursor)
+	assert.NotContains(t, cursor, position, "cursor should be opaque")
+
+	t.Run("round trip", func(t *testing.T) {
+		decoded, err := codec.decode(cursor, &database.ServerFilter{
+			SubstringName: stringPtr("weather"),
+			SortBy:        database.SortByPublishedAt,
+			SortOrder:     database.SortOrderDesc,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, position, decoded)
+	})
+
+	t.Run("empty cursors", func(t *testing.T) {
+		assert.Empty(t, codec.encode("", filter))
+
+		decoded, err := codec.decode("", filter)
+		require.NoError(t, err)
+		assert.Empty(t, decoded)
+	})
+
+	tamperedPayload, signature, _ := strings.Cut(cursor, ".")
+	payload, err := base64.RawURLEncoding.DecodeString(tamperedPayload)
+	require.NoError(t, err)
+	tamperedPayload = base64.RawURLEncoding.EncodeToString([]byte(strings.Replace(string(payload), position, "other-position", 1)))
+
+	tests := []struct {
+		name          string
+		codec         cursorCodec
+		cursor        string
+		filter        *database.ServerFilter
+		expectedError string
+	}{
+		{
+			name:          "different filters",
+			codec:         codec,
+			cursor:        cursor,
+			filter:        &database.ServerFilter{SubstringName: stringPtr("maps"), SortBy: database.SortByPublishedAt, SortOrder: database.SortOrderDesc},
+			expectedError: "cursor was issued for different filters",
+		},
+		{
+			name:          "different sort order",
+			codec:         codec,
+			cursor:        cursor,
+			filter:        &database.ServerFilter{SubstringName: stringPtr("weather"), SortBy: database.SortByPublishedAt},
+			expectedError: "cursor was issued for a different sort order",
+		},
+		{
+			name:          "modified position",
+			codec:         codec,
+			cursor:        tamperedPayload + "." + signature,
+			filter:        filter,
+			expectedError: "cursor signature is invalid",
+		},
+		{
+			name:          "signed with another secret",
+			codec:         newCursorCodec(&config.Config{CursorSecret: "other-secret"}),
+			cursor:        cursor,
+			filter:        filter,
+			expectedError: "cursor signature is invalid",
+		},
+		{
+			name:          "legacy serverName:version cursor",
+			codec:         codec,
+			cursor:        "com.example/server:1.0.0",
+			filter:        filter,
+			expectedError: "malformed cursor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.codec.decode(tt.cursor, tt.filter)
+			require.ErrorIs(t, err, database.ErrInvalidInput)
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+
+	t.Run("legacy cursors in the default order during the deprecation window", func(t *testing.T) {
+		decoded, err := codec.decode("com.example/server:1.0.0", nil)
+		require.NoError(t, err)
+		assert.Equal(t, database.NameListPosition("com.example/server", "1.0.0"), decoded)
+
+		decoded, err = codec.decode("com.example/server", &database.ServerFilter{SubstringName: stringPtr("server")})
+		require.NoError(t, err)
+		assert.Equal(t, database.NameListPosition("com.example/server", ""), decoded)
+
+		_, err = codec.decode("com.example/server:1.0.0", &database.ServerFilter{Query: stringPtr("server")})
+		require.ErrorIs(t, err, database.ErrInvalidInput)
+
+		expired := codec
+		expired.legacyUntil = time.Now().Add(-time.Hour)
+		_, err = expired.decode("com.example/server:1.0.0", nil)
+		require.ErrorIs(t, err, database.ErrInvalidInput)
+		assert.ErrorContains(t, err, "malformed cursor")
+	})
+}
@@ -0,0 +1,336 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// Kinds of change in a server diff
+const (
+	diffAdded   = "added"
+	diffRemoved = "removed"
+	diffChanged = "changed"
+)
+
+// DiffServerVersions compares two versions of a server. Either side may also be a dist-tag.
+func (s *registryServiceImpl) DiffServerVersions(ctx context.Context, serverName, from, to string) (*apiv0.ServerDiff, error) {
+	fromServer, err := s.GetServerByNameAndVersionOrTag(ctx, serverName, from)
+	if err != nil {
+		return nil, fmt.Errorf("version %s: %w", from, err)
+	}
+	toServer, err := s.GetServerByNameAndVersionOrTag(ctx, serverName, to)
+	if err != nil {
+		return nil, fmt.Errorf("version %s: %w", to, err)
+	}
+
+	diff := diffServers(&fromServer.Server, &toServer.Server)
+	return &diff, nil
+}
+
+// fieldChanges collects changed scalar fields
+type fieldChanges []apiv0.FieldChange
+
+// add records a field when its value differs between the two versions
+func (c *fieldChanges) add(field, before, after string) {
+	if before != after {
+		*c = append(*c, apiv0.FieldChange{Field: field, Before: before, After: after})
+	}
+}
+
+// diffServers compares two server.json documents field by field
+func diffServers(from, to *apiv0.ServerJSON) apiv0.ServerDiff {
+	diff := apiv0.ServerDiff{
+		ServerName: to.Name,
+		From:       from.Version,
+		To:         to.Version,
+	}
+
+	var fields fieldChanges
+	fields.add("$schema", from.Schema, to.Schema)
+	fields.add("description", from.Description, to.Description)
+	fields.add("title", from.Title, to.Title)
+	fields.add("websiteUrl", from.WebsiteURL, to.WebsiteURL)
+	fromRepository, toRepository := repositoryOrEmpty(from.Repository), repositoryOrEmpty(to.Repository)
+	fields.add("repository.url", fromRepository.URL, toRepository.URL)
+	fields.add("repository.source", fromRepository.Source, toRepository.Source)
+	fields.add("repository.id", fromRepository.ID, toRepository.ID)
+	fields.add("repository.subfolder", fromRepository.Subfolder, toRepository.Subfolder)
+	fields.add("icons", iconsText(from.Icons), iconsText(to.Icons))
+	fields.add("_meta", jsonText(from.Meta), jsonText(to.Meta))
+	diff.Fields = fields
+
+	// Endpoints are new when the older version did not use the URL anywhere
+	knownEndpoints := make(map[string]bool)
+	for _, pkg := range from.Packages {
+		knownEndpoints[pkg.Transport.URL] = true
+	}
+	for _, remote := range from.Remotes {
+		knownEndpoints[remote.URL] = true
+	}
+	for _, url := range endpointsOf(to) {
+		if url != "" && !knownEndpoints[url] {
+			knownEndpoints[url] = true
+			diff.Highlights.NewEndpoints = append(diff.Highlights.NewEndpoints, url)
+		}
+	}
+
+	diff.Packages = diffPackages(from.Packages, to.Packages, &diff.Highlights)
+	diff.Remotes = diffRemotes(from.Remotes, to.Remotes, &diff.Highlights)
+	return diff
+}
+
+// endpointsOf lists the package transport and remote URLs of a server, in document order
+func endpointsOf(server *apiv0.ServerJSON) []string {
+	var urls []string
+	for _, pkg := range server.Packages {
+		urls = append(urls, pkg.Transport.URL)
+	}
+	for _, remote := range server.Remotes {
+		urls = append(urls, remote.URL)
+	}
+	return urls
+}
+
+// diffPackages matches packages by registry type and identifier and compares each pair
+func diffPackages(from, to []model.Package, highlights *apiv0.DiffHighlights) []apiv0.PackageChange {
+	fromByKey := make(map[string]model.Package, len(from))
+	for _, pkg := range from {
+		fromByKey[packageKey(pkg)] = pkg
+	}
+
+	var changes []apiv0.PackageChange
+	seen := make(map[string]bool, len(to))
+	for _, pkg := range to {
+		key := packageKey(pkg)
+		seen[key] = true
+
+		before, existed := fromByKey[key]
+		change := apiv0.PackageChange{Change: diffChanged, RegistryType: pkg.RegistryType, Identifier: pkg.Identifier}
+		if !existed {
+			// Inputs of a new package are all reported as added
+			change.Change = diffAdded
+			highlights.NewPackages = append(highlights.NewPackages, key)
+		} else {
+			var fields fieldChanges
+			fields.add("version", before.Version, pkg.Version)
+			fields.add("registryBaseUrl", before.RegistryBaseURL, pkg.RegistryBaseURL)
+			fields.add("fileSha256", before.FileSHA256, pkg.FileSHA256)
+			fields.add("runtimeHint", before.RunTimeHint, pkg.RunTimeHint)
+			fields.add("transport.type", before.Transport.Type, pkg.Transport.Type)
+			fields.add("transport.url", before.Transport.URL, pkg.Transport.URL)
+			fields.add("transport.variables", variablesText(before.Transport.Variables), variablesText(pkg.Transport.Variables))
+			change.Fields = fields
+		}
+
+		change.EnvironmentVariables = diffInputs(keyValueInputs(before.EnvironmentVariables), keyValueInputs(pkg.EnvironmentVariables), key+" env ", highlights)
+		change.RuntimeArguments = diffInputs(argumentInputs(before.RuntimeArguments), argumentInputs(pkg.RuntimeArguments), key+" runtime argument ", highlights)
+		change.PackageArguments = diffInputs(argumentInputs(before.PackageArguments), argumentInputs(pkg.PackageArguments), key+" argument ", highlights)
+		change.Headers = diffInputs(keyValueInputs(before.Transport.Headers), keyValueInputs(pkg.Transport.Headers), key+" header ", highlights)
+
+		if change.Change == diffChanged && len(change.Fields) == 0 && len(change.EnvironmentVariables) == 0 &&
+			len(change.RuntimeArguments) == 0 && len(change.PackageArguments) == 0 && len(change.Headers) == 0 {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	for _, pkg := range from {
+		if !seen[packageKey(pkg)] {
+			changes = append(changes, apiv0.PackageChange{Change: diffRemoved, RegistryType: pkg.RegistryType, Identifier: pkg.Identifier})
+		}
+	}
+	return changes
+}
+
+// packageKey identifies a package across versions of a server
+func packageKey(pkg model.Package) string {
+	return pkg.RegistryType + ":" + pkg.Identifier
+}
+
+// diffRemotes matches remotes by URL and compares each pair
+func diffRemotes(from, to []model.Transport, highlights *apiv0.DiffHighlights) []apiv0.RemoteChange {
+	fromByURL := make(map[string]model.Transport, len(from))
+	for _, remote := range from {
+		fromByURL[remote.URL] = remote
+	}
+
+	var changes []apiv0.RemoteChange
+	seen := make(map[string]bool, len(to))
+	for _, remote := range to {
+		seen[remote.URL] = true
+
+		before, existed := fromByURL[remote.URL]
+		change := apiv0.RemoteChange{Change: diffChanged, URL: remote.URL}
+		if !existed {
+			change.Change = diffAdded
+		} else {
+			var fields fieldChanges
+			fields.add("type", before.Type, remote.Type)
+			fields.add("variables", variablesText(before.Variables), variablesText(remote.Variables))
+			change.Fields = fields
+		}
+		change.Headers = diffInputs(keyValueInputs(before.Headers), keyValueInputs(remote.Headers), remote.URL+" header ", highlights)
+
+		if change.Change == diffChanged && len(change.Fields) == 0 && len(change.Headers) == 0 {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	for _, remote := range from {
+		if !seen[remote.URL] {
+			changes = append(changes, apiv0.RemoteChange{Change: diffRemoved, URL: remote.URL})
+		}
+	}
+	return changes
+}
+
+// diffInput is an environment variable, argument or header flattened for comparison
+type diffInput struct {
+	name       string
+	isRequired bool
+	isSecret   bool
+	fields     [][2]string // field name and rendered value, in a fixed order
+}
+
+// newDiffInput flattens an input, followed by any fields specific to its kind
+func newDiffInput(name string, input model.InputWithVariables, extra ...[2]string) diffInput {
+	return diffInput{
+		name:       name,
+		isRequired: input.IsRequired,
+		isSecret:   input.IsSecret,
+		fields: append([][2]string{
+			{"description", input.Description},
+			{"format", string(input.Format)},
+			{"value", input.Value},
+			{"default", input.Default},
+			{"placeholder", input.Placeholder},
+			{"choices", strings.Join(input.Choices, ", ")},
+			{"isRequired", strconv.FormatBool(input.IsRequired)},
+			{"isSecret", strconv.FormatBool(input.IsSecret)},
+			{"variables", variablesText(input.Variables)},
+		}, extra...),
+	}
+}
+
+// keyValueInputs flattens environment variables or headers, which are identified by name
+func keyValueInputs(inputs []model.KeyValueInput) []diffInput {
+	result := make([]diffInput, 0, len(inputs))
+	for _, input := range inputs {
+		result = append(result, newDiffInput(input.Name, input.InputWithVariables))
+	}
+	return result
+}
+
+// argumentInputs flattens arguments, identified by flag name, then value hint, then position
+func argumentInputs(arguments []model.Argument) []diffInput {
+	result := make([]diffInput, 0, len(arguments))
+	for i, argument := range arguments {
+		name := argument.Name
+		if name == "" {
+			name = argument.ValueHint
+		}
+		if name == "" {
+			name = "#" + strconv.Itoa(i+1)
+		}
+		result = append(result, newDiffInput(name, argument.InputWithVariables,
+			[2]string{"type", string(argument.Type)},
+			[2]string{"isRepeated", strconv.FormatBool(argument.IsRepeated)},
+		))
+	}
+	return result
+}
+
+// diffInputs matches inputs by name and compares each pair, highlighting inputs that become secret
+// or required. label prefixes the input name in highlights.
+func diffInputs(from, to []diffInput, label string, highlights *apiv0.DiffHighlights) []apiv0.InputChange {
+	fromByName := make(map[string]diffInput, len(from))
+	for _, input := range from {
+		if _, ok := fromByName[input.name]; !ok {
+			fromByName[input.name] = input
+		}
+	}
+
+	var changes []apiv0.InputChange
+	seen := make(map[string]bool, len(to))
+	for _, input := range to {
+		if seen[input.name] {
+			continue
+		}
+		seen[input.name] = true
+
+		before, existed := fromByName[input.name]
+		if input.isSecret && !before.isSecret {
+			highlights.NewSecrets = append(highlights.NewSecrets, label+input.name)
+		}
+		if input.isRequired && !before.isRequired {
+			highlights.NewRequiredInputs = append(highlights.NewRequiredInputs, label+input.name)
+		}
+
+		change := apiv0.InputChange{Change: diffAdded, Name: input.name, IsRequired: input.isRequired, IsSecret: input.isSecret}
+		if existed {
+			var fields fieldChanges
+			for i, field := range input.fields {
+				fields.add(field[0], before.fields[i][1], field[1])
+			}
+			if len(fields) == 0 {
+				continue
+			}
+			change.Change = diffChanged
+			change.Fields = fields
+		}
+		changes = append(changes, change)
+	}
+
+	for _, input := range from {
+		if !seen[input.name] {
+			seen[input.name] = true
+			changes = append(changes, apiv0.InputChange{Change: diffRemoved, Name: input.name, IsRequired: input.isRequired, IsSecret: input.isSecret})
+		}
+	}
+	return changes
+}
+
+// repositoryOrEmpty returns the repository of a server, or an empty one when it has none
+func repositoryOrEmpty(repository *model.Repository) model.Repository {
+	if repository == nil {
+		return model.Repository{}
+	}
+	return *repository
+}
+
+// iconsText renders icons as their sources
+func iconsText(icons []model.Icon) string {
+	sources := make([]string, 0, len(icons))
+	for _, icon := range icons {
+		sources = append(sources, icon.Src)
+	}
+	return strings.Join(sources, ", ")
+}
+
+// variablesText renders URL or input variables as their sorted names
+func variablesText(variables map[string]model.Input) string {
+	names := make([]string, 0, len(variables))
+	for name := range variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// jsonText renders extension metadata as compact JSON, or "" when it is unset
+func jsonText(meta *apiv0.ServerMeta) string {
+	if meta == nil {
+		return ""
+	}
+	// ServerMeta only holds JSON-decoded values, so marshalling cannot fail
+	data, _ := json.Marshal(meta)
+	return string(data)
+}
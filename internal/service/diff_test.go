@@ -0,0 +1,173 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// diffTestServer returns a server with one npm package and one remote, which tests then modify
+func diffTestServer(version string) *apiv0.ServerJSON {
+	return &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/weather",
+		Description: "Weather forecasts",
+		Version:     version,
+		Repository:  &model.Repository{URL: "https://github.com/example/weather", Source: "github"},
+		Packages: []model.Package{{
+			RegistryType: "npm",
+			Identifier:   "@example/weather",
+			Version:      version,
+			Transport:    model.Transport{Type: "stdio"},
+			EnvironmentVariables: []model.KeyValueInput{
+				{Name: "WEATHER_REGION", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "eu"}}},
+			},
+			PackageArguments: []model.Argument{
+				{Type: model.ArgumentTypeNamed, Name: "--units", InputWithVariables: model.InputWithVariables{Input: model.Input{Default: "metric"}}},
+			},
+		}},
+		Remotes: []model.Transport{{
+			Type: "streamable-http",
+			URL:  "https://weather.example.com/mcp",
+		}},
+	}
+}
+
+func TestDiffServers(t *testing.T) {
+	t.Run("identical versions have no changes", func(t *testing.T) {
+		diff := diffServers(diffTestServer("1.0.0"), diffTestServer("1.0.0"))
+		assert.Empty(t, diff.Fields)
+		assert.Empty(t, diff.Packages)
+		assert.Empty(t, diff.Remotes)
+		assert.Equal(t, apiv0.DiffHighlights{}, diff.Highlights)
+	})
+
+	t.Run("package version bumps are reported as field changes", func(t *testing.T) {
+		diff := diffServers(diffTestServer("1.0.0"), diffTestServer("1.1.0"))
+		assert.Equal(t, "1.0.0", diff.From)
+		assert.Equal(t, "1.1.0", diff.To)
+		require.Len(t, diff.Packages, 1)
+		assert.Equal(t, diffChanged, diff.Packages[0].Change)
+		assert.Equal(t, []apiv0.FieldChange{{Field: "version", Before: "1.0.0", After: "1.1.0"}}, diff.Packages[0].Fields)
+		assert.Equal(t, apiv0.DiffHighlights{}, diff.Highlights)
+	})
+
+	t.Run("new secrets, required inputs and endpoints are highlighted", func(t *testing.T) {
+		from := diffTestServer("1.0.0")
+		to := diffTestServer("1.0.0")
+		to.Description = "Weather forecasts and alerts"
+		to.Packages[0].EnvironmentVariables = append(to.Packages[0].EnvironmentVariables, model.KeyValueInput{
+			Name:               "WEATHER_API_KEY",
+			InputWithVariables: model.InputWithVariables{Input: model.Input{IsRequired: true, IsSecret: true}},
+		})
+		to.Packages[0].PackageArguments[0].IsRequired = true
+		to.Remotes[0].Headers = []model.KeyValueInput{
+			{Name: "Authorization", InputWithVariables: model.InputWithVariables{Input: model.Input{IsSecret: true}}},
+		}
+		to.Remotes = append(to.Remotes, model.Transport{Type: "sse", URL: "https://alerts.example.net/sse"})
+		to.Packages = append(to.Packages, model.Package{
+			RegistryType: "pypi",
+			Identifier:   "example-weather",
+			Version:      "1.0.0",
+			Transport:    model.Transport{Type: "streamable-http", URL: "http://localhost:8080/mcp"},
+		})
+
+		diff := diffServers(from, to)
+
+		assert.Equal(t, []apiv0.FieldChange{{Field: "description", Before: "Weather forecasts", After: "Weather forecasts and alerts"}}, diff.Fields)
+		assert.Equal(t, apiv0.DiffHighlights{
+			NewPackages:  []string{"pypi:example-weather"},
+			NewEndpoints: []string{"http://localhost:8080/mcp", "https://alerts.example.net/sse"},
+			NewSecrets: []string{
+				"npm:@example/weather env WEATHER_API_KEY",
+				"https://weather.example.com/mcp header Authorization",
+			},
+			NewRequiredInputs: []string{
+				"npm:@example/weather env WEATHER_API_KEY",
+				"npm:@example/weather argument --units",
+			},
+		}, diff.Highlights)
+
+		require.Len(t, diff.Packages, 2)
+		npm := diff.Packages[0]
+		assert.Equal(t, diffChanged, npm.Change)
+		assert.Empty(t, npm.Fields)
+		assert.Equal(t, []apiv0.InputChange{{Change: diffAdded, Name: "WEATHER_API_KEY", IsRequired: true, IsSecret: true}}, npm.EnvironmentVariables)
+		assert.Equal(t, []apiv0.InputChange{{
+			Change:     diffChanged,
+			Name:       "--units",
+			IsRequired: true,
+			Fields:     []apiv0.FieldChange{{Field: "isRequired", Before: "false", After: "true"}},
+		}}, npm.PackageArguments)
+		assert.Equal(t, diffAdded, diff.Packages[1].Change)
+
+		require.Len(t, diff.Remotes, 2)
+		assert.Equal(t, diffChanged, diff.Remotes[0].Change)
+		assert.Equal(t, []apiv0.InputChange{{Change: diffAdded, Name: "Authorization", IsSecret: true}}, diff.Remotes[0].Headers)
+		assert.Equal(t, apiv0.RemoteChange{Change: diffAdded, URL: "https://alerts.example.net/sse"}, diff.Remotes[1])
+	})
+
+	t.Run("removals are listed without highlights", func(t *testing.T) {
+		from := diffTestServer("1.0.0")
+		from.Packages[0].EnvironmentVariables[0].IsSecret = true
+		to := diffTestServer("1.0.0")
+		to.Packages[0].EnvironmentVariables = nil
+		to.Remotes = nil
+		to.Repository = nil
+
+		diff := diffServers(from, to)
+
+		assert.Equal(t, []apiv0.FieldChange{
+			{Field: "repository.url", Before: "https://github.com/example/weather"},
+			{Field: "repository.source", Before: "github"},
+		}, diff.Fields)
+		require.Len(t, diff.Packages, 1)
+		assert.Equal(t, []apiv0.InputChange{{Change: diffRemoved, Name: "WEATHER_REGION", IsSecret: true}}, diff.Packages[0].EnvironmentVariables)
+		assert.Equal(t, []apiv0.RemoteChange{{Change: diffRemoved, URL: "https://weather.example.com/mcp"}}, diff.Remotes)
+		assert.Equal(t, apiv0.DiffHighlights{}, diff.Highlights)
+	})
+
+	t.Run("a remote moving to a new URL is a new endpoint", func(t *testing.T) {
+		from := diffTestServer("1.0.0")
+		to := diffTestServer("1.0.0")
+		to.Remotes[0].URL = "https://weather.example.org/mcp"
+
+		diff := diffServers(from, to)
+
+		assert.Equal(t, []string{"https://weather.example.org/mcp"}, diff.Highlights.NewEndpoints)
+		require.Len(t, diff.Remotes, 2)
+		assert.Equal(t, diffAdded, diff.Remotes[0].Change)
+		assert.Equal(t, diffRemoved, diff.Remotes[1].Change)
+	})
+}
+
+func TestDiffServerVersions(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	_, err := service.CreateServer(ctx, diffTestServer("1.0.0"))
+	require.NoError(t, err)
+	_, err = service.CreateServer(ctx, diffTestServer("2.0.0"))
+	require.NoError(t, err)
+
+	t.Run("versions can be given as tags", func(t *testing.T) {
+		diff, err := service.DiffServerVersions(ctx, "com.example/weather", "1.0.0", database.TagLatest)
+		require.NoError(t, err)
+		assert.Equal(t, "com.example/weather", diff.ServerName)
+		assert.Equal(t, "2.0.0", diff.To)
+		require.Len(t, diff.Packages, 1)
+	})
+
+	t.Run("unknown versions are not found", func(t *testing.T) {
+		_, err := service.DiffServerVersions(ctx, "com.example/weather", "3.0.0", "1.0.0")
+		require.ErrorIs(t, err, database.ErrNotFound)
+		assert.Contains(t, err.Error(), "3.0.0")
+	})
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// Checks run by validatePublishRequest and createServerInTransaction, in order. Publishing fails with the
+// first check that does not pass.
+const (
+	publishCheckNamespaceBlock   = "namespace_block"
+	publishCheckValidation       = "publish_validation"
+	publishCheckRemoteURLs       = "remote_urls"
+	publishCheckVersionLimit     = "version_limit"
+	publishCheckDuplicateVersion = "duplicate_version"
+	// publishCheckPublish covers writing the new version once every check has passed
+	publishCheckPublish = "publish"
+)
+
+// errDryRunRollback ends a dry-run transaction that succeeded, so that nothing it wrote is committed
+var errDryRunRollback = errors.New("dry run: rolling back")
+
+// publishCheckError is the failure of a publish check, which dry runs report as that check failing.
+// Other errors of a publish, such as database failures, are not the outcome of a check.
+type publishCheckError struct {
+	check string
+	err   error
+}
+
+func (e *publishCheckError) Error() string {
+	return e.err.Error()
+}
+
+func (e *publishCheckError) Unwrap() error {
+	return e.err
+}
+
+// failCheck attributes an error to the publish check that failed
+func failCheck(check string, err error) error {
+	return &publishCheckError{check: check, err: err}
+}
+
+// publishReport records the checks a dry-run publish passes. Its methods do nothing on a nil report,
+// which is what a real publish passes.
+type publishReport struct {
+	checks        []apiv0.PublishCheck
+	currentLatest string
+}
+
+// pass records a passed check
+func (r *publishReport) pass(name, message string) {
+	if r == nil {
+		return
+	}
+	r.checks = append(r.checks, apiv0.PublishCheck{Name: name, Passed: true, Message: message})
+}
+
+// setCurrentLatest records the latest version before the publish
+func (r *publishReport) setCurrentLatest(version string) {
+	if r == nil {
+		return
+	}
+	r.currentLatest = version
+}
+
+// DryRunCreateServer runs every check and write of CreateServer, then rolls the transaction back and
+// reports what publishing would have done. Failed checks are part of the report, not errors; other
+// failures, such as of the database, are returned as errors.
+func (s *registryServiceImpl) DryRunCreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.PublishDryRunResponse, error) {
+	blocks, err := s.activeNamespaceBlocks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &publishReport{}
+	var created *apiv0.ServerResponse
+	err = s.validatePublishRequest(ctx, req, blocks, report)
+	if err == nil {
+		validated := len(report.checks)
+		_, err = database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+			// Start over from the checks made before the transaction when it is retried
+			report.checks, report.currentLatest = report.checks[:validated], ""
+			var err error
+			created, err = s.createServerInTransaction(ctx, tx, req, report)
+			if err != nil {
+				return nil, err
+			}
+			return nil, errDryRunRollback
+		})
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	response := &apiv0.PublishDryRunResponse{
+		ServerName:    req.Name,
+		Version:       req.Version,
+		CurrentLatest: report.currentLatest,
+		Checks:        report.checks,
+	}
+
+	if !errors.Is(err, errDryRunRollback) {
+		var checkErr *publishCheckError
+		if !errors.As(err, &checkErr) {
+			return nil, err
+		}
+		response.Checks = append(response.Checks, apiv0.PublishCheck{Name: checkErr.check, Message: checkErr.Error()})
+		return response, nil
+	}
+
+	response.Checks = append(response.Checks, apiv0.PublishCheck{Name: publishCheckPublish, Passed: true, Message: "The version would be published"})
+	response.WouldPublish = true
+	response.WouldBecomeLatest = created.Meta.Official != nil && created.Meta.Official.IsLatest
+	response.Server = created
+	return response, nil
+}
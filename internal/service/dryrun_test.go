@@ -0,0 +1,119 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableCountDatabase fails to count server versions, like a database that went away mid-publish
+type unavailableCountDatabase struct {
+	database.Database
+}
+
+func (unavailableCountDatabase) CountServerVersions(_ context.Context, _ pgx.Tx, _ string) (int, error) {
+	return 0, errors.New("connection reset by peer")
+}
+
+func TestDryRunCreateServer(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	newServer := func(name, version, remoteURL string) *apiv0.ServerJSON {
+		server := &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "Server checked before publishing",
+			Version:     version,
+		}
+		if remoteURL != "" {
+			server.Remotes = []model.Transport{{Type: "streamable-http", URL: remoteURL}}
+		}
+		return server
+	}
+
+	_, err := service.CreateServer(ctx, newServer("com.example/weather", "1.0.0", "https://weather.example.com/mcp"))
+	require.NoError(t, err)
+
+	checkNames := func(report *apiv0.PublishDryRunResponse) []string {
+		names := make([]string, 0, len(report.Checks))
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+		}
+		return names
+	}
+
+	t.Run("a new version would publish and become latest", func(t *testing.T) {
+		report, err := service.DryRunCreateServer(ctx, newServer("com.example/weather", "1.1.0", "https://weather.example.com/mcp"))
+		require.NoError(t, err)
+
+		assert.True(t, report.WouldPublish)
+		assert.True(t, report.WouldBecomeLatest)
+		assert.Equal(t, "1.0.0", report.CurrentLatest)
+		assert.Equal(t, []string{"namespace_block", "publish_validation", "remote_urls", "version_limit", "duplicate_version", "publish"}, checkNames(report))
+		for _, check := range report.Checks {
+			assert.True(t, check.Passed, check.Name)
+		}
+		assert.Equal(t, "1 of 10000 versions used", report.Checks[3].Message)
+		require.NotNil(t, report.Server)
+		assert.Equal(t, "1.1.0", report.Server.Server.Version)
+
+		// Nothing was written
+		_, err = service.GetServerByNameAndVersion(ctx, "com.example/weather", "1.1.0")
+		require.ErrorIs(t, err, database.ErrNotFound)
+		latest, err := service.GetServerByName(ctx, "com.example/weather")
+		require.NoError(t, err)
+		assert.Equal(t, "1.0.0", latest.Server.Version)
+		changes, err := service.ListChanges(ctx, 0, 100)
+		require.NoError(t, err)
+		assert.Len(t, changes, 1)
+		events, _, err := service.ListAuditEvents(ctx, nil, "", 100)
+		require.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("a prerelease would publish without becoming latest", func(t *testing.T) {
+		report, err := service.DryRunCreateServer(ctx, newServer("com.example/weather", "2.0.0-beta.1", ""))
+		require.NoError(t, err)
+		assert.True(t, report.WouldPublish)
+		assert.False(t, report.WouldBecomeLatest)
+	})
+
+	t.Run("a duplicate version fails its check", func(t *testing.T) {
+		report, err := service.DryRunCreateServer(ctx, newServer("com.example/weather", "1.0.0", ""))
+		require.NoError(t, err)
+
+		assert.False(t, report.WouldPublish)
+		assert.Nil(t, report.Server)
+		assert.Equal(t, []string{"namespace_block", "publish_validation", "remote_urls", "version_limit", "duplicate_version"}, checkNames(report))
+		failed := report.Checks[len(report.Checks)-1]
+		assert.False(t, failed.Passed)
+		assert.Contains(t, failed.Message, "cannot publish duplicate version")
+	})
+
+	t.Run("a remote URL used by another server fails its check", func(t *testing.T) {
+		report, err := service.DryRunCreateServer(ctx, newServer("com.example/other", "1.0.0", "https://weather.example.com/mcp"))
+		require.NoError(t, err)
+
+		assert.False(t, report.WouldPublish)
+		assert.Empty(t, report.CurrentLatest)
+		assert.Equal(t, []string{"namespace_block", "publish_validation", "remote_urls"}, checkNames(report))
+		assert.Contains(t, report.Checks[2].Message, "is already used by server com.example/weather")
+	})
+
+	t.Run("failures other than checks are errors", func(t *testing.T) {
+		unavailable := NewRegistryService(unavailableCountDatabase{database.NewMemory()}, &config.Config{EnableRegistryValidation: false})
+		_, err := unavailable.DryRunCreateServer(ctx, newServer("com.example/weather", "1.0.0", ""))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "connection reset by peer")
+	})
+}
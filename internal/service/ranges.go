@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// ErrNoMatchingVersion is returned by ResolveVersionRange when the server exists but none of its
+// active versions satisfies the range
+var ErrNoMatchingVersion = fmt.Errorf("%w: no active version satisfies the range", database.ErrNotFound)
+
+// maxVersionRangeLength bounds the range expressions accepted from clients
+const maxVersionRangeLength = 256
+
+// ResolveVersionRange returns the highest active version of a server that satisfies a semver range
+func (s *registryServiceImpl) ResolveVersionRange(ctx context.Context, serverName, rangeExpr string) (*apiv0.ServerResponse, error) {
+	versionRange, err := parseVersionRange(rangeExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, err := s.db.GetAllVersionsByServerName(ctx, nil, serverName)
+	if err != nil {
+		return nil, err
+	}
+
+	var best *apiv0.ServerResponse
+	for _, candidate := range versions {
+		if candidate.Meta.Official == nil || candidate.Meta.Official.Status != model.StatusActive {
+			continue
+		}
+		if !versionRange.satisfiedBy(candidate.Server.Version) {
+			continue
+		}
+		if best == nil {
+			best = candidate
+			continue
+		}
+		// Versions differing only in build metadata are equal, so prefer the one published last
+		c := compareSemanticVersions(candidate.Server.Version, best.Server.Version)
+		if c > 0 || (c == 0 && candidate.Meta.Official.PublishedAt.After(best.Meta.Official.PublishedAt)) {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return nil, ErrNoMatchingVersion
+	}
+
+	return best, nil
+}
+
+// versionComparator is a single constraint such as >=1.2.0 on a full semantic version
+type versionComparator struct {
+	op      string // one of <, <=, >, >=, =
+	version string
+}
+
+// versionRange is a parsed semver range in the npm syntax: a version satisfies it when it
+// satisfies every comparator of at least one comparator set. An empty set matches any release.
+type versionRange [][]versionComparator
+
+// partialVersion is a version in a range expression, where trailing parts may be missing or
+// wildcards (1, 1.2, 1.x, *). specified counts the leading numeric parts that were given.
+type partialVersion struct {
+	major, minor, patch int
+	specified           int
+	prerelease          string
+}
+
+var (
+	// operatorSpacePattern matches whitespace between an operator and its version, which npm allows
+	operatorSpacePattern = regexp.MustCompile(`(<=|>=|<|>|=|~|\^)\s+`)
+	// comparatorPattern splits a comparator into operator and partial version
+	comparatorPattern = regexp.MustCompile(`^(<=|>=|<|>|=|~>|~|\^)?(.*)$`)
+)
+
+// parseVersionRange parses a range such as ^1.2.0, ~1.2, >=1.0.0 <2.0.0, 1.2.x, 1.0.0 - 1.4 or
+// several of them joined by ||. Invalid ranges are rejected with database.ErrInvalidInput.
+func parseVersionRange(expr string) (versionRange, error) {
+	if len(expr) > maxVersionRangeLength {
+		return nil, fmt.Errorf("%w: version range is longer than %d characters", database.ErrInvalidInput, maxVersionRangeLength)
+	}
+
+	var result versionRange
+	for _, alternative := range strings.Split(expr, "||") {
+		set, err := parseComparatorSet(strings.TrimSpace(alternative))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid version range %q: %w", database.ErrInvalidInput, expr, err)
+		}
+		result = append(result, set)
+	}
+	return result, nil
+}
+
+// parseComparatorSet parses one alternative of a range into the comparators it stands for
+func parseComparatorSet(expr string) ([]versionComparator, error) {
+	// Hyphen ranges: 1.2.3 - 2.3.4
+	if lower, upper, ok := strings.Cut(expr, " - "); ok {
+		from, err := parsePartialVersion(strings.TrimSpace(lower))
+		if err != nil {
+			return nil, err
+		}
+		to, err := parsePartialVersion(strings.TrimSpace(upper))
+		if err != nil {
+			return nil, err
+		}
+		return append(expandComparator(">=", from), expandComparator("<=", to)...), nil
+	}
+
+	set := []versionComparator{}
+	for _, token := range strings.Fields(operatorSpacePattern.ReplaceAllString(expr, "$1")) {
+		match := comparatorPattern.FindStringSubmatch(token)
+		partial, err := parsePartialVersion(match[2])
+		if err != nil {
+			return nil, err
+		}
+
+		switch match[1] {
+		case "~", "~>":
+			set = append(set, expandTilde(partial)...)
+		case "^":
+			set = append(set, expandCaret(partial)...)
+		default:
+			set = append(set, expandComparator(match[1], partial)...)
+		}
+	}
+	return set, nil
+}
+
+// parsePartialVersion parses a possibly incomplete version such as 1, 1.2, 1.2.x or 1.2.3-beta.1
+func parsePartialVersion(s string) (partialVersion, error) {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "="), "v")
+	core, _, _ := strings.Cut(s, "+")
+	core, prerelease, hasPrerelease := strings.Cut(core, "-")
+
+	var p partialVersion
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return p, fmt.Errorf("%q has more than three parts", s)
+	}
+
+	values := []*int{&p.major, &p.minor, &p.patch}
+	wildcard := false
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			wildcard = true
+			continue
+		}
+		if wildcard {
+			return p, fmt.Errorf("%q has a number after a wildcard", s)
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (len(part) > 1 && part[0] == '0') {
+			return p, fmt.Errorf("%q is not a valid version", s)
+		}
+		*values[i] = n
+		p.specified++
+	}
+
+	if hasPrerelease {
+		if p.specified != 3 || !IsSemanticVersion(p.String()+"-"+prerelease) {
+			return p, fmt.Errorf("%q is not a valid prerelease version", s)
+		}
+		p.prerelease = prerelease
+	}
+	return p, nil
+}
+
+// String formats the partial version with missing parts as zeros
+func (p partialVersion) String() string {
+	version := fmt.Sprintf("%d.%d.%d", p.major, p.minor, p.patch)
+	if p.prerelease != "" {
+		version += "-" + p.prerelease
+	}
+	return version
+}
+
+// versionBefore formats the lowest version of the given release, so that "<" it excludes that
+// release's prereleases too
+func versionBefore(major, minor, patch int) string {
+	return fmt.Sprintf("%d.%d.%d-0", major, minor, patch)
+}
+
+// expandComparator turns an operator applied to a partial version into full-version comparators
+func expandComparator(op string, p partialVersion) []versionComparator {
+	if p.specified == 3 {
+		if op == "" {
+			op = "="
+		}
+		return []versionComparator{{op: op, version: p.String()}}
+	}
+
+	// Bounds of the release block the partial version stands for, such as [1.2.0, 1.3.0) for 1.2
+	lower := p.String()
+	var next partialVersion
+	switch p.specified {
+	case 1:
+		next = partialVersion{major: p.major + 1}
+	case 2:
+		next = partialVersion{major: p.major, minor: p.minor + 1}
+	}
+	upper := versionBefore(next.major, next.minor, 0)
+
+	switch op {
+	case "", "=":
+		if p.specified == 0 {
+			return nil
+		}
+		return []versionComparator{{op: ">=", version: lower}, {op: "<", version: upper}}
+	case ">=":
+		if p.specified == 0 {
+			return nil
+		}
+		return []versionComparator{{op: ">=", version: lower}}
+	case ">":
+		if p.specified == 0 {
+			return []versionComparator{{op: "<", version: versionBefore(0, 0, 0)}}
+		}
+		return []versionComparator{{op: ">=", version: next.String()}}
+	case "<":
+		if p.specified == 0 {
+			return []versionComparator{{op: "<", version: versionBefore(0, 0, 0)}}
+		}
+		return []versionComparator{{op: "<", version: versionBefore(p.major, p.minor, p.patch)}}
+	default: // "<="
+		if p.specified == 0 {
+			return nil
+		}
+		return []versionComparator{{op: "<", version: upper}}
+	}
+}
+
+// expandTilde allows patch-level changes when a minor version is given, and minor-level changes otherwise
+func expandTilde(p partialVersion) []versionComparator {
+	switch p.specified {
+	case 0:
+		return nil
+	case 1:
+		return []versionComparator{{op: ">=", version: p.String()}, {op: "<", version: versionBefore(p.major+1, 0, 0)}}
+	}
+	return []versionComparator{{op: ">=", version: p.String()}, {op: "<", version: versionBefore(p.major, p.minor+1, 0)}}
+}
+
+// expandCaret allows changes that do not modify the left-most non-zero part of the version
+func expandCaret(p partialVersion) []versionComparator {
+	var upper string
+	switch {
+	case p.specified == 0:
+		return nil
+	case p.major > 0 || p.specified == 1:
+		upper = versionBefore(p.major+1, 0, 0)
+	case p.minor > 0 || p.specified == 2:
+		upper = versionBefore(0, p.minor+1, 0)
+	default:
+		upper = versionBefore(0, 0, p.patch+1)
+	}
+	return []versionComparator{{op: ">=", version: p.String()}, {op: "<", version: upper}}
+}
+
+// satisfiedBy reports whether a version satisfies the range. Non-semver versions never do, and
+// prereleases only do when a comparator of the matching set names a prerelease of the same release.
+func (r versionRange) satisfiedBy(version string) bool {
+	if !IsSemanticVersion(version) {
+		return false
+	}
+
+	for _, set := range r {
+		if comparatorSetSatisfiedBy(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// comparatorSetSatisfiedBy reports whether a version satisfies every comparator of a set
+func comparatorSetSatisfiedBy(set []versionComparator, version string) bool {
+	for _, comparator := range set {
+		c := compareSemanticVersions(version, comparator.version)
+		var ok bool
+		switch comparator.op {
+		case "<":
+			ok = c < 0
+		case "<=":
+			ok = c <= 0
+		case ">":
+			ok = c > 0
+		case ">=":
+			ok = c >= 0
+		default:
+			ok = c == 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if !isPrerelease(version) {
+		return true
+	}
+	for _, comparator := range set {
+		if isPrerelease(comparator.version) && releaseOf(comparator.version) == releaseOf(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// releaseOf strips the prerelease and build metadata from a semantic version
+func releaseOf(version string) string {
+	release, _, _ := strings.Cut(version, "+")
+	release, _, _ = strings.Cut(release, "-")
+	return strings.TrimPrefix(release, "v")
+}
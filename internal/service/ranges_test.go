@@ -0,0 +1,128 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionRangeSatisfiedBy(t *testing.T) {
+	tests := []struct {
+		rangeExpr string
+		matches   []string
+		rejects   []string
+	}{
+		{"^1.2.0", []string{"1.2.0", "1.2.5", "1.9.0"}, []string{"1.1.9", "2.0.0", "2.0.0-alpha", "1.3.0-beta.1"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0", "0.2.2"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.0.2"}},
+		{"^1.x", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.0"}},
+		{"^0.x", []string{"0.0.1", "0.9.0"}, []string{"1.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0"}},
+		{">=1.0.0 <2.0.0", []string{"1.0.0", "1.9.9"}, []string{"2.0.0", "0.9.9"}},
+		{">= 1.0.0 < 2.0.0", []string{"1.5.0"}, []string{"2.0.0"}},
+		{">1.2", []string{"1.3.0", "2.0.0"}, []string{"1.2.9", "1.3.0-beta.1"}},
+		{"<=1.2", []string{"1.2.9", "1.0.0"}, []string{"1.3.0"}},
+		{"<1.2", []string{"1.1.9"}, []string{"1.2.0", "1.2.0-beta.1"}},
+		{"1.2.x", []string{"1.2.0", "1.2.7"}, []string{"1.3.0", "1.1.0"}},
+		{"1.2", []string{"1.2.0", "1.2.7"}, []string{"1.3.0"}},
+		{"=1.2.3", []string{"1.2.3", "1.2.3+build.5"}, []string{"1.2.4"}},
+		{"*", []string{"0.0.1", "9.9.9"}, []string{"1.0.0-rc.1", "snapshot"}},
+		{"", []string{"1.0.0"}, []string{"1.0.0-rc.1"}},
+		{"1.2.3 - 2.3.4", []string{"1.2.3", "2.3.4"}, []string{"2.3.5", "1.2.2"}},
+		{"1.2 - 2.3", []string{"1.2.0", "2.3.9"}, []string{"2.4.0"}},
+		{"^1.0.0 || ^3.0.0", []string{"1.5.0", "3.1.0"}, []string{"2.0.0", "4.0.0"}},
+		{">=1.3.0-beta.1 <2.0.0", []string{"1.3.0-beta.2", "1.3.0", "1.4.0"}, []string{"1.3.0-alpha", "1.4.0-beta.1"}},
+		{"^1.3.0-beta.1", []string{"1.3.0-beta.1", "1.3.0-rc.1", "1.5.0"}, []string{"1.4.0-beta.1", "1.3.0-alpha"}},
+		{"v1.2.3", []string{"1.2.3", "v1.2.3"}, []string{"1.2.4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rangeExpr, func(t *testing.T) {
+			versionRange, err := parseVersionRange(tt.rangeExpr)
+			require.NoError(t, err)
+			for _, version := range tt.matches {
+				assert.True(t, versionRange.satisfiedBy(version), "%s should satisfy %s", version, tt.rangeExpr)
+			}
+			for _, version := range tt.rejects {
+				assert.False(t, versionRange.satisfiedBy(version), "%s should not satisfy %s", version, tt.rangeExpr)
+			}
+		})
+	}
+}
+
+func TestParseVersionRangeErrors(t *testing.T) {
+	for _, rangeExpr := range []string{
+		"^1.2.3.4",
+		"1.x.2",
+		">=",
+		"^01.2.3",
+		"1.2-beta",
+		"latest",
+		"^1.0.0 || abc",
+		"~1.2.3-",
+	} {
+		t.Run(rangeExpr, func(t *testing.T) {
+			_, err := parseVersionRange(rangeExpr)
+			assert.ErrorIs(t, err, database.ErrInvalidInput)
+		})
+	}
+}
+
+func TestResolveVersionRange(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	serverName := "com.example/ranged-server"
+	newServer := func(version string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Server resolved by range",
+			Version:     version,
+		}
+	}
+	for _, version := range []string{"1.0.0", "1.2.0", "1.4.0", "1.5.0-beta.1", "2.0.0", "snapshot"} {
+		_, err := service.CreateServer(ctx, newServer(version))
+		require.NoError(t, err)
+	}
+	_, err := service.UpdateServer(ctx, serverName, "1.4.0", newServer("1.4.0"), stringPtr(string(model.StatusDeprecated)))
+	require.NoError(t, err)
+
+	tests := []struct {
+		rangeExpr string
+		want      string
+		wantErr   error
+	}{
+		{"^1.2.0", "1.2.0", nil}, // 1.4.0 is deprecated and 1.5.0-beta.1 is a prerelease
+		{"^1.5.0-beta.1", "1.5.0-beta.1", nil},
+		{"*", "2.0.0", nil},
+		{"~1.0", "1.0.0", nil},
+		{"^3.0.0", "", ErrNoMatchingVersion},
+		{"not a range", "", database.ErrInvalidInput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rangeExpr, func(t *testing.T) {
+			server, err := service.ResolveVersionRange(ctx, serverName, tt.rangeExpr)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, server.Server.Version)
+		})
+	}
+
+	_, err = service.ResolveVersionRange(ctx, "com.example/missing", "^1.0.0")
+	assert.ErrorIs(t, err, database.ErrNotFound)
+	assert.NotErrorIs(t, err, ErrNoMatchingVersion)
+}
@@ -18,32 +18,62 @@ const maxServerVersionsPerServer = 10000
 
 // registryServiceImpl implements the RegistryService interface using our Database
 type registryServiceImpl struct {
-	db  database.Database
-	cfg *config.Config
+	db      database.Database
+	cfg     *config.Config
+	cursors cursorCodec
 }
 
 // NewRegistryService creates a new registry service with the provided database
 func NewRegistryService(db database.Database, cfg *config.Config) RegistryService {
 	return &registryServiceImpl{
-		db:  db,
-		cfg: cfg,
+		db:      db,
+		cfg:     cfg,
+		cursors: newCursorCodec(cfg),
 	}
 }
 
 // ListServers returns registry entries with cursor-based pagination and optional filtering
 func (s *registryServiceImpl) ListServers(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error) {
+	return s.listServers(ctx, filter, cursor, limit, s.db.ListServers)
+}
+
+// ListServersRaw returns registry entries like ListServers without decoding the stored server documents
+func (s *registryServiceImpl) ListServersRaw(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error) {
+	return s.listServers(ctx, filter, cursor, limit, s.db.ListServersRaw)
+}
+
+// listServers pages through servers with the given database list method
+func (s *registryServiceImpl) listServers(
+	ctx context.Context,
+	filter *database.ServerFilter,
+	cursor string,
+	limit int,
+	list func(ctx context.Context, tx pgx.Tx, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error),
+) ([]*apiv0.ServerResponse, string, error) {
 	// If limit is not set or negative, use a default limit
 	if limit <= 0 {
 		limit = 30
 	}
 
-	// Use the database's ListServers method with pagination and filtering
-	serverRecords, nextCursor, err := s.db.ListServers(ctx, nil, filter, cursor, limit)
+	// Client cursors are signed wrappers around the database position
+	position, err := s.cursors.decode(cursor, filter)
 	if err != nil {
 		return nil, "", err
 	}
 
-	return serverRecords, nextCursor, nil
+	// Use the database's list method with pagination and filtering
+	serverRecords, nextPosition, err := list(ctx, nil, filter, position, limit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if s.cfg.EnableRemoteProbing {
+		if err := s.attachRemoteHealth(ctx, serverRecords); err != nil {
+			return nil, "", err
+		}
+	}
+
+	return serverRecords, s.cursors.encode(nextPosition, filter), nil
 }
 
 // GetServerByName retrieves the latest version of a server by its server name
@@ -76,21 +106,58 @@ func (s *registryServiceImpl) GetAllVersionsByServerName(ctx context.Context, se
 	return serverRecords, nil
 }
 
+// GetAllVersionsByServerNameRaw retrieves all versions of a server without decoding the stored server documents
+func (s *registryServiceImpl) GetAllVersionsByServerNameRaw(ctx context.Context, serverName string) ([]*apiv0.ServerResponse, error) {
+	return s.db.GetAllVersionsByServerNameRaw(ctx, nil, serverName)
+}
+
+// ListPackageValidations returns the outcomes of revalidating package registry ownership of latest active versions
+func (s *registryServiceImpl) ListPackageValidations(ctx context.Context, serverName *string, failingOnly bool) ([]*apiv0.PackageValidation, error) {
+	return s.db.ListPackageValidations(ctx, nil, &database.PackageValidationFilter{ServerName: serverName, FailingOnly: failingOnly})
+}
+
 // CreateServer creates a new server version
 func (s *registryServiceImpl) CreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
+	blocks, err := s.activeNamespaceBlocks(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validatePublishRequest(ctx, req, blocks, nil); err != nil {
+		return nil, err
+	}
+
 	// Wrap the entire operation in a transaction
 	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
-		return s.createServerInTransaction(ctx, tx, req)
+		return s.createServerInTransaction(ctx, tx, req, nil)
 	})
 }
 
-// createServerInTransaction contains the actual CreateServer logic within a transaction
-func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx pgx.Tx, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error) {
-	// Validate the request
+// validatePublishRequest runs the checks of a publish that do not need its transaction. Validation makes
+// requests to package registries, so it runs once before the transaction rather than on every retry of it.
+// The namespace is checked against blocks, the blocks in force, which callers read once per request.
+// Dry runs pass a report to record the checks that pass; publishing passes nil.
+func (s *registryServiceImpl) validatePublishRequest(ctx context.Context, req *apiv0.ServerJSON, blocks []*apiv0.NamespaceBlock, report *publishReport) error {
+	// Refuse blocked namespaces before validation makes requests to package registries
+	if err := namespaceBlockedError(req.Name, blocks); err != nil {
+		return failCheck(publishCheckNamespaceBlock, err)
+	}
+	report.pass(publishCheckNamespaceBlock, "The namespace is not blocked")
+
 	if err := validators.ValidatePublishRequest(ctx, *req, s.cfg); err != nil {
-		return nil, err
+		return failCheck(publishCheckValidation, err)
+	}
+	if s.cfg.EnableRegistryValidation {
+		report.pass(publishCheckValidation, fmt.Sprintf("Publisher extensions are valid and registry ownership of %d package(s) was verified", len(req.Packages)))
+	} else {
+		report.pass(publishCheckValidation, "Publisher extensions are valid; registry ownership validation is disabled on this registry")
 	}
 
+	return nil
+}
+
+// createServerInTransaction contains the CreateServer logic within a transaction, for a request that passed
+// validatePublishRequest. Dry runs pass a report to record the checks that pass; publishing passes nil.
+func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx pgx.Tx, req *apiv0.ServerJSON, report *publishReport) (*apiv0.ServerResponse, error) {
 	publishTime := time.Now()
 	serverJSON := *req
 
@@ -99,10 +166,11 @@ func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx
 		return nil, err
 	}
 
-	// Check for duplicate remote URLs
-	if err := s.validateNoDuplicateRemoteURLs(ctx, tx, serverJSON); err != nil {
+	// Claim the remote URLs, which fails when another server holds one of them
+	if err := s.claimRemoteURLs(ctx, tx, serverJSON); err != nil {
 		return nil, err
 	}
+	report.pass(publishCheckRemoteURLs, fmt.Sprintf("No other server uses the %d remote URL(s)", len(serverJSON.Remotes)))
 
 	// Check we haven't exceeded the maximum versions allowed for a server
 	versionCount, err := s.db.CountServerVersions(ctx, tx, serverJSON.Name)
@@ -110,8 +178,9 @@ func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx
 		return nil, err
 	}
 	if versionCount >= maxServerVersionsPerServer {
-		return nil, database.ErrMaxServersReached
+		return nil, failCheck(publishCheckVersionLimit, database.ErrMaxServersReached)
 	}
+	report.pass(publishCheckVersionLimit, fmt.Sprintf("%d of %d versions used", versionCount, maxServerVersionsPerServer))
 
 	// Check this isn't a duplicate version
 	versionExists, err := s.db.CheckVersionExists(ctx, tx, serverJSON.Name, serverJSON.Version)
@@ -119,28 +188,32 @@ func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx
 		return nil, err
 	}
 	if versionExists {
-		return nil, database.ErrInvalidVersion
+		return nil, failCheck(publishCheckDuplicateVersion, database.ErrInvalidVersion)
 	}
+	report.pass(publishCheckDuplicateVersion, fmt.Sprintf("Version %s has not been published yet", serverJSON.Version))
 
 	// Get current latest version to determine if new version should be latest
 	currentLatest, err := s.db.GetCurrentLatestVersion(ctx, tx, serverJSON.Name)
 	if err != nil && !errors.Is(err, database.ErrNotFound) {
 		return nil, err
 	}
+	if currentLatest != nil {
+		report.setCurrentLatest(currentLatest.Server.Version)
+	}
 
-	// Determine if this version should be marked as latest
+	// Determine if this version should be tagged as latest; the first version always is
 	isNewLatest := true
 	if currentLatest != nil {
 		var existingPublishedAt time.Time
 		if currentLatest.Meta.Official != nil {
 			existingPublishedAt = currentLatest.Meta.Official.PublishedAt
 		}
-		isNewLatest = CompareVersions(
+		isNewLatest = shouldTakeLatest(
 			serverJSON.Version,
 			currentLatest.Server.Version,
 			publishTime,
 			existingPublishedAt,
-		) > 0
+		)
 	}
 
 	// Unmark old latest version if needed
@@ -159,60 +232,96 @@ func (s *registryServiceImpl) createServerInTransaction(ctx context.Context, tx
 	}
 
 	// Insert new server version
-	return s.db.CreateServer(ctx, tx, &serverJSON, officialMeta)
-}
+	created, err := s.db.CreateServer(ctx, tx, &serverJSON, officialMeta)
+	if err != nil {
+		return nil, err
+	}
 
-// validateNoDuplicateRemoteURLs checks that no other server is using the same remote URLs
-func (s *registryServiceImpl) validateNoDuplicateRemoteURLs(ctx context.Context, tx pgx.Tx, serverDetail apiv0.ServerJSON) error {
-	// Check each remote URL in the new server for conflicts
-	for _, remote := range serverDetail.Remotes {
-		// Use filter to find servers with this remote URL
-		filter := &database.ServerFilter{RemoteURL: &remote.URL}
+	// Dry runs leave no trace in the audit log, webhook deliveries or change feed, even rolled back
+	if report != nil {
+		return created, nil
+	}
 
-		conflictingServers, _, err := s.db.ListServers(ctx, tx, filter, "", 1000)
-		if err != nil {
-			return fmt.Errorf("failed to check remote URL conflict: %w", err)
-		}
+	if err := s.recordAuditEvent(ctx, tx, database.AuditActionPublish, nil, created); err != nil {
+		return nil, err
+	}
+	if err := s.recordWebhookEvent(ctx, tx, nil, created); err != nil {
+		return nil, err
+	}
 
-		// Check if any conflicting server has a different name
-		for _, conflictingServer := range conflictingServers {
-			if conflictingServer.Server.Name != serverDetail.Name {
-				return fmt.Errorf("remote URL %s is already used by server %s", remote.URL, conflictingServer.Server.Name)
-			}
+	// Feed consumers need to see the previous latest version lose its flag as well as the new version
+	if isNewLatest && currentLatest != nil {
+		if err := s.recordChange(ctx, tx, database.ChangeTypeLatestChange, serverJSON.Name, currentLatest.Server.Version); err != nil {
+			return nil, err
 		}
 	}
+	if err := s.recordChange(ctx, tx, database.ChangeTypePublish, serverJSON.Name, serverJSON.Version); err != nil {
+		return nil, err
+	}
 
-	return nil
+	return created, nil
 }
 
 // UpdateServer updates an existing server with new details
 func (s *registryServiceImpl) UpdateServer(ctx context.Context, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (*apiv0.ServerResponse, error) {
+	skipRegistryValidation, err := s.validateUpdateRequest(ctx, serverName, version, req, newStatus)
+	if err != nil {
+		return nil, err
+	}
+
 	// Wrap the entire operation in a transaction
 	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
-		return s.updateServerInTransaction(ctx, tx, serverName, version, req, newStatus)
+		return s.updateServerInTransaction(ctx, tx, serverName, version, req, newStatus, skipRegistryValidation)
 	})
 }
 
-// updateServerInTransaction contains the actual UpdateServer logic within a transaction
-func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (*apiv0.ServerResponse, error) {
-	// Get current server to check if it's deleted or being deleted
-	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+// validateUpdateRequest runs the checks of an edit that do not need its transaction. Validation makes
+// requests to package registries, so it runs once before the transaction rather than on every retry of it.
+// It returns whether registry validation was skipped because the version is deleted or being deleted.
+func (s *registryServiceImpl) validateUpdateRequest(ctx context.Context, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (bool, error) {
+	// Refuse blocked namespaces before validation makes requests to package registries
+	if ActorFromContext(ctx).isPublisher() {
+		if err := s.checkNamespaceNotBlocked(ctx, nil, serverName); err != nil {
+			return false, err
+		}
+	}
+
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, nil, serverName, version)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+
+	// Validate the request, potentially skipping registry validation for deleted servers
+	skipRegistryValidation := skipsRegistryValidation(currentServer, newStatus)
+	if err := validators.ValidateUpdateRequest(ctx, *req, s.cfg, skipRegistryValidation); err != nil {
+		return false, err
 	}
 
-	// Skip registry validation if:
-	// 1. Server is currently deleted, OR
-	// 2. Server is being set to deleted status
+	return skipRegistryValidation, nil
+}
+
+// skipsRegistryValidation reports whether an edit of a server version skips registry validation, which is
+// when the version is currently deleted or being set to deleted status
+func skipsRegistryValidation(currentServer *apiv0.ServerResponse, newStatus *string) bool {
 	currentlyDeleted := currentServer.Meta.Official != nil && currentServer.Meta.Official.Status == model.StatusDeleted
 	beingDeleted := newStatus != nil && *newStatus == string(model.StatusDeleted)
-	skipRegistryValidation := currentlyDeleted || beingDeleted
+	return currentlyDeleted || beingDeleted
+}
 
-	// Validate the request, potentially skipping registry validation for deleted servers
-	if err := validators.ValidateUpdateRequest(ctx, *req, s.cfg, skipRegistryValidation); err != nil {
+// updateServerInTransaction contains the UpdateServer logic within a transaction, for a request that passed
+// validateUpdateRequest
+func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string, req *apiv0.ServerJSON, newStatus *string, skipRegistryValidation bool) (*apiv0.ServerResponse, error) {
+	// Get current server to check if it's deleted or being deleted
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
 		return nil, err
 	}
 
+	// Registry validation was skipped for a deleted version, which must not have been restored since
+	if skipRegistryValidation && !skipsRegistryValidation(currentServer, newStatus) {
+		return nil, fmt.Errorf("%w: server version was restored while being edited, please retry", database.ErrInvalidInput)
+	}
+
 	// Acquire advisory lock to prevent concurrent edits of servers with same name
 	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
 		return nil, err
@@ -221,9 +330,11 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 	// Merge the request with the current server, preserving metadata
 	updatedServer := *req
 
-	// Check for duplicate remote URLs using the updated server
-	if err := s.validateNoDuplicateRemoteURLs(ctx, tx, updatedServer); err != nil {
-		return nil, err
+	// Claim the remote URLs of the updated server. Deleted versions give up their remote URLs, so they claim nothing.
+	if !skipRegistryValidation {
+		if err := s.claimRemoteURLs(ctx, tx, updatedServer); err != nil {
+			return nil, err
+		}
 	}
 
 	// Update server in database
@@ -234,11 +345,32 @@ func (s *registryServiceImpl) updateServerInTransaction(ctx context.Context, tx
 
 	// Handle status change if provided
 	if newStatus != nil {
-		updatedWithStatus, err := s.db.SetServerStatus(ctx, tx, serverName, version, *newStatus)
+		updatedServerResponse, err = s.db.SetServerStatus(ctx, tx, serverName, version, *newStatus)
 		if err != nil {
 			return nil, err
 		}
-		return updatedWithStatus, nil
+	}
+
+	// Release the URLs that the edit or deletion left unused
+	if err := s.releaseUnusedRemoteURLClaims(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	// Status changes (including takedowns) are recorded as such even when the edit also touched other fields
+	statusChanged := currentServer.Meta.Official != nil && updatedServerResponse.Meta.Official != nil &&
+		currentServer.Meta.Official.Status != updatedServerResponse.Meta.Official.Status
+	action, changeType := database.AuditActionEdit, database.ChangeTypeEdit
+	if statusChanged {
+		action, changeType = database.AuditActionStatusChange, database.ChangeTypeStatusChange
+	}
+	if err := s.recordAuditEvent(ctx, tx, action, currentServer, updatedServerResponse); err != nil {
+		return nil, err
+	}
+	if err := s.recordWebhookEvent(ctx, tx, currentServer, updatedServerResponse); err != nil {
+		return nil, err
+	}
+	if err := s.recordChange(ctx, tx, changeType, serverName, version); err != nil {
+		return nil, err
 	}
 
 	return updatedServerResponse, nil
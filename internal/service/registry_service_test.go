@@ -16,7 +16,7 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
+func TestClaimRemoteURLs(t *testing.T) {
 	ctx := context.Background()
 
 	// Create test data
@@ -115,7 +115,7 @@ func TestValidateNoDuplicateRemoteURLs(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			impl := service.(*registryServiceImpl)
 
-			err := impl.validateNoDuplicateRemoteURLs(ctx, nil, tt.serverDetail)
+			err := impl.claimRemoteURLs(ctx, nil, tt.serverDetail)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -442,8 +442,16 @@ func TestCreateServerConcurrentVersionsNoRace(t *testing.T) {
 }
 
 func TestUpdateServer(t *testing.T) {
+	testUpdateServer(t, database.NewTestDB)
+}
+
+func TestUpdateServerWithMemoryDatabase(t *testing.T) {
+	testUpdateServer(t, newMemoryDB)
+}
+
+func testUpdateServer(t *testing.T, newDB func(testing.TB) database.Database) {
 	ctx := context.Background()
-	testDB := database.NewTestDB(t)
+	testDB := newDB(t)
 	service := NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false})
 
 	serverName := "com.example/update-test-server"
@@ -643,9 +651,17 @@ func TestUpdateServer_SkipValidationForDeletedServers(t *testing.T) {
 }
 
 func TestListServers(t *testing.T) {
+	testListServers(t, database.NewTestDB)
+}
+
+func TestListServersWithMemoryDatabase(t *testing.T) {
+	testListServers(t, newMemoryDB)
+}
+
+func testListServers(t *testing.T, newDB func(testing.TB) database.Database) {
 	ctx := context.Background()
-	testDB := database.NewTestDB(t)
-	service := NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false})
+	testDB := newDB(t)
+	service := NewRegistryService(testDB, &config.Config{EnableRegistryValidation: false, CursorLegacyUntil: time.Now().Add(time.Hour)})
 
 	// Create test servers
 	testServers := []struct {
@@ -732,6 +748,18 @@ func TestListServers(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("cursor pagination", func(t *testing.T) {
+		firstPage, nextCursor, err := service.ListServers(ctx, nil, "", 1)
+		require.NoError(t, err)
+		require.Len(t, firstPage, 1)
+		assert.Equal(t, "com.example/server-alpha", firstPage[0].Server.Name)
+
+		// Should return servers after 'server-alpha' alphabetically
+		secondPage, _, err := service.ListServers(ctx, nil, nextCursor, 10)
+		require.NoError(t, err)
+		assert.Len(t, secondPage, 2)
+	})
 }
 
 func TestVersionComparison(t *testing.T) {
@@ -786,7 +814,64 @@ func TestVersionComparison(t *testing.T) {
 	assert.Equal(t, 1, latestCount, "Exactly one version should be marked as latest")
 }
 
+func TestRegistryServiceWithMemoryDatabase(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	serverName := "com.example/memory-backed-server"
+	for _, version := range []string{"1.0.0", "1.2.0", "1.1.0"} {
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Server stored in memory",
+			Version:     version,
+			Remotes: []model.Transport{
+				{Type: "streamable-http", URL: "https://memory.example.com/mcp"},
+			},
+		})
+		require.NoError(t, err, "Failed to create version %s", version)
+	}
+
+	latest, err := service.GetServerByName(ctx, serverName)
+	require.NoError(t, err)
+	assert.Equal(t, "1.2.0", latest.Server.Version)
+
+	// Duplicate remote URLs are detected through the claims of the in-memory backend
+	_, err = service.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        "com.example/memory-other-server",
+		Description: "Server reusing a remote URL",
+		Version:     "1.0.0",
+		Remotes: []model.Transport{
+			{Type: "streamable-http", URL: "https://memory.example.com/mcp"},
+		},
+	})
+	assert.ErrorContains(t, err, "is already used by server "+serverName)
+
+	// A failed publish must leave no trace
+	_, err = service.CreateServer(ctx, &apiv0.ServerJSON{
+		Schema:      model.CurrentSchemaURL,
+		Name:        serverName,
+		Description: "Duplicate version",
+		Version:     "1.2.0",
+	})
+	assert.ErrorIs(t, err, database.ErrInvalidVersion)
+
+	allVersions, err := service.GetAllVersionsByServerName(ctx, serverName)
+	require.NoError(t, err)
+	assert.Len(t, allVersions, 3)
+
+	deprecated, err := service.UpdateServer(ctx, serverName, "1.2.0", &latest.Server, stringPtr(string(model.StatusDeprecated)))
+	require.NoError(t, err)
+	assert.Equal(t, model.StatusDeprecated, deprecated.Meta.Official.Status)
+}
+
 // Helper functions
 func stringPtr(s string) *string {
 	return &s
 }
+
+// newMemoryDB creates an in-memory database for tests that also run against PostgreSQL
+func newMemoryDB(_ testing.TB) database.Database {
+	return database.NewMemory()
+}
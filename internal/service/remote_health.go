@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// attachRemoteHealth adds the latest probe of each remote to the metadata of the listed server versions
+func (s *registryServiceImpl) attachRemoteHealth(ctx context.Context, servers []*apiv0.ServerResponse) error {
+	if len(servers) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(servers))
+	seen := map[string]bool{}
+	for _, server := range servers {
+		if !seen[server.Server.Name] {
+			seen[server.Server.Name] = true
+			names = append(names, server.Server.Name)
+		}
+	}
+
+	probes, err := s.db.ListRemoteProbes(ctx, nil, &database.RemoteProbeFilter{ServerNames: names})
+	if err != nil {
+		return err
+	}
+
+	type versionKey struct{ name, version string }
+	health := map[versionKey][]apiv0.RemoteHealth{}
+	for _, probe := range probes {
+		key := versionKey{probe.ServerName, probe.Version}
+		health[key] = append(health[key], probe.RemoteHealth)
+	}
+	for _, server := range servers {
+		server.Meta.RemoteHealth = health[versionKey{server.Server.Name, server.Server.Version}]
+	}
+	return nil
+}
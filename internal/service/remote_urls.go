@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// urlSpace is the whitespace trimmed from remote URLs, the ASCII whitespace that the migration backfilling
+// claims trims as well
+const urlSpace = " \t\n\r\f\v"
+
+var (
+	// templateVariable matches a template variable of a remote URL, such as {tenant}
+	templateVariable = regexp.MustCompile(`\{[^}]*\}`)
+	// urlOrigin matches the scheme and authority of a URL, such as https://API.example.com:8443
+	urlOrigin = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9+.-]*://[^/?#]*`)
+)
+
+// canonicalRemoteURL returns the form of a remote URL that claims are keyed on, so that URLs that
+// differ only in case of the scheme and host, default ports, trailing slashes or template variable
+// names conflict. Migration 024_add_remote_url_claims applies the same rules to backfill the claims.
+func canonicalRemoteURL(rawURL string) string {
+	canonical := templateVariable.ReplaceAllString(strings.Trim(rawURL, urlSpace), "{}")
+	origin := urlOrigin.FindString(canonical)
+	path, query, hasQuery := strings.Cut(canonical[len(origin):], "?")
+
+	origin = strings.ToLower(origin)
+	switch {
+	case strings.HasPrefix(origin, "https://"):
+		origin = strings.TrimSuffix(origin, ":443")
+	case strings.HasPrefix(origin, "http://"):
+		origin = strings.TrimSuffix(origin, ":80")
+	}
+
+	canonical = origin + strings.TrimRight(path, "/")
+	if hasQuery {
+		canonical += "?" + query
+	}
+	return canonical
+}
+
+// canonicalRemoteURLs returns the distinct canonical URLs of remotes, in order
+func canonicalRemoteURLs(remotes []model.Transport) []string {
+	seen := map[string]bool{}
+	var urls []string
+	for _, remote := range remotes {
+		url := canonicalRemoteURL(remote.URL)
+		if url == "" || seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+// claimRemoteURLs claims the remote URLs of a server version for its server, failing the remote URL
+// publish check when another server holds one of them
+func (s *registryServiceImpl) claimRemoteURLs(ctx context.Context, tx pgx.Tx, serverJSON apiv0.ServerJSON) error {
+	conflicts, err := s.db.ClaimRemoteURLs(ctx, tx, serverJSON.Name, canonicalRemoteURLs(serverJSON.Remotes))
+	if err != nil {
+		return fmt.Errorf("failed to check remote URL conflict: %w", err)
+	}
+
+	for _, remote := range serverJSON.Remotes {
+		if owner, ok := conflicts[canonicalRemoteURL(remote.URL)]; ok {
+			return failCheck(publishCheckRemoteURLs, fmt.Errorf("remote URL %s is already used by server %s", remote.URL, owner))
+		}
+	}
+
+	return nil
+}
+
+// releaseUnusedRemoteURLClaims releases the claims of a server on URLs that none of its versions
+// use any more, such as after an edit removed a remote or the last version using it was deleted
+func (s *registryServiceImpl) releaseUnusedRemoteURLClaims(ctx context.Context, tx pgx.Tx, serverName string) error {
+	versions, err := s.db.GetAllVersionsByServerName(ctx, tx, serverName)
+	if err != nil {
+		return err
+	}
+
+	var remotes []model.Transport
+	for _, version := range versions {
+		if version.Meta.Official != nil && version.Meta.Official.Status == model.StatusDeleted {
+			continue
+		}
+		remotes = append(remotes, version.Server.Remotes...)
+	}
+
+	return s.db.ReleaseRemoteURLClaims(ctx, tx, serverName, canonicalRemoteURLs(remotes))
+}
+
+// ListRemoteURLClaims returns remote URL claims, for a single URL (in any form that canonicalizes to it) or server when given
+func (s *registryServiceImpl) ListRemoteURLClaims(ctx context.Context, url, serverName *string) ([]*apiv0.RemoteURLClaim, error) {
+	filter := &database.RemoteURLClaimFilter{ServerName: serverName}
+	if url != nil {
+		canonical := canonicalRemoteURL(*url)
+		filter.URL = &canonical
+	}
+	return s.db.ListRemoteURLClaims(ctx, nil, filter)
+}
+
+// ReleaseRemoteURLClaim releases the claim on a remote URL, so that another server can publish it.
+// The server holding the claim takes it again the next time one of its versions with the URL is published or edited.
+func (s *registryServiceImpl) ReleaseRemoteURLClaim(ctx context.Context, url string) error {
+	canonical := canonicalRemoteURL(url)
+	if canonical == "" {
+		return fmt.Errorf("%w: a remote URL is required", database.ErrInvalidInput)
+	}
+
+	return s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		claims, err := s.db.ListRemoteURLClaims(ctx, tx, &database.RemoteURLClaimFilter{URL: &canonical})
+		if err != nil {
+			return err
+		}
+		if len(claims) == 0 {
+			return database.ErrNotFound
+		}
+		if err := s.db.DeleteRemoteURLClaim(ctx, tx, canonical); err != nil {
+			return err
+		}
+
+		// The event is recorded against the server that held the claim
+		before := map[string]any{"url": claims[0].URL, "serverName": claims[0].ServerName}
+		return s.recordAuditChange(ctx, tx, database.AuditActionRemoteURLRelease, claims[0].ServerName, "", before, nil)
+	})
+}
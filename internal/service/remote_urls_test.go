@@ -0,0 +1,131 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalRemoteURL(t *testing.T) {
+	for input, expected := range map[string]string{
+		"https://api.example.com/mcp":                  "https://api.example.com/mcp",
+		"HTTPS://API.Example.com/MCP/":                 "https://api.example.com/MCP",
+		"  https://api.example.com//  ":                "https://api.example.com",
+		"https://api.example.com:8443/mcp/?Key=Value/": "https://api.example.com:8443/mcp?Key=Value/",
+		"https://{tenant}.example.com/{region}/mcp":    "https://{}.example.com/{}/mcp",
+		"https://{TENANT_ID}.example.com/mcp":          "https://{}.example.com/mcp",
+		"https://API.example.com:443/mcp":              "https://api.example.com/mcp",
+		"http://api.example.com:80/mcp":                "http://api.example.com/mcp",
+		"http://api.example.com:443/mcp":               "http://api.example.com:443/mcp",
+		"https://api.example.com:80/mcp":               "https://api.example.com:80/mcp",
+		"https://api.example.com:4430/mcp":             "https://api.example.com:4430/mcp",
+		"\thttps://api.example.com/mcp\n":              "https://api.example.com/mcp",
+		"\u00a0https://api.example.com/mcp":            "\u00a0https://api.example.com/mcp",
+		"not a url/":                                   "not a url",
+		"":                                             "",
+	} {
+		assert.Equal(t, expected, canonicalRemoteURL(input), input)
+	}
+}
+
+func TestRemoteURLClaims(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	newServer := func(name, version string, urls ...string) *apiv0.ServerJSON {
+		server := &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: "A remote server",
+			Version:     version,
+		}
+		for _, url := range urls {
+			server.Remotes = append(server.Remotes, model.Transport{Type: "streamable-http", URL: url})
+		}
+		return server
+	}
+	claimedURLs := func(serverName string) []string {
+		claims, err := service.ListRemoteURLClaims(ctx, nil, &serverName)
+		require.NoError(t, err)
+		urls := make([]string, len(claims))
+		for i, claim := range claims {
+			urls[i] = claim.URL
+		}
+		return urls
+	}
+
+	_, err := service.CreateServer(ctx, newServer("com.example/weather", "1.0.0", "https://{tenant}.Weather.example.com/mcp/", "https://weather.example.com/sse"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://weather.example.com/sse", "https://{}.weather.example.com/mcp"}, claimedURLs("com.example/weather"))
+
+	t.Run("URLs that canonicalize to a claimed URL conflict with its owner", func(t *testing.T) {
+		for _, url := range []string{"HTTPS://{org}.weather.example.com/mcp", "https://WEATHER.example.com/sse/"} {
+			_, err := service.CreateServer(ctx, newServer("com.example/other", "1.0.0", url))
+			require.Error(t, err, url)
+			assert.Contains(t, err.Error(), "remote URL "+url+" is already used by server com.example/weather")
+		}
+		assert.Empty(t, claimedURLs("com.example/other"))
+	})
+
+	t.Run("claims are looked up by any form of the URL", func(t *testing.T) {
+		url := "https://{id}.WEATHER.example.com/mcp/"
+		claims, err := service.ListRemoteURLClaims(ctx, &url, nil)
+		require.NoError(t, err)
+		require.Len(t, claims, 1)
+		assert.Equal(t, "com.example/weather", claims[0].ServerName)
+	})
+
+	t.Run("edits release the URLs they remove", func(t *testing.T) {
+		_, err := service.CreateServer(ctx, newServer("com.example/weather", "1.1.0", "https://weather.example.com/sse"))
+		require.NoError(t, err)
+
+		// 1.1.0 still uses the SSE URL, so only the templated URL is released
+		_, err = service.UpdateServer(ctx, "com.example/weather", "1.0.0", newServer("com.example/weather", "1.0.0", "https://weather.example.com/sse"), nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://weather.example.com/sse"}, claimedURLs("com.example/weather"))
+
+		_, err = service.CreateServer(ctx, newServer("com.example/other", "1.0.0", "https://{org}.weather.example.com/mcp"))
+		require.NoError(t, err)
+	})
+
+	t.Run("deleting the last version using a URL releases it", func(t *testing.T) {
+		deleted := string(model.StatusDeleted)
+		_, err := service.UpdateServer(ctx, "com.example/weather", "1.0.0", newServer("com.example/weather", "1.0.0", "https://weather.example.com/sse"), &deleted)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://weather.example.com/sse"}, claimedURLs("com.example/weather"))
+
+		_, err = service.UpdateServer(ctx, "com.example/weather", "1.1.0", newServer("com.example/weather", "1.1.0", "https://weather.example.com/sse"), &deleted)
+		require.NoError(t, err)
+		assert.Empty(t, claimedURLs("com.example/weather"))
+	})
+
+	t.Run("admins release claims so that another server can publish the URL", func(t *testing.T) {
+		_, err := service.CreateServer(ctx, newServer("com.example/calendar", "1.0.0", "https://calendar.example.com/mcp"))
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, service.ReleaseRemoteURLClaim(ctx, "https://unclaimed.example.com/mcp"), database.ErrNotFound)
+		assert.ErrorIs(t, service.ReleaseRemoteURLClaim(ctx, " "), database.ErrInvalidInput)
+		admin := WithActor(ctx, Actor{AuthMethod: auth.MethodOIDC, Subject: "admin@example.com"})
+		require.NoError(t, service.ReleaseRemoteURLClaim(admin, "HTTPS://Calendar.example.com/mcp/"))
+
+		action := database.AuditActionRemoteURLRelease
+		events, _, err := service.ListAuditEvents(ctx, &database.AuditEventFilter{Action: &action}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, "com.example/calendar", events[0].ServerName)
+		assert.Equal(t, "admin@example.com", events[0].ActorSubject)
+		assert.Equal(t, "https://calendar.example.com/mcp", events[0].Before["url"])
+
+		_, err = service.CreateServer(ctx, newServer("com.example/other", "1.1.0", "https://calendar.example.com/mcp"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"https://calendar.example.com/mcp", "https://{}.weather.example.com/mcp"}, claimedURLs("com.example/other"))
+	})
+}
@@ -11,14 +11,71 @@ import (
 type RegistryService interface {
 	// ListServers retrieve all servers with optional filtering
 	ListServers(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
+	// ListServersRaw retrieve servers like ListServers, passing stored server documents through undecoded for responses
+	ListServersRaw(ctx context.Context, filter *database.ServerFilter, cursor string, limit int) ([]*apiv0.ServerResponse, string, error)
 	// GetServerByName retrieve latest version of a server by server name
 	GetServerByName(ctx context.Context, serverName string) (*apiv0.ServerResponse, error)
 	// GetServerByNameAndVersion retrieve specific version of a server by server name and version
 	GetServerByNameAndVersion(ctx context.Context, serverName string, version string) (*apiv0.ServerResponse, error)
+	// GetServerByNameAndVersionOrTag retrieve a version of a server by exact version, or by a dist-tag pointing at it
+	GetServerByNameAndVersionOrTag(ctx context.Context, serverName string, versionOrTag string) (*apiv0.ServerResponse, error)
+	// ResolveVersionRange retrieve the highest active version of a server that satisfies a semver range
+	ResolveVersionRange(ctx context.Context, serverName, rangeExpr string) (*apiv0.ServerResponse, error)
+	// DiffServerVersions compare two versions of a server, each given as a version or dist-tag
+	DiffServerVersions(ctx context.Context, serverName, from, to string) (*apiv0.ServerDiff, error)
 	// GetAllVersionsByServerName retrieve all versions of a server by server name
 	GetAllVersionsByServerName(ctx context.Context, serverName string) ([]*apiv0.ServerResponse, error)
+	// GetAllVersionsByServerNameRaw retrieve all versions of a server, passing stored server documents through undecoded for responses
+	GetAllVersionsByServerNameRaw(ctx context.Context, serverName string) ([]*apiv0.ServerResponse, error)
 	// CreateServer creates a new server version
 	CreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.ServerResponse, error)
+	// CreateServers publishes several server versions in one transaction, all or none
+	CreateServers(ctx context.Context, reqs []*apiv0.ServerJSON) (*apiv0.BatchPublishResponse, error)
+	// DryRunCreateServer runs every publish check and write, rolls them back and reports the outcome
+	DryRunCreateServer(ctx context.Context, req *apiv0.ServerJSON) (*apiv0.PublishDryRunResponse, error)
 	// UpdateServer updates an existing server and optionally its status
 	UpdateServer(ctx context.Context, serverName, version string, req *apiv0.ServerJSON, newStatus *string) (*apiv0.ServerResponse, error)
+	// UpdateServerStatus deprecates a server version with an optional message and successor, or reactivates it
+	UpdateServerStatus(ctx context.Context, serverName, version string, req *apiv0.StatusUpdateRequest) (*apiv0.ServerResponse, error)
+	// ListServerTags retrieve the dist-tags of a server
+	ListServerTags(ctx context.Context, serverName string) ([]*apiv0.ServerTag, error)
+	// SetServerTag points a dist-tag at an existing version of a server
+	SetServerTag(ctx context.Context, serverName, tag string, req *apiv0.TagUpdateRequest) (*apiv0.ServerTag, error)
+	// DeleteServerTag removes a dist-tag other than latest from a server
+	DeleteServerTag(ctx context.Context, serverName, tag string) error
+	// ListAuditEvents retrieve audit log entries, newest first, with optional filtering
+	ListAuditEvents(ctx context.Context, filter *database.AuditEventFilter, cursor string, limit int) ([]*apiv0.AuditEvent, string, error)
+	// ListChanges retrieve change feed events after a sequence number, in sequence order
+	ListChanges(ctx context.Context, after int64, limit int) ([]*apiv0.ChangeEvent, error)
+	// BlockNamespace stops a namespace and its subdomains from getting publish tokens and publishing
+	BlockNamespace(ctx context.Context, namespace string, req *apiv0.NamespaceBlockRequest) (*apiv0.NamespaceBlock, error)
+	// ListNamespaceBlocks retrieve the blocked namespaces, including those whose block expired if requested
+	ListNamespaceBlocks(ctx context.Context, includeExpired bool) ([]*apiv0.NamespaceBlock, error)
+	// UnblockNamespace lifts the block of a namespace
+	UnblockNamespace(ctx context.Context, namespace string) error
+	// BlockedNamespaces retrieve the namespaces that are currently blocked
+	BlockedNamespaces(ctx context.Context) ([]string, error)
+	// CheckNamespacesNotBlocked checks the namespaces of servers against the blocks in force, returning for each
+	// server nil or an error matching database.ErrNamespaceBlocked
+	CheckNamespacesNotBlocked(ctx context.Context, serverNames []string) ([]error, error)
+	// ListRemoteURLClaims retrieve remote URL claims, for a single remote URL or server when given
+	ListRemoteURLClaims(ctx context.Context, url, serverName *string) ([]*apiv0.RemoteURLClaim, error)
+	// ReleaseRemoteURLClaim releases the claim on a remote URL so that another server can publish it
+	ReleaseRemoteURLClaim(ctx context.Context, url string) error
+	// ListPackageValidations retrieve the outcomes of revalidating package registry ownership, for a single server
+	// when given, and only those that failed if requested
+	ListPackageValidations(ctx context.Context, serverName *string, failingOnly bool) ([]*apiv0.PackageValidation, error)
+	// GetServerCapabilities retrieve the tools, prompts and resources recorded for a version of a server, given
+	// by exact version or by a dist-tag pointing at it
+	GetServerCapabilities(ctx context.Context, serverName, versionOrTag string) (*apiv0.ServerCapabilities, error)
+	// CreateWebhookSubscription subscribes an HTTPS endpoint to events of matching servers, owned by the actor in the context
+	CreateWebhookSubscription(ctx context.Context, req *apiv0.WebhookSubscriptionRequest) (*apiv0.WebhookSubscription, error)
+	// ListWebhookSubscriptions retrieve the webhook subscriptions of owner, or all of them when owner is nil
+	ListWebhookSubscriptions(ctx context.Context, owner *Actor) ([]*apiv0.WebhookSubscription, error)
+	// DeleteWebhookSubscription removes a webhook subscription of owner together with its deliveries
+	DeleteWebhookSubscription(ctx context.Context, id int64, owner *Actor) error
+	// ListWebhookDeliveries retrieve the deliveries of a webhook subscription of owner, newest first
+	ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status *string, limit int, owner *Actor) ([]*apiv0.WebhookDelivery, error)
+	// RedeliverWebhookDelivery queues a delivery of a webhook subscription of owner again, with fresh attempts
+	RedeliverWebhookDelivery(ctx context.Context, subscriptionID, deliveryID int64, owner *Actor) (*apiv0.WebhookDelivery, error)
 }
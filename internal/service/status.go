@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// UpdateServerStatus deprecates a server version, with an optional message and successor, or reactivates it
+func (s *registryServiceImpl) UpdateServerStatus(ctx context.Context, serverName, version string, req *apiv0.StatusUpdateRequest) (*apiv0.ServerResponse, error) {
+	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerResponse, error) {
+		return s.updateServerStatusInTransaction(ctx, tx, serverName, version, req)
+	})
+}
+
+// updateServerStatusInTransaction contains the actual UpdateServerStatus logic within a transaction
+func (s *registryServiceImpl) updateServerStatusInTransaction(ctx context.Context, tx pgx.Tx, serverName, version string, req *apiv0.StatusUpdateRequest) (*apiv0.ServerResponse, error) {
+	switch req.Status {
+	case model.StatusDeprecated:
+	case model.StatusActive:
+		if req.Message != "" || req.ReplacedBy != "" {
+			return nil, fmt.Errorf("%w: message and replacedBy can only be set when deprecating", database.ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: status can only be changed to active or deprecated", database.ErrInvalidInput)
+	}
+
+	if ActorFromContext(ctx).isPublisher() {
+		if err := s.checkNamespaceNotBlocked(ctx, tx, serverName); err != nil {
+			return nil, err
+		}
+	}
+
+	// Serialize with publishes and edits of the same server
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	currentServer, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+	if currentServer.Meta.Official != nil && currentServer.Meta.Official.Status == model.StatusDeleted {
+		return nil, fmt.Errorf("%w: deleted servers cannot be undeleted", database.ErrInvalidInput)
+	}
+
+	// Clients follow the successor pointer, so it must lead somewhere
+	if req.ReplacedBy != "" {
+		count, err := s.db.CountServerVersions(ctx, tx, req.ReplacedBy)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: replacedBy server %s is not in the registry", database.ErrInvalidInput, req.ReplacedBy)
+		}
+	}
+
+	var updatedServer *apiv0.ServerResponse
+	if req.Status == model.StatusDeprecated {
+		updatedServer, err = s.db.DeprecateServer(ctx, tx, serverName, version, req.Message, req.ReplacedBy)
+	} else {
+		updatedServer, err = s.db.SetServerStatus(ctx, tx, serverName, version, string(model.StatusActive))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.recordAuditEvent(ctx, tx, database.AuditActionStatusChange, currentServer, updatedServer); err != nil {
+		return nil, err
+	}
+	if err := s.recordWebhookEvent(ctx, tx, currentServer, updatedServer); err != nil {
+		return nil, err
+	}
+	if err := s.recordChange(ctx, tx, database.ChangeTypeStatusChange, serverName, version); err != nil {
+		return nil, err
+	}
+
+	return updatedServer, nil
+}
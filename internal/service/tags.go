@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// tagNamePattern restricts dist-tags to short names that start with a letter, so that they are
+// never mistaken for the semantic versions they sit next to in URLs
+var tagNamePattern = regexp.MustCompile(`^[a-z][a-z0-9._-]{0,99}$`)
+
+// validateTagName checks that a dist-tag name is well formed
+func validateTagName(tag string) error {
+	if !tagNamePattern.MatchString(tag) {
+		return fmt.Errorf("%w: tag %q must start with a lowercase letter and contain at most 100 lowercase letters, digits, '.', '_' or '-'", database.ErrInvalidInput, tag)
+	}
+	return nil
+}
+
+// GetServerByNameAndVersionOrTag retrieves a version of a server by its exact version, falling back to
+// the version a dist-tag of that name points at
+func (s *registryServiceImpl) GetServerByNameAndVersionOrTag(ctx context.Context, serverName, versionOrTag string) (*apiv0.ServerResponse, error) {
+	serverRecord, err := s.db.GetServerByNameAndVersion(ctx, nil, serverName, versionOrTag)
+	if errors.Is(err, database.ErrNotFound) && validateTagName(versionOrTag) == nil {
+		return s.db.GetServerByNameAndTag(ctx, nil, serverName, versionOrTag)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return serverRecord, nil
+}
+
+// ListServerTags retrieves the dist-tags of a server
+func (s *registryServiceImpl) ListServerTags(ctx context.Context, serverName string) ([]*apiv0.ServerTag, error) {
+	versionCount, err := s.db.CountServerVersions(ctx, nil, serverName)
+	if err != nil {
+		return nil, err
+	}
+	if versionCount == 0 {
+		return nil, database.ErrNotFound
+	}
+
+	return s.db.ListServerTags(ctx, nil, serverName)
+}
+
+// SetServerTag points a dist-tag at a version of a server, creating the tag if needed
+func (s *registryServiceImpl) SetServerTag(ctx context.Context, serverName, tag string, req *apiv0.TagUpdateRequest) (*apiv0.ServerTag, error) {
+	if err := validateTagName(tag); err != nil {
+		return nil, err
+	}
+
+	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.ServerTag, error) {
+		return s.setServerTagInTransaction(ctx, tx, serverName, tag, req.Version)
+	})
+}
+
+// setServerTagInTransaction contains the actual SetServerTag logic within a transaction
+func (s *registryServiceImpl) setServerTagInTransaction(ctx context.Context, tx pgx.Tx, serverName, tag, version string) (*apiv0.ServerTag, error) {
+	// Serialize with publishes, which move the latest tag themselves
+	if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+		return nil, err
+	}
+
+	target, err := s.db.GetServerByNameAndVersion(ctx, tx, serverName, version)
+	if err != nil {
+		return nil, err
+	}
+	if target.Meta.Official != nil && target.Meta.Official.Status == model.StatusDeleted {
+		return nil, fmt.Errorf("%w: deleted versions cannot be tagged", database.ErrInvalidInput)
+	}
+
+	previous, tagged, err := s.findServerTag(ctx, tx, serverName, tag)
+	if err != nil {
+		return nil, err
+	}
+	if tagged && previous.Version == version {
+		return previous, nil
+	}
+
+	currentLatest, err := s.db.GetCurrentLatestVersion(ctx, tx, serverName)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return nil, err
+	}
+
+	serverTag, err := s.db.SetServerTag(ctx, tx, serverName, tag, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var before map[string]any
+	if tagged {
+		before = map[string]any{"tag": tag, "version": previous.Version}
+	}
+	if err := s.recordAuditChange(ctx, tx, database.AuditActionTagSet, serverName, version, before, map[string]any{"tag": tag, "version": version}); err != nil {
+		return nil, err
+	}
+
+	if tag != database.TagLatest {
+		return serverTag, nil
+	}
+
+	// Moving latest changes isLatest on both versions, which feed consumers need to see
+	if currentLatest != nil && currentLatest.Server.Version == version {
+		return serverTag, nil
+	}
+	if currentLatest != nil {
+		if err := s.recordChange(ctx, tx, database.ChangeTypeLatestChange, serverName, currentLatest.Server.Version); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.recordChange(ctx, tx, database.ChangeTypeLatestChange, serverName, version); err != nil {
+		return nil, err
+	}
+
+	return serverTag, nil
+}
+
+// DeleteServerTag removes a dist-tag from a server. The latest tag can only be moved, not removed.
+func (s *registryServiceImpl) DeleteServerTag(ctx context.Context, serverName, tag string) error {
+	if tag == database.TagLatest {
+		return fmt.Errorf("%w: the latest tag cannot be removed, move it to another version instead", database.ErrInvalidInput)
+	}
+
+	return s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := s.db.AcquirePublishLock(ctx, tx, serverName); err != nil {
+			return err
+		}
+		previous, tagged, err := s.findServerTag(ctx, tx, serverName, tag)
+		if err != nil {
+			return err
+		}
+		if !tagged {
+			return database.ErrNotFound
+		}
+		if err := s.db.DeleteServerTag(ctx, tx, serverName, tag); err != nil {
+			return err
+		}
+		return s.recordAuditChange(ctx, tx, database.AuditActionTagDelete, serverName, previous.Version, map[string]any{"tag": tag, "version": previous.Version}, nil)
+	})
+}
+
+// findServerTag returns a dist-tag of a server, and whether the server has a tag of that name
+func (s *registryServiceImpl) findServerTag(ctx context.Context, tx pgx.Tx, serverName, tag string) (*apiv0.ServerTag, bool, error) {
+	tags, err := s.db.ListServerTags(ctx, tx, serverName)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, serverTag := range tags {
+		if serverTag.Tag == tag {
+			return serverTag, true, nil
+		}
+	}
+	return nil, false, nil
+}
@@ -0,0 +1,180 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShouldTakeLatest(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-time.Hour)
+
+	tests := []struct {
+		name    string
+		version string
+		current string
+		want    bool
+	}{
+		{"newer release", "1.3.0", "1.2.9", true},
+		{"older release", "1.2.8", "1.2.9", false},
+		{"prerelease of a newer version", "1.3.0-beta.1", "1.2.9", false},
+		{"newer prerelease over a prerelease", "2.0.0-alpha.2", "2.0.0-alpha.1", true},
+		{"older prerelease over a prerelease", "2.0.0-alpha.1", "2.0.0-alpha.2", false},
+		{"older release over a prerelease", "1.0.0", "2.0.0-alpha.1", true},
+		{"prerelease over a non-semver version", "1.0.0-rc.1", "snapshot", false},
+		{"non-semver over a non-semver version", "nightly", "snapshot", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, shouldTakeLatest(tt.version, tt.current, now, earlier))
+		})
+	}
+}
+
+func TestServerTags(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	serverName := "com.example/tagged-server"
+	publish := func(version string) {
+		t.Helper()
+		_, err := service.CreateServer(ctx, &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Server with release channels",
+			Version:     version,
+		})
+		require.NoError(t, err, "Failed to create version %s", version)
+	}
+	latestVersion := func() string {
+		t.Helper()
+		latest, err := service.GetServerByName(ctx, serverName)
+		require.NoError(t, err)
+		return latest.Server.Version
+	}
+
+	publish("1.2.9")
+	publish("1.3.0-beta.1")
+	assert.Equal(t, "1.2.9", latestVersion(), "prereleases do not take latest")
+
+	_, err := service.SetServerTag(ctx, serverName, "beta", &apiv0.TagUpdateRequest{Version: "1.3.0-beta.1"})
+	require.NoError(t, err)
+
+	t.Run("tags resolve in place of versions", func(t *testing.T) {
+		server, err := service.GetServerByNameAndVersionOrTag(ctx, serverName, "beta")
+		require.NoError(t, err)
+		assert.Equal(t, "1.3.0-beta.1", server.Server.Version)
+
+		server, err = service.GetServerByNameAndVersionOrTag(ctx, serverName, "1.2.9")
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.9", server.Server.Version)
+
+		_, err = service.GetServerByNameAndVersionOrTag(ctx, serverName, "next")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("tags are listed by name", func(t *testing.T) {
+		tags, err := service.ListServerTags(ctx, serverName)
+		require.NoError(t, err)
+		require.Len(t, tags, 2)
+		assert.Equal(t, "beta", tags[0].Tag)
+		assert.Equal(t, "1.3.0-beta.1", tags[0].Version)
+		assert.Equal(t, database.TagLatest, tags[1].Tag)
+		assert.Equal(t, "1.2.9", tags[1].Version)
+
+		_, err = service.ListServerTags(ctx, "com.example/missing")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("moving latest moves isLatest and is recorded in the feed", func(t *testing.T) {
+		before, err := service.ListChanges(ctx, 0, 1000)
+		require.NoError(t, err)
+
+		_, err = service.SetServerTag(ctx, serverName, database.TagLatest, &apiv0.TagUpdateRequest{Version: "1.3.0-beta.1"})
+		require.NoError(t, err)
+		assert.Equal(t, "1.3.0-beta.1", latestVersion())
+
+		versions, err := service.GetAllVersionsByServerName(ctx, serverName)
+		require.NoError(t, err)
+		for _, version := range versions {
+			assert.Equal(t, version.Server.Version == "1.3.0-beta.1", version.Meta.Official.IsLatest, version.Server.Version)
+		}
+
+		latestFilter := database.TagLatest
+		listed, _, err := service.ListServers(ctx, &database.ServerFilter{Name: &serverName, Tag: &latestFilter}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, listed, 1)
+		assert.Equal(t, "1.3.0-beta.1", listed[0].Server.Version)
+
+		after, err := service.ListChanges(ctx, before[len(before)-1].Sequence, 1000)
+		require.NoError(t, err)
+		require.Len(t, after, 2)
+		assert.Equal(t, database.ChangeTypeLatestChange, after[0].Type)
+		assert.Equal(t, "1.2.9", after[0].Version)
+		assert.Equal(t, "1.3.0-beta.1", after[1].Version)
+
+		// A release publish takes latest back from the prerelease
+		publish("1.3.0")
+		assert.Equal(t, "1.3.0", latestVersion())
+	})
+
+	t.Run("invalid changes are rejected", func(t *testing.T) {
+		_, err := service.SetServerTag(ctx, serverName, "1.0.0", &apiv0.TagUpdateRequest{Version: "1.2.9"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+		_, err = service.SetServerTag(ctx, serverName, "Beta", &apiv0.TagUpdateRequest{Version: "1.2.9"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+		_, err = service.SetServerTag(ctx, serverName, "next", &apiv0.TagUpdateRequest{Version: "9.9.9"})
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		err = service.DeleteServerTag(ctx, serverName, database.TagLatest)
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+		err = service.DeleteServerTag(ctx, serverName, "next")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+
+		_, err = service.UpdateServer(ctx, serverName, "1.2.9", &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        serverName,
+			Description: "Server with release channels",
+			Version:     "1.2.9",
+		}, stringPtr(string(model.StatusDeleted)))
+		require.NoError(t, err)
+		_, err = service.SetServerTag(ctx, serverName, "old", &apiv0.TagUpdateRequest{Version: "1.2.9"})
+		assert.ErrorIs(t, err, database.ErrInvalidInput)
+	})
+
+	t.Run("removed tags no longer resolve", func(t *testing.T) {
+		require.NoError(t, service.DeleteServerTag(ctx, serverName, "beta"))
+		_, err := service.GetServerByNameAndVersionOrTag(ctx, serverName, "beta")
+		assert.ErrorIs(t, err, database.ErrNotFound)
+	})
+
+	t.Run("tag changes are audited", func(t *testing.T) {
+		action := database.AuditActionTagSet
+		events, _, err := service.ListAuditEvents(ctx, &database.AuditEventFilter{ServerName: &serverName, Action: &action}, "", 10)
+		require.NoError(t, err)
+		// Publishes moving latest are audited as publishes
+		require.Len(t, events, 2)
+		assert.Equal(t, "1.3.0-beta.1", events[0].Version)
+		assert.Equal(t, map[string]any{"tag": database.TagLatest, "version": "1.2.9"}, events[0].Before)
+		assert.Equal(t, map[string]any{"tag": database.TagLatest, "version": "1.3.0-beta.1"}, events[0].After)
+		assert.Nil(t, events[1].Before)
+		assert.Equal(t, map[string]any{"tag": "beta", "version": "1.3.0-beta.1"}, events[1].After)
+
+		action = database.AuditActionTagDelete
+		events, _, err = service.ListAuditEvents(ctx, &database.AuditEventFilter{ServerName: &serverName, Action: &action}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		assert.Equal(t, map[string]any{"tag": "beta", "version": "1.3.0-beta.1"}, events[0].Before)
+		assert.Nil(t, events[0].After)
+	})
+}
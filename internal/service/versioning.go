@@ -84,3 +84,22 @@ func CompareVersions(version1 string, version2 string, timestamp1 time.Time, tim
 	}
 	return -1
 }
+
+// isPrerelease reports whether a version is a semantic version with a prerelease suffix, such as 1.3.0-beta.1
+func isPrerelease(version string) bool {
+	return IsSemanticVersion(version) && semver.Prerelease(ensureVPrefix(version)) != ""
+}
+
+// shouldTakeLatest decides whether a newly published version takes the latest tag from the current
+// latest version. Prereleases are kept off latest by default: a prerelease only takes the tag from an
+// older prerelease, while a release always takes it from a prerelease and otherwise follows CompareVersions.
+func shouldTakeLatest(version string, currentVersion string, publishedAt time.Time, currentPublishedAt time.Time) bool {
+	newPrerelease, currentPrerelease := isPrerelease(version), isPrerelease(currentVersion)
+	switch {
+	case newPrerelease && !currentPrerelease:
+		return false
+	case !newPrerelease && currentPrerelease:
+		return true
+	}
+	return CompareVersions(version, currentVersion, publishedAt, currentPublishedAt) > 0
+}
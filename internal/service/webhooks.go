@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+)
+
+// webhookSecretBytes is the amount of randomness in a webhook signing secret
+const webhookSecretBytes = 32
+
+// CreateWebhookSubscription subscribes an HTTPS endpoint to events of servers matching a namespace
+// pattern. The subscription is owned by the actor in the context, and the returned subscription is
+// the only time its signing secret is exposed.
+func (s *registryServiceImpl) CreateWebhookSubscription(ctx context.Context, req *apiv0.WebhookSubscriptionRequest) (*apiv0.WebhookSubscription, error) {
+	endpoint, err := url.Parse(req.URL)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return nil, fmt.Errorf("%w: webhook URL must be an absolute https URL", database.ErrInvalidInput)
+	}
+
+	pattern := req.NamespacePattern
+	if pattern == "" {
+		pattern = "*"
+	}
+	if strings.Contains(strings.TrimSuffix(pattern, "*"), "*") || strings.ContainsAny(pattern, " \t\n") {
+		return nil, fmt.Errorf("%w: namespace pattern must be *, a prefix ending in *, or a server name", database.ErrInvalidInput)
+	}
+
+	secret := make([]byte, webhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	actor := ActorFromContext(ctx)
+	subscription := &apiv0.WebhookSubscription{
+		URL:              req.URL,
+		NamespacePattern: pattern,
+		Secret:           hex.EncodeToString(secret),
+		OwnerMethod:      string(actor.AuthMethod),
+		OwnerSubject:     actor.Subject,
+	}
+	err = s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		if err := s.db.CreateWebhookSubscription(ctx, tx, subscription); err != nil {
+			return err
+		}
+		return s.recordAuditChange(ctx, tx, database.AuditActionWebhookSubscribe, "", "", nil, webhookAuditDocument(subscription))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// ListWebhookSubscriptions returns the webhook subscriptions of owner, or all of them when owner is nil
+func (s *registryServiceImpl) ListWebhookSubscriptions(ctx context.Context, owner *Actor) ([]*apiv0.WebhookSubscription, error) {
+	subscriptions, err := s.db.ListWebhookSubscriptions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*apiv0.WebhookSubscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if ownsWebhookSubscription(owner, subscription) {
+			results = append(results, subscription)
+		}
+	}
+	return results, nil
+}
+
+// DeleteWebhookSubscription removes a webhook subscription of owner (any subscription when owner is nil)
+// together with its queued deliveries
+func (s *registryServiceImpl) DeleteWebhookSubscription(ctx context.Context, id int64, owner *Actor) error {
+	return s.db.InTransaction(ctx, func(ctx context.Context, tx pgx.Tx) error {
+		subscription, err := s.getOwnedWebhookSubscription(ctx, tx, id, owner)
+		if err != nil {
+			return err
+		}
+		if err := s.db.DeleteWebhookSubscription(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.recordAuditChange(ctx, tx, database.AuditActionWebhookUnsubscribe, "", "", webhookAuditDocument(subscription), nil)
+	})
+}
+
+// webhookAuditDocument describes a webhook subscription in the audit log, leaving out its signing secret
+func webhookAuditDocument(subscription *apiv0.WebhookSubscription) map[string]any {
+	return map[string]any{
+		"id":               subscription.ID,
+		"url":              subscription.URL,
+		"namespacePattern": subscription.NamespacePattern,
+		"ownerMethod":      subscription.OwnerMethod,
+		"ownerSubject":     subscription.OwnerSubject,
+	}
+}
+
+// ListWebhookDeliveries returns the deliveries of a webhook subscription of owner, newest first
+func (s *registryServiceImpl) ListWebhookDeliveries(ctx context.Context, subscriptionID int64, status *string, limit int, owner *Actor) ([]*apiv0.WebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 30
+	}
+
+	if _, err := s.getOwnedWebhookSubscription(ctx, nil, subscriptionID, owner); err != nil {
+		return nil, err
+	}
+
+	return s.db.ListWebhookDeliveries(ctx, nil, subscriptionID, status, limit)
+}
+
+// RedeliverWebhookDelivery queues a delivery of a webhook subscription of owner again, typically one
+// that went dead while the subscriber was down. Its attempts start over.
+func (s *registryServiceImpl) RedeliverWebhookDelivery(ctx context.Context, subscriptionID, deliveryID int64, owner *Actor) (*apiv0.WebhookDelivery, error) {
+	return database.InTransactionT(ctx, s.db, func(ctx context.Context, tx pgx.Tx) (*apiv0.WebhookDelivery, error) {
+		if _, err := s.getOwnedWebhookSubscription(ctx, tx, subscriptionID, owner); err != nil {
+			return nil, err
+		}
+
+		delivery, err := s.db.RedeliverWebhookDelivery(ctx, tx, deliveryID)
+		if err != nil {
+			return nil, err
+		}
+		// Returning an error rolls back the redelivery of another subscription's delivery
+		if delivery.SubscriptionID != subscriptionID {
+			return nil, database.ErrNotFound
+		}
+		return delivery, nil
+	})
+}
+
+// getOwnedWebhookSubscription retrieves a webhook subscription, reporting subscriptions of someone
+// other than owner as not found so their existence is not revealed
+func (s *registryServiceImpl) getOwnedWebhookSubscription(ctx context.Context, tx pgx.Tx, id int64, owner *Actor) (*apiv0.WebhookSubscription, error) {
+	subscription, err := s.db.GetWebhookSubscription(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !ownsWebhookSubscription(owner, subscription) {
+		return nil, database.ErrNotFound
+	}
+	return subscription, nil
+}
+
+// ownsWebhookSubscription reports whether owner created the subscription; a nil owner owns all of them
+func ownsWebhookSubscription(owner *Actor, subscription *apiv0.WebhookSubscription) bool {
+	return owner == nil || (subscription.OwnerMethod == string(owner.AuthMethod) && subscription.OwnerSubject == owner.Subject)
+}
+
+// recordWebhookEvent queues a delivery of the change from before to after to every matching webhook
+// subscription within the caller's transaction, so events are only sent for changes that commit.
+// before is nil for newly published versions.
+func (s *registryServiceImpl) recordWebhookEvent(ctx context.Context, tx pgx.Tx, before, after *apiv0.ServerResponse) error {
+	subscriptions, err := s.db.ListWebhookSubscriptions(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	eventType := webhookEventType(before, after)
+	for _, subscription := range subscriptions {
+		if !matchesNamespacePattern(after.Server.Name, subscription.NamespacePattern) {
+			continue
+		}
+
+		// Every subscriber receives the same body, so it is only built once there is one
+		if payload == nil {
+			payload, err = json.Marshal(apiv0.WebhookEvent{
+				Type:       eventType,
+				ServerName: after.Server.Name,
+				Version:    after.Server.Version,
+				OccurredAt: time.Now().UTC(),
+				Server:     after,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal webhook event: %w", err)
+			}
+		}
+
+		err := s.db.CreateWebhookDelivery(ctx, tx, &apiv0.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventType:      eventType,
+			ServerName:     after.Server.Name,
+			Version:        after.Server.Version,
+		}, payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// webhookEventType classifies a change of a server version for webhook subscribers
+func webhookEventType(before, after *apiv0.ServerResponse) string {
+	if before == nil {
+		return database.WebhookEventPublished
+	}
+
+	var beforeStatus, afterStatus model.Status
+	if before.Meta.Official != nil {
+		beforeStatus = before.Meta.Official.Status
+	}
+	if after.Meta.Official != nil {
+		afterStatus = after.Meta.Official.Status
+	}
+	if beforeStatus != afterStatus {
+		switch afterStatus {
+		case model.StatusDeprecated:
+			return database.WebhookEventDeprecated
+		case model.StatusDeleted:
+			return database.WebhookEventDeleted
+		}
+	}
+	return database.WebhookEventUpdated
+}
+
+// matchesNamespacePattern reports whether a server name matches a webhook namespace pattern,
+// using the same wildcard rules as permission resource patterns
+func matchesNamespacePattern(serverName, pattern string) bool {
+	if pattern == "*" {
+		return true
+	}
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(serverName, strings.TrimSuffix(pattern, "*"))
+	}
+	return serverName == pattern
+}
@@ -0,0 +1,183 @@
+//nolint:testpackage
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/modelcontextprotocol/registry/internal/auth"
+	"github.com/modelcontextprotocol/registry/internal/config"
+	"github.com/modelcontextprotocol/registry/internal/database"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+	"github.com/modelcontextprotocol/registry/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookSubscriptions(t *testing.T) {
+	ctx := context.Background()
+	service := NewRegistryService(database.NewMemory(), &config.Config{EnableRegistryValidation: false})
+
+	alice := Actor{AuthMethod: auth.MethodGitHubAT, Subject: "alice"}
+	bob := Actor{AuthMethod: auth.MethodGitHubAT, Subject: "bob"}
+
+	subscription, err := service.CreateWebhookSubscription(WithActor(ctx, alice), &apiv0.WebhookSubscriptionRequest{URL: "https://hooks.example.com/mcp"})
+	require.NoError(t, err)
+	assert.Equal(t, "*", subscription.NamespacePattern)
+	assert.Len(t, subscription.Secret, 64)
+	assert.Equal(t, "alice", subscription.OwnerSubject)
+
+	t.Run("invalid subscriptions are rejected", func(t *testing.T) {
+		for _, req := range []apiv0.WebhookSubscriptionRequest{
+			{URL: "http://hooks.example.com/mcp"},
+			{URL: "hooks.example.com/mcp"},
+			{URL: "https://hooks.example.com/mcp", NamespacePattern: "io.github.*/weather"},
+		} {
+			_, err := service.CreateWebhookSubscription(WithActor(ctx, alice), &req)
+			assert.ErrorIs(t, err, database.ErrInvalidInput, req)
+		}
+	})
+
+	t.Run("subscriptions are only visible to their owner", func(t *testing.T) {
+		subscriptions, err := service.ListWebhookSubscriptions(ctx, &alice)
+		require.NoError(t, err)
+		require.Len(t, subscriptions, 1)
+		assert.Empty(t, subscriptions[0].Secret)
+
+		subscriptions, err = service.ListWebhookSubscriptions(ctx, &bob)
+		require.NoError(t, err)
+		assert.Empty(t, subscriptions)
+
+		_, err = service.ListWebhookDeliveries(ctx, subscription.ID, nil, 10, &bob)
+		require.ErrorIs(t, err, database.ErrNotFound)
+		require.ErrorIs(t, service.DeleteWebhookSubscription(ctx, subscription.ID, &bob), database.ErrNotFound)
+
+		// Admins manage every subscription
+		subscriptions, err = service.ListWebhookSubscriptions(ctx, nil)
+		require.NoError(t, err)
+		assert.Len(t, subscriptions, 1)
+	})
+
+	t.Run("deleting a subscription", func(t *testing.T) {
+		other, err := service.CreateWebhookSubscription(WithActor(ctx, bob), &apiv0.WebhookSubscriptionRequest{URL: "https://bob.example.com/hooks"})
+		require.NoError(t, err)
+		require.NoError(t, service.DeleteWebhookSubscription(WithActor(ctx, bob), other.ID, &bob))
+		require.ErrorIs(t, service.DeleteWebhookSubscription(ctx, other.ID, nil), database.ErrNotFound)
+	})
+
+	t.Run("subscription changes are audited without their secret", func(t *testing.T) {
+		bobSubject := "bob"
+		events, _, err := service.ListAuditEvents(ctx, &database.AuditEventFilter{ActorSubject: &bobSubject}, "", 10)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, database.AuditActionWebhookUnsubscribe, events[0].Action)
+		assert.Equal(t, "https://bob.example.com/hooks", events[0].Before["url"])
+		assert.Nil(t, events[0].After)
+		assert.Equal(t, database.AuditActionWebhookSubscribe, events[1].Action)
+		assert.Empty(t, events[1].ServerName)
+		assert.Equal(t, "bob", events[1].After["ownerSubject"])
+		assert.NotContains(t, events[1].After, "secret")
+	})
+}
+
+func TestWebhookEvents(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+	service := NewRegistryService(db, &config.Config{EnableRegistryValidation: false})
+
+	owner := Actor{AuthMethod: auth.MethodGitHubAT, Subject: "example"}
+	subscribe := func(pattern string) *apiv0.WebhookSubscription {
+		subscription, err := service.CreateWebhookSubscription(WithActor(ctx, owner), &apiv0.WebhookSubscriptionRequest{
+			URL:              "https://hooks.example.com/mcp",
+			NamespacePattern: pattern,
+		})
+		require.NoError(t, err)
+		return subscription
+	}
+	all := subscribe("*")
+	weather := subscribe("com.example/weather")
+	other := subscribe("org.other/*")
+
+	eventTypes := func(subscription *apiv0.WebhookSubscription) []string {
+		deliveries, err := service.ListWebhookDeliveries(ctx, subscription.ID, nil, 100, nil)
+		require.NoError(t, err)
+		types := make([]string, len(deliveries))
+		// Deliveries are listed newest first
+		for i, delivery := range deliveries {
+			types[len(deliveries)-1-i] = delivery.EventType
+		}
+		return types
+	}
+
+	newServer := func(name, version, description string) *apiv0.ServerJSON {
+		return &apiv0.ServerJSON{
+			Schema:      model.CurrentSchemaURL,
+			Name:        name,
+			Description: description,
+			Version:     version,
+		}
+	}
+
+	_, err := service.CreateServer(ctx, newServer("com.example/weather", "1.0.0", "Weather"))
+	require.NoError(t, err)
+	_, err = service.CreateServer(ctx, newServer("com.example/calendar", "1.0.0", "Calendar"))
+	require.NoError(t, err)
+	_, err = service.UpdateServer(ctx, "com.example/weather", "1.0.0", newServer("com.example/weather", "1.0.0", "Weather forecasts"), nil)
+	require.NoError(t, err)
+	_, err = service.UpdateServerStatus(ctx, "com.example/weather", "1.0.0", &apiv0.StatusUpdateRequest{Status: model.StatusDeprecated})
+	require.NoError(t, err)
+	_, err = service.UpdateServerStatus(ctx, "com.example/weather", "1.0.0", &apiv0.StatusUpdateRequest{Status: model.StatusActive})
+	require.NoError(t, err)
+	deleted := string(model.StatusDeleted)
+	_, err = service.UpdateServer(ctx, "com.example/calendar", "1.0.0", newServer("com.example/calendar", "1.0.0", "Calendar"), &deleted)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		database.WebhookEventPublished, database.WebhookEventPublished, database.WebhookEventUpdated,
+		database.WebhookEventDeprecated, database.WebhookEventUpdated, database.WebhookEventDeleted,
+	}, eventTypes(all))
+	assert.Equal(t, []string{
+		database.WebhookEventPublished, database.WebhookEventUpdated, database.WebhookEventDeprecated, database.WebhookEventUpdated,
+	}, eventTypes(weather))
+	assert.Empty(t, eventTypes(other))
+
+	t.Run("payloads carry the server after the change", func(t *testing.T) {
+		jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 100, 0)
+		require.NoError(t, err)
+		require.Len(t, jobs, 10)
+
+		var event apiv0.WebhookEvent
+		for _, job := range jobs {
+			if job.Delivery.EventType == database.WebhookEventUpdated {
+				require.NoError(t, json.Unmarshal(job.Payload, &event))
+				break
+			}
+		}
+		assert.Equal(t, database.WebhookEventUpdated, event.Type)
+		assert.Equal(t, "com.example/weather", event.ServerName)
+		require.NotNil(t, event.Server)
+		assert.Equal(t, "Weather forecasts", event.Server.Server.Description)
+	})
+
+	t.Run("no events are queued for changes that roll back", func(t *testing.T) {
+		_, err := service.CreateServer(ctx, newServer("com.example/weather", "1.0.0", "Duplicate"))
+		require.Error(t, err)
+		_, err = service.DryRunCreateServer(ctx, newServer("com.example/weather", "2.0.0", "Dry run"))
+		require.NoError(t, err)
+		assert.Len(t, eventTypes(all), 6)
+	})
+
+	t.Run("redelivery is scoped to the subscription", func(t *testing.T) {
+		deliveries, err := service.ListWebhookDeliveries(ctx, weather.ID, nil, 1, &owner)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+
+		_, err = service.RedeliverWebhookDelivery(ctx, all.ID, deliveries[0].ID, &owner)
+		require.ErrorIs(t, err, database.ErrNotFound)
+
+		redelivered, err := service.RedeliverWebhookDelivery(ctx, weather.ID, deliveries[0].ID, &owner)
+		require.NoError(t, err)
+		assert.Equal(t, database.WebhookDeliveryPending, redelivered.Status)
+	})
+}
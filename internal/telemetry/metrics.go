@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/contrib/instrumentation/runtime"
@@ -32,6 +33,12 @@ type Metrics struct {
 
 	// Up tracks the health of the service
 	Up metric.Int64Gauge
+
+	// TransactionRetries tracks database transactions retried after transient failures
+	TransactionRetries metric.Int64Counter
+
+	// meter creates instruments that are registered after startup, such as the replica lag gauge
+	meter metric.Meter
 }
 
 // ShutdownFunc is a delegate that shuts down the OpenTelemetry components.
@@ -73,14 +80,48 @@ func NewMetrics(meter metric.Meter) (*Metrics, error) {
 		return nil, fmt.Errorf("failed to create service up gauge: %w", err)
 	}
 
+	txRetries, err := meter.Int64Counter(
+		Namespace+".db.transaction.retries",
+		metric.WithDescription("Total number of database transactions retried after transient failures"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create transaction retry counter: %w", err)
+	}
+
 	return &Metrics{
-		Requests:        req,
-		RequestDuration: reqDuration,
-		ErrorCount:      errCount,
-		Up:              up,
+		Requests:           req,
+		RequestDuration:    reqDuration,
+		ErrorCount:         errCount,
+		Up:                 up,
+		TransactionRetries: txRetries,
+		meter:              meter,
 	}, nil
 }
 
+// ReplicaLagFunc reports how far a database read replica is behind the primary
+type ReplicaLagFunc func(ctx context.Context) (time.Duration, error)
+
+// ObserveReplicaLag registers a gauge reporting the read replica lag, measured with lag on every collection
+func (m *Metrics) ObserveReplicaLag(lag ReplicaLagFunc) error {
+	_, err := m.meter.Float64ObservableGauge(
+		Namespace+".db.replica.lag",
+		metric.WithDescription("Seconds the database read replica is behind the primary"),
+		metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(ctx context.Context, observer metric.Float64Observer) error {
+			replicaLag, err := lag(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to measure replica lag: %w", err)
+			}
+			observer.Observe(replicaLag.Seconds())
+			return nil
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create replica lag gauge: %w", err)
+	}
+	return nil
+}
+
 func NewPrometheusMeterProvider(res *resource.Resource, exp *prometheus.Exporter) (*sdkmetric.MeterProvider, error) {
 	if exp == nil {
 		return nil, errors.New("exporter cannot be nil")
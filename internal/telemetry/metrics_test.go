@@ -1,14 +1,18 @@
 package telemetry_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/metric/noop"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/resource"
 
 	"github.com/modelcontextprotocol/registry/internal/telemetry"
@@ -82,3 +86,32 @@ func TestNewPrometheusMeterProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveReplicaLag(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	metrics, err := telemetry.NewMetrics(meter)
+	require.NoError(t, err)
+	require.NoError(t, metrics.ObserveReplicaLag(func(_ context.Context) (time.Duration, error) {
+		return 1500 * time.Millisecond, nil
+	}))
+
+	var collected metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &collected))
+
+	var found bool
+	for _, scope := range collected.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != telemetry.Namespace+".db.replica.lag" {
+				continue
+			}
+			gauge, ok := m.Data.(metricdata.Gauge[float64])
+			require.True(t, ok)
+			require.Len(t, gauge.DataPoints, 1)
+			assert.InDelta(t, 1.5, gauge.DataPoints[0].Value, 0.0001)
+			found = true
+		}
+	}
+	assert.True(t, found, "replica lag gauge was not collected")
+}
@@ -65,10 +65,13 @@ func ValidateMCPB(ctx context.Context, pkg model.Package, _ string) error {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to verify MCPB package accessibility: %w", err)
+		return registryUnavailable(fmt.Errorf("failed to verify MCPB package accessibility: %w", err))
 	}
 	defer resp.Body.Close()
 
+	if isUnavailableStatus(resp.StatusCode) {
+		return registryUnavailable(fmt.Errorf("MCPB package host returned status %d for '%s'", resp.StatusCode, pkg.Identifier))
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("MCPB package '%s' is not publicly accessible (status: %d)", pkg.Identifier, resp.StatusCode)
 	}
@@ -65,10 +65,13 @@ func ValidateNPM(ctx context.Context, pkg model.Package, serverName string) erro
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to fetch package metadata from NPM: %w", err)
+		return registryUnavailable(fmt.Errorf("failed to fetch package metadata from NPM: %w", err))
 	}
 	defer resp.Body.Close()
 
+	if isUnavailableStatus(resp.StatusCode) {
+		return registryUnavailable(fmt.Errorf("NPM registry returned status %d for package '%s'", resp.StatusCode, pkg.Identifier))
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("NPM package '%s' not found (status: %d)", pkg.Identifier, resp.StatusCode)
 	}
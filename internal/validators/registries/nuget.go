@@ -172,12 +172,16 @@ func fetchAndCacheServiceIndex(ctx context.Context, client *http.Client, baseURL
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch NuGet service index: %w", err)
+		return nil, registryUnavailable(fmt.Errorf("failed to fetch NuGet service index: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("NuGet service index returned status %d", resp.StatusCode)
+		err := fmt.Errorf("NuGet service index returned status %d", resp.StatusCode)
+		if isUnavailableStatus(resp.StatusCode) {
+			return nil, registryUnavailable(err)
+		}
+		return nil, err
 	}
 
 	var index serviceIndex
@@ -221,7 +225,7 @@ func validateReadme(ctx context.Context, serverName, lowerID, lowerVersion strin
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return NoReadme, fmt.Errorf("failed to fetch NuGet README: %w", err)
+		return NoReadme, registryUnavailable(fmt.Errorf("failed to fetch NuGet README: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -247,7 +251,11 @@ func validateReadme(ctx context.Context, serverName, lowerID, lowerVersion strin
 		return NoReadme, nil
 	}
 
-	return InvalidReadme, fmt.Errorf("NuGet README request returned status %d", resp.StatusCode)
+	err = fmt.Errorf("NuGet README request returned status %d", resp.StatusCode)
+	if isUnavailableStatus(resp.StatusCode) {
+		return InvalidReadme, registryUnavailable(err)
+	}
+	return InvalidReadme, err
 }
 
 func getPackageContentBaseURL(index *serviceIndex) (string, error) {
@@ -278,7 +286,7 @@ func validatePackageExists(ctx context.Context, lowerID, lowerVersion string, cl
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return PackageIDNotFound, fmt.Errorf("failed to fetch NuGet package index: %w", err)
+		return PackageIDNotFound, registryUnavailable(fmt.Errorf("failed to fetch NuGet package index: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -287,7 +295,11 @@ func validatePackageExists(ctx context.Context, lowerID, lowerVersion string, cl
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return PackageIDNotFound, fmt.Errorf("NuGet package index returned status %d", resp.StatusCode)
+		err := fmt.Errorf("NuGet package index returned status %d", resp.StatusCode)
+		if isUnavailableStatus(resp.StatusCode) {
+			return PackageIDNotFound, registryUnavailable(err)
+		}
+		return PackageIDNotFound, err
 	}
 
 	var contentIndex packageContentIndex
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"strings"
 	"time"
@@ -95,7 +96,7 @@ func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) erro
 	if err != nil {
 		// Check if this is a timeout error
 		if errors.Is(err, context.DeadlineExceeded) {
-			return fmt.Errorf("OCI image validation timed out after 30 seconds for '%s'. The registry may be slow or unreachable", pkg.Identifier)
+			return registryUnavailable(fmt.Errorf("OCI image validation timed out after 30 seconds for '%s'. The registry may be slow or unreachable", pkg.Identifier))
 		}
 
 		// Check for specific HTTP status codes
@@ -112,6 +113,13 @@ func ValidateOCI(ctx context.Context, pkg model.Package, serverName string) erro
 			case http.StatusUnauthorized, http.StatusForbidden:
 				return fmt.Errorf("OCI image '%s' is private or requires authentication. Only public images are supported", pkg.Identifier)
 			}
+			if isUnavailableStatus(transportErr.StatusCode) {
+				return registryUnavailable(fmt.Errorf("failed to fetch OCI image: %w", err))
+			}
+		}
+		var netErr net.Error
+		if errors.As(err, &netErr) {
+			return registryUnavailable(fmt.Errorf("failed to fetch OCI image: %w", err))
 		}
 		return fmt.Errorf("failed to fetch OCI image: %w", err)
 	}
@@ -63,10 +63,13 @@ func ValidatePyPI(ctx context.Context, pkg model.Package, serverName string) err
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to fetch package metadata from PyPI: %w", err)
+		return registryUnavailable(fmt.Errorf("failed to fetch package metadata from PyPI: %w", err))
 	}
 	defer resp.Body.Close()
 
+	if isUnavailableStatus(resp.StatusCode) {
+		return registryUnavailable(fmt.Errorf("PyPI returned status %d for package '%s'", resp.StatusCode, pkg.Identifier))
+	}
 	if resp.StatusCode != http.StatusOK {
 		return fmt.Errorf("PyPI package '%s' not found (status: %d)", pkg.Identifier, resp.StatusCode)
 	}
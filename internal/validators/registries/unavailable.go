@@ -0,0 +1,38 @@
+package registries
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrRegistryUnavailable is matched by validation errors caused by the package registry rather than the
+// package: the registry could not be reached, timed out, rate limited us or returned a server error.
+// Checking again later may succeed.
+var ErrRegistryUnavailable = errors.New("package registry unavailable")
+
+// unavailableError marks err as caused by the registry, keeping its message
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unavailableError) Unwrap() error {
+	return e.err
+}
+
+func (e *unavailableError) Is(target error) bool {
+	return target == ErrRegistryUnavailable
+}
+
+// registryUnavailable marks err as caused by the registry being unavailable
+func registryUnavailable(err error) error {
+	return &unavailableError{err: err}
+}
+
+// isUnavailableStatus reports whether a registry response status says nothing about the package
+func isUnavailableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
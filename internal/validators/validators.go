@@ -678,6 +678,29 @@ func validatePublisherExtensions(req apiv0.ServerJSON) error {
 		}
 	}
 
+	// The capabilities manifest must list named tools and prompts, and resources with URIs
+	manifest, ok, err := req.Meta.CapabilitiesManifest()
+	if err != nil {
+		return fmt.Errorf("_meta.io.modelcontextprotocol.registry/publisher-provided.%s is not a valid capabilities manifest: %w", apiv0.CapabilitiesManifestKey, err)
+	}
+	if ok {
+		for i, tool := range manifest.Tools {
+			if tool.Name == "" {
+				return fmt.Errorf("_meta.io.modelcontextprotocol.registry/publisher-provided.%s.tools[%d] has no name", apiv0.CapabilitiesManifestKey, i)
+			}
+		}
+		for i, prompt := range manifest.Prompts {
+			if prompt.Name == "" {
+				return fmt.Errorf("_meta.io.modelcontextprotocol.registry/publisher-provided.%s.prompts[%d] has no name", apiv0.CapabilitiesManifestKey, i)
+			}
+		}
+		for i, resource := range manifest.Resources {
+			if resource.URI == "" {
+				return fmt.Errorf("_meta.io.modelcontextprotocol.registry/publisher-provided.%s.resources[%d] has no uri", apiv0.CapabilitiesManifestKey, i)
+			}
+		}
+	}
+
 	// Note: ServerJSON._meta only contains PublisherProvided data
 	// Official registry metadata is handled separately in the response structure
 
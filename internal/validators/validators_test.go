@@ -1855,6 +1855,65 @@ func TestValidate_RegistryTypesAndUrls(t *testing.T) {
 	}
 }
 
+func TestValidatePublishRequest_CapabilitiesManifest(t *testing.T) {
+	tests := []struct {
+		name          string
+		manifest      interface{}
+		expectedError string
+	}{
+		{
+			name: "valid manifest",
+			manifest: map[string]interface{}{
+				"tools":     []interface{}{map[string]interface{}{"name": "create_issue", "description": "Create an issue"}},
+				"prompts":   []interface{}{map[string]interface{}{"name": "summarize_issue"}},
+				"resources": []interface{}{map[string]interface{}{"uri": "repo://issues", "mimeType": "application/json"}},
+			},
+		},
+		{
+			name:          "manifest that is not an object",
+			manifest:      []interface{}{"create_issue"},
+			expectedError: "publisher-provided.capabilities is not a valid capabilities manifest",
+		},
+		{
+			name:          "unknown fields",
+			manifest:      map[string]interface{}{"tools": []interface{}{map[string]interface{}{"name": "create_issue", "inputSchema": map[string]interface{}{}}}},
+			expectedError: "publisher-provided.capabilities is not a valid capabilities manifest",
+		},
+		{
+			name:          "tool without a name",
+			manifest:      map[string]interface{}{"tools": []interface{}{map[string]interface{}{"description": "Create an issue"}}},
+			expectedError: "publisher-provided.capabilities.tools[0] has no name",
+		},
+		{
+			name:          "resource without a uri",
+			manifest:      map[string]interface{}{"resources": []interface{}{map[string]interface{}{"name": "issues"}}},
+			expectedError: "publisher-provided.capabilities.resources[0] has no uri",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serverJSON := apiv0.ServerJSON{
+				Schema:      model.CurrentSchemaURL,
+				Name:        "com.example/test-server",
+				Description: "A test server",
+				Version:     "1.0.0",
+				Meta: &apiv0.ServerMeta{
+					PublisherProvided: map[string]interface{}{apiv0.CapabilitiesManifestKey: tt.manifest},
+				},
+			}
+
+			err := validators.ValidatePublishRequest(context.Background(), serverJSON, &config.Config{})
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+			}
+		})
+	}
+}
+
 func createValidServerWithArgument(arg model.Argument) apiv0.ServerJSON {
 	return apiv0.ServerJSON{
 		Schema:      model.CurrentSchemaURL,
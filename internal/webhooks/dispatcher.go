@@ -0,0 +1,196 @@
+// Package webhooks delivers queued webhook events to their subscribers
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/safehttp"
+)
+
+// maxErrorLength bounds how much of a failed response is kept as the delivery's last error
+const maxErrorLength = 512
+
+// Options configures a Dispatcher. Zero values are replaced with defaults.
+type Options struct {
+	// MaxAttempts is the number of attempts after which a delivery is marked dead
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry; every later retry waits twice as long
+	BaseBackoff time.Duration
+	// MaxBackoff caps the delay between retries
+	MaxBackoff time.Duration
+	// BatchSize is the number of deliveries claimed, and sent concurrently, per poll
+	BatchSize int
+	// Lease is how long a claimed delivery is hidden from other dispatchers. It must exceed the client timeout.
+	Lease time.Duration
+	// PollInterval is how often Run looks for due deliveries
+	PollInterval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 8
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = 30 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = 6 * time.Hour
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 20
+	}
+	if o.Lease <= 0 {
+		o.Lease = time.Minute
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 5 * time.Second
+	}
+	return o
+}
+
+// Dispatcher sends due webhook deliveries from the database outbox. Several dispatchers can share a
+// database: each delivery is leased to one of them at a time.
+type Dispatcher struct {
+	db     database.Database
+	client *http.Client
+	opts   Options
+}
+
+// NewDispatcher creates a dispatcher sending deliveries with client, or with a client that times out
+// after 10 seconds and does not follow redirects or connect to non-public addresses when client is nil
+func NewDispatcher(db database.Database, client *http.Client, opts Options) *Dispatcher {
+	if client == nil {
+		client = safehttp.NewClient(10 * time.Second)
+	}
+	return &Dispatcher{db: db, client: client, opts: opts.withDefaults()}
+}
+
+// Run delivers due webhooks every poll interval until ctx is canceled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Keep going while there is a backlog rather than waiting for the next tick
+		for {
+			n, err := d.DeliverDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("Failed to deliver webhooks: %v", err)
+			}
+			if err != nil || n < d.opts.BatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DeliverDue claims one batch of due deliveries, sends them and records the outcomes. It returns the
+// number of deliveries attempted.
+func (d *Dispatcher) DeliverDue(ctx context.Context) (int, error) {
+	jobs, err := d.db.ClaimWebhookDeliveries(ctx, nil, d.opts.BatchSize, d.opts.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(jobs))
+	for i, job := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			attempt := d.attempt(ctx, job)
+			errs[i] = d.db.RecordWebhookAttempt(ctx, nil, job.Delivery.ID, attempt)
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return len(jobs), fmt.Errorf("failed to record webhook attempt: %w", err)
+		}
+	}
+	return len(jobs), nil
+}
+
+// attempt sends one delivery and decides what happens to it next
+func (d *Dispatcher) attempt(ctx context.Context, job *database.WebhookJob) *database.WebhookAttempt {
+	statusCode, err := d.send(ctx, job)
+	if err == nil {
+		return &database.WebhookAttempt{Status: database.WebhookDeliveryDelivered, StatusCode: statusCode}
+	}
+
+	attempts := job.Delivery.Attempts + 1
+	result := &database.WebhookAttempt{
+		Status:     database.WebhookDeliveryPending,
+		StatusCode: statusCode,
+		Error:      err.Error(),
+	}
+	if attempts >= d.opts.MaxAttempts {
+		result.Status = database.WebhookDeliveryDead
+	} else {
+		result.NextAttemptAt = time.Now().Add(d.backoff(attempts))
+	}
+	return result
+}
+
+// backoff returns the delay after the given number of failed attempts
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseBackoff
+	for i := 1; i < attempts && delay < d.opts.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, d.opts.MaxBackoff)
+}
+
+// send POSTs a delivery to its subscriber, returning the response status when there was one.
+// Any status other than 2xx is an error.
+func (d *Dispatcher) send(ctx context.Context, job *database.WebhookJob) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, job.URL, bytes.NewReader(job.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mcp-registry-webhooks")
+	req.Header.Set(HeaderEvent, job.Delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(job.Delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(job.Secret, timestamp, job.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+		if len(body) == 0 {
+			return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+		// Responses are arbitrary bytes, but the error is stored as text
+		message := strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+		return resp.StatusCode, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, message)
+	}
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorLength))
+
+	return resp.StatusCode, nil
+}
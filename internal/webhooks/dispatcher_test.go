@@ -0,0 +1,196 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/modelcontextprotocol/registry/internal/database"
+	"github.com/modelcontextprotocol/registry/internal/webhooks"
+	apiv0 "github.com/modelcontextprotocol/registry/pkg/api/v0"
+)
+
+// receiver is a webhook subscriber that records what it was sent and answers with a configurable status
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []receivedRequest
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, receivedRequest{header: req.Header.Clone(), body: body})
+	w.WriteHeader(r.status)
+}
+
+func (r *receiver) setStatus(status int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = status
+}
+
+func (r *receiver) received() []receivedRequest {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]receivedRequest(nil), r.requests...)
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	db := database.NewMemory()
+
+	sink := &receiver{status: http.StatusOK}
+	server := httptest.NewTLSServer(sink)
+	defer server.Close()
+
+	subscription := &apiv0.WebhookSubscription{
+		URL:              server.URL + "/hooks",
+		NamespacePattern: "*",
+		Secret:           "test-secret",
+		OwnerMethod:      "github-at",
+		OwnerSubject:     "example",
+	}
+	require.NoError(t, db.CreateWebhookSubscription(ctx, nil, subscription))
+
+	queue := func(t *testing.T, version string) *apiv0.WebhookDelivery {
+		t.Helper()
+		delivery := &apiv0.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventType:      database.WebhookEventPublished,
+			ServerName:     "com.example/weather",
+			Version:        version,
+		}
+		require.NoError(t, db.CreateWebhookDelivery(ctx, nil, delivery, []byte(`{"type":"server.published","version":"`+version+`"}`)))
+		return delivery
+	}
+
+	getDelivery := func(t *testing.T, id int64) *apiv0.WebhookDelivery {
+		t.Helper()
+		deliveries, err := db.ListWebhookDeliveries(ctx, nil, subscription.ID, nil, 100)
+		require.NoError(t, err)
+		for _, delivery := range deliveries {
+			if delivery.ID == id {
+				return delivery
+			}
+		}
+		t.Fatalf("delivery %d not found", id)
+		return nil
+	}
+
+	// A tiny backoff lets the test wait out retries
+	dispatcher := webhooks.NewDispatcher(db, server.Client(), webhooks.Options{
+		MaxAttempts: 3,
+		BaseBackoff: time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+	})
+
+	t.Run("deliveries are signed and sent once", func(t *testing.T) {
+		delivery := queue(t, "1.0.0")
+
+		n, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		requests := sink.received()
+		require.Len(t, requests, 1)
+		header := requests[0].header
+		assert.Equal(t, "server.published", header.Get(webhooks.HeaderEvent))
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.NotEmpty(t, header.Get(webhooks.HeaderDelivery))
+		assert.True(t, webhooks.Verify("test-secret", header.Get(webhooks.HeaderTimestamp), requests[0].body, header.Get(webhooks.HeaderSignature)))
+		assert.False(t, webhooks.Verify("other-secret", header.Get(webhooks.HeaderTimestamp), requests[0].body, header.Get(webhooks.HeaderSignature)))
+
+		stored := getDelivery(t, delivery.ID)
+		assert.Equal(t, database.WebhookDeliveryDelivered, stored.Status)
+		assert.Equal(t, 1, stored.Attempts)
+		assert.Equal(t, http.StatusOK, stored.LastStatusCode)
+		assert.NotNil(t, stored.DeliveredAt)
+		assert.Nil(t, stored.NextAttemptAt)
+
+		// Nothing is left to deliver
+		n, err = dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Len(t, sink.received(), 1)
+	})
+
+	t.Run("failures are retried until the delivery is dead", func(t *testing.T) {
+		sink.setStatus(http.StatusServiceUnavailable)
+		delivery := queue(t, "1.1.0")
+
+		for attempt := 1; attempt <= 3; attempt++ {
+			time.Sleep(5 * time.Millisecond)
+			n, err := dispatcher.DeliverDue(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+
+			stored := getDelivery(t, delivery.ID)
+			assert.Equal(t, attempt, stored.Attempts)
+			assert.Equal(t, http.StatusServiceUnavailable, stored.LastStatusCode)
+			assert.Contains(t, stored.LastError, "unexpected status 503")
+			if attempt < 3 {
+				assert.Equal(t, database.WebhookDeliveryPending, stored.Status)
+			} else {
+				assert.Equal(t, database.WebhookDeliveryDead, stored.Status)
+			}
+		}
+
+		time.Sleep(5 * time.Millisecond)
+		n, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+
+		t.Run("redelivery starts over", func(t *testing.T) {
+			sink.setStatus(http.StatusNoContent)
+			redelivered, err := db.RedeliverWebhookDelivery(ctx, nil, delivery.ID)
+			require.NoError(t, err)
+			assert.Equal(t, database.WebhookDeliveryPending, redelivered.Status)
+			assert.Zero(t, redelivered.Attempts)
+
+			n, err := dispatcher.DeliverDue(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, 1, n)
+			assert.Equal(t, database.WebhookDeliveryDelivered, getDelivery(t, delivery.ID).Status)
+		})
+	})
+
+	t.Run("claimed deliveries are not claimed again during their lease", func(t *testing.T) {
+		queue(t, "1.2.0")
+
+		jobs, err := db.ClaimWebhookDeliveries(ctx, nil, 10, time.Hour)
+		require.NoError(t, err)
+		require.Len(t, jobs, 1)
+		assert.Equal(t, subscription.URL, jobs[0].URL)
+		assert.Equal(t, "test-secret", jobs[0].Secret)
+
+		n, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"type":"server.published"}`)
+
+	signature := webhooks.Sign("secret", "1700000000", body)
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, webhooks.Verify("secret", "1700000000", body, signature))
+
+	// The timestamp is covered by the signature
+	assert.False(t, webhooks.Verify("secret", "1700000001", body, signature))
+	assert.False(t, webhooks.Verify("secret", "1700000000", []byte(`{}`), signature))
+}
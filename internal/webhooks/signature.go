@@ -0,0 +1,34 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Headers sent with every webhook delivery
+const (
+	HeaderEvent     = "X-MCP-Registry-Event"
+	HeaderDelivery  = "X-MCP-Registry-Delivery"
+	HeaderTimestamp = "X-MCP-Registry-Timestamp"
+	HeaderSignature = "X-MCP-Registry-Signature"
+)
+
+// signaturePrefix names the algorithm in the signature header value
+const signaturePrefix = "sha256="
+
+// Sign returns the X-MCP-Registry-Signature value for a body sent at the given Unix timestamp:
+// the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret. Covering the
+// timestamp lets subscribers reject replays of old deliveries.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of body sent at timestamp, in constant time
+func Verify(secret, timestamp string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -1,6 +1,8 @@
 package v0
 
 import (
+	"bytes"
+	"encoding/json"
 	"time"
 
 	"github.com/modelcontextprotocol/registry/pkg/model"
@@ -11,15 +13,56 @@ type RegistryExtensions struct {
 	PublishedAt time.Time    `json:"publishedAt" format:"date-time" doc:"Timestamp when the server was first published to the registry"`
 	UpdatedAt   time.Time    `json:"updatedAt,omitempty" format:"date-time" doc:"Timestamp when the server entry was last updated"`
 	IsLatest    bool         `json:"isLatest" doc:"Whether this is the latest version of the server"`
+
+	DeprecationMessage string `json:"deprecationMessage,omitempty" doc:"Publisher's explanation of why this version is deprecated" example:"Superseded by the v2 server, which supports streamable HTTP"`
+	ReplacedBy         string `json:"replacedBy,omitempty" doc:"Name of the server that users of this deprecated version should migrate to" example:"io.github.user/weather-v2"`
+}
+
+// StatusUpdateRequest is a publisher's request to deprecate or reactivate one of their server versions
+type StatusUpdateRequest struct {
+	Status     model.Status `json:"status" enum:"active,deprecated" doc:"New status of the version. Deleting a version remains an admin-only operation."`
+	Message    string       `json:"message,omitempty" maxLength:"500" doc:"Why the version is deprecated, shown to users. Only allowed when deprecating." example:"Superseded by the v2 server, which supports streamable HTTP"`
+	ReplacedBy string       `json:"replacedBy,omitempty" maxLength:"200" doc:"Name of an existing server that users should migrate to. Only allowed when deprecating." example:"io.github.user/weather-v2"`
+}
+
+// ServerTag is a dist-tag: a named pointer, such as latest or beta, to one version of a server
+type ServerTag struct {
+	Tag       string    `json:"tag" doc:"Name of the tag" example:"beta"`
+	Version   string    `json:"version" doc:"Version the tag points at" example:"1.3.0-beta.1"`
+	UpdatedAt time.Time `json:"updatedAt" format:"date-time" doc:"Timestamp when the tag was last moved"`
+}
+
+type ServerTagListResponse struct {
+	Tags []ServerTag `json:"tags" doc:"Tags of the server, ordered by name"`
+}
+
+// TagUpdateRequest moves a dist-tag to another version of the same server
+type TagUpdateRequest struct {
+	Version string `json:"version" minLength:"1" maxLength:"255" doc:"Existing version of the server the tag should point at" example:"1.3.0-beta.1"`
 }
 
 type ResponseMeta struct {
-	Official *RegistryExtensions `json:"io.modelcontextprotocol.registry/official,omitempty" doc:"Official MCP registry metadata"`
+	Official     *RegistryExtensions `json:"io.modelcontextprotocol.registry/official,omitempty" doc:"Official MCP registry metadata"`
+	RemoteHealth []RemoteHealth      `json:"io.modelcontextprotocol.registry/remote-health,omitempty" doc:"Outcome of the latest MCP initialize handshake with each remote of the version, on registries that probe remotes. Only latest active versions are probed."`
 }
 
 type ServerResponse struct {
 	Server ServerJSON   `json:"server" doc:"Server configuration and metadata"`
 	Meta   ResponseMeta `json:"_meta" doc:"Registry-managed metadata"`
+
+	// RawServer is the stored server.json document of a response built by NewRawServerResponse. It is
+	// never marshalled; list endpoints write it out in place of Server.
+	RawServer json.RawMessage `json:"-"`
+}
+
+// NewRawServerResponse builds a response around a stored server.json document without decoding it.
+// Only Server.Name and Server.Version are set, the document itself is kept in RawServer.
+func NewRawServerResponse(name, version string, rawServer json.RawMessage, meta ResponseMeta) *ServerResponse {
+	return &ServerResponse{
+		Server:    ServerJSON{Name: name, Version: version},
+		Meta:      meta,
+		RawServer: rawServer,
+	}
 }
 
 type ServerListResponse struct {
@@ -49,3 +92,315 @@ type Metadata struct {
 	NextCursor string `json:"nextCursor,omitempty" doc:"Pagination cursor for retrieving the next page of results. Use this exact value in the cursor query parameter of your next request."`
 	Count      int    `json:"count" doc:"Number of items in current page"`
 }
+
+// BatchPublishResponse reports the outcome of publishing several servers at once. Either every
+// server was published or none was.
+type BatchPublishResponse struct {
+	Published bool                 `json:"published" doc:"Whether every server in the batch was published"`
+	Results   []BatchPublishResult `json:"results" doc:"Outcome for each server, in request order"`
+}
+
+// BatchPublishResult is the outcome of one server in a batch publish
+type BatchPublishResult struct {
+	Name    string          `json:"name" doc:"Server name" example:"io.github.user/weather"`
+	Version string          `json:"version" doc:"Server version" example:"1.0.2"`
+	Status  string          `json:"status" enum:"published,failed,not_published" doc:"published when the whole batch was published, failed for the servers that stopped it, and not_published for the others"`
+	Error   string          `json:"error,omitempty" doc:"Why the server could not be published"`
+	Server  *ServerResponse `json:"server,omitempty" doc:"Published registry entry"`
+}
+
+// PublishDryRunResponse reports what publishing a server.json would do, without publishing it
+type PublishDryRunResponse struct {
+	ServerName        string          `json:"serverName" doc:"Name of the server that would be published" example:"io.github.user/weather"`
+	Version           string          `json:"version" doc:"Version that would be published" example:"1.0.2"`
+	WouldPublish      bool            `json:"wouldPublish" doc:"Whether every check passed, so publishing would succeed"`
+	WouldBecomeLatest bool            `json:"wouldBecomeLatest" doc:"Whether the version would become the latest version of the server"`
+	CurrentLatest     string          `json:"currentLatest,omitempty" doc:"Current latest version of the server, empty when it has not been published before" example:"1.0.1"`
+	Checks            []PublishCheck  `json:"checks" doc:"Checks in the order publishing runs them, up to and including the first that failed"`
+	Server            *ServerResponse `json:"server,omitempty" doc:"Registry entry that publishing would create, when every check passed"`
+}
+
+// PublishCheck is the outcome of one publish check
+type PublishCheck struct {
+	Name    string `json:"name" enum:"permission,schema,publish_validation,remote_urls,version_limit,duplicate_version,publish" doc:"Check that ran"`
+	Passed  bool   `json:"passed" doc:"Whether the check passed"`
+	Message string `json:"message" doc:"What the check found" example:"Version 1.0.2 has not been published yet"`
+}
+
+// ServerDiff is a field-aware comparison of two versions of a server, for reviewing upgrades
+type ServerDiff struct {
+	ServerName string          `json:"serverName" doc:"Name of the compared server" example:"io.github.user/weather"`
+	From       string          `json:"from" doc:"Version the diff starts from" example:"1.0.0"`
+	To         string          `json:"to" doc:"Version the diff ends at" example:"1.1.0"`
+	Highlights DiffHighlights  `json:"highlights" doc:"Security-relevant additions, summarized from the detailed changes"`
+	Fields     []FieldChange   `json:"fields,omitempty" doc:"Changed top-level fields such as description, websiteUrl or repository.url"`
+	Packages   []PackageChange `json:"packages,omitempty" doc:"Added, removed and changed packages, matched by registry type and identifier"`
+	Remotes    []RemoteChange  `json:"remotes,omitempty" doc:"Added, removed and changed remotes, matched by URL"`
+}
+
+// DiffHighlights lists what a security reviewer should look at first when approving an upgrade
+type DiffHighlights struct {
+	NewPackages       []string `json:"newPackages,omitempty" doc:"Packages the newer version adds, as registryType:identifier"`
+	NewEndpoints      []string `json:"newEndpoints,omitempty" doc:"Remote and package transport URLs the newer version adds"`
+	NewSecrets        []string `json:"newSecrets,omitempty" doc:"Inputs that are secret in the newer version but were not before, as the package or remote followed by the input"`
+	NewRequiredInputs []string `json:"newRequiredInputs,omitempty" doc:"Inputs that are required in the newer version but were not before, in the same form as newSecrets"`
+}
+
+// FieldChange is a changed scalar field, with list and object values rendered as text
+type FieldChange struct {
+	Field  string `json:"field" doc:"Path of the changed field" example:"websiteUrl"`
+	Before string `json:"before,omitempty" doc:"Value in the older version, empty when unset"`
+	After  string `json:"after,omitempty" doc:"Value in the newer version, empty when unset"`
+}
+
+// PackageChange describes a package that was added, removed or changed
+type PackageChange struct {
+	Change               string        `json:"change" enum:"added,removed,changed" doc:"How the package changed"`
+	RegistryType         string        `json:"registryType" doc:"Registry type of the package" example:"npm"`
+	Identifier           string        `json:"identifier" doc:"Identifier of the package" example:"@example/weather"`
+	Fields               []FieldChange `json:"fields,omitempty" doc:"Changed package fields such as version or transport.url"`
+	EnvironmentVariables []InputChange `json:"environmentVariables,omitempty" doc:"Added, removed and changed environment variables"`
+	RuntimeArguments     []InputChange `json:"runtimeArguments,omitempty" doc:"Added, removed and changed runtime arguments"`
+	PackageArguments     []InputChange `json:"packageArguments,omitempty" doc:"Added, removed and changed package arguments"`
+	Headers              []InputChange `json:"headers,omitempty" doc:"Added, removed and changed transport headers"`
+}
+
+// RemoteChange describes a remote that was added, removed or changed
+type RemoteChange struct {
+	Change  string        `json:"change" enum:"added,removed,changed" doc:"How the remote changed"`
+	URL     string        `json:"url" doc:"URL of the remote" example:"https://weather.example.com/mcp"`
+	Fields  []FieldChange `json:"fields,omitempty" doc:"Changed remote fields such as type"`
+	Headers []InputChange `json:"headers,omitempty" doc:"Added, removed and changed headers"`
+}
+
+// InputChange describes an environment variable, argument or header that was added, removed or changed
+type InputChange struct {
+	Change     string        `json:"change" enum:"added,removed,changed" doc:"How the input changed"`
+	Name       string        `json:"name" doc:"Name of the input. Arguments are named by flag, then value hint, then position." example:"WEATHER_API_KEY"`
+	IsRequired bool          `json:"isRequired,omitempty" doc:"Whether the input is required, in the newer version unless it was removed"`
+	IsSecret   bool          `json:"isSecret,omitempty" doc:"Whether the input is secret, in the newer version unless it was removed"`
+	Fields     []FieldChange `json:"fields,omitempty" doc:"Changed fields of the input such as isSecret or default"`
+}
+
+type AuditEvent struct {
+	ID           int64          `json:"id" doc:"Sequential identifier of the audit event"`
+	Action       string         `json:"action" enum:"publish,edit,status_change,tag_set,tag_delete,namespace_block,namespace_unblock,remote_url_release,webhook_subscribe,webhook_unsubscribe" doc:"Mutation that was performed"`
+	ServerName   string         `json:"serverName,omitempty" doc:"Name of the affected server. Omitted for namespace blocks and webhook subscriptions, which concern no server." example:"io.github.user/weather"`
+	Version      string         `json:"version,omitempty" doc:"Version of the affected server, or the version a dist-tag pointed at. Omitted for mutations that do not concern a version." example:"1.0.2"`
+	ActorMethod  string         `json:"actorMethod" doc:"Authentication method of the actor that performed the mutation" example:"github-at"`
+	ActorSubject string         `json:"actorSubject,omitempty" doc:"Subject of the actor within the authentication method, such as a GitHub username or domain" example:"user"`
+	Before       map[string]any `json:"before,omitempty" doc:"Top-level fields that changed, with their values before the mutation"`
+	After        map[string]any `json:"after,omitempty" doc:"Top-level fields that changed, with their values after the mutation"`
+	CreatedAt    time.Time      `json:"createdAt" format:"date-time" doc:"Timestamp when the mutation was recorded"`
+}
+
+type AuditEventListResponse struct {
+	Events   []AuditEvent `json:"events" doc:"List of audit events, newest first"`
+	Metadata Metadata     `json:"metadata" doc:"Pagination metadata"`
+}
+
+type ChangeEvent struct {
+	Sequence   int64           `json:"sequence" doc:"Position of the change in the feed. Sequence numbers strictly increase in commit order."`
+	Type       string          `json:"type" enum:"publish,edit,status_change,latest_change" doc:"Kind of change. latest_change means the version gained or lost the isLatest flag because another version was published or the latest tag was moved."`
+	ServerName string          `json:"serverName" doc:"Name of the changed server" example:"io.github.user/weather"`
+	Version    string          `json:"version" doc:"Version of the changed server" example:"1.0.2"`
+	ChangedAt  time.Time       `json:"changedAt" format:"date-time" doc:"Timestamp when the change was made"`
+	Server     *ServerResponse `json:"server,omitempty" doc:"Current state of the changed server version"`
+}
+
+type ChangeListResponse struct {
+	Changes  []ChangeEvent      `json:"changes" doc:"Change events in sequence order"`
+	Metadata ChangeFeedMetadata `json:"metadata" doc:"Feed position metadata"`
+}
+
+type ChangeFeedMetadata struct {
+	NextAfter int64 `json:"nextAfter" doc:"Sequence number to pass as the after query parameter to resume the feed. Store it after processing the page."`
+	Count     int   `json:"count" doc:"Number of changes in current page"`
+}
+
+// WebhookSubscription is an endpoint that receives signed events when matching servers change
+type WebhookSubscription struct {
+	ID               int64     `json:"id" doc:"Identifier of the subscription"`
+	URL              string    `json:"url" format:"uri" doc:"HTTPS endpoint events are POSTed to" example:"https://aggregator.example.com/hooks/mcp"`
+	NamespacePattern string    `json:"namespacePattern" doc:"Server names the subscription receives events for: * for all servers, a prefix ending in * such as io.github.user/*, or an exact server name" example:"io.github.user/*"`
+	Secret           string    `json:"secret,omitempty" doc:"Key for verifying the X-MCP-Registry-Signature header of events. Only returned when the subscription is created."`
+	OwnerMethod      string    `json:"ownerMethod" doc:"Authentication method of whoever created the subscription" example:"github-at"`
+	OwnerSubject     string    `json:"ownerSubject,omitempty" doc:"Subject of whoever created the subscription, such as a GitHub username or domain" example:"user"`
+	CreatedAt        time.Time `json:"createdAt" format:"date-time" doc:"Timestamp when the subscription was created"`
+}
+
+// WebhookSubscriptionRequest is the body for creating a webhook subscription
+type WebhookSubscriptionRequest struct {
+	URL              string `json:"url" required:"true" format:"uri" maxLength:"2048" doc:"HTTPS endpoint to POST events to" example:"https://aggregator.example.com/hooks/mcp"`
+	NamespacePattern string `json:"namespacePattern,omitempty" maxLength:"255" doc:"Server names to receive events for: * (the default) for all servers, a prefix ending in * such as io.github.user/*, or an exact server name" example:"io.github.user/*"`
+}
+
+type WebhookSubscriptionListResponse struct {
+	Subscriptions []WebhookSubscription `json:"subscriptions" doc:"Webhook subscriptions in creation order"`
+}
+
+// WebhookEvent is the JSON body POSTed to webhook subscribers
+type WebhookEvent struct {
+	Type       string          `json:"type" enum:"server.published,server.updated,server.deprecated,server.deleted" doc:"Kind of event. server.updated covers edits and reactivation."`
+	ServerName string          `json:"serverName" doc:"Name of the changed server" example:"io.github.user/weather"`
+	Version    string          `json:"version" doc:"Version of the changed server" example:"1.0.2"`
+	OccurredAt time.Time       `json:"occurredAt" format:"date-time" doc:"Timestamp when the change was made"`
+	Server     *ServerResponse `json:"server" doc:"State of the server version after the change"`
+}
+
+// WebhookDelivery is an event queued for, or sent to, one webhook subscription
+type WebhookDelivery struct {
+	ID             int64      `json:"id" doc:"Identifier of the delivery, sent in the X-MCP-Registry-Delivery header"`
+	SubscriptionID int64      `json:"subscriptionId" doc:"Subscription the event is delivered to"`
+	EventType      string     `json:"eventType" enum:"server.published,server.updated,server.deprecated,server.deleted" doc:"Kind of event"`
+	ServerName     string     `json:"serverName" doc:"Name of the changed server" example:"io.github.user/weather"`
+	Version        string     `json:"version" doc:"Version of the changed server" example:"1.0.2"`
+	Status         string     `json:"status" enum:"pending,delivered,dead" doc:"pending deliveries are retried with exponential backoff until they are delivered or run out of attempts, when they become dead"`
+	Attempts       int        `json:"attempts" doc:"Number of attempts made"`
+	NextAttemptAt  *time.Time `json:"nextAttemptAt,omitempty" format:"date-time" doc:"When a pending delivery is attempted next"`
+	LastStatusCode int        `json:"lastStatusCode,omitempty" doc:"HTTP status of the last response, when there was one"`
+	LastError      string     `json:"lastError,omitempty" doc:"Why the last attempt failed"`
+	CreatedAt      time.Time  `json:"createdAt" format:"date-time" doc:"Timestamp when the event was queued"`
+	DeliveredAt    *time.Time `json:"deliveredAt,omitempty" format:"date-time" doc:"Timestamp when the subscriber accepted the event"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries []WebhookDelivery `json:"deliveries" doc:"Deliveries of the subscription, newest first"`
+}
+
+// NamespaceBlock stops a namespace from getting publish tokens and publishing servers
+type NamespaceBlock struct {
+	Namespace string     `json:"namespace" doc:"Blocked namespace. Its subdomain namespaces are blocked too." example:"io.github.spammer"`
+	Reason    string     `json:"reason" doc:"Why the namespace is blocked, shown to its publishers" example:"Publishing spam servers"`
+	CreatedBy string     `json:"createdBy" doc:"Admin who blocked the namespace, as auth method and subject" example:"oidc:admin@modelcontextprotocol.io"`
+	CreatedAt time.Time  `json:"createdAt" format:"date-time" doc:"Timestamp when the namespace was blocked"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" format:"date-time" doc:"Timestamp when the block lifts by itself. Blocks without one last until they are removed."`
+}
+
+// NamespaceBlockRequest is the body for blocking a namespace
+type NamespaceBlockRequest struct {
+	Reason    string     `json:"reason" required:"true" minLength:"1" maxLength:"1000" doc:"Why the namespace is blocked, shown to its publishers" example:"Publishing spam servers"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" format:"date-time" doc:"When the block lifts by itself; omit to block until the block is removed"`
+}
+
+type NamespaceBlockListResponse struct {
+	Blocks []NamespaceBlock `json:"blocks" doc:"Namespace blocks in namespace order"`
+}
+
+// RemoteURLClaim records which server a remote URL belongs to. No other server can publish a remote with the URL.
+type RemoteURLClaim struct {
+	URL        string    `json:"url" doc:"Canonical remote URL: scheme and host lowercased, trailing slashes trimmed and template variables written as {}" example:"https://{}.example.com/mcp"`
+	ServerName string    `json:"serverName" doc:"Server holding the claim" example:"com.example/weather"`
+	ClaimedAt  time.Time `json:"claimedAt" format:"date-time" doc:"Timestamp when the server claimed the URL"`
+}
+
+type RemoteURLClaimListResponse struct {
+	Claims []RemoteURLClaim `json:"claims" doc:"Remote URL claims in URL order"`
+}
+
+// PackageValidation is the outcome of the latest periodic check of registry ownership for a package of a published version
+type PackageValidation struct {
+	ServerName          string     `json:"serverName" doc:"Name of the server" example:"io.github.user/weather"`
+	Version             string     `json:"version" doc:"Version of the server the package belongs to" example:"1.0.2"`
+	RegistryType        string     `json:"registryType" doc:"Package registry" example:"npm"`
+	Identifier          string     `json:"identifier" doc:"Package name in the registry" example:"@user/weather-mcp"`
+	PackageVersion      string     `json:"packageVersion,omitempty" doc:"Version of the package" example:"1.0.2"`
+	Passed              bool       `json:"passed" doc:"Whether the package was found in its registry and still names this server"`
+	Error               string     `json:"error,omitempty" doc:"Why the latest check failed"`
+	Inconclusive        bool       `json:"inconclusive,omitempty" doc:"Whether the latest check failed for a reason unrelated to the package, such as its registry timing out or returning a server error. Inconclusive checks do not count as failures."`
+	ConsecutiveFailures int        `json:"consecutiveFailures" doc:"Number of checks that failed since the last one that passed, not counting inconclusive ones"`
+	CheckedAt           time.Time  `json:"checkedAt" format:"date-time" doc:"Timestamp of the latest check"`
+	LastPassedAt        *time.Time `json:"lastPassedAt,omitempty" format:"date-time" doc:"Timestamp of the latest check that passed"`
+}
+
+type PackageValidationListResponse struct {
+	Validations []PackageValidation `json:"validations" doc:"Package validations ordered by server name, version and package"`
+}
+
+// Remote probe statuses
+const (
+	RemoteStatusUp      = "up"
+	RemoteStatusDown    = "down"
+	RemoteStatusSkipped = "skipped"
+)
+
+// RemoteHealth is the outcome of probing a remote of a server version with an MCP initialize handshake
+type RemoteHealth struct {
+	URL             string     `json:"url" doc:"URL of the remote as published" example:"https://api.example.com/mcp"`
+	TransportType   string     `json:"transportType" enum:"streamable-http,sse" doc:"Transport of the remote"`
+	Status          string     `json:"status" enum:"up,down,skipped" doc:"Outcome of the latest probe. Remotes needing a URL variable or header without a published value are skipped."`
+	Error           string     `json:"error,omitempty" doc:"Why the latest probe failed or was skipped"`
+	ProtocolVersion string     `json:"protocolVersion,omitempty" doc:"MCP protocol version the server chose in the latest successful handshake" example:"2025-06-18"`
+	LatencyMs       *int       `json:"latencyMs,omitempty" doc:"Time the latest successful handshake took, in milliseconds"`
+	CheckedAt       time.Time  `json:"checkedAt" format:"date-time" doc:"Timestamp of the latest probe"`
+	LastSuccessAt   *time.Time `json:"lastSuccessAt,omitempty" format:"date-time" doc:"Timestamp of the latest successful handshake"`
+}
+
+// Sources of server capabilities
+const (
+	CapabilitySourceManifest      = "manifest"
+	CapabilitySourceIntrospection = "introspection"
+)
+
+// CapabilitiesManifestKey is the key of the publisher-provided metadata under which publishers may list the
+// tools, prompts and resources of their server instead of having the registry connect to its remotes
+const CapabilitiesManifestKey = "capabilities"
+
+// CapabilityTool is a tool offered by an MCP server
+type CapabilityTool struct {
+	Name        string `json:"name" minLength:"1" maxLength:"128" doc:"Name of the tool" example:"create_issue"`
+	Description string `json:"description,omitempty" maxLength:"2000" doc:"What the tool does"`
+}
+
+// CapabilityPrompt is a prompt offered by an MCP server
+type CapabilityPrompt struct {
+	Name        string `json:"name" minLength:"1" maxLength:"128" doc:"Name of the prompt" example:"summarize_issue"`
+	Description string `json:"description,omitempty" maxLength:"2000" doc:"What the prompt is for"`
+}
+
+// CapabilityResource is a resource offered by an MCP server
+type CapabilityResource struct {
+	URI         string `json:"uri" minLength:"1" maxLength:"2048" doc:"URI of the resource" example:"repo://issues"`
+	Name        string `json:"name,omitempty" maxLength:"128" doc:"Name of the resource" example:"issues"`
+	Description string `json:"description,omitempty" maxLength:"2000" doc:"What the resource contains"`
+	MimeType    string `json:"mimeType,omitempty" doc:"MIME type of the resource" example:"application/json"`
+}
+
+// CapabilitiesManifest lists the tools, prompts and resources of a server, as declared by its publisher
+type CapabilitiesManifest struct {
+	Tools     []CapabilityTool     `json:"tools,omitempty" doc:"Tools offered by the server"`
+	Prompts   []CapabilityPrompt   `json:"prompts,omitempty" doc:"Prompts offered by the server"`
+	Resources []CapabilityResource `json:"resources,omitempty" doc:"Resources offered by the server"`
+}
+
+// CapabilitiesManifest returns the manifest in the publisher-provided metadata, and whether there is one
+func (m *ServerMeta) CapabilitiesManifest() (*CapabilitiesManifest, bool, error) {
+	if m == nil || m.PublisherProvided[CapabilitiesManifestKey] == nil {
+		return nil, false, nil
+	}
+
+	data, err := json.Marshal(m.PublisherProvided[CapabilitiesManifestKey])
+	if err != nil {
+		return nil, true, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var manifest CapabilitiesManifest
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, true, err
+	}
+	return &manifest, true, nil
+}
+
+// ServerCapabilities are the tools, prompts and resources recorded for a server version
+type ServerCapabilities struct {
+	ServerName string               `json:"serverName" doc:"Name of the server" example:"io.github.user/weather"`
+	Version    string               `json:"version" doc:"Version of the server" example:"1.0.2"`
+	Source     string               `json:"source" enum:"manifest,introspection" doc:"Whether the capabilities were declared by the publisher in _meta or listed by the server itself"`
+	Tools      []CapabilityTool     `json:"tools" doc:"Tools offered by the server"`
+	Prompts    []CapabilityPrompt   `json:"prompts" doc:"Prompts offered by the server"`
+	Resources  []CapabilityResource `json:"resources" doc:"Resources offered by the server"`
+	Error      string               `json:"error,omitempty" doc:"Why the latest introspection failed. The capabilities found by the last successful one are kept."`
+	CheckedAt  time.Time            `json:"checkedAt" format:"date-time" doc:"Timestamp of the latest manifest read or introspection"`
+	UpdatedAt  *time.Time           `json:"updatedAt,omitempty" format:"date-time" doc:"Timestamp of the latest successful manifest read or introspection"`
+}